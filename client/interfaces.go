@@ -182,6 +182,7 @@ type InstanceServer interface {
 
 	GetInstanceConsoleLog(instanceName string, args *InstanceConsoleLogArgs) (content io.ReadCloser, err error)
 	DeleteInstanceConsoleLog(instanceName string, args *InstanceConsoleLogArgs) (err error)
+	GetInstanceConsoleScreenshot(instanceName string) (content io.ReadCloser, err error)
 
 	GetInstanceFile(instanceName string, path string) (content io.ReadCloser, resp *InstanceFileResponse, err error)
 	CreateInstanceFile(instanceName string, path string, args InstanceFileArgs) (err error)
@@ -264,6 +265,7 @@ type InstanceServer interface {
 	GetNetworkLoadBalancerAddresses(networkName string) ([]string, error)
 	GetNetworkLoadBalancers(networkName string) ([]api.NetworkLoadBalancer, error)
 	GetNetworkLoadBalancer(networkName string, listenAddress string) (forward *api.NetworkLoadBalancer, ETag string, err error)
+	GetNetworkLoadBalancerState(networkName string, listenAddress string) (state *api.NetworkLoadBalancerState, err error)
 	CreateNetworkLoadBalancer(networkName string, forward api.NetworkLoadBalancersPost) error
 	UpdateNetworkLoadBalancer(networkName string, listenAddress string, forward api.NetworkLoadBalancerPut, ETag string) (err error)
 	DeleteNetworkLoadBalancer(networkName string, listenAddress string) (err error)
@@ -281,6 +283,7 @@ type InstanceServer interface {
 	GetNetworkACLs() (acls []api.NetworkACL, err error)
 	GetNetworkACL(name string) (acl *api.NetworkACL, ETag string, err error)
 	GetNetworkACLLogfile(name string) (log io.ReadCloser, err error)
+	GetNetworkACLState(name string) (aclState *api.NetworkACLState, err error)
 	CreateNetworkACL(acl api.NetworkACLsPost) (err error)
 	UpdateNetworkACL(name string, acl api.NetworkACLPut, ETag string) (err error)
 	RenameNetworkACL(name string, acl api.NetworkACLPost) (err error)
@@ -551,6 +554,9 @@ type StoragePoolVolumeCopyArgs struct {
 
 	// API extension: custom_volume_refresh
 	Refresh bool
+
+	// API extension: storage_volume_clone
+	Clone bool
 }
 
 // The StoragePoolVolumeMoveArgs struct is used to pass additional options
@@ -2432,6 +2432,10 @@ func (r *ProtocolLXD) ConsoleInstance(instanceName string, console api.InstanceC
 		return nil, fmt.Errorf("The server is missing the required \"console_vga_type\" API extension")
 	}
 
+	if console.Type == "vnc" && !r.HasExtension("console_vnc_type") {
+		return nil, fmt.Errorf("The server is missing the required \"console_vnc_type\" API extension")
+	}
+
 	// Send the request
 	useEventListener := r.CheckExtension("operation_wait") != nil
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/console", path, url.PathEscape(instanceName)), console, "", useEventListener)
@@ -2521,6 +2525,10 @@ func (r *ProtocolLXD) ConsoleInstanceDynamic(instanceName string, console api.In
 		return nil, nil, fmt.Errorf("The server is missing the required \"console_vga_type\" API extension")
 	}
 
+	if console.Type == "vnc" && !r.HasExtension("console_vnc_type") {
+		return nil, nil, fmt.Errorf("The server is missing the required \"console_vnc_type\" API extension")
+	}
+
 	// Send the request.
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/console", path, url.PathEscape(instanceName)), console, "", true)
 	if err != nil {
@@ -2630,6 +2638,49 @@ func (r *ProtocolLXD) GetInstanceConsoleLog(instanceName string, args *InstanceC
 	return resp.Body, err
 }
 
+// GetInstanceConsoleScreenshot requests a PNG screenshot of the display of a virtual machine.
+//
+// Note that it's the caller's responsibility to close the returned ReadCloser.
+func (r *ProtocolLXD) GetInstanceConsoleScreenshot(instanceName string) (io.ReadCloser, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("console_screenshot") {
+		return nil, fmt.Errorf("The server is missing the required \"console_screenshot\" API extension")
+	}
+
+	// Prepare the HTTP request
+	url := fmt.Sprintf("%s/1.0%s/%s/console?type=screenshot", r.httpBaseURL.String(), path, url.PathEscape(instanceName))
+
+	url, err = r.setQueryAttributes(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, err
+}
+
 // DeleteInstanceConsoleLog deletes the requested instance's console log.
 func (r *ProtocolLXD) DeleteInstanceConsoleLog(instanceName string, args *InstanceConsoleLogArgs) error {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -2727,6 +2778,18 @@ func (r *ProtocolLXD) CreateInstanceBackup(instanceName string, backup api.Insta
 		return nil, fmt.Errorf("The server is missing the required \"container_backup\" API extension")
 	}
 
+	if backup.IncrementalFrom != "" && !r.HasExtension("backup_incremental") {
+		return nil, fmt.Errorf("The server is missing the required \"backup_incremental\" API extension")
+	}
+
+	if (len(backup.EncryptionRecipients) > 0 || backup.EncryptionPassphrase != "") && !r.HasExtension("backup_encryption") {
+		return nil, fmt.Errorf("The server is missing the required \"backup_encryption\" API extension")
+	}
+
+	if backup.Target != nil && !r.HasExtension("backup_s3_target") {
+		return nil, fmt.Errorf("The server is missing the required \"backup_s3_target\" API extension")
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/backups", path, url.PathEscape(instanceName)), backup, "", true)
 	if err != nil {
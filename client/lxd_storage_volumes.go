@@ -529,6 +529,10 @@ func (r *ProtocolLXD) CopyStoragePoolVolume(pool string, source InstanceServer,
 		return nil, fmt.Errorf("The target server is missing the required \"custom_volume_refresh\" API extension")
 	}
 
+	if args != nil && args.Clone && !r.HasExtension("storage_volume_clone") {
+		return nil, fmt.Errorf("The target server is missing the required \"storage_volume_clone\" API extension")
+	}
+
 	req := api.StorageVolumesPost{
 		Name: args.Name,
 		Type: volume.Type,
@@ -538,6 +542,7 @@ func (r *ProtocolLXD) CopyStoragePoolVolume(pool string, source InstanceServer,
 			Pool:       sourcePool,
 			VolumeOnly: args.VolumeOnly,
 			Refresh:    args.Refresh,
+			Clone:      args.Clone,
 		},
 	}
 
@@ -592,6 +597,10 @@ func (r *ProtocolLXD) CopyStoragePoolVolume(pool string, source InstanceServer,
 		return &rop, nil
 	}
 
+	if args != nil && args.Clone {
+		return nil, fmt.Errorf("Clones can only be created within the same server")
+	}
+
 	if !r.HasExtension("storage_api_remote_volume_handling") {
 		return nil, fmt.Errorf("The server is missing the required \"storage_api_remote_volume_handling\" API extension")
 	}
@@ -871,6 +880,14 @@ func (r *ProtocolLXD) CreateStoragePoolVolumeBackup(pool string, volName string,
 		return nil, fmt.Errorf("The server is missing the required \"custom_volume_backup\" API extension")
 	}
 
+	if (len(backup.EncryptionRecipients) > 0 || backup.EncryptionPassphrase != "") && !r.HasExtension("backup_encryption") {
+		return nil, fmt.Errorf("The server is missing the required \"backup_encryption\" API extension")
+	}
+
+	if backup.Target != nil && !r.HasExtension("backup_s3_target") {
+		return nil, fmt.Errorf("The server is missing the required \"backup_s3_target\" API extension")
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("/storage-pools/%s/volumes/custom/%s/backups", url.PathEscape(pool), url.PathEscape(volName)), backup, "", true)
 	if err != nil {
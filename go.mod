@@ -3,6 +3,7 @@ module github.com/canonical/lxd
 go 1.20
 
 require (
+	filippo.io/age v1.1.1
 	github.com/Rican7/retry v0.3.1
 	github.com/armon/go-proxyproto v0.1.0
 	github.com/canonical/candid v1.12.2
@@ -25,8 +25,10 @@ import (
 type cmdConsole struct {
 	global *cmdGlobal
 
-	flagShowLog bool
-	flagType    string
+	flagShowLog    bool
+	flagType       string
+	flagScreenshot string
+	flagTCP        bool
 }
 
 func (c *cmdConsole) Command() *cobra.Command {
@@ -41,7 +43,9 @@ as well as retrieve past log entries from it.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVar(&c.flagShowLog, "show-log", false, i18n.G("Retrieve the instance's console log"))
-	cmd.Flags().StringVarP(&c.flagType, "type", "t", "console", i18n.G("Type of connection to establish: 'console' for serial console, 'vga' for SPICE graphical output")+"``")
+	cmd.Flags().StringVar(&c.flagScreenshot, "screenshot", "", i18n.G("Save a PNG screenshot of the virtual machine's display to the given file")+"``")
+	cmd.Flags().BoolVar(&c.flagTCP, "tcp", false, i18n.G("Expose graphical consoles on a local TCP port instead of a unix socket"))
+	cmd.Flags().StringVarP(&c.flagType, "type", "t", "console", i18n.G("Type of connection to establish: 'console' for serial console, 'vga' for SPICE graphical output, 'vnc' for VNC graphical output")+"``")
 
 	return cmd
 }
@@ -104,10 +108,14 @@ func (c *cmdConsole) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate flags.
-	if !shared.ValueInSlice(c.flagType, []string{"console", "vga"}) {
+	if !shared.ValueInSlice(c.flagType, []string{"console", "vga", "vnc"}) {
 		return fmt.Errorf(i18n.G("Unknown output type %q"), c.flagType)
 	}
 
+	if c.flagScreenshot != "" && c.flagShowLog {
+		return fmt.Errorf(i18n.G("The --screenshot and --show-log flags cannot be used together"))
+	}
+
 	// Connect to LXD
 	remote, name, err := conf.ParseRemote(args[0])
 	if err != nil {
@@ -140,6 +148,30 @@ func (c *cmdConsole) Run(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Save a screenshot if requested
+	if c.flagScreenshot != "" {
+		screenshot, err := d.GetInstanceConsoleScreenshot(name)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = screenshot.Close() }()
+
+		file, err := os.Create(c.flagScreenshot)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = file.Close() }()
+
+		_, err = io.Copy(file, screenshot)
+		if err != nil {
+			return err
+		}
+
+		return file.Close()
+	}
+
 	return c.Console(d, name)
 }
 
@@ -153,6 +185,8 @@ func (c *cmdConsole) Console(d lxd.InstanceServer, name string) error {
 		return c.console(d, name)
 	case "vga":
 		return c.vga(d, name)
+	case "vnc":
+		return c.vnc(d, name)
 	}
 
 	return fmt.Errorf(i18n.G("Unknown console type %q"), c.flagType)
@@ -224,8 +258,120 @@ func (c *cmdConsole) console(d lxd.InstanceServer, name string) error {
 }
 
 func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
-	var err error
-	conf := c.global.conf
+	listener, err := c.graphicalListen("spice")
+	if err != nil {
+		return err
+	}
+
+	var socket string
+	unixAddr, isUnix := listener.Addr().(*net.UnixAddr)
+	if isUnix {
+		socket = fmt.Sprintf("spice+unix://%s", unixAddr.Name)
+	} else {
+		socket = fmt.Sprintf("spice://%s", listener.Addr().String())
+	}
+
+	// Use either spicy or remote-viewer if available.
+	var cmd *exec.Cmd
+	remoteViewer := c.findCommand("remote-viewer")
+	spicy := c.findCommand("spicy")
+
+	if remoteViewer != "" {
+		cmd = exec.Command(remoteViewer, socket)
+	} else if spicy != "" {
+		cmd = exec.Command(spicy, fmt.Sprintf("--uri=%s", socket))
+	}
+
+	return c.graphical(d, name, "vga", listener, cmd, func() {
+		fmt.Println(i18n.G("LXD automatically uses either spicy or remote-viewer when present."))
+		fmt.Println(i18n.G("As neither could be found, the raw SPICE socket can be found at:"))
+		fmt.Printf("  %s\n", socket)
+	})
+}
+
+func (c *cmdConsole) vnc(d lxd.InstanceServer, name string) error {
+	listener, err := c.graphicalListen("vnc")
+	if err != nil {
+		return err
+	}
+
+	// VNC viewers take a unix socket path as is, and a TCP port after a double colon.
+	var uri string
+	var address string
+	unixAddr, isUnix := listener.Addr().(*net.UnixAddr)
+	if isUnix {
+		uri = fmt.Sprintf("vnc+unix://%s", unixAddr.Name)
+		address = unixAddr.Name
+	} else {
+		tcpAddr := listener.Addr().(*net.TCPAddr)
+		uri = fmt.Sprintf("vnc://%s", tcpAddr.String())
+		address = fmt.Sprintf("%s::%d", tcpAddr.IP.String(), tcpAddr.Port)
+	}
+
+	// Use either remote-viewer or vncviewer if available.
+	var cmd *exec.Cmd
+	remoteViewer := c.findCommand("remote-viewer")
+	vncViewer := c.findCommand("vncviewer")
+
+	if remoteViewer != "" {
+		cmd = exec.Command(remoteViewer, uri)
+	} else if vncViewer != "" {
+		cmd = exec.Command(vncViewer, address)
+	}
+
+	return c.graphical(d, name, "vnc", listener, cmd, func() {
+		fmt.Println(i18n.G("LXD automatically uses either remote-viewer or vncviewer when present."))
+		fmt.Println(i18n.G("As neither could be found, the VNC server can be reached at:"))
+		fmt.Printf("  %s\n", listener.Addr().String())
+	})
+}
+
+// graphicalListen returns the local listener to expose a graphical console on.
+// It's a unix socket only accessible to the current user, except on Windows or when a TCP port was requested.
+func (c *cmdConsole) graphicalListen(extension string) (net.Listener, error) {
+	if runtime.GOOS == "windows" || c.flagTCP {
+		return net.Listen("tcp", "127.0.0.1:0")
+	}
+
+	socketsPath := c.global.conf.ConfigPath("sockets")
+	err := os.MkdirAll(socketsPath, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate a random file name.
+	file, err := os.CreateTemp(socketsPath, "*."+extension)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = file.Close()
+
+	err = os.Remove(file.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", file.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Chmod(file.Name(), 0700)
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// graphical proxies the connections to the listener to the graphical console of the given type.
+// If a viewer command is given, it's started and the console is closed when it exits. Otherwise, printAddress
+// is called and the console is closed once all connections are done.
+func (c *cmdConsole) graphical(d lxd.InstanceServer, name string, consoleType string, listener net.Listener, cmd *exec.Cmd, printAddress func()) error {
+	// Closing a unix listener also removes its socket.
+	defer func() { _ = listener.Close() }()
 
 	// We currently use the control websocket just to abort in case of errors.
 	controlDone := make(chan struct{}, 1)
@@ -237,7 +383,7 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 
 	// Prepare the remote console.
 	req := api.InstanceConsolePost{
-		Type: "vga",
+		Type: consoleType,
 	}
 
 	chDisconnect := make(chan bool)
@@ -248,50 +394,6 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 		ConsoleDisconnect: chDisconnect,
 	}
 
-	// Setup local socket.
-	var socket string
-	var listener net.Listener
-	if runtime.GOOS != "windows" {
-		// Create a temporary unix socket mirroring the instance's spice socket.
-		if !shared.PathExists(conf.ConfigPath("sockets")) {
-			err := os.MkdirAll(conf.ConfigPath("sockets"), 0700)
-			if err != nil {
-				return err
-			}
-		}
-
-		// Generate a random file name.
-		path, err := os.CreateTemp(conf.ConfigPath("sockets"), "*.spice")
-		if err != nil {
-			return err
-		}
-
-		_ = path.Close()
-
-		err = os.Remove(path.Name())
-		if err != nil {
-			return err
-		}
-
-		// Listen on the socket.
-		listener, err = net.Listen("unix", path.Name())
-		if err != nil {
-			return err
-		}
-
-		defer func() { _ = os.Remove(path.Name()) }()
-
-		socket = fmt.Sprintf("spice+unix://%s", path.Name())
-	} else {
-		listener, err = net.Listen("tcp", "127.0.0.1:0")
-		if err != nil {
-			return err
-		}
-
-		addr := listener.Addr().(*net.TCPAddr)
-		socket = fmt.Sprintf("spice://127.0.0.1:%d", addr.Port)
-	}
-
 	// Clean everything up when the viewer is done.
 	go func() {
 		<-chViewer
@@ -336,18 +438,7 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 		}
 	}()
 
-	// Use either spicy or remote-viewer if available.
-	remoteViewer := c.findCommand("remote-viewer")
-	spicy := c.findCommand("spicy")
-
-	if remoteViewer != "" || spicy != "" {
-		var cmd *exec.Cmd
-		if remoteViewer != "" {
-			cmd = exec.Command(remoteViewer, socket)
-		} else {
-			cmd = exec.Command(spicy, fmt.Sprintf("--uri=%s", socket))
-		}
-
+	if cmd != nil {
 		// Start the command.
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
@@ -374,9 +465,7 @@ func (c *cmdConsole) vga(d lxd.InstanceServer, name string) error {
 			_ = cmd.Process.Kill()
 		}()
 	} else {
-		fmt.Println(i18n.G("LXD automatically uses either spicy or remote-viewer when present."))
-		fmt.Println(i18n.G("As neither could be found, the raw SPICE socket can be found at:"))
-		fmt.Printf("  %s\n", socket)
+		printAddress()
 
 		// Wait for all connections to complete.
 		<-chConnected
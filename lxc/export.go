@@ -23,17 +23,27 @@ type cmdExport struct {
 	flagInstanceOnly         bool
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
+	flagIncrementalFrom      string
+	flagKeep                 bool
+	flagEncryptRecipients    []string
+	flagEncryptPassphrase    bool
 }
 
 func (c *cmdExport) Command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("export", i18n.G("[<remote>:]<instance> [target] [--instance-only] [--optimized-storage]"))
+	cmd.Use = usage("export", i18n.G("[<remote>:]<instance> [target] [--instance-only] [--optimized-storage] [--incremental-from <backup>]"))
 	cmd.Short = i18n.G("Export instance backups")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`Export instances as backup tarballs.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`lxc export u1 backup0.tar.gz
-    Download a backup tarball of the u1 instance.`))
+    Download a backup tarball of the u1 instance.
+
+lxc export u1 backup1.tar.gz --incremental-from backup0 --keep
+    Download a backup tarball of the u1 instance only containing the changes since the backup0 backup kept on the server.
+
+lxc export u1 backup0.tar.gz.age --encrypt-to age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+    Download a backup tarball of the u1 instance encrypted to the given X25519 public key.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVar(&c.flagInstanceOnly, "instance-only", false,
@@ -41,6 +51,10 @@ func (c *cmdExport) Command() *cobra.Command {
 	cmd.Flags().BoolVar(&c.flagOptimizedStorage, "optimized-storage", false,
 		i18n.G("Use storage driver optimized format (can only be restored on a similar pool)"))
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Compression algorithm to use (none for uncompressed)")+"``")
+	cmd.Flags().StringVar(&c.flagIncrementalFrom, "incremental-from", "", i18n.G("Only include the changes since a previous backup kept on the server")+"``")
+	cmd.Flags().BoolVar(&c.flagKeep, "keep", false, i18n.G("Keep the backup on the server so it can be used as the base of incremental backups"))
+	cmd.Flags().StringArrayVar(&c.flagEncryptRecipients, "encrypt-to", nil, i18n.G("Encrypt the backup to an X25519 public key (age recipient)")+"``")
+	cmd.Flags().BoolVar(&c.flagEncryptPassphrase, "encrypt-passphrase", false, i18n.G("Encrypt the backup with a passphrase (prompted for)"))
 
 	return cmd
 }
@@ -74,6 +88,17 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 		InstanceOnly:         instanceOnly,
 		OptimizedStorage:     c.flagOptimizedStorage,
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		IncrementalFrom:      c.flagIncrementalFrom,
+		EncryptionRecipients: c.flagEncryptRecipients,
+	}
+
+	if c.flagEncryptPassphrase {
+		req.EncryptionPassphrase = cli.AskPassword(i18n.G("Backup passphrase: "))
+	}
+
+	// Kept backups don't expire.
+	if c.flagKeep {
+		req.ExpiresAt = time.Time{}
 	}
 
 	op, err := d.CreateInstanceBackup(name, req)
@@ -120,7 +145,11 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	defer func() {
-		// Delete backup after we're done
+		// Delete backup after we're done unless asked to keep it
+		if c.flagKeep {
+			return
+		}
+
 		op, err = d.DeleteInstanceBackup(name, backupName)
 		if err == nil {
 			_ = op.Wait()
@@ -173,9 +202,13 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		_, ext, _, err := shared.DetectCompressionFile(target)
-		if err != nil {
-			return err
+		// The compression of encrypted backups can't be detected.
+		ext := ".backup.age"
+		if len(req.EncryptionRecipients) == 0 && req.EncryptionPassphrase == "" {
+			_, ext, _, err = shared.DetectCompressionFile(target)
+			if err != nil {
+				return err
+			}
 		}
 
 		err = os.Rename(shared.HostPathFollow(targetName), shared.HostPathFollow(name+ext))
@@ -189,6 +222,11 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Failed to close export file: %w", err)
 	}
 
+	if c.flagKeep {
+		progress.Done(fmt.Sprintf(i18n.G("Backup %q exported successfully and kept on the server"), backupName))
+		return nil
+	}
+
 	progress.Done(i18n.G("Backup exported successfully!"))
 	return nil
 }
@@ -18,7 +18,8 @@ import (
 type cmdImport struct {
 	global *cmdGlobal
 
-	flagStorage string
+	flagStorage  string
+	flagIdentity string
 }
 
 func (c *cmdImport) Command() *cobra.Command {
@@ -29,10 +30,14 @@ func (c *cmdImport) Command() *cobra.Command {
 		`Import backups of instances including their snapshots.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`lxc import backup0.tar.gz
-    Create a new instance using backup0.tar.gz as the source.`))
+    Create a new instance using backup0.tar.gz as the source.
+
+lxc import backup0.tar.gz.age --identity key.txt
+    Create a new instance using the encrypted backup0.tar.gz.age as the source, decrypting it with the identities of key.txt.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", i18n.G("Storage pool name")+"``")
+	cmd.Flags().StringVar(&c.flagIdentity, "identity", "", i18n.G("File containing the X25519 identities to decrypt an encrypted backup with (prompts for a passphrase if not set)")+"``")
 
 	return cmd
 }
@@ -95,18 +100,24 @@ func (c *cmdImport) Run(cmd *cobra.Command, args []string) error {
 		Quiet:  c.global.flagQuiet,
 	}
 
-	createArgs := lxd.InstanceBackupArgs{
-		BackupFile: &ioprogress.ProgressReader{
-			ReadCloser: file,
-			Tracker: &ioprogress.ProgressTracker{
-				Length: fstat.Size(),
-				Handler: func(percent int64, speed int64) {
-					progress.UpdateProgress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
-				},
+	// Decrypt encrypted backups locally, the passphrase can't be prompted for when reading the backup from stdin.
+	backupFile, err := backupDecryptReader(&ioprogress.ProgressReader{
+		ReadCloser: file,
+		Tracker: &ioprogress.ProgressTracker{
+			Length: fstat.Size(),
+			Handler: func(percent int64, speed int64) {
+				progress.UpdateProgress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
 			},
 		},
-		PoolName: c.flagStorage,
-		Name:     instanceName,
+	}, c.flagIdentity, srcFile != "-")
+	if err != nil {
+		return err
+	}
+
+	createArgs := lxd.InstanceBackupArgs{
+		BackupFile: backupFile,
+		PoolName:   c.flagStorage,
+		Name:       instanceName,
 	}
 
 	op, err := resource.server.CreateInstanceFromBackup(createArgs)
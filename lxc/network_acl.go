@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -40,6 +41,10 @@ func (c *cmdNetworkACL) Command() *cobra.Command {
 	networkACLShowLogCmd := cmdNetworkACLShowLog{global: c.global, networkACL: c}
 	cmd.AddCommand(networkACLShowLogCmd.Command())
 
+	// Info.
+	networkACLInfoCmd := cmdNetworkACLInfo{global: c.global, networkACL: c}
+	cmd.AddCommand(networkACLInfoCmd.Command())
+
 	// Get.
 	networkACLGetCmd := cmdNetworkACLGet{global: c.global, networkACL: c}
 	cmd.AddCommand(networkACLGetCmd.Command())
@@ -251,6 +256,88 @@ func (c *cmdNetworkACLShowLog) Run(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+// Info.
+type cmdNetworkACLInfo struct {
+	global     *cmdGlobal
+	networkACL *cmdNetworkACL
+
+	flagFormat string
+}
+
+func (c *cmdNetworkACLInfo) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("info", i18n.G("[<remote>:]<ACL>"))
+	cmd.Short = i18n.G("Show network ACL rule hit counters")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Show network ACL rule hit counters"))
+	cmd.RunE = c.Run
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	return cmd
+}
+
+func (c *cmdNetworkACLInfo) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing network ACL name"))
+	}
+
+	netACL, _, err := resource.server.GetNetworkACL(resource.name)
+	if err != nil {
+		return err
+	}
+
+	aclState, err := resource.server.GetNetworkACLState(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	addRows := func(direction string, rules []api.NetworkACLRule, counters []api.NetworkACLRuleCounters) {
+		for i, rule := range rules {
+			var ruleCounters api.NetworkACLRuleCounters
+			if i < len(counters) {
+				ruleCounters = counters[i]
+			}
+
+			data = append(data, []string{
+				direction,
+				strconv.Itoa(i),
+				rule.Action,
+				rule.State,
+				strconv.FormatUint(ruleCounters.Packets, 10),
+				strconv.FormatUint(ruleCounters.Bytes, 10),
+			})
+		}
+	}
+
+	addRows("ingress", netACL.Ingress, aclState.Ingress)
+	addRows("egress", netACL.Egress, aclState.Egress)
+
+	header := []string{
+		i18n.G("DIRECTION"),
+		i18n.G("RULE"),
+		i18n.G("ACTION"),
+		i18n.G("STATE"),
+		i18n.G("PACKETS"),
+		i18n.G("BYTES"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, aclState)
+}
+
 // Get.
 type cmdNetworkACLGet struct {
 	global     *cmdGlobal
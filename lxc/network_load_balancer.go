@@ -36,6 +36,10 @@ func (c *cmdNetworkLoadBalancer) Command() *cobra.Command {
 	networkLoadBalancerShowCmd := cmdNetworkLoadBalancerShow{global: c.global, networkLoadBalancer: c}
 	cmd.AddCommand(networkLoadBalancerShowCmd.Command())
 
+	// Info.
+	networkLoadBalancerInfoCmd := cmdNetworkLoadBalancerInfo{global: c.global, networkLoadBalancer: c}
+	cmd.AddCommand(networkLoadBalancerInfoCmd.Command())
+
 	// Create.
 	networkLoadBalancerCreateCmd := cmdNetworkLoadBalancerCreate{global: c.global, networkLoadBalancer: c}
 	cmd.AddCommand(networkLoadBalancerCreateCmd.Command())
@@ -220,6 +224,95 @@ func (c *cmdNetworkLoadBalancerShow) Run(cmd *cobra.Command, args []string) erro
 	return nil
 }
 
+// Info.
+type cmdNetworkLoadBalancerInfo struct {
+	global              *cmdGlobal
+	networkLoadBalancer *cmdNetworkLoadBalancer
+
+	flagFormat string
+}
+
+func (c *cmdNetworkLoadBalancerInfo) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("info", i18n.G("[<remote>:]<network> <listen_address>"))
+	cmd.Short = i18n.G("Show network load balancer backend health")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Show network load balancer backend health"))
+	cmd.RunE = c.Run
+
+	cmd.Flags().StringVar(&c.networkLoadBalancer.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	return cmd
+}
+
+func (c *cmdNetworkLoadBalancerInfo) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing network name"))
+	}
+
+	if args[1] == "" {
+		return fmt.Errorf(i18n.G("Missing listen address"))
+	}
+
+	client := resource.server
+
+	// If a target was specified, use the load balancer on the given member.
+	if c.networkLoadBalancer.flagTarget != "" {
+		client = client.UseTarget(c.networkLoadBalancer.flagTarget)
+	}
+
+	loadBalancerState, err := client.GetNetworkLoadBalancerState(resource.name, args[1])
+	if err != nil {
+		return err
+	}
+
+	const layout = "2006/01/02 15:04:05 UTC"
+
+	data := make([][]string, 0, len(loadBalancerState.BackendHealth))
+	for name, backend := range loadBalancerState.BackendHealth {
+		lastCheck := ""
+		if !backend.LastCheck.IsZero() {
+			lastCheck = backend.LastCheck.UTC().Format(layout)
+		}
+
+		data = append(data, []string{
+			name,
+			backend.Address,
+			backend.HealthCheck,
+			strings.ToUpper(backend.Status),
+			lastCheck,
+			backend.Error,
+		})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("BACKEND"),
+		i18n.G("ADDRESS"),
+		i18n.G("HEALTH CHECK"),
+		i18n.G("STATUS"),
+		i18n.G("LAST CHECK"),
+		i18n.G("ERROR"),
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, loadBalancerState)
+}
+
 // Create.
 type cmdNetworkLoadBalancerCreate struct {
 	global              *cmdGlobal
@@ -722,6 +815,10 @@ func (c *cmdNetworkLoadBalancerDelete) Run(cmd *cobra.Command, args []string) er
 type cmdNetworkLoadBalancerBackend struct {
 	global              *cmdGlobal
 	networkLoadBalancer *cmdNetworkLoadBalancer
+
+	flagHealthCheck     string
+	flagHealthCheckPort string
+	flagHealthCheckPath string
 }
 
 func (c *cmdNetworkLoadBalancerBackend) Command() *cobra.Command {
@@ -747,6 +844,9 @@ func (c *cmdNetworkLoadBalancerBackend) CommandAdd() *cobra.Command {
 	cmd.RunE = c.RunAdd
 
 	cmd.Flags().StringVar(&c.networkLoadBalancer.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
+	cmd.Flags().StringVar(&c.flagHealthCheck, "health-check", "", i18n.G("Health check to run against the backend (tcp or http)")+"``")
+	cmd.Flags().StringVar(&c.flagHealthCheckPort, "health-check-port", "", i18n.G("Port to run the health check against")+"``")
+	cmd.Flags().StringVar(&c.flagHealthCheckPath, "health-check-path", "", i18n.G("Path to request for http health checks")+"``")
 
 	return cmd
 }
@@ -788,14 +888,21 @@ func (c *cmdNetworkLoadBalancerBackend) RunAdd(cmd *cobra.Command, args []string
 	}
 
 	backend := api.NetworkLoadBalancerBackend{
-		Name:          args[2],
-		TargetAddress: args[3],
+		Name:            args[2],
+		TargetAddress:   args[3],
+		HealthCheck:     c.flagHealthCheck,
+		HealthCheckPort: c.flagHealthCheckPort,
+		HealthCheckPath: c.flagHealthCheckPath,
 	}
 
 	if len(args) >= 5 {
 		backend.TargetPort = args[4]
 	}
 
+	if (backend.HealthCheck != "" || backend.HealthCheckPort != "" || backend.HealthCheckPath != "") && !client.HasExtension("network_load_balancer_health_check") {
+		return fmt.Errorf(i18n.G("The server doesn't implement load balancer health checks"))
+	}
+
 	loadBalancer.Backends = append(loadBalancer.Backends, backend)
 
 	loadBalancer.Normalise()
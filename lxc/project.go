@@ -834,13 +834,16 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render the output
-	byteLimits := []string{"disk", "memory"}
+	byteLimits := []string{"disk", "disk.read", "disk.write", "memory"}
+	bitLimits := []string{"network.egress", "network.ingress"}
 	data := [][]string{}
 	for k, v := range projectState.Resources {
 		limit := i18n.G("UNLIMITED")
 		if v.Limit >= 0 {
 			if shared.ValueInSlice(k, byteLimits) {
 				limit = units.GetByteSizeStringIEC(v.Limit, 2)
+			} else if shared.ValueInSlice(k, bitLimits) {
+				limit = fmt.Sprintf("%dbit", v.Limit)
 			} else {
 				limit = fmt.Sprintf("%d", v.Limit)
 			}
@@ -849,6 +852,8 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 		usage := ""
 		if shared.ValueInSlice(k, byteLimits) {
 			usage = units.GetByteSizeStringIEC(v.Usage, 2)
+		} else if shared.ValueInSlice(k, bitLimits) {
+			usage = fmt.Sprintf("%dbit", v.Usage)
 		} else {
 			usage = fmt.Sprintf("%d", v.Usage)
 		}
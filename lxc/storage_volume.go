@@ -321,6 +321,7 @@ type cmdStorageVolumeCopy struct {
 	flagVolumeOnly    bool
 	flagTargetProject string
 	flagRefresh       bool
+	flagClone         bool
 }
 
 func (c *cmdStorageVolumeCopy) Command() *cobra.Command {
@@ -337,6 +338,7 @@ func (c *cmdStorageVolumeCopy) Command() *cobra.Command {
 	cmd.Flags().BoolVar(&c.flagVolumeOnly, "volume-only", false, i18n.G("Copy the volume without its snapshots"))
 	cmd.Flags().StringVar(&c.flagTargetProject, "target-project", "", i18n.G("Copy to a project different from the source")+"``")
 	cmd.Flags().BoolVar(&c.flagRefresh, "refresh", false, i18n.G("Refresh and update the existing storage volume copies"))
+	cmd.Flags().BoolVar(&c.flagClone, "clone", false, i18n.G("Create a copy-on-write clone of the volume in the same storage pool"))
 	cmd.RunE = c.Run
 
 	return cmd
@@ -472,6 +474,7 @@ func (c *cmdStorageVolumeCopy) Run(cmd *cobra.Command, args []string) error {
 		args.Mode = mode
 		args.VolumeOnly = c.flagVolumeOnly
 		args.Refresh = c.flagRefresh
+		args.Clone = c.flagClone
 
 		if c.flagTargetProject != "" {
 			dstServer = dstServer.UseProject(c.flagTargetProject)
@@ -1290,6 +1293,10 @@ func (c *cmdStorageVolumeInfo) Run(cmd *cobra.Command, args []string) error {
 		fmt.Printf(i18n.G("Created: %s")+"\n", vol.CreatedAt.Local().Format(layout))
 	}
 
+	if volState != nil && volState.Replication != nil {
+		fmt.Printf(i18n.G("Last replication: %s (lag: %s)")+"\n", volState.Replication.LastSync.Local().Format(layout), time.Duration(volState.Replication.Lag)*time.Second)
+	}
+
 	// List snapshots
 	firstSnapshot := true
 	if len(volSnapshots) > 0 {
@@ -2263,6 +2270,8 @@ type cmdStorageVolumeExport struct {
 	flagVolumeOnly           bool
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
+	flagEncryptRecipients    []string
+	flagEncryptPassphrase    bool
 }
 
 func (c *cmdStorageVolumeExport) Command() *cobra.Command {
@@ -2276,6 +2285,8 @@ func (c *cmdStorageVolumeExport) Command() *cobra.Command {
 	cmd.Flags().BoolVar(&c.flagOptimizedStorage, "optimized-storage", false,
 		i18n.G("Use storage driver optimized format (can only be restored on a similar pool)"))
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Define a compression algorithm: for backup or none")+"``")
+	cmd.Flags().StringArrayVar(&c.flagEncryptRecipients, "encrypt-to", nil, i18n.G("Encrypt the backup to an X25519 public key (age recipient)")+"``")
+	cmd.Flags().BoolVar(&c.flagEncryptPassphrase, "encrypt-passphrase", false, i18n.G("Encrypt the backup with a passphrase (prompted for)"))
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
 	cmd.RunE = c.Run
 
@@ -2320,6 +2331,11 @@ func (c *cmdStorageVolumeExport) Run(cmd *cobra.Command, args []string) error {
 		VolumeOnly:           volumeOnly,
 		OptimizedStorage:     c.flagOptimizedStorage,
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		EncryptionRecipients: c.flagEncryptRecipients,
+	}
+
+	if c.flagEncryptPassphrase {
+		req.EncryptionPassphrase = cli.AskPassword(i18n.G("Backup passphrase: "))
 	}
 
 	op, err := d.CreateStoragePoolVolumeBackup(name, volName, req)
@@ -2378,6 +2394,9 @@ func (c *cmdStorageVolumeExport) Run(cmd *cobra.Command, args []string) error {
 		targetName = args[2]
 	} else {
 		targetName = "backup.tar.gz"
+		if len(req.EncryptionRecipients) > 0 || req.EncryptionPassphrase != "" {
+			targetName = "backup.tar.gz.age"
+		}
 	}
 
 	target, err := os.Create(shared.HostPathFollow(targetName))
@@ -2416,7 +2435,8 @@ type cmdStorageVolumeImport struct {
 	storage       *cmdStorage
 	storageVolume *cmdStorageVolume
 
-	flagType string
+	flagType     string
+	flagIdentity string
 }
 
 func (c *cmdStorageVolumeImport) Command() *cobra.Command {
@@ -2431,6 +2451,7 @@ func (c *cmdStorageVolumeImport) Command() *cobra.Command {
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
 	cmd.RunE = c.Run
 	cmd.Flags().StringVar(&c.flagType, "type", "", i18n.G("Import type, backup or iso (default \"backup\")")+"``")
+	cmd.Flags().StringVar(&c.flagIdentity, "identity", "", i18n.G("File containing the X25519 identities to decrypt an encrypted backup with (prompts for a passphrase if not set)")+"``")
 
 	return cmd
 }
@@ -2500,17 +2521,27 @@ func (c *cmdStorageVolumeImport) Run(cmd *cobra.Command, args []string) error {
 		Quiet:  c.global.flagQuiet,
 	}
 
-	createArgs := lxd.StoragePoolVolumeBackupArgs{
-		BackupFile: &ioprogress.ProgressReader{
-			ReadCloser: file,
-			Tracker: &ioprogress.ProgressTracker{
-				Length: fstat.Size(),
-				Handler: func(percent int64, speed int64) {
-					progress.UpdateProgress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
-				},
+	var backupFile io.Reader = &ioprogress.ProgressReader{
+		ReadCloser: file,
+		Tracker: &ioprogress.ProgressTracker{
+			Length: fstat.Size(),
+			Handler: func(percent int64, speed int64) {
+				progress.UpdateProgress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
 			},
 		},
-		Name: volName,
+	}
+
+	// Decrypt encrypted backups locally.
+	if c.flagType == "backup" {
+		backupFile, err = backupDecryptReader(backupFile, c.flagIdentity, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	createArgs := lxd.StoragePoolVolumeBackupArgs{
+		BackupFile: backupFile,
+		Name:       volName,
 	}
 
 	var op lxd.Operation
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -8,10 +9,14 @@ import (
 	"sort"
 	"strings"
 
+	"filippo.io/age"
+
 	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/lxc/config"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/canonical/lxd/shared/encryption"
 	"github.com/canonical/lxd/shared/i18n"
 	"github.com/canonical/lxd/shared/termios"
 )
@@ -388,3 +393,52 @@ func getImgInfo(d lxd.InstanceServer, conf *config.Config, imgRemote string, ins
 
 	return imgRemoteServer, imgInfo, nil
 }
+
+// backupDecryptReader returns a reader for the backup file, decrypting it if the file is encrypted.
+// Encrypted files are decrypted with the identities of the identity file, or else a passphrase the user is
+// prompted for when canPrompt is true.
+func backupDecryptReader(r io.Reader, identityFile string, canPrompt bool) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	header, _ := br.Peek(len(encryption.Magic) + 1)
+	if !encryption.IsEncrypted(header) {
+		if identityFile != "" {
+			return nil, fmt.Errorf(i18n.G("The backup file isn't encrypted"))
+		}
+
+		return br, nil
+	}
+
+	var identities []age.Identity
+	if identityFile != "" {
+		f, err := os.Open(shared.HostPathFollow(identityFile))
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		identities, err = age.ParseIdentities(f)
+		if err != nil {
+			return nil, fmt.Errorf(i18n.G("Failed parsing identity file %q: %w"), identityFile, err)
+		}
+	} else {
+		if !canPrompt {
+			return nil, fmt.Errorf(i18n.G("The backup file is encrypted, use --identity to provide the key to decrypt it"))
+		}
+
+		identity, err := age.NewScryptIdentity(cli.AskPasswordOnce(i18n.G("Backup passphrase: ")))
+		if err != nil {
+			return nil, err
+		}
+
+		identities = []age.Identity{identity}
+	}
+
+	decrypter, err := age.Decrypt(br, identities...)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Failed decrypting backup file: %w"), err)
+	}
+
+	return decrypter, nil
+}
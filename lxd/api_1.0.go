@@ -13,6 +13,7 @@ import (
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/auth/candid"
 	"github.com/canonical/lxd/lxd/auth/oidc"
+	"github.com/canonical/lxd/lxd/cgroup"
 	"github.com/canonical/lxd/lxd/cluster"
 	clusterConfig "github.com/canonical/lxd/lxd/cluster/config"
 	"github.com/canonical/lxd/lxd/config"
@@ -85,11 +86,13 @@ var api10 = []APIEndpoint{
 	networkACLCmd,
 	networkACLsCmd,
 	networkACLLogCmd,
+	networkACLStateCmd,
 	networkAllocationsCmd,
 	networkForwardCmd,
 	networkForwardsCmd,
 	networkLoadBalancerCmd,
 	networkLoadBalancersCmd,
+	networkLoadBalancerStateCmd,
 	networkPeerCmd,
 	networkPeersCmd,
 	networkZoneCmd,
@@ -906,6 +909,8 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 			dnsChanged = true
 		case "core.syslog_socket":
 			syslogSocketChanged = true
+		case "instances.cpu_balancing":
+			cgroup.TaskSchedulerTrigger("daemon", "cpu_balancing", "changed")
 		}
 	}
 
@@ -1051,6 +1056,15 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	// Compile and load the instance validation scriptlet.
+	value, ok = clusterChanged["instances.validation.scriptlet"]
+	if ok {
+		err := scriptletLoad.InstanceValidationSet(value)
+		if err != nil {
+			return fmt.Errorf("Failed saving instance validation scriptlet: %w", err)
+		}
+	}
+
 	if oidcChanged {
 		oidcIssuer, oidcClientID, oidcAudience := clusterConfig.OIDCServer()
 
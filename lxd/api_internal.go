@@ -903,6 +903,127 @@ func internalImportFromBackup(s *state.State, projectName string, instName strin
 	return nil
 }
 
+// internalImportFromIncrementalBackup creates the database records of the snapshots restored onto an existing
+// instance from an incremental backup, and applies the instance config contained in the backup.
+func internalImportFromIncrementalBackup(s *state.State, inst instance.Instance, bInfo *backup.Info) error {
+	if bInfo.Config == nil || bInfo.Config.Container == nil {
+		return fmt.Errorf("Backup doesn't contain the instance config")
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	projectName := inst.Project().Name
+
+	instancePoolName, err := inst.StoragePool()
+	if err != nil {
+		return err
+	}
+
+	instanceVolType, err := storagePools.InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, snap := range bInfo.Config.Snapshots {
+		snapInstName := fmt.Sprintf("%s%s%s", inst.Name(), shared.SnapshotDelimiter, snap.Name)
+
+		arch, err := osarch.ArchitectureId(snap.Architecture)
+		if err != nil {
+			return err
+		}
+
+		profiles, err := s.DB.Cluster.GetProfiles(projectName, snap.Profiles)
+		if err != nil {
+			return fmt.Errorf("Failed loading profiles for instance snapshot %q: %w", snapInstName, err)
+		}
+
+		// Add root device if needed.
+		if snap.Devices == nil {
+			snap.Devices = make(map[string]map[string]string, 0)
+		}
+
+		if snap.ExpandedDevices == nil {
+			snap.ExpandedDevices = make(map[string]map[string]string, 0)
+		}
+
+		internalImportRootDevicePopulate(instancePoolName, snap.Devices, snap.ExpandedDevices, profiles)
+
+		_, snapInstOp, cleanup, err := instance.CreateInternal(s, db.InstanceArgs{
+			Project:      projectName,
+			Architecture: arch,
+			BaseImage:    snap.Config["volatile.base_image"],
+			Config:       snap.Config,
+			CreationDate: snap.CreatedAt,
+			Type:         inst.Type(),
+			Snapshot:     true,
+			Devices:      deviceConfig.NewDevices(snap.Devices),
+			Ephemeral:    snap.Ephemeral,
+			LastUsedDate: snap.LastUsedAt,
+			Name:         snapInstName,
+			Profiles:     profiles,
+			Stateful:     snap.Stateful,
+		}, true)
+		if err != nil {
+			return fmt.Errorf("Failed creating instance snapshot record %q: %w", snap.Name, err)
+		}
+
+		revert.Add(cleanup)
+		defer snapInstOp.Done(err)
+
+		// Create missing mountpoints and symlinks.
+		volStorageName := project.Instance(projectName, snapInstName)
+		snapshotMountPoint := storageDrivers.GetVolumeMountPath(instancePoolName, instanceVolType, volStorageName)
+		snapshotPath := storagePools.InstancePath(inst.Type(), projectName, inst.Name(), true)
+		snapshotTargetPath := storageDrivers.GetVolumeSnapshotDir(instancePoolName, instanceVolType, volStorageName)
+
+		err = storagePools.CreateSnapshotMountpoint(snapshotMountPoint, snapshotTargetPath, snapshotPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Apply the instance config from the backup.
+	backupInst := bInfo.Config.Container
+
+	arch, err := osarch.ArchitectureId(backupInst.Architecture)
+	if err != nil {
+		return err
+	}
+
+	profiles, err := s.DB.Cluster.GetProfiles(projectName, backupInst.Profiles)
+	if err != nil {
+		return fmt.Errorf("Failed loading profiles for instance %q: %w", inst.Name(), err)
+	}
+
+	if backupInst.Devices == nil {
+		backupInst.Devices = make(map[string]map[string]string, 0)
+	}
+
+	if backupInst.ExpandedDevices == nil {
+		backupInst.ExpandedDevices = make(map[string]map[string]string, 0)
+	}
+
+	internalImportRootDevicePopulate(instancePoolName, backupInst.Devices, backupInst.ExpandedDevices, profiles)
+
+	err = inst.Update(db.InstanceArgs{
+		Architecture: arch,
+		Config:       backupInst.Config,
+		Description:  backupInst.Description,
+		Devices:      deviceConfig.NewDevices(backupInst.Devices),
+		Ephemeral:    backupInst.Ephemeral,
+		Profiles:     profiles,
+		Project:      projectName,
+		Type:         inst.Type(),
+	}, false)
+	if err != nil {
+		return fmt.Errorf("Failed updating instance config from backup: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
 // internalImportRootDevicePopulate considers the local and expanded devices from backup.yaml as well as the
 // expanded devices in the current profiles and if needed will populate localDevices with a new root disk config
 // to attempt to maintain the same effective config as specified in backup.yaml. Where possible no new root disk
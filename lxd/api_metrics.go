@@ -7,11 +7,13 @@ import (
 	"net"
 	"net/http"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/canonical/lxd/lxd/auth"
+	clusterRequest "github.com/canonical/lxd/lxd/cluster/request"
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/instance"
@@ -19,6 +21,8 @@ import (
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/locking"
 	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/network/acl"
+	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
@@ -286,6 +290,23 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	wg.Wait()
 	close(instMetricsCh)
 
+	// Add the network ACL rule hit counters.
+	for _, filter := range projectsToFetch {
+		projectName := *filter.Project
+
+		aclMetrics, err := networkACLMetrics(s, projectName)
+		if err != nil {
+			logger.Warn("Failed getting network ACL metrics", logger.Ctx{"project": projectName, "err": err})
+			continue
+		}
+
+		if newMetrics[projectName] == nil {
+			newMetrics[projectName] = metrics.NewMetricSet(nil)
+		}
+
+		newMetrics[projectName].Merge(aclMetrics)
+	}
+
 	// Put the new data in the global cache and in response.
 	metricsCacheLock.Lock()
 
@@ -402,5 +423,56 @@ func internalMetrics(ctx context.Context, daemonStartTime time.Time, tx *db.Clus
 	out.AddSamples(metrics.GoStackSysBytes, metrics.Sample{Value: float64(ms.StackSys)})
 	out.AddSamples(metrics.GoSysBytes, metrics.Sample{Value: float64(ms.Sys)})
 
+	// Daemon latency histograms
+	out.AddSamples(metrics.APIRequestDurationSeconds, metrics.APIRequestDuration.Samples()...)
+	out.AddSamples(metrics.OperationDurationSeconds, metrics.OperationDuration.Samples()...)
+	out.AddSamples(metrics.DatabaseTransactionDurationSeconds, metrics.DatabaseTransactionDuration.Samples()...)
+
 	return out
 }
+
+// networkACLMetrics returns the hit counters of the rules of the network ACLs in the project on this member.
+func networkACLMetrics(s *state.State, projectName string) (*metrics.MetricSet, error) {
+	out := metrics.NewMetricSet(nil)
+
+	// Projects without their own networks share the ACLs of the default project, which are reported there.
+	networkProjectName, _, err := project.NetworkProject(s.DB.Cluster, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	if networkProjectName != projectName {
+		return out, nil
+	}
+
+	aclNames, err := s.DB.Cluster.GetNetworkACLs(projectName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading network ACLs: %w", err)
+	}
+
+	for _, aclName := range aclNames {
+		netACL, err := acl.LoadByName(s, projectName, aclName)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only get the counters of this member.
+		aclState, err := netACL.GetState(clusterRequest.ClientTypeNotifier)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting network ACL %q state: %w", aclName, err)
+		}
+
+		addSamples := func(direction string, counters []api.NetworkACLRuleCounters) {
+			for i, ruleCounters := range counters {
+				labels := map[string]string{"project": projectName, "acl": aclName, "direction": direction, "rule": strconv.Itoa(i)}
+				out.AddSamples(metrics.NetworkACLRulePacketsTotal, metrics.Sample{Labels: labels, Value: float64(ruleCounters.Packets)})
+				out.AddSamples(metrics.NetworkACLRuleBytesTotal, metrics.Sample{Labels: labels, Value: float64(ruleCounters.Bytes)})
+			}
+		}
+
+		addSamples("ingress", aclState.Ingress)
+		addSamples("egress", aclState.Egress)
+	}
+
+	return out, nil
+}
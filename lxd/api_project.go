@@ -1185,17 +1185,73 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum number of CPUs to use in the project
 		"limits.cpu": validate.Optional(validate.IsUint32),
 		// lxdmeta:generate(entities=project; group=limits; key=limits.disk)
-		// This value is the maximum value of the aggregate disk space used by all instance volumes, custom volumes, and images of the project.
+		// This value is the maximum value of the aggregate disk space used by all instance volumes, custom volumes, storage buckets, and images of the project.
 		// ---
 		//  type: string
 		//  shortdesc: Maximum disk space used by the project
 		"limits.disk": validate.Optional(validate.IsSize),
+		// lxdmeta:generate(entities=project; group=limits; key=limits.disk.read)
+		// This value is the maximum value for the sum of the individual `limits.read` (or `limits.max`) byte rate limits set on the disk devices of the instances of the project.
+		// When set, all disk devices of the project's instances must have a byte rate read limit set.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum aggregate disk read rate (in bytes/s) of the project
+		"limits.disk.read": validate.Optional(validate.IsSize),
+		// lxdmeta:generate(entities=project; group=limits; key=limits.disk.write)
+		// This value is the maximum value for the sum of the individual `limits.write` (or `limits.max`) byte rate limits set on the disk devices of the instances of the project.
+		// When set, all disk devices of the project's instances must have a byte rate write limit set.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum aggregate disk write rate (in bytes/s) of the project
+		"limits.disk.write": validate.Optional(validate.IsSize),
+		// lxdmeta:generate(entities=project; group=limits; key=limits.disk.iops.read)
+		// This value is the maximum value for the sum of the individual `limits.read` (or `limits.max`) IOPS limits set on the disk devices of the instances of the project.
+		// When set, all disk devices of the project's instances must have an IOPS read limit set.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum aggregate disk read IOPS of the project
+		"limits.disk.iops.read": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=project; group=limits; key=limits.disk.iops.write)
+		// This value is the maximum value for the sum of the individual `limits.write` (or `limits.max`) IOPS limits set on the disk devices of the instances of the project.
+		// When set, all disk devices of the project's instances must have an IOPS write limit set.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum aggregate disk write IOPS of the project
+		"limits.disk.iops.write": validate.Optional(validate.IsUint32),
 		// lxdmeta:generate(entities=project; group=limits; key=limits.networks)
 		//
 		// ---
 		//  type: integer
 		//  shortdesc: Maximum number of networks that the project can have
 		"limits.networks": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=project; group=limits; key=limits.storage-buckets)
+		//
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of storage buckets that the project can have
+		"limits.storage-buckets": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=project; group=limits; key=limits.snapshots)
+		// This value is the maximum number of snapshots of custom storage volumes in the project.
+		// Scheduled snapshots are skipped once the limit is reached.
+		// Copies, migrations and imports of custom volumes that include snapshots are refused if they would exceed the limit.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of custom volume snapshots that the project can have
+		"limits.snapshots": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=project; group=limits; key=limits.network.ingress)
+		// This value is the maximum value for the sum of the individual `limits.ingress` (or `limits.max`) limits set on the NIC devices of the instances of the project.
+		// When set, all NIC devices of the project's instances that support bandwidth limits (`bridged`, `p2p` and `routed`) must have an ingress limit set.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum aggregate ingress bandwidth (in bit/s) of the project
+		"limits.network.ingress": validate.Optional(validate.IsBitSize),
+		// lxdmeta:generate(entities=project; group=limits; key=limits.network.egress)
+		// This value is the maximum value for the sum of the individual `limits.egress` (or `limits.max`) limits set on the NIC devices of the instances of the project.
+		// When set, all NIC devices of the project's instances that support bandwidth limits (`bridged`, `p2p` and `routed`) must have an egress limit set.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum aggregate egress bandwidth (in bit/s) of the project
+		"limits.network.egress": validate.Optional(validate.IsBitSize),
 		// lxdmeta:generate(entities=project; group=restricted; key=restricted)
 		// This option must be enabled to allow the `restricted.*` keys to take effect.
 		// To temporarily remove the restrictions, you can disable this option instead of clearing the related keys.
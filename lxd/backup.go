@@ -3,11 +3,13 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"time"
 
+	"filippo.io/age"
 	"gopkg.in/yaml.v2"
 
 	"github.com/canonical/lxd/lxd/backup"
@@ -52,6 +54,28 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		args.OptimizedStorage = false
 	}
 
+	recipients, err := backupEncryptionRecipients(args.EncryptionRecipients, args.EncryptionPassphrase)
+	if err != nil {
+		return err
+	}
+
+	// Find the snapshot incremental backups are based on.
+	var baseSnapshot string
+	if args.IncrementalFrom != "" {
+		var baseOptimized bool
+
+		baseSnapshot, baseOptimized, err = backupIncrementalBase(s, sourceInst, args.IncrementalFrom)
+		if err != nil {
+			return err
+		}
+
+		// Optimized incremental backups can only be generated by some drivers and need an optimized base
+		// backup to be restored onto, otherwise fallback to generating a non-optimized incremental backup.
+		if args.OptimizedStorage && (!pool.Driver().Info().OptimizedIncrementalBackups || !baseOptimized) {
+			args.OptimizedStorage = false
+		}
+	}
+
 	// Create the database entry.
 	err = s.DB.Cluster.CreateInstanceBackup(args)
 	if err != nil {
@@ -98,28 +122,48 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		}
 	}
 
-	// Create the target path if needed.
-	backupsPath := shared.VarPath("backups", "instances", project.Instance(sourceInst.Project().Name, sourceInst.Name()))
-	if !shared.PathExists(backupsPath) {
-		err := os.MkdirAll(backupsPath, 0700)
+	var tarFileWriter io.WriteCloser
+	if args.Target != nil {
+		// Stream the tarball to the S3 target rather than storing it on the server.
+		l.Debug("Uploading backup tarball", logger.Ctx{"bucket": args.Target.BucketName, "object": args.Target.ObjectName})
+		targetWriter, err := backupTargetUpload(s, sourceInst.Project().Name, args.Target)
 		if err != nil {
 			return err
 		}
 
-		revert.Add(func() { _ = os.Remove(backupsPath) })
-	}
+		revert.Add(targetWriter.Abort)
+		tarFileWriter = targetWriter
+	} else {
+		// Create the target path if needed.
+		backupsPath := shared.VarPath("backups", "instances", project.Instance(sourceInst.Project().Name, sourceInst.Name()))
+		if !shared.PathExists(backupsPath) {
+			err := os.MkdirAll(backupsPath, 0700)
+			if err != nil {
+				return err
+			}
 
-	target := shared.VarPath("backups", "instances", project.Instance(sourceInst.Project().Name, b.Name()))
+			revert.Add(func() { _ = os.Remove(backupsPath) })
+		}
 
-	// Setup the tarball writer.
-	l.Debug("Opening backup tarball for writing", logger.Ctx{"path": target})
-	tarFileWriter, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		return fmt.Errorf("Error opening backup tarball for writing %q: %w", target, err)
+		target := shared.VarPath("backups", "instances", project.Instance(sourceInst.Project().Name, b.Name()))
+
+		// Setup the tarball writer.
+		l.Debug("Opening backup tarball for writing", logger.Ctx{"path": target})
+		tarFile, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("Error opening backup tarball for writing %q: %w", target, err)
+		}
+
+		defer func() { _ = tarFile.Close() }()
+		revert.Add(func() { _ = os.Remove(target) })
+		tarFileWriter = tarFile
 	}
 
-	defer func() { _ = tarFileWriter.Close() }()
-	revert.Add(func() { _ = os.Remove(target) })
+	// Encrypt the tarball after compression if requested.
+	backupFileWriter, encryptWriter, err := backupEncryptWriter(tarFileWriter, recipients)
+	if err != nil {
+		return err
+	}
 
 	// Get IDMap to unshift container as the tarball is created.
 	var idmap *idmap.IdmapSet
@@ -159,7 +203,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		l.Debug("Started backup tarball writer")
 		defer l.Debug("Finished backup tarball writer")
 		if compress != "none" {
-			backupProgressWriter.WriteCloser = tarFileWriter
+			backupProgressWriter.WriteCloser = backupFileWriter
 			compressErr = compressFile(compress, tarPipeReader, backupProgressWriter)
 
 			// If a compression error occurred, close the tarPipeWriter to end the export.
@@ -167,7 +211,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 				_ = tarPipeWriter.Close()
 			}
 		} else {
-			backupProgressWriter.WriteCloser = tarFileWriter
+			backupProgressWriter.WriteCloser = backupFileWriter
 			_, err = io.Copy(backupProgressWriter, tarPipeReader)
 		}
 
@@ -176,7 +220,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 
 	// Write index file.
 	l.Debug("Adding backup index file")
-	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), tarWriter)
+	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), b.IncrementalFrom(), baseSnapshot, tarWriter)
 
 	// Check compression errors.
 	if compressErr != nil {
@@ -188,7 +232,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error writing backup index file: %w", err)
 	}
 
-	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), nil)
+	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), baseSnapshot, nil)
 	if err != nil {
 		return fmt.Errorf("Backup create: %w", err)
 	}
@@ -210,6 +254,13 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error writing tarball: %w", err)
 	}
 
+	if encryptWriter != nil {
+		err = encryptWriter.Close()
+		if err != nil {
+			return fmt.Errorf("Error closing tarball encryption writer: %w", err)
+		}
+	}
+
 	err = tarFileWriter.Close()
 	if err != nil {
 		return fmt.Errorf("Error closing tar file: %w", err)
@@ -221,8 +272,99 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 	return nil
 }
 
+// backupIncrementalBase returns the snapshot that incremental backups generated from the named backup of the
+// instance are based on (the latest snapshot the backup contains) and whether that backup is optimized.
+func backupIncrementalBase(s *state.State, inst instance.Instance, baseName string) (string, bool, error) {
+	baseBackup, err := instance.BackupLoadByName(s, inst.Project().Name, inst.Name()+shared.SnapshotDelimiter+baseName)
+	if err != nil {
+		return "", false, fmt.Errorf("Failed loading base backup %q: %w", baseName, err)
+	}
+
+	if baseBackup.InstanceOnly() {
+		return "", false, fmt.Errorf("Base backup %q doesn't include snapshots", baseName)
+	}
+
+	if baseBackup.Target() != nil {
+		return "", false, fmt.Errorf("Base backup %q was uploaded to a backup target and isn't stored on the server", baseName)
+	}
+
+	baseBackupPath := shared.VarPath("backups", "instances", project.Instance(inst.Project().Name, baseBackup.Name()))
+	baseBackupFile, err := os.Open(baseBackupPath)
+	if err != nil {
+		return "", false, fmt.Errorf("Failed opening base backup %q: %w", baseName, err)
+	}
+
+	defer func() { _ = baseBackupFile.Close() }()
+
+	baseInfo, err := backup.GetInfo(baseBackupFile, s.OS, baseBackupPath)
+	if err != nil {
+		if errors.Is(err, backup.ErrEncrypted) {
+			return "", false, fmt.Errorf("Base backup %q is encrypted and can't be used for incremental backups", baseName)
+		}
+
+		return "", false, fmt.Errorf("Failed reading base backup %q: %w", baseName, err)
+	}
+
+	// An incremental backup without new snapshots is based on the same snapshot as its own base.
+	baseSnapshot := baseInfo.BaseSnapshot
+	if len(baseInfo.Snapshots) > 0 {
+		baseSnapshot = baseInfo.Snapshots[len(baseInfo.Snapshots)-1]
+	}
+
+	if baseSnapshot == "" {
+		return "", false, fmt.Errorf("Base backup %q doesn't contain any snapshot", baseName)
+	}
+
+	return baseSnapshot, baseInfo.OptimizedStorage != nil && *baseInfo.OptimizedStorage, nil
+}
+
+// backupEncryptionRecipients parses the X25519 public keys or the passphrase a backup tarball is encrypted to.
+// Returns no recipients when the backup isn't to be encrypted.
+func backupEncryptionRecipients(publicKeys []string, passphrase string) ([]age.Recipient, error) {
+	if passphrase != "" {
+		if len(publicKeys) > 0 {
+			return nil, fmt.Errorf("Encryption recipients and passphrase can't be combined")
+		}
+
+		recipient, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		return []age.Recipient{recipient}, nil
+	}
+
+	recipients := make([]age.Recipient, 0, len(publicKeys))
+	for _, publicKey := range publicKeys {
+		recipient, err := age.ParseX25519Recipient(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid encryption recipient: %w", err)
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
+
+// backupEncryptWriter returns the writer the (compressed) tarball must be written to, encrypting it to the
+// recipients if any. When encrypting, the encryption writer is also returned and must be closed before w.
+func backupEncryptWriter(w io.WriteCloser, recipients []age.Recipient) (io.WriteCloser, io.WriteCloser, error) {
+	if len(recipients) == 0 {
+		return w, nil, nil
+	}
+
+	encryptWriter, err := age.Encrypt(w, recipients...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed setting up tarball encryption: %w", err)
+	}
+
+	return encryptWriter, encryptWriter, nil
+}
+
 // backupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
-func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, tarWriter *instancewriter.InstanceTarWriter) error {
+// For incremental backups only the snapshots taken after the base snapshot are included.
+func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, incrementalFrom string, baseSnapshot string, tarWriter *instancewriter.InstanceTarWriter) error {
 	// Indicate whether the driver will include a driver-specific optimized header.
 	poolDriverOptimizedHeader := false
 	if optimized {
@@ -257,6 +399,36 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		OptimizedStorage: &optimized,
 		OptimizedHeader:  &poolDriverOptimizedHeader,
 		Config:           config,
+		IncrementalFrom:  incrementalFrom,
+		BaseSnapshot:     baseSnapshot,
+	}
+
+	if baseSnapshot != "" {
+		baseIndex := -1
+		for i, snap := range config.Snapshots {
+			if snap.Name == baseSnapshot {
+				baseIndex = i
+				break
+			}
+		}
+
+		if baseIndex < 0 {
+			return fmt.Errorf("Base snapshot %q not found", baseSnapshot)
+		}
+
+		config.Snapshots = config.Snapshots[baseIndex+1:]
+
+		volSnapshots := make([]*api.StorageVolumeSnapshot, 0, len(config.Snapshots))
+		for _, volSnap := range config.VolumeSnapshots {
+			for _, snap := range config.Snapshots {
+				if volSnap.Name == snap.Name {
+					volSnapshots = append(volSnapshots, volSnap)
+					break
+				}
+			}
+		}
+
+		config.VolumeSnapshots = volSnapshots
 	}
 
 	if snapshots {
@@ -360,7 +532,19 @@ func pruneExpiredInstanceBackups(ctx context.Context, s *state.State) error {
 			return fmt.Errorf("Error loading instance for deleting backup %q: %w", b.Name, err)
 		}
 
-		instBackup := backup.NewInstanceBackup(s, inst, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.InstanceOnly, b.OptimizedStorage)
+		// Backups uploaded to a target are deleted by the member hosting the instance.
+		if b.Target != nil {
+			if inst.Location() != s.ServerName {
+				continue
+			}
+
+			err = backupTargetDelete(s, inst.Project().Name, b.Target)
+			if err != nil {
+				return fmt.Errorf("Error deleting instance backup %q from its target: %w", b.Name, err)
+			}
+		}
+
+		instBackup := backup.NewInstanceBackup(s, inst, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.InstanceOnly, b.OptimizedStorage, b.IncrementalFrom, b.Target)
 		err = instBackup.Delete()
 		if err != nil {
 			return fmt.Errorf("Error deleting instance backup %q: %w", b.Name, err)
@@ -389,6 +573,11 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		args.OptimizedStorage = false
 	}
 
+	recipients, err := backupEncryptionRecipients(args.EncryptionRecipients, args.EncryptionPassphrase)
+	if err != nil {
+		return err
+	}
+
 	// Create the database entry.
 	err = s.DB.Cluster.CreateStoragePoolVolumeBackup(args)
 	if err != nil {
@@ -417,28 +606,48 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		compress = s.GlobalConfig.BackupsCompressionAlgorithm()
 	}
 
-	// Create the target path if needed.
-	backupsPath := shared.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, volumeName))
-	if !shared.PathExists(backupsPath) {
-		err := os.MkdirAll(backupsPath, 0700)
+	var tarFileWriter io.WriteCloser
+	if args.Target != nil {
+		// Stream the tarball to the S3 target rather than storing it on the server.
+		l.Debug("Uploading backup tarball", logger.Ctx{"bucket": args.Target.BucketName, "object": args.Target.ObjectName})
+		targetWriter, err := backupTargetUpload(s, projectName, args.Target)
 		if err != nil {
 			return err
 		}
 
-		revert.Add(func() { _ = os.Remove(backupsPath) })
-	}
+		revert.Add(targetWriter.Abort)
+		tarFileWriter = targetWriter
+	} else {
+		// Create the target path if needed.
+		backupsPath := shared.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, volumeName))
+		if !shared.PathExists(backupsPath) {
+			err := os.MkdirAll(backupsPath, 0700)
+			if err != nil {
+				return err
+			}
 
-	target := shared.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, backupRow.Name))
+			revert.Add(func() { _ = os.Remove(backupsPath) })
+		}
 
-	// Setup the tarball writer.
-	l.Debug("Opening backup tarball for writing", logger.Ctx{"path": target})
-	tarFileWriter, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		return fmt.Errorf("Error opening backup tarball for writing %q: %w", target, err)
+		target := shared.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, backupRow.Name))
+
+		// Setup the tarball writer.
+		l.Debug("Opening backup tarball for writing", logger.Ctx{"path": target})
+		tarFile, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("Error opening backup tarball for writing %q: %w", target, err)
+		}
+
+		defer func() { _ = tarFile.Close() }()
+		revert.Add(func() { _ = os.Remove(target) })
+		tarFileWriter = tarFile
 	}
 
-	defer func() { _ = tarFileWriter.Close() }()
-	revert.Add(func() { _ = os.Remove(target) })
+	// Encrypt the tarball after compression if requested.
+	backupFileWriter, encryptWriter, err := backupEncryptWriter(tarFileWriter, recipients)
+	if err != nil {
+		return err
+	}
 
 	// Create the tarball.
 	tarPipeReader, tarPipeWriter := io.Pipe()
@@ -453,14 +662,14 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		l.Debug("Started backup tarball writer")
 		defer l.Debug("Finished backup tarball writer")
 		if compress != "none" {
-			compressErr = compressFile(compress, tarPipeReader, tarFileWriter)
+			compressErr = compressFile(compress, tarPipeReader, backupFileWriter)
 
 			// If a compression error occurred, close the tarPipeWriter to end the export.
 			if compressErr != nil {
 				_ = tarPipeWriter.Close()
 			}
 		} else {
-			_, err = io.Copy(tarFileWriter, tarPipeReader)
+			_, err = io.Copy(backupFileWriter, tarPipeReader)
 		}
 
 		resCh <- err
@@ -502,6 +711,13 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		return fmt.Errorf("Error writing tarball: %w", err)
 	}
 
+	if encryptWriter != nil {
+		err = encryptWriter.Close()
+		if err != nil {
+			return fmt.Errorf("Error closing tarball encryption writer: %w", err)
+		}
+	}
+
 	err = tarFileWriter.Close()
 	if err != nil {
 		return fmt.Errorf("Error closing tar file: %w", err)
@@ -589,7 +805,7 @@ func pruneExpiredStorageVolumeBackups(ctx context.Context, s *state.State) error
 				continue
 			}
 
-			volBackup := backup.NewVolumeBackup(s, vol.ProjectName, vol.PoolName, vol.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+			volBackup := backup.NewVolumeBackup(s, vol.ProjectName, vol.PoolName, vol.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage, b.Target)
 
 			volumeBackups = append(volumeBackups, volBackup)
 		}
@@ -603,6 +819,13 @@ func pruneExpiredStorageVolumeBackups(ctx context.Context, s *state.State) error
 	// The deletion is done outside of the transaction to avoid any unnecessary IO while inside of
 	// the transaction.
 	for _, b := range volumeBackups {
+		if b.Target() != nil {
+			err := backupTargetDelete(s, b.ProjectName(), b.Target())
+			if err != nil {
+				return fmt.Errorf("Error deleting storage volume backup %q from its target: %w", b.Name(), err)
+			}
+		}
+
 		err := b.Delete()
 		if err != nil {
 			return fmt.Errorf("Error deleting storage volume backup %q: %w", b.Name(), err)
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
 )
 
 // WorkingDirPrefix is used when temporary working directories are needed.
@@ -18,6 +19,7 @@ type CommonBackup struct {
 	expiryDate           time.Time
 	optimizedStorage     bool
 	compressionAlgorithm string
+	target               *api.BackupTarget
 }
 
 // Name returns the name of the backup.
@@ -25,6 +27,11 @@ func (b *CommonBackup) Name() string {
 	return b.name
 }
 
+// CreationDate returns when the backup was created.
+func (b *CommonBackup) CreationDate() time.Time {
+	return b.creationDate
+}
+
 // CompressionAlgorithm returns the compression used for the tarball.
 func (b *CommonBackup) CompressionAlgorithm() string {
 	return b.compressionAlgorithm
@@ -40,3 +47,21 @@ func (b *CommonBackup) SetCompressionAlgorithm(compression string) {
 func (b *CommonBackup) OptimizedStorage() bool {
 	return b.optimizedStorage
 }
+
+// Target returns the S3 bucket the tarball is uploaded to, or nil if it is stored on the server.
+func (b *CommonBackup) Target() *api.BackupTarget {
+	return b.target
+}
+
+// renderTarget returns the S3 bucket the tarball is uploaded to, without its credentials.
+func (b *CommonBackup) renderTarget() *api.BackupTarget {
+	if b.target == nil {
+		return nil
+	}
+
+	target := *b.target
+	target.AccessKey = ""
+	target.SecretKey = ""
+
+	return &target
+}
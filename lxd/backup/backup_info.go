@@ -51,6 +51,8 @@ type Info struct {
 	OptimizedHeader  *bool          `json:"optimized_header,omitempty" yaml:"optimized_header,omitempty"` // Optional field to handle older optimized backups that don't have this field.
 	Type             Type           `json:"type,omitempty" yaml:"type,omitempty"`                         // Type of backup.
 	Config           *config.Config `json:"config,omitempty" yaml:"config,omitempty"`                     // Equivalent of backup.yaml but embedded in index for quick retrieval.
+	IncrementalFrom  string         `json:"incremental_from,omitempty" yaml:"incremental_from,omitempty"` // Name of the backup this backup is incremental from.
+	BaseSnapshot     string         `json:"base_snapshot,omitempty" yaml:"base_snapshot,omitempty"`       // Snapshot the incremental backup is based on.
 }
 
 // GetInfo extracts backup information from a given ReadSeeker.
@@ -25,12 +25,13 @@ type Instance interface {
 type InstanceBackup struct {
 	CommonBackup
 
-	instance     Instance
-	instanceOnly bool
+	instance        Instance
+	instanceOnly    bool
+	incrementalFrom string
 }
 
 // NewInstanceBackup instantiates a new InstanceBackup struct.
-func NewInstanceBackup(state *state.State, inst Instance, ID int, name string, creationDate time.Time, expiryDate time.Time, instanceOnly bool, optimizedStorage bool) *InstanceBackup {
+func NewInstanceBackup(state *state.State, inst Instance, ID int, name string, creationDate time.Time, expiryDate time.Time, instanceOnly bool, optimizedStorage bool, incrementalFrom string, target *api.BackupTarget) *InstanceBackup {
 	return &InstanceBackup{
 		CommonBackup: CommonBackup{
 			state:            state,
@@ -39,9 +40,11 @@ func NewInstanceBackup(state *state.State, inst Instance, ID int, name string, c
 			creationDate:     creationDate,
 			expiryDate:       expiryDate,
 			optimizedStorage: optimizedStorage,
+			target:           target,
 		},
-		instance:     inst,
-		instanceOnly: instanceOnly,
+		instance:        inst,
+		instanceOnly:    instanceOnly,
+		incrementalFrom: incrementalFrom,
 	}
 }
 
@@ -50,6 +53,11 @@ func (b *InstanceBackup) InstanceOnly() bool {
 	return b.instanceOnly
 }
 
+// IncrementalFrom returns the name of the backup this backup is incremental from, if any.
+func (b *InstanceBackup) IncrementalFrom() string {
+	return b.incrementalFrom
+}
+
 // Instance returns the instance to be backed up.
 func (b *InstanceBackup) Instance() Instance {
 	return b.instance
@@ -68,31 +76,34 @@ func (b *InstanceBackup) Rename(newName string) error {
 	newParentName, _, _ := api.GetParentAndSnapshotName(newName)
 	newParentBackupsPath := shared.VarPath("backups", "instances", project.Instance(b.instance.Project().Name, newParentName))
 
-	// Create the new backup path if doesn't exist.
-	if !shared.PathExists(newParentBackupsPath) {
-		err := os.MkdirAll(newParentBackupsPath, 0700)
-		if err != nil {
-			return err
+	// Backups uploaded to a target have no on-disk data, only the database record is renamed.
+	if b.target == nil {
+		// Create the new backup path if doesn't exist.
+		if !shared.PathExists(newParentBackupsPath) {
+			err := os.MkdirAll(newParentBackupsPath, 0700)
+			if err != nil {
+				return err
+			}
 		}
-	}
-
-	// Rename the backup directory.
-	err := os.Rename(oldBackupPath, newBackupPath)
-	if err != nil {
-		return err
-	}
 
-	// Check if we can remove the old parent directory.
-	empty, _ := shared.PathIsEmpty(oldParentBackupsPath)
-	if empty {
-		err := os.Remove(oldParentBackupsPath)
+		// Rename the backup directory.
+		err := os.Rename(oldBackupPath, newBackupPath)
 		if err != nil {
 			return err
 		}
+
+		// Check if we can remove the old parent directory.
+		empty, _ := shared.PathIsEmpty(oldParentBackupsPath)
+		if empty {
+			err := os.Remove(oldParentBackupsPath)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Rename the database record.
-	err = b.state.DB.Cluster.RenameInstanceBackup(b.name, newName)
+	err := b.state.DB.Cluster.RenameInstanceBackup(b.name, newName)
 	if err != nil {
 		return err
 	}
@@ -145,5 +156,7 @@ func (b *InstanceBackup) Render() *api.InstanceBackup {
 		InstanceOnly:     b.instanceOnly,
 		ContainerOnly:    b.instanceOnly,
 		OptimizedStorage: b.optimizedStorage,
+		IncrementalFrom:  b.incrementalFrom,
+		Target:           b.renderTarget(),
 	}
 }
@@ -3,21 +3,51 @@ package backup
 import (
 	"archive/tar"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
 	"github.com/canonical/lxd/lxd/archive"
 	"github.com/canonical/lxd/lxd/sys"
 	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/encryption"
 )
 
+// ErrEncrypted is returned when reading an encrypted backup file.
+var ErrEncrypted = errors.New("Backup is encrypted and must be decrypted before import")
+
+// IsEncrypted rewinds backup file handle r and returns whether the backup file is encrypted.
+func IsEncrypted(r io.ReadSeeker) (bool, error) {
+	_, err := r.Seek(0, io.SeekStart)
+	if err != nil {
+		return false, err
+	}
+
+	header := make([]byte, len(encryption.Magic)+1)
+	_, err = io.ReadFull(r, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+
+	_, err = r.Seek(0, io.SeekStart)
+	if err != nil {
+		return false, err
+	}
+
+	return encryption.IsEncrypted(header), nil
+}
+
 // TarReader rewinds backup file handle r and returns new tar reader and process cleanup function.
 func TarReader(r io.ReadSeeker, sysOS *sys.OS, outputPath string) (*tar.Reader, context.CancelFunc, error) {
-	_, err := r.Seek(0, io.SeekStart)
+	encrypted, err := IsEncrypted(r)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	if encrypted {
+		return nil, nil, ErrEncrypted
+	}
+
 	_, _, unpacker, err := shared.DetectCompressionFile(r)
 	if err != nil {
 		return nil, nil, err
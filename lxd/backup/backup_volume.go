@@ -23,7 +23,7 @@ type VolumeBackup struct {
 }
 
 // NewVolumeBackup instantiates a new VolumeBackup struct.
-func NewVolumeBackup(state *state.State, projectName, poolName, volumeName string, ID int, name string, creationDate, expiryDate time.Time, volumeOnly, optimizedStorage bool) *VolumeBackup {
+func NewVolumeBackup(state *state.State, projectName, poolName, volumeName string, ID int, name string, creationDate, expiryDate time.Time, volumeOnly, optimizedStorage bool, target *api.BackupTarget) *VolumeBackup {
 	return &VolumeBackup{
 		CommonBackup: CommonBackup{
 			state:            state,
@@ -32,6 +32,7 @@ func NewVolumeBackup(state *state.State, projectName, poolName, volumeName strin
 			creationDate:     creationDate,
 			expiryDate:       expiryDate,
 			optimizedStorage: optimizedStorage,
+			target:           target,
 		},
 		projectName: projectName,
 		poolName:    poolName,
@@ -40,6 +41,11 @@ func NewVolumeBackup(state *state.State, projectName, poolName, volumeName strin
 	}
 }
 
+// ProjectName returns the project of the volume.
+func (b *VolumeBackup) ProjectName() string {
+	return b.projectName
+}
+
 // VolumeOnly returns whether only the volume itself is to be backed up.
 func (b *VolumeBackup) VolumeOnly() bool {
 	return b.volumeOnly
@@ -66,33 +72,36 @@ func (b *VolumeBackup) Rename(newName string) error {
 	revert := revert.New()
 	defer revert.Fail()
 
-	// Create the new backup path if doesn't exist.
-	if !shared.PathExists(newParentBackupsPath) {
-		err := os.MkdirAll(newParentBackupsPath, 0700)
+	// Backups uploaded to a target have no on-disk data, only the database record is renamed.
+	if b.target == nil {
+		// Create the new backup path if doesn't exist.
+		if !shared.PathExists(newParentBackupsPath) {
+			err := os.MkdirAll(newParentBackupsPath, 0700)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Rename the backup directory.
+		err := os.Rename(oldBackupPath, newBackupPath)
 		if err != nil {
 			return err
 		}
-	}
-
-	// Rename the backup directory.
-	err := os.Rename(oldBackupPath, newBackupPath)
-	if err != nil {
-		return err
-	}
 
-	revert.Add(func() { _ = os.Rename(newBackupPath, oldBackupPath) })
+		revert.Add(func() { _ = os.Rename(newBackupPath, oldBackupPath) })
 
-	// Check if we can remove the old parent directory.
-	empty, _ := shared.PathIsEmpty(oldParentBackupsPath)
-	if empty {
-		err := os.Remove(oldParentBackupsPath)
-		if err != nil {
-			return err
+		// Check if we can remove the old parent directory.
+		empty, _ := shared.PathIsEmpty(oldParentBackupsPath)
+		if empty {
+			err := os.Remove(oldParentBackupsPath)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	// Rename the database record.
-	err = b.state.DB.Cluster.RenameVolumeBackup(b.name, newName)
+	err := b.state.DB.Cluster.RenameVolumeBackup(b.name, newName)
 	if err != nil {
 		return err
 	}
@@ -139,5 +148,6 @@ func (b *VolumeBackup) Render() *api.StoragePoolVolumeBackup {
 		ExpiresAt:        b.expiryDate,
 		VolumeOnly:       b.volumeOnly,
 		OptimizedStorage: b.optimizedStorage,
+		Target:           b.renderTarget(),
 	}
 }
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/backup"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// backupScheduledPattern is the name pattern of the backups created by the backups.schedule setting.
+const backupScheduledPattern = "auto%d"
+
+// backupScheduledIndex returns the index of a scheduled backup name, or -1 if the name doesn't match backupScheduledPattern.
+func backupScheduledIndex(name string) int {
+	var num int
+	count, err := fmt.Sscanf(name, backupScheduledPattern, &num)
+	if err != nil || count != 1 || fmt.Sprintf(backupScheduledPattern, num) != name {
+		return -1
+	}
+
+	return num
+}
+
+// backupNextScheduledName returns the name of the next scheduled backup given the names of the existing backups.
+func backupNextScheduledName(names []string) string {
+	next := 0
+	for _, name := range names {
+		num := backupScheduledIndex(name)
+		if num >= next {
+			next = num + 1
+		}
+	}
+
+	return fmt.Sprintf(backupScheduledPattern, next)
+}
+
+// backupScheduledExcess returns the names of the scheduled backups exceeding the backups.retain setting, oldest first.
+// The created argument maps the names of the existing backups to their creation date.
+func backupScheduledExcess(retain string, created map[string]time.Time) []string {
+	limit, err := strconv.Atoi(retain)
+	if err != nil || limit <= 0 {
+		return nil
+	}
+
+	scheduled := make([]string, 0, len(created))
+	for name := range created {
+		if backupScheduledIndex(name) >= 0 {
+			scheduled = append(scheduled, name)
+		}
+	}
+
+	if len(scheduled) <= limit {
+		return nil
+	}
+
+	sort.Slice(scheduled, func(i, j int) bool {
+		if created[scheduled[i]].Equal(created[scheduled[j]]) {
+			return backupScheduledIndex(scheduled[i]) < backupScheduledIndex(scheduled[j])
+		}
+
+		return created[scheduled[i]].Before(created[scheduled[j]])
+	})
+
+	return scheduled[:len(scheduled)-limit]
+}
+
+// backupScheduleTarget returns the storage bucket configured by the backups.target setting, or nil if unset.
+func backupScheduleTarget(value string, objectName string) *api.BackupTarget {
+	poolName, bucketName, found := strings.Cut(value, "/")
+	if !found {
+		return nil
+	}
+
+	return &api.BackupTarget{
+		Pool:       poolName,
+		BucketName: bucketName,
+		ObjectName: objectName,
+	}
+}
+
+// autoCreateInstanceBackups creates the scheduled backups of the instances and deletes their scheduled backups
+// exceeding backups.retain.
+func autoCreateInstanceBackups(ctx context.Context, s *state.State, op *operations.Operation, instances []instance.Instance) error {
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return project.AllowBackupCreation(tx, inst.Project().Name)
+		})
+		if err != nil {
+			l.Warn("Skipping scheduled instance backup", logger.Ctx{"err": err})
+			continue
+		}
+
+		backups, err := inst.Backups()
+		if err != nil {
+			return fmt.Errorf("Failed loading backups of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		names := make([]string, 0, len(backups))
+		for _, b := range backups {
+			_, backupName, _ := api.GetParentAndSnapshotName(b.Name())
+			names = append(names, backupName)
+		}
+
+		backupName := backupNextScheduledName(names)
+
+		expiry, err := shared.GetExpiry(time.Now(), inst.ExpandedConfig()["backups.expiry"])
+		if err != nil {
+			return fmt.Errorf("Failed getting backups.expiry of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		args := db.InstanceBackup{
+			Name:         inst.Name() + shared.SnapshotDelimiter + backupName,
+			InstanceID:   inst.ID(),
+			CreationDate: time.Now(),
+			ExpiryDate:   expiry,
+			Target:       backupScheduleTarget(inst.ExpandedConfig()["backups.target"], path.Join(project.Instance(inst.Project().Name, inst.Name()), backupName)),
+		}
+
+		err = backupCreate(s, args, inst, op)
+		if err != nil {
+			return fmt.Errorf("Failed creating scheduled backup %q of instance %q (project %q): %w", backupName, inst.Name(), inst.Project().Name, err)
+		}
+
+		l.Debug("Created scheduled instance backup", logger.Ctx{"backup": backupName})
+
+		// Delete the oldest scheduled backups.
+		backups, err = inst.Backups()
+		if err != nil {
+			return fmt.Errorf("Failed loading backups of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		created := make(map[string]time.Time, len(backups))
+		backupsByName := make(map[string]backup.InstanceBackup, len(backups))
+		for _, b := range backups {
+			_, backupName, _ := api.GetParentAndSnapshotName(b.Name())
+			created[backupName] = b.CreationDate()
+			backupsByName[backupName] = b
+		}
+
+		for _, backupName := range backupScheduledExcess(inst.ExpandedConfig()["backups.retain"], created) {
+			b := backupsByName[backupName]
+			if b.Target() != nil {
+				err = backupTargetDelete(s, inst.Project().Name, b.Target())
+				if err != nil {
+					return fmt.Errorf("Failed deleting scheduled backup %q of instance %q (project %q) from its target: %w", backupName, inst.Name(), inst.Project().Name, err)
+				}
+			}
+
+			err = b.Delete()
+			if err != nil {
+				return fmt.Errorf("Failed deleting scheduled backup %q of instance %q (project %q): %w", backupName, inst.Name(), inst.Project().Name, err)
+			}
+
+			l.Debug("Deleted scheduled instance backup", logger.Ctx{"backup": backupName})
+		}
+	}
+
+	return nil
+}
+
+// autoCreateCustomVolumeBackups creates the scheduled backups of the custom volumes and deletes their scheduled
+// backups exceeding backups.retain.
+func autoCreateCustomVolumeBackups(ctx context.Context, s *state.State, op *operations.Operation, volumes []db.StorageVolumeArgs) error {
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		l := logger.AddContext(logger.Ctx{"project": v.ProjectName, "pool": v.PoolName, "volume": v.Name})
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return project.AllowBackupCreation(tx, v.ProjectName)
+		})
+		if err != nil {
+			l.Warn("Skipping scheduled custom volume backup", logger.Ctx{"err": err})
+			continue
+		}
+
+		pool, err := storagePools.LoadByName(s, v.PoolName)
+		if err != nil {
+			return fmt.Errorf("Failed loading storage pool %q: %w", v.PoolName, err)
+		}
+
+		backups, err := s.DB.Cluster.GetStoragePoolVolumeBackups(v.ProjectName, v.Name, pool.ID())
+		if err != nil {
+			return fmt.Errorf("Failed loading backups of custom volume %q (project %q): %w", v.Name, v.ProjectName, err)
+		}
+
+		names := make([]string, 0, len(backups))
+		for _, b := range backups {
+			_, backupName, _ := api.GetParentAndSnapshotName(b.Name)
+			names = append(names, backupName)
+		}
+
+		backupName := backupNextScheduledName(names)
+
+		expiry, err := shared.GetExpiry(time.Now(), v.Config["backups.expiry"])
+		if err != nil {
+			return fmt.Errorf("Failed getting backups.expiry of custom volume %q (project %q): %w", v.Name, v.ProjectName, err)
+		}
+
+		args := db.StoragePoolVolumeBackup{
+			Name:         v.Name + shared.SnapshotDelimiter + backupName,
+			VolumeID:     v.ID,
+			CreationDate: time.Now(),
+			ExpiryDate:   expiry,
+			Target:       backupScheduleTarget(v.Config["backups.target"], path.Join(project.StorageVolume(v.ProjectName, v.Name), backupName)),
+		}
+
+		err = volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name)
+		if err != nil {
+			return fmt.Errorf("Failed creating scheduled backup %q of custom volume %q (project %q): %w", backupName, v.Name, v.ProjectName, err)
+		}
+
+		s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupCreated.Event(v.PoolName, db.StoragePoolVolumeTypeNameCustom, args.Name, v.ProjectName, op.Requestor(), logger.Ctx{"type": db.StoragePoolVolumeTypeNameCustom}))
+
+		// Delete the oldest scheduled backups.
+		backups, err = s.DB.Cluster.GetStoragePoolVolumeBackups(v.ProjectName, v.Name, pool.ID())
+		if err != nil {
+			return fmt.Errorf("Failed loading backups of custom volume %q (project %q): %w", v.Name, v.ProjectName, err)
+		}
+
+		created := make(map[string]time.Time, len(backups))
+		backupsByName := make(map[string]db.StoragePoolVolumeBackup, len(backups))
+		for _, b := range backups {
+			_, backupName, _ := api.GetParentAndSnapshotName(b.Name)
+			created[backupName] = b.CreationDate
+			backupsByName[backupName] = b
+		}
+
+		for _, backupName := range backupScheduledExcess(v.Config["backups.retain"], created) {
+			b := backupsByName[backupName]
+			if b.Target != nil {
+				err = backupTargetDelete(s, v.ProjectName, b.Target)
+				if err != nil {
+					return fmt.Errorf("Failed deleting scheduled backup %q of custom volume %q (project %q) from its target: %w", backupName, v.Name, v.ProjectName, err)
+				}
+			}
+
+			volBackup := backup.NewVolumeBackup(s, v.ProjectName, v.PoolName, v.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage, b.Target)
+			err = volBackup.Delete()
+			if err != nil {
+				return fmt.Errorf("Failed deleting scheduled backup %q of custom volume %q (project %q): %w", backupName, v.Name, v.ProjectName, err)
+			}
+
+			s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupDeleted.Event(v.PoolName, db.StoragePoolVolumeTypeNameCustom, b.Name, v.ProjectName, op.Requestor(), nil))
+		}
+	}
+
+	return nil
+}
+
+func autoCreateBackupsTask(d *Daemon) (task.Func, task.Schedule) {
+	return scheduledTask{
+		scheduleKey:    "backups.schedule",
+		instanceOpType: operationtype.BackupCreate,
+		instanceRun:    autoCreateInstanceBackups,
+		volumeOpType:   operationtype.CustomVolumeBackupCreate,
+		volumeRun:      autoCreateCustomVolumeBackups,
+	}.task(d)
+}
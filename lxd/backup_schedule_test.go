@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupNextScheduledName(t *testing.T) {
+	assert.Equal(t, "auto0", backupNextScheduledName(nil))
+	assert.Equal(t, "auto0", backupNextScheduledName([]string{"backup0", "backup1"}))
+	assert.Equal(t, "auto3", backupNextScheduledName([]string{"auto0", "auto2", "backup7"}))
+	assert.Equal(t, "auto1", backupNextScheduledName([]string{"auto0", "auto05", "autox"}))
+}
+
+func TestBackupScheduledExcess(t *testing.T) {
+	now := time.Now()
+	created := map[string]time.Time{
+		"auto0":   now.Add(-3 * time.Hour),
+		"auto1":   now.Add(-2 * time.Hour),
+		"auto2":   now.Add(-1 * time.Hour),
+		"backup0": now.Add(-4 * time.Hour),
+	}
+
+	assert.Nil(t, backupScheduledExcess("", created))
+	assert.Nil(t, backupScheduledExcess("0", created))
+	assert.Nil(t, backupScheduledExcess("3", created))
+	assert.Equal(t, []string{"auto0"}, backupScheduledExcess("2", created))
+	assert.Equal(t, []string{"auto0", "auto1"}, backupScheduledExcess("1", created))
+}
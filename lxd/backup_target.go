@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// backupTargetPartSize is the size of the parts backup tarballs are uploaded in.
+// As the size of the tarball isn't known in advance, each part is buffered in memory before being uploaded.
+// With the S3 limit of 10000 parts per upload, this allows uploading tarballs of up to 625GiB.
+const backupTargetPartSize = 64 * 1024 * 1024
+
+// backupTargetValidate validates the S3 target of a backup and fills in the default object name.
+// External targets must use one of the endpoints allowed by the backups.allowed_target_urls server setting.
+func backupTargetValidate(s *state.State, target *api.BackupTarget, defaultObjectName string) error {
+	if target.BucketName == "" {
+		return fmt.Errorf("Backup target bucket name is required")
+	}
+
+	if target.URL != "" {
+		u, err := url.Parse(target.URL)
+		if err != nil {
+			return fmt.Errorf("Invalid backup target URL %q: %w", target.URL, err)
+		}
+
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("Backup target URL must use the http or https scheme")
+		}
+
+		// The S3 client only supports endpoints at the root of the host.
+		if u.Path != "" && u.Path != "/" {
+			return fmt.Errorf("Backup target URL can't contain a path")
+		}
+
+		if !remoteURLAllowed(u, s.GlobalConfig.BackupsAllowedTargetURLs()) {
+			return fmt.Errorf("Backup target URL %q isn't allowed by the server configuration", target.URL)
+		}
+
+		if target.Pool != "" {
+			return fmt.Errorf("Backup target URL and pool can't be combined")
+		}
+
+		if target.AccessKey == "" || target.SecretKey == "" {
+			return fmt.Errorf("Backup target access key and secret key are required when using a URL")
+		}
+	} else {
+		if target.Pool == "" {
+			return fmt.Errorf("Backup target requires either a URL or a storage pool")
+		}
+
+		if target.AccessKey != "" || target.SecretKey != "" {
+			return fmt.Errorf("Backup target keys can only be used with a URL")
+		}
+	}
+
+	if target.ObjectName == "" {
+		target.ObjectName = defaultObjectName
+	}
+
+	return nil
+}
+
+// remoteURLAllowed checks whether the scheme and host of the URL match one of the allowed URLs.
+func remoteURLAllowed(u *url.URL, allowedURLs []string) bool {
+	for _, allowedURL := range allowedURLs {
+		allowed, err := url.Parse(strings.TrimSpace(allowedURL))
+		if err != nil {
+			continue
+		}
+
+		if allowed.Scheme == u.Scheme && strings.EqualFold(allowed.Host, u.Host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backupTargetClient returns an S3 client for the backup target.
+// Targets without URL use a storage bucket of the project on a local storage pool of this member.
+func backupTargetClient(s *state.State, projectName string, target *api.BackupTarget) (*minio.Client, error) {
+	if target.URL != "" {
+		u, err := url.Parse(target.URL)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid backup target URL %q: %w", target.URL, err)
+		}
+
+		return minio.New(u.Host, &minio.Options{
+			Creds:  credentials.NewStaticV4(target.AccessKey, target.SecretKey, ""),
+			Secure: u.Scheme == "https",
+		})
+	}
+
+	pool, err := storagePools.LoadByName(s, target.Pool)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading backup target storage pool %q: %w", target.Pool, err)
+	}
+
+	if !pool.Driver().Info().Buckets || pool.Driver().Info().Remote {
+		return nil, fmt.Errorf("Backup target storage pool %q must be a local storage pool supporting buckets", target.Pool)
+	}
+
+	bucketProjectName, err := project.StorageBucketProject(context.TODO(), s.DB.Cluster, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the bucket exists on this member.
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.GetStoragePoolBucket(ctx, pool.ID(), bucketProjectName, true, target.BucketName)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading backup target bucket %q: %w", target.BucketName, err)
+	}
+
+	minioProc, err := pool.ActivateBucket(bucketProjectName, target.BucketName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed activating backup target bucket %q: %w", target.BucketName, err)
+	}
+
+	return minioProc.S3Client()
+}
+
+// backupTargetWriter uploads the data written to it to the backup target.
+type backupTargetWriter struct {
+	*io.PipeWriter
+
+	mu   sync.Mutex
+	res  chan error
+	err  error
+	done bool
+}
+
+// Close ends the upload and waits for it to complete.
+func (w *backupTargetWriter) Close() error {
+	return w.finish(nil)
+}
+
+// Abort cancels the upload, leaving no object in the bucket.
+func (w *backupTargetWriter) Abort() {
+	_ = w.finish(fmt.Errorf("Backup upload aborted"))
+}
+
+func (w *backupTargetWriter) finish(abortErr error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.done {
+		return w.err
+	}
+
+	w.done = true
+	_ = w.PipeWriter.CloseWithError(abortErr)
+	w.err = <-w.res
+
+	return w.err
+}
+
+// backupTargetUpload starts uploading a backup tarball to the target and returns the writer to write it to.
+func backupTargetUpload(s *state.State, projectName string, target *api.BackupTarget) (*backupTargetWriter, error) {
+	client, err := backupTargetClient(s, projectName, target)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := client.BucketExists(context.TODO(), target.BucketName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed checking backup target bucket %q: %w", target.BucketName, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("Backup target bucket %q doesn't exist", target.BucketName)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	w := &backupTargetWriter{
+		PipeWriter: pipeWriter,
+		res:        make(chan error, 1),
+	}
+
+	go func() {
+		_, err := client.PutObject(context.Background(), target.BucketName, target.ObjectName, pipeReader, -1, minio.PutObjectOptions{
+			ContentType: "application/octet-stream",
+			PartSize:    backupTargetPartSize,
+		})
+		if err != nil {
+			err = fmt.Errorf("Failed uploading backup to bucket %q: %w", target.BucketName, err)
+		}
+
+		// Unblock the writer if the upload failed.
+		_ = pipeReader.CloseWithError(err)
+		w.res <- err
+	}()
+
+	return w, nil
+}
+
+// backupTargetDelete deletes a backup tarball uploaded to the target.
+func backupTargetDelete(s *state.State, projectName string, target *api.BackupTarget) error {
+	// Backups uploaded to external targets before their credentials were stored can't be deleted.
+	if target.URL != "" && (target.AccessKey == "" || target.SecretKey == "") {
+		logger.Warn("Leaving backup in external bucket without credentials", logger.Ctx{"url": target.URL, "bucket": target.BucketName, "object": target.ObjectName})
+		return nil
+	}
+
+	client, err := backupTargetClient(s, projectName, target)
+	if err != nil {
+		return err
+	}
+
+	err = client.RemoveObject(context.TODO(), target.BucketName, target.ObjectName, minio.RemoveObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("Failed deleting backup from bucket %q: %w", target.BucketName, err)
+	}
+
+	return nil
+}
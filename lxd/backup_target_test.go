@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestRemoteURLAllowed(t *testing.T) {
+	allowed := []string{"https://s3.example.com", " http://minio.example.net:9000/"}
+
+	for _, rawURL := range []string{"https://s3.example.com", "https://S3.example.com/", "http://minio.example.net:9000"} {
+		u, err := url.Parse(rawURL)
+		assert.NoError(t, err)
+		assert.True(t, remoteURLAllowed(u, allowed), rawURL)
+	}
+
+	for _, rawURL := range []string{"http://s3.example.com", "https://s3.example.com:8443", "http://minio.example.net", "http://169.254.169.254"} {
+		u, err := url.Parse(rawURL)
+		assert.NoError(t, err)
+		assert.False(t, remoteURLAllowed(u, allowed), rawURL)
+	}
+
+	u, err := url.Parse("https://s3.example.com")
+	assert.NoError(t, err)
+	assert.False(t, remoteURLAllowed(u, nil))
+}
+
+// Expired backups uploaded to an external target are deleted from its bucket.
+func TestPruneExpiredStorageVolumeBackupsTarget(t *testing.T) {
+	t.Setenv("LXD_DIR", t.TempDir())
+
+	var mu sync.Mutex
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, isLocation := r.URL.Query()["location"]
+		switch {
+		case r.Method == http.MethodGet && isLocation:
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`))
+		case r.Method == http.MethodDelete:
+			mu.Lock()
+			deleted = append(deleted, r.URL.Path)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	defer server.Close()
+
+	cluster, cleanup := db.NewTestCluster(t)
+	defer cleanup()
+
+	poolID, err := cluster.CreateStoragePool("pool1", "", "zfs", nil)
+	require.NoError(t, err)
+
+	volID, err := cluster.CreateStoragePoolVolume("default", "vol1", "", db.StoragePoolVolumeTypeCustom, poolID, nil, db.StoragePoolVolumeContentTypeFS, time.Now())
+	require.NoError(t, err)
+
+	target := &api.BackupTarget{
+		URL:        server.URL,
+		BucketName: "backups",
+		ObjectName: "vol1/backup0",
+		AccessKey:  "access",
+		SecretKey:  "secret",
+	}
+
+	err = cluster.CreateStoragePoolVolumeBackup(db.StoragePoolVolumeBackup{
+		VolumeID:     volID,
+		Name:         "vol1/backup0",
+		CreationDate: time.Now().Add(-2 * time.Hour),
+		ExpiryDate:   time.Now().Add(-time.Hour),
+		Target:       target,
+	})
+	require.NoError(t, err)
+
+	s := &state.State{DB: &db.DB{Cluster: cluster}}
+	err = pruneExpiredStorageVolumeBackups(context.Background(), s)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/backups/vol1/backup0"}, deleted)
+
+	backups, err := cluster.GetStoragePoolVolumeBackupsNames("default", "vol1", poolID)
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+}
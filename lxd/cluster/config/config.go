@@ -48,6 +48,29 @@ func (c *Config) BackupsCompressionAlgorithm() string {
 	return c.m.GetString("backups.compression_algorithm")
 }
 
+// BackupsAllowedTargetURLs returns the URLs of the S3 endpoints backups can be uploaded to.
+func (c *Config) BackupsAllowedTargetURLs() []string {
+	var urls []string
+
+	if c.m.GetString("backups.allowed_target_urls") != "" {
+		urls = strings.Split(c.m.GetString("backups.allowed_target_urls"), ",")
+	}
+
+	return urls
+}
+
+// ReplicationAllowedRemoteURLs returns the URLs of the LXD servers outside of the cluster that instances and custom
+// volumes can be replicated to.
+func (c *Config) ReplicationAllowedRemoteURLs() []string {
+	var urls []string
+
+	if c.m.GetString("replication.allowed_remote_urls") != "" {
+		urls = strings.Split(c.m.GetString("replication.allowed_remote_urls"), ",")
+	}
+
+	return urls
+}
+
 // MetricsAuthentication checks whether metrics API requires authentication.
 func (c *Config) MetricsAuthentication() bool {
 	return c.m.GetBool("core.metrics_authentication")
@@ -160,6 +183,11 @@ func (c *Config) MaxStandBy() int64 {
 	return c.m.GetInt64("cluster.max_standby")
 }
 
+// StorageWarningThreshold returns the storage pool usage percentage above which a low space warning is raised.
+func (c *Config) StorageWarningThreshold() int64 {
+	return c.m.GetInt64("storage.warning_threshold")
+}
+
 // NetworkOVNIntegrationBridge returns the integration OVS bridge to use for OVN networks.
 func (c *Config) NetworkOVNIntegrationBridge() string {
 	return c.m.GetString("network.ovn.integration_bridge")
@@ -207,6 +235,11 @@ func (c *Config) ImagesRemoteCacheExpiryDays() int64 {
 	return c.m.GetInt64("images.remote_cache_expiry")
 }
 
+// InstancesCPUBalancing returns the mode used to balance the CPUs of instances.
+func (c *Config) InstancesCPUBalancing() string {
+	return c.m.GetString("instances.cpu_balancing")
+}
+
 // InstancesNICHostname returns hostname mode to use for instance NICs.
 func (c *Config) InstancesNICHostname() string {
 	return c.m.GetString("instances.nic.host_name")
@@ -217,6 +250,11 @@ func (c *Config) InstancesPlacementScriptlet() string {
 	return c.m.GetString("instances.placement.scriptlet")
 }
 
+// InstancesValidationScriptlet returns the instances validation scriptlet source code.
+func (c *Config) InstancesValidationScriptlet() string {
+	return c.m.GetString("instances.validation.scriptlet")
+}
+
 // LokiServer returns all the Loki settings needed to connect to a server.
 func (c *Config) LokiServer() (string, string, string, string, []string, string, []string) {
 	var types []string
@@ -348,6 +386,15 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Agree to ACME terms of service
 	"acme.agree_tos": {Type: config.Bool, Default: "false"},
 
+	// lxdmeta:generate(entities=server; group=miscellaneous; key=backups.allowed_target_urls)
+	// Specify a comma-separated list of S3 endpoint URLs that backups can be uploaded to.
+	// Backups can only be uploaded to storage buckets of the project if this option isn't set.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: S3 endpoints that backups can be uploaded to
+	"backups.allowed_target_urls": {Validator: validate.Optional(validate.IsListOf(validate.IsRequestURL))},
+
 	// lxdmeta:generate(entities=server; group=miscellaneous; key=backups.compression_algorithm)
 	// Possible values are `bzip2`, `gzip`, `lzma`, `xz`, or `none`.
 	// ---
@@ -612,6 +659,21 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: When an unused cached remote image is flushed
 	"images.remote_cache_expiry": {Type: config.Int64, Default: "10"},
 
+	// lxdmeta:generate(entities=server; group=miscellaneous; key=instances.cpu_balancing)
+	// Possible values are `count` and `usage`.
+	//
+	// If set to `count`, instance CPUs are spread across the host CPUs based on the number of instances using each CPU.
+	// If set to `usage`, the measured CPU usage of the instances is used instead, and virtual machines without pinned vCPUs take part in the balancing too.
+	// The busiest instances are placed first, on the least loaded CPUs of a single NUMA node where possible, avoiding SMT siblings of busy CPUs.
+	// An instance is moved at most once every five minutes.
+	// Switching back to `count` lets the vCPUs of those virtual machines float over all host CPUs again.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `count`
+	//  shortdesc: How to balance the CPUs of instances that aren't pinned
+	"instances.cpu_balancing": {Validator: validate.Optional(validate.IsOneOf("count", "usage")), Default: "count"},
+
 	// lxdmeta:generate(entities=server; group=miscellaneous; key=instances.nic.host_name)
 	// Possible values are `random` and `mac`.
 	//
@@ -633,6 +695,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Instance placement scriptlet for automatic instance placement
 	"instances.placement.scriptlet": {Validator: validate.Optional(scriptletLoad.InstancePlacementValidate)},
 
+	// lxdmeta:generate(entities=server; group=miscellaneous; key=instances.validation.scriptlet)
+	// When using custom instance validation logic, this option stores the scriptlet.
+	// The scriptlet is run when instances are created or updated and can reject the request or modify the requested configuration and devices.
+	// See {ref}`instance-validation-scriptlet` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Instance validation scriptlet for instance create and update requests
+	"instances.validation.scriptlet": {Validator: validate.Optional(scriptletLoad.InstanceValidationValidate)},
+
 	// lxdmeta:generate(entities=server; group=loki; key=loki.auth.username)
 	//
 	// ---
@@ -831,6 +903,28 @@ var ConfigSchema = config.Schema{
 	//  defaultdesc: Content of `/etc/ovn/key_host` if present
 	//  shortdesc: OVN SSL client key
 	"network.ovn.client_key": {Default: ""},
+
+	// lxdmeta:generate(entities=server; group=miscellaneous; key=replication.allowed_remote_urls)
+	// Specify a comma-separated list of URLs of LXD servers outside of the cluster that instances and custom volumes can be replicated to.
+	// Replication is only possible within the cluster if this option isn't set.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: LXD servers that instances and custom volumes can be replicated to
+	"replication.allowed_remote_urls": {Validator: validate.Optional(validate.IsListOf(validate.IsRequestURL))},
+
+	// lxdmeta:generate(entities=server; group=miscellaneous; key=storage.warning_threshold)
+	// Specify the percentage of used space above which LXD raises a warning for a storage pool.
+	// A warning is also raised if the growth of the pool usage over the last day indicates that the pool will be full within a day.
+	// The warning is resolved automatically once neither applies anymore.
+	// Set this option to `0` to disable storage pool low space warnings.
+	// The threshold can be overridden for a storage pool with its `warning_threshold` configuration option.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `90`
+	//  shortdesc: Storage pool usage percentage that triggers a low space warning
+	"storage.warning_threshold": {Type: config.Int64, Default: "90", Validator: validate.IsInRange(0, 100)},
 }
 
 func expiryValidator(value string) error {
@@ -50,6 +50,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/loki"
 	"github.com/canonical/lxd/lxd/maas"
+	"github.com/canonical/lxd/lxd/metrics"
 	networkZone "github.com/canonical/lxd/lxd/network/zone"
 	"github.com/canonical/lxd/lxd/node"
 	"github.com/canonical/lxd/lxd/request"
@@ -613,6 +614,8 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			return action.Handler(d, r)
 		}
 
+		requestStart := time.Now()
+
 		switch r.Method {
 		case "GET":
 			resp = handleRequest(c.Get)
@@ -638,6 +641,11 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				logger.Error("Failed writing error for HTTP response", logger.Ctx{"url": uri, "err": err, "writeErr": writeErr})
 			}
 		}
+
+		// Record the request duration (the events endpoint is long-lived so is skipped).
+		if c.Path != "events" {
+			metrics.APIRequestDuration.Observe(map[string]string{"method": r.Method, "endpoint": uri}, time.Since(requestStart).Seconds())
+		}
 	})
 
 	// If the endpoint has a canonical name then record it so it can be used to build URLS
@@ -1318,6 +1326,7 @@ func (d *Daemon) init() error {
 	oidcIssuer, oidcClientID, oidcAudience := d.globalConfig.OIDCServer()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 	instancePlacementScriptlet := d.globalConfig.InstancesPlacementScriptlet()
+	instanceValidationScriptlet := d.globalConfig.InstancesValidationScriptlet()
 
 	d.endpoints.NetworkUpdateTrustedProxy(d.globalConfig.HTTPSTrustedProxy())
 	d.globalConfigMu.Unlock()
@@ -1449,6 +1458,14 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Load instance validation scriptlet.
+	if instanceValidationScriptlet != "" {
+		err = scriptletLoad.InstanceValidationSet(instanceValidationScriptlet)
+		if err != nil {
+			logger.Warn("Failed loading instance validation scriptlet", logger.Ctx{"err": err})
+		}
+	}
+
 	// Apply all patches that need to be run after networks are initialised.
 	err = patchesApply(d, patchPostNetworks)
 	if err != nil {
@@ -1584,12 +1601,21 @@ func (d *Daemon) init() error {
 		// Remove expired backups (hourly)
 		d.tasks.Add(pruneExpiredBackupsTask(d))
 
+		// Take scheduled backups of instances and custom volumes (minutely check of configurable cron expression)
+		d.tasks.Add(autoCreateBackupsTask(d))
+
+		// Replicate instances and custom volumes (minutely check of configurable cron expression)
+		d.tasks.Add(autoReplicateTask(d))
+
 		// Prune expired instance snapshots and take snapshot of instances (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateInstanceSnapshotsTask(d))
 
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d))
 
+		// Start and stop instances according to their power schedules (minutely check of configurable cron expression)
+		d.tasks.Add(instancesPowerScheduleTask(d))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d))
 
@@ -1598,6 +1624,15 @@ func (d *Daemon) init() error {
 
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d))
+
+		// Run network load balancer backend health checks (every 5s)
+		d.tasks.Add(networkLoadBalancerHealthCheckTask(d))
+
+		// Check storage pool usage and raise low space warnings (every 10 minutes)
+		d.tasks.Add(storagePoolUsageTask(d))
+
+		// Rebalance the instance CPUs when balancing by usage (every minute)
+		d.tasks.Add(deviceTaskBalanceTask(d))
 	}
 
 	// Start all background tasks
@@ -1959,7 +1994,7 @@ func (d *Daemon) setupSyslogSocket(enable bool) error {
 
 	logger.Debug("Starting syslog socket")
 
-	err := StartSyslogListener(ctx, d.events)
+	err := StartSyslogListener(ctx, d.events, d.State)
 	if err != nil {
 		return err
 	}
@@ -2152,6 +2187,13 @@ func (d *Daemon) nodeRefreshTask(heartbeatData *cluster.APIHeartbeat, isLeader b
 		logger.Error("Error restarting OVN networks", logger.Ctx{"err": err})
 	}
 
+	// Refresh wireguard peers.
+	err = networkUpdateWireguardPeersTask(s, heartbeatData)
+	if err != nil {
+		stateChangeTaskFailure = true
+		logger.Error("Error refreshing wireguard peers", logger.Ctx{"err": err, "local": localClusterAddress})
+	}
+
 	if d.hasMemberStateChanged(heartbeatData) {
 		logger.Info("Cluster member state has changed", logger.Ctx{"local": localClusterAddress})
 
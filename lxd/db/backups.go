@@ -5,6 +5,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -24,6 +25,10 @@ type InstanceBackup struct {
 	InstanceOnly         bool
 	OptimizedStorage     bool
 	CompressionAlgorithm string
+	IncrementalFrom      string
+	Target               *api.BackupTarget
+	EncryptionRecipients []string
+	EncryptionPassphrase string
 }
 
 // StoragePoolVolumeBackup is a value object holding all db-related details about a storage volume backup.
@@ -36,6 +41,40 @@ type StoragePoolVolumeBackup struct {
 	VolumeOnly           bool
 	OptimizedStorage     bool
 	CompressionAlgorithm string
+	Target               *api.BackupTarget
+	EncryptionRecipients []string
+	EncryptionPassphrase string
+}
+
+// backupTargetToDB encodes the S3 target of a backup for storage in the database.
+// The credentials of external targets are stored so that the uploaded object can be deleted along with the backup,
+// they are never returned through the API.
+func backupTargetToDB(target *api.BackupTarget) (string, error) {
+	if target == nil {
+		return "", nil
+	}
+
+	data, err := json.Marshal(target)
+	if err != nil {
+		return "", fmt.Errorf("Failed encoding backup target: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// backupTargetFromDB decodes the S3 target of a backup stored in the database.
+func backupTargetFromDB(value string) (*api.BackupTarget, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	target := &api.BackupTarget{}
+	err := json.Unmarshal([]byte(value), target)
+	if err != nil {
+		return nil, fmt.Errorf("Failed decoding backup target: %w", err)
+	}
+
+	return target, nil
 }
 
 // Returns the ID of the instance backup with the given name.
@@ -59,10 +98,12 @@ func (c *Cluster) GetInstanceBackup(projectName string, name string) (InstanceBa
 
 	instanceOnlyInt := -1
 	optimizedStorageInt := -1
+	target := ""
 	q := `
 SELECT instances_backups.id, instances_backups.instance_id,
        instances_backups.creation_date, instances_backups.expiry_date,
-       instances_backups.container_only, instances_backups.optimized_storage
+       instances_backups.container_only, instances_backups.optimized_storage,
+       instances_backups.incremental_from, instances_backups.target
     FROM instances_backups
     JOIN instances ON instances.id=instances_backups.instance_id
     JOIN projects ON projects.id=instances.project_id
@@ -70,7 +111,7 @@ SELECT instances_backups.id, instances_backups.instance_id,
 `
 	arg1 := []any{projectName, name}
 	arg2 := []any{&args.ID, &args.InstanceID, &args.CreationDate,
-		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt}
+		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt, &args.IncrementalFrom, &target}
 	err := dbQueryRowScan(c, q, arg1, arg2)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -88,6 +129,11 @@ SELECT instances_backups.id, instances_backups.instance_id,
 		args.OptimizedStorage = true
 	}
 
+	args.Target, err = backupTargetFromDB(target)
+	if err != nil {
+		return args, err
+	}
+
 	return args, nil
 }
 
@@ -98,10 +144,12 @@ func (c *Cluster) GetInstanceBackupWithID(backupID int) (InstanceBackup, error)
 
 	instanceOnlyInt := -1
 	optimizedStorageInt := -1
+	target := ""
 	q := `
 SELECT instances_backups.name, instances_backups.instance_id,
        instances_backups.creation_date, instances_backups.expiry_date,
-       instances_backups.container_only, instances_backups.optimized_storage
+       instances_backups.container_only, instances_backups.optimized_storage,
+       instances_backups.incremental_from, instances_backups.target
     FROM instances_backups
     JOIN instances ON instances.id=instances_backups.instance_id
     JOIN projects ON projects.id=instances.project_id
@@ -109,7 +157,7 @@ SELECT instances_backups.name, instances_backups.instance_id,
 `
 	arg1 := []any{backupID}
 	arg2 := []any{&args.Name, &args.InstanceID, &args.CreationDate,
-		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt}
+		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt, &args.IncrementalFrom, &target}
 	err := dbQueryRowScan(c, q, arg1, arg2)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -127,6 +175,11 @@ SELECT instances_backups.name, instances_backups.instance_id,
 		args.OptimizedStorage = true
 	}
 
+	args.Target, err = backupTargetFromDB(target)
+	if err != nil {
+		return args, err
+	}
+
 	return args, nil
 }
 
@@ -171,7 +224,12 @@ func (c *Cluster) CreateInstanceBackup(args InstanceBackup) error {
 			optimizedStorageInt = 1
 		}
 
-		str := "INSERT INTO instances_backups (instance_id, name, creation_date, expiry_date, container_only, optimized_storage) VALUES (?, ?, ?, ?, ?, ?)"
+		target, err := backupTargetToDB(args.Target)
+		if err != nil {
+			return err
+		}
+
+		str := "INSERT INTO instances_backups (instance_id, name, creation_date, expiry_date, container_only, optimized_storage, incremental_from, target) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
 		stmt, err := tx.tx.Prepare(str)
 		if err != nil {
 			return err
@@ -180,7 +238,7 @@ func (c *Cluster) CreateInstanceBackup(args InstanceBackup) error {
 		defer func() { _ = stmt.Close() }()
 		result, err := stmt.Exec(args.InstanceID, args.Name,
 			args.CreationDate.Unix(), args.ExpiryDate.Unix(), instanceOnlyInt,
-			optimizedStorageInt)
+			optimizedStorageInt, args.IncrementalFrom, target)
 		if err != nil {
 			return err
 		}
@@ -245,9 +303,10 @@ func (c *Cluster) GetExpiredInstanceBackups() ([]InstanceBackup, error) {
 	var name string
 	var expiryDate string
 	var instanceID int
+	var target string
 
-	q := `SELECT instances_backups.name, instances_backups.expiry_date, instances_backups.instance_id FROM instances_backups`
-	outfmt := []any{name, expiryDate, instanceID}
+	q := `SELECT instances_backups.name, instances_backups.expiry_date, instances_backups.instance_id, instances_backups.target FROM instances_backups`
+	outfmt := []any{name, expiryDate, instanceID, target}
 	dbResults, err := queryScan(c, q, nil, outfmt)
 	if err != nil {
 		return nil, err
@@ -271,10 +330,16 @@ func (c *Cluster) GetExpiredInstanceBackups() ([]InstanceBackup, error) {
 
 		// Backup has expired
 		if time.Now().Unix()-backupExpiry.Unix() >= 0 {
+			backupTarget, err := backupTargetFromDB(r[3].(string))
+			if err != nil {
+				return []InstanceBackup{}, err
+			}
+
 			result = append(result, InstanceBackup{
 				Name:       r[0].(string),
 				InstanceID: r[2].(int),
 				ExpiryDate: backupExpiry,
+				Target:     backupTarget,
 			})
 		}
 	}
@@ -286,13 +351,19 @@ func (c *Cluster) GetExpiredInstanceBackups() ([]InstanceBackup, error) {
 func (c *ClusterTx) GetExpiredStorageVolumeBackups(ctx context.Context) ([]StoragePoolVolumeBackup, error) {
 	var backups []StoragePoolVolumeBackup
 
-	q := `SELECT storage_volumes_backups.name, storage_volumes_backups.expiry_date, storage_volumes_backups.storage_volume_id FROM storage_volumes_backups`
+	q := `SELECT storage_volumes_backups.name, storage_volumes_backups.expiry_date, storage_volumes_backups.storage_volume_id, storage_volumes_backups.target FROM storage_volumes_backups`
 
 	err := query.Scan(ctx, c.Tx(), q, func(scan func(dest ...any) error) error {
 		var b StoragePoolVolumeBackup
 		var expiryTime sql.NullTime
+		var target string
+
+		err := scan(&b.Name, &expiryTime, &b.VolumeID, &target)
+		if err != nil {
+			return err
+		}
 
-		err := scan(&b.Name, &expiryTime, &b.VolumeID)
+		b.Target, err = backupTargetFromDB(target)
 		if err != nil {
 			return err
 		}
@@ -330,7 +401,8 @@ func (c *Cluster) GetStoragePoolVolumeBackups(projectName string, volumeName str
 		backups.creation_date,
 		backups.expiry_date,
 		backups.volume_only,
-		backups.optimized_storage
+		backups.optimized_storage,
+		backups.target
 	FROM storage_volumes_backups AS backups
 	JOIN storage_volumes ON storage_volumes.id=backups.storage_volume_id
 	JOIN projects ON projects.id=storage_volumes.project_id
@@ -344,8 +416,14 @@ func (c *Cluster) GetStoragePoolVolumeBackups(projectName string, volumeName str
 		return query.Scan(ctx, tx.Tx(), q, func(scan func(dest ...any) error) error {
 			var b StoragePoolVolumeBackup
 			var expiryTime sql.NullTime
+			var target string
+
+			err := scan(&b.ID, &b.VolumeID, &b.Name, &b.CreationDate, &expiryTime, &b.VolumeOnly, &b.OptimizedStorage, &target)
+			if err != nil {
+				return err
+			}
 
-			err := scan(&b.ID, &b.VolumeID, &b.Name, &b.CreationDate, &expiryTime, &b.VolumeOnly, &b.OptimizedStorage)
+			b.Target, err = backupTargetFromDB(target)
 			if err != nil {
 				return err
 			}
@@ -405,7 +483,12 @@ func (c *Cluster) CreateStoragePoolVolumeBackup(args StoragePoolVolumeBackup) er
 			optimizedStorageInt = 1
 		}
 
-		str := "INSERT INTO storage_volumes_backups (storage_volume_id, name, creation_date, expiry_date, volume_only, optimized_storage) VALUES (?, ?, ?, ?, ?, ?)"
+		target, err := backupTargetToDB(args.Target)
+		if err != nil {
+			return err
+		}
+
+		str := "INSERT INTO storage_volumes_backups (storage_volume_id, name, creation_date, expiry_date, volume_only, optimized_storage, target) VALUES (?, ?, ?, ?, ?, ?, ?)"
 		stmt, err := tx.tx.Prepare(str)
 		if err != nil {
 			return err
@@ -414,7 +497,7 @@ func (c *Cluster) CreateStoragePoolVolumeBackup(args StoragePoolVolumeBackup) er
 		defer func() { _ = stmt.Close() }()
 		result, err := stmt.Exec(args.VolumeID, args.Name,
 			args.CreationDate.Unix(), args.ExpiryDate.Unix(), volumeOnlyInt,
-			optimizedStorageInt)
+			optimizedStorageInt, target)
 		if err != nil {
 			return err
 		}
@@ -462,6 +545,7 @@ func (c *Cluster) DeleteStoragePoolVolumeBackup(name string) error {
 // GetStoragePoolVolumeBackup returns the volume backup with the given name.
 func (c *Cluster) GetStoragePoolVolumeBackup(projectName string, poolName string, backupName string) (StoragePoolVolumeBackup, error) {
 	args := StoragePoolVolumeBackup{}
+	target := ""
 	q := `
 SELECT
 	backups.id,
@@ -470,14 +554,15 @@ SELECT
 	backups.creation_date,
 	backups.expiry_date,
 	backups.volume_only,
-	backups.optimized_storage
+	backups.optimized_storage,
+	backups.target
 FROM storage_volumes_backups AS backups
 JOIN storage_volumes ON storage_volumes.id=backups.storage_volume_id
 JOIN projects ON projects.id=storage_volumes.project_id
 WHERE projects.name=? AND backups.name=?
 `
 	arg1 := []any{projectName, backupName}
-	outfmt := []any{&args.ID, &args.VolumeID, &args.Name, &args.CreationDate, &args.ExpiryDate, &args.VolumeOnly, &args.OptimizedStorage}
+	outfmt := []any{&args.ID, &args.VolumeID, &args.Name, &args.CreationDate, &args.ExpiryDate, &args.VolumeOnly, &args.OptimizedStorage, &target}
 	err := dbQueryRowScan(c, q, arg1, outfmt)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -487,12 +572,18 @@ WHERE projects.name=? AND backups.name=?
 		return args, err
 	}
 
+	args.Target, err = backupTargetFromDB(target)
+	if err != nil {
+		return args, err
+	}
+
 	return args, nil
 }
 
 // GetStoragePoolVolumeBackupWithID returns the volume backup with the given ID.
 func (c *Cluster) GetStoragePoolVolumeBackupWithID(backupID int) (StoragePoolVolumeBackup, error) {
 	args := StoragePoolVolumeBackup{}
+	target := ""
 	q := `
 SELECT
 	backups.id,
@@ -501,14 +592,15 @@ SELECT
 	backups.creation_date,
 	backups.expiry_date,
 	backups.volume_only,
-	backups.optimized_storage
+	backups.optimized_storage,
+	backups.target
 FROM storage_volumes_backups AS backups
 JOIN storage_volumes ON storage_volumes.id=backups.storage_volume_id
 JOIN projects ON projects.id=storage_volumes.project_id
 WHERE backups.id=?
 `
 	arg1 := []any{backupID}
-	outfmt := []any{&args.ID, &args.VolumeID, &args.Name, &args.CreationDate, &args.ExpiryDate, &args.VolumeOnly, &args.OptimizedStorage}
+	outfmt := []any{&args.ID, &args.VolumeID, &args.Name, &args.CreationDate, &args.ExpiryDate, &args.VolumeOnly, &args.OptimizedStorage, &target}
 	err := dbQueryRowScan(c, q, arg1, outfmt)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -518,6 +610,11 @@ WHERE backups.id=?
 		return args, err
 	}
 
+	args.Target, err = backupTargetFromDB(target)
+	if err != nil {
+		return args, err
+	}
+
 	return args, nil
 }
 
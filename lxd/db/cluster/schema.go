@@ -120,7 +120,7 @@ CREATE TABLE "instances_backups" (
     creation_date DATETIME,
     expiry_date DATETIME,
     container_only INTEGER NOT NULL default 0,
-    optimized_storage INTEGER NOT NULL default 0,
+    optimized_storage INTEGER NOT NULL default 0, incremental_from VARCHAR(255) NOT NULL DEFAULT "", target TEXT NOT NULL DEFAULT "",
     FOREIGN KEY (instance_id) REFERENCES "instances" (id) ON DELETE CASCADE,
     UNIQUE (instance_id, name)
 );
@@ -472,6 +472,10 @@ CREATE TABLE "storage_buckets_keys" (
 	FOREIGN KEY (storage_bucket_id) REFERENCES "storage_buckets" (id) ON DELETE CASCADE
 );
 CREATE UNIQUE INDEX storage_buckets_unique_storage_pool_id_node_id_name ON "storage_buckets" (storage_pool_id, IFNULL(node_id, -1), name);
+CREATE TABLE storage_encryption_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    key TEXT NOT NULL
+);
 CREATE TABLE "storage_pools" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
@@ -490,6 +494,13 @@ CREATE TABLE "storage_pools_config" (
     FOREIGN KEY (storage_pool_id) REFERENCES "storage_pools" (id) ON DELETE CASCADE,
     FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
 );
+CREATE TABLE storage_pools_luns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    storage_pool_id INTEGER NOT NULL,
+    wwid TEXT NOT NULL,
+    FOREIGN KEY (storage_pool_id) REFERENCES "storage_pools" (id) ON DELETE CASCADE,
+    UNIQUE (storage_pool_id, wwid)
+);
 CREATE TABLE "storage_pools_nodes" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     storage_pool_id INTEGER NOT NULL,
@@ -555,7 +566,7 @@ CREATE TABLE "storage_volumes_backups" (
     creation_date DATETIME,
     expiry_date DATETIME,
     volume_only INTEGER NOT NULL default 0,
-    optimized_storage INTEGER NOT NULL default 0,
+    optimized_storage INTEGER NOT NULL default 0, target TEXT NOT NULL DEFAULT "",
     FOREIGN KEY (storage_volume_id) REFERENCES "storage_volumes" (id) ON DELETE CASCADE,
     UNIQUE (storage_volume_id, name)
 );
@@ -621,5 +632,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (69, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (73, strftime("%s"))
 `
@@ -106,6 +106,66 @@ var updates = map[int]schema.Update{
 	67: updateFromV66,
 	68: updateFromV67,
 	69: updateFromV68,
+	70: updateFromV69,
+	71: updateFromV70,
+	72: updateFromV71,
+	73: updateFromV72,
+}
+
+// updateFromV72 adds the storage_encryption_keys table.
+func updateFromV72(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+CREATE TABLE storage_encryption_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    key TEXT NOT NULL
+);
+`)
+	if err != nil {
+		return fmt.Errorf("Failed creating storage_encryption_keys table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV71 adds the storage_pools_luns table.
+func updateFromV71(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+CREATE TABLE storage_pools_luns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    storage_pool_id INTEGER NOT NULL,
+    wwid TEXT NOT NULL,
+    FOREIGN KEY (storage_pool_id) REFERENCES "storage_pools" (id) ON DELETE CASCADE,
+    UNIQUE (storage_pool_id, wwid)
+);
+`)
+	if err != nil {
+		return fmt.Errorf("Failed creating storage_pools_luns table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV70 adds the target column to instances_backups and storage_volumes_backups.
+func updateFromV70(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+ALTER TABLE instances_backups ADD COLUMN target TEXT NOT NULL DEFAULT "";
+ALTER TABLE storage_volumes_backups ADD COLUMN target TEXT NOT NULL DEFAULT "";
+`)
+	if err != nil {
+		return fmt.Errorf("Failed adding target column to backups tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV69 adds the incremental_from column to instances_backups.
+func updateFromV69(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE instances_backups ADD COLUMN incremental_from VARCHAR(255) NOT NULL DEFAULT "";`)
+	if err != nil {
+		return fmt.Errorf("Failed adding incremental_from column to instances_backups table: %w", err)
+	}
+
+	return nil
 }
 
 // updateFromV68 fixes unique index for record name to make it zone specific.
@@ -20,6 +20,7 @@ import (
 	"github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/node"
 	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/metrics"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/logger"
 )
@@ -365,6 +366,11 @@ func (c *Cluster) transaction(ctx context.Context, f func(context.Context, *Clus
 		nodeID: c.nodeID,
 	}
 
+	start := time.Now()
+	defer func() {
+		metrics.DatabaseTransactionDuration.Observe(nil, time.Since(start).Seconds())
+	}()
+
 	return c.retry(func() error {
 		txFunc := func(ctx context.Context, tx *sql.Tx) error {
 			clusterTx.tx = tx
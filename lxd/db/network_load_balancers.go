@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 )
 
@@ -388,6 +389,66 @@ func (c *ClusterTx) GetProjectNetworkLoadBalancerListenAddressesOnMember(ctx con
 	return loadBalancers, nil
 }
 
+// GetNetworksWithLoadBalancerHealthChecks returns the names of the networks that have load balancers with backend
+// health checks configured, keyed on project name. If memberSpecific is true, then only load balancers that belong
+// to this member are considered, otherwise only load balancers that do not have a specific member are considered.
+func (c *ClusterTx) GetNetworksWithLoadBalancerHealthChecks(ctx context.Context, memberSpecific bool) (map[string][]string, error) {
+	q := `
+	SELECT
+		projects.name,
+		networks.name,
+		networks_load_balancers.backends
+	FROM networks_load_balancers
+	JOIN networks on networks.id = networks_load_balancers.network_id
+	JOIN projects ON projects.id = networks.project_id
+	`
+
+	var args []any
+	if memberSpecific {
+		q += "WHERE networks_load_balancers.node_id = ?"
+		args = append(args, c.nodeID)
+	} else {
+		q += "WHERE networks_load_balancers.node_id IS NULL"
+	}
+
+	networks := make(map[string][]string)
+
+	err := query.Scan(ctx, c.Tx(), q, func(scan func(dest ...any) error) error {
+		var projectName string
+		var networkName string
+		var backendsJSON string
+
+		err := scan(&projectName, &networkName, &backendsJSON)
+		if err != nil {
+			return err
+		}
+
+		if shared.ValueInSlice(networkName, networks[projectName]) || backendsJSON == "" {
+			return nil
+		}
+
+		var backends []api.NetworkLoadBalancerBackend
+		err = json.Unmarshal([]byte(backendsJSON), &backends)
+		if err != nil {
+			return fmt.Errorf("Failed unmarshalling backends: %w", err)
+		}
+
+		for _, backend := range backends {
+			if backend.HealthCheck != "" {
+				networks[projectName] = append(networks[projectName], networkName)
+				break
+			}
+		}
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return networks, nil
+}
+
 // GetNetworkLoadBalancers returns map of Network Load Balancers for the given network ID keyed on Load Balancer ID.
 // If memberSpecific is true, then the search is restricted to load balancers that belong to this member or belong
 // to all members. Can optionally retrieve only specific network load balancers by listen address.
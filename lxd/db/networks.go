@@ -286,6 +286,32 @@ WHERE networks.id = ? AND networks.state = ?
 	return configs, nil
 }
 
+// GetNetworkNodeConfigValues returns the value of the given member-specific config key for the given networkID,
+// keyed by the ID of each cluster member that has it set.
+func (c *ClusterTx) GetNetworkNodeConfigValues(ctx context.Context, networkID int64, key string) (map[int64]string, error) {
+	q := "SELECT node_id, value FROM networks_config WHERE network_id=? AND key=? AND node_id IS NOT NULL"
+
+	values := map[int64]string{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var nodeID int64
+		var value string
+
+		err := scan(&nodeID, &value)
+		if err != nil {
+			return err
+		}
+
+		values[nodeID] = value
+
+		return nil
+	}, networkID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
 // CreatePendingNetwork creates a new pending network on the node with the given name.
 func (c *ClusterTx) CreatePendingNetwork(ctx context.Context, node string, projectName string, name string, netType NetworkType, conf map[string]string) error {
 	// First check if a network with the given name exists, and, if so, that it's in the pending state.
@@ -543,11 +569,12 @@ type NetworkType int
 
 // Network types.
 const (
-	NetworkTypeBridge   NetworkType = iota // Network type bridge.
-	NetworkTypeMacvlan                     // Network type macvlan.
-	NetworkTypeSriov                       // Network type sriov.
-	NetworkTypeOVN                         // Network type ovn.
-	NetworkTypePhysical                    // Network type physical.
+	NetworkTypeBridge    NetworkType = iota // Network type bridge.
+	NetworkTypeMacvlan                      // Network type macvlan.
+	NetworkTypeSriov                        // Network type sriov.
+	NetworkTypeOVN                          // Network type ovn.
+	NetworkTypePhysical                     // Network type physical.
+	NetworkTypeWireguard                    // Network type wireguard.
 )
 
 // NetworkNode represents a network node.
@@ -689,6 +716,8 @@ func networkFillType(network *api.Network, netType NetworkType) {
 		network.Type = "ovn"
 	case NetworkTypePhysical:
 		network.Type = "physical"
+	case NetworkTypeWireguard:
+		network.Type = "wireguard"
 	default:
 		network.Type = "" // Unknown
 	}
@@ -911,4 +940,5 @@ var NodeSpecificNetworkConfig = []string{
 	"bgp.ipv6.nexthop",
 	"bridge.external_interfaces",
 	"parent",
+	"volatile.wireguard.public_key",
 }
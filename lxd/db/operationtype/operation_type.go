@@ -75,6 +75,9 @@ const (
 	RenewServerCertificate
 	RemoveExpiredTokens
 	ClusterHeal
+	InstancesPowerSchedule
+	InstancesReplicate
+	CustomVolumesReplicate
 )
 
 // Description return a human-readable description of the operation type.
@@ -198,6 +201,12 @@ func (t Type) Description() string {
 		return "Remove expired tokens"
 	case ClusterHeal:
 		return "Healing cluster"
+	case InstancesPowerSchedule:
+		return "Applying instance power schedules"
+	case InstancesReplicate:
+		return "Replicating instances"
+	case CustomVolumesReplicate:
+		return "Replicating custom volumes"
 	default:
 		return "Executing operation"
 	}
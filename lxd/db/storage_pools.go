@@ -79,6 +79,36 @@ func (c *ClusterTx) GetStoragePoolDriver(ctx context.Context, id int64) (string,
 	}
 }
 
+// CreateStoragePoolLUN records that the LUN with the given WWID holds volumes of the storage pool with the given
+// ID. Returns ErrAlreadyDefined if the LUN is already recorded for the pool.
+func (c *ClusterTx) CreateStoragePoolLUN(ctx context.Context, poolID int64, wwid string) error {
+	count, err := query.Count(ctx, c.tx, "storage_pools_luns", "storage_pool_id=? AND wwid=?", poolID, wwid)
+	if err != nil {
+		return err
+	}
+
+	if count != 0 {
+		return ErrAlreadyDefined
+	}
+
+	_, err = c.tx.ExecContext(ctx, "INSERT INTO storage_pools_luns (storage_pool_id, wwid) VALUES (?, ?)", poolID, wwid)
+	if err != nil {
+		return fmt.Errorf("Failed recording LUN %q: %w", wwid, err)
+	}
+
+	return nil
+}
+
+// DeleteStoragePoolLUN removes the record of the LUN with the given WWID from the storage pool with the given ID.
+func (c *ClusterTx) DeleteStoragePoolLUN(ctx context.Context, poolID int64, wwid string) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM storage_pools_luns WHERE storage_pool_id=? AND wwid=?", poolID, wwid)
+	if err != nil {
+		return fmt.Errorf("Failed removing LUN %q: %w", wwid, err)
+	}
+
+	return nil
+}
+
 // GetNonPendingStoragePoolsNamesToIDs returns a map associating each storage pool name to its ID.
 //
 // Pending storage pools are skipped.
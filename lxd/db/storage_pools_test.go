@@ -252,3 +252,70 @@ func TestCreateStoragePoolVolume_Snapshot(t *testing.T) {
 	n = cluster.GetNextStorageVolumeSnapshotIndex("p2", "v1", 1, "snap%d")
 	assert.Equal(t, n, 0)
 }
+
+// Test moving a volume to another storage pool.
+func TestMoveStoragePoolVolume(t *testing.T) {
+	cluster, cleanup := db.NewTestCluster(t)
+	defer cleanup()
+
+	poolID, err := cluster.CreateStoragePool("p1", "", "dir", nil)
+	require.NoError(t, err)
+
+	poolID1, err := cluster.CreateStoragePool("p2", "", "dir", nil)
+	require.NoError(t, err)
+
+	_, err = cluster.CreateStoragePoolVolume("default", "v1", "", db.StoragePoolVolumeTypeVM, poolID, map[string]string{"k": "v"}, db.StoragePoolVolumeContentTypeBlock, time.Now())
+	require.NoError(t, err)
+
+	err = cluster.MoveStoragePoolVolume("default", "v1", "moved", db.StoragePoolVolumeTypeVM, poolID, poolID1, map[string]string{"k": "v2"}, db.StoragePoolVolumeContentTypeBlock, time.Now())
+	require.NoError(t, err)
+
+	err = cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.GetStoragePoolVolume(ctx, poolID, "default", db.StoragePoolVolumeTypeVM, "v1", true)
+		assert.True(t, response.IsNotFoundError(err))
+
+		volume, err := tx.GetStoragePoolVolume(ctx, poolID1, "default", db.StoragePoolVolumeTypeVM, "v1", true)
+		require.NoError(t, err)
+		assert.Equal(t, "moved", volume.Description)
+		assert.Equal(t, map[string]string{"k": "v2"}, volume.Config)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Moving a volume that doesn't exist on the source pool fails without changes.
+	err = cluster.MoveStoragePoolVolume("default", "v1", "", db.StoragePoolVolumeTypeVM, poolID, poolID1, nil, db.StoragePoolVolumeContentTypeBlock, time.Now())
+	assert.True(t, response.IsNotFoundError(err))
+}
+
+// A LUN can only be recorded once per storage pool, until it's removed again.
+func TestCreateStoragePoolLUN(t *testing.T) {
+	cluster, cleanup := db.NewTestCluster(t)
+	defer cleanup()
+
+	poolID, err := cluster.CreateStoragePool("p1", "", "lun", nil)
+	require.NoError(t, err)
+
+	poolID1, err := cluster.CreateStoragePool("p2", "", "lun", nil)
+	require.NoError(t, err)
+
+	err = cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.CreateStoragePoolLUN(ctx, poolID, "naa.1")
+		require.NoError(t, err)
+
+		err = tx.CreateStoragePoolLUN(ctx, poolID, "naa.1")
+		assert.Equal(t, db.ErrAlreadyDefined, err)
+
+		err = tx.CreateStoragePoolLUN(ctx, poolID1, "naa.1")
+		require.NoError(t, err)
+
+		err = tx.DeleteStoragePoolLUN(ctx, poolID, "naa.1")
+		require.NoError(t, err)
+
+		err = tx.CreateStoragePoolLUN(ctx, poolID, "naa.1")
+		require.NoError(t, err)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -223,6 +223,25 @@ func (c *ClusterTx) GetExpiredStorageVolumeSnapshots(ctx context.Context, member
 	return snapshots, nil
 }
 
+// GetCustomVolumeSnapshotsCount returns the number of custom volume snapshots in the given project.
+func (c *ClusterTx) GetCustomVolumeSnapshotsCount(ctx context.Context, projectName string) (int, error) {
+	q := `
+	SELECT COUNT(*)
+	FROM storage_volumes_snapshots
+	JOIN storage_volumes ON storage_volumes_snapshots.storage_volume_id = storage_volumes.id
+	JOIN projects ON storage_volumes.project_id = projects.id
+	WHERE storage_volumes.type = ? AND projects.name = ?
+	`
+
+	var count int
+	err := c.tx.QueryRowContext(ctx, q, StoragePoolVolumeTypeCustom, projectName).Scan(&count)
+	if err != nil {
+		return -1, fmt.Errorf("Failed counting custom volume snapshots: %w", err)
+	}
+
+	return count, nil
+}
+
 // Updates the expiry date of a storage volume snapshot.
 func storageVolumeSnapshotExpiryDateUpdate(tx *sql.Tx, volumeID int64, expiryDate time.Time) error {
 	stmt := "UPDATE storage_volumes_snapshots SET expiry_date=? WHERE id=?"
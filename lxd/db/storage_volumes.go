@@ -468,51 +468,79 @@ func (c *Cluster) CreateStoragePoolVolume(projectName string, volumeName string,
 		return -1, fmt.Errorf("Volume name may not be a snapshot")
 	}
 
-	remoteDrivers := StorageRemoteDriverNames()
-
 	err := c.Transaction(context.TODO(), func(ctx context.Context, tx *ClusterTx) error {
-		driver, err := tx.GetStoragePoolDriver(ctx, poolID)
+		var err error
+		volumeID, err = tx.createStoragePoolVolume(ctx, projectName, volumeName, volumeDescription, volumeType, poolID, volumeConfig, contentType, creationDate)
+		return err
+	})
+	if err != nil {
+		volumeID = -1
+	}
+
+	return volumeID, err
+}
+
+// MoveStoragePoolVolume moves a storage volume without snapshots from one storage pool to another by replacing
+// its record on the source pool with a new one on the target pool in a single transaction.
+func (c *Cluster) MoveStoragePoolVolume(projectName string, volumeName string, volumeDescription string, volumeType int, srcPoolID int64, poolID int64, volumeConfig map[string]string, contentType int, creationDate time.Time) error {
+	if shared.IsSnapshot(volumeName) {
+		return fmt.Errorf("Volume name may not be a snapshot")
+	}
+
+	return c.Transaction(context.TODO(), func(ctx context.Context, tx *ClusterTx) error {
+		volume, err := tx.GetStoragePoolVolume(ctx, srcPoolID, projectName, volumeType, volumeName, true)
 		if err != nil {
 			return err
 		}
 
-		var result sql.Result
+		_, err = tx.tx.Exec("DELETE FROM storage_volumes WHERE id=?", volume.ID)
+		if err != nil {
+			return err
+		}
 
-		if shared.ValueInSlice(driver, remoteDrivers) {
-			result, err = tx.tx.Exec(`
+		_, err = tx.createStoragePoolVolume(ctx, projectName, volumeName, volumeDescription, volumeType, poolID, volumeConfig, contentType, creationDate)
+		return err
+	})
+}
+
+// createStoragePoolVolume inserts the record of a new storage volume attached to a given storage pool.
+func (c *ClusterTx) createStoragePoolVolume(ctx context.Context, projectName string, volumeName string, volumeDescription string, volumeType int, poolID int64, volumeConfig map[string]string, contentType int, creationDate time.Time) (int64, error) {
+	driver, err := c.GetStoragePoolDriver(ctx, poolID)
+	if err != nil {
+		return -1, err
+	}
+
+	var result sql.Result
+
+	if shared.ValueInSlice(driver, StorageRemoteDriverNames()) {
+		result, err = c.tx.Exec(`
 INSERT INTO storage_volumes (storage_pool_id, type, name, description, project_id, content_type, creation_date)
  VALUES (?, ?, ?, ?, (SELECT id FROM projects WHERE name = ?), ?, ?)
 `,
-				poolID, volumeType, volumeName, volumeDescription, projectName, contentType, creationDate)
-		} else {
-			result, err = tx.tx.Exec(`
+			poolID, volumeType, volumeName, volumeDescription, projectName, contentType, creationDate)
+	} else {
+		result, err = c.tx.Exec(`
 INSERT INTO storage_volumes (storage_pool_id, node_id, type, name, description, project_id, content_type, creation_date)
  VALUES (?, ?, ?, ?, ?, (SELECT id FROM projects WHERE name = ?), ?, ?)
 `,
-				poolID, c.nodeID, volumeType, volumeName, volumeDescription, projectName, contentType, creationDate)
-		}
-
-		if err != nil {
-			return err
-		}
+			poolID, c.nodeID, volumeType, volumeName, volumeDescription, projectName, contentType, creationDate)
+	}
 
-		volumeID, err = result.LastInsertId()
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return -1, err
+	}
 
-		err = storageVolumeConfigAdd(tx.tx, volumeID, volumeConfig, false)
-		if err != nil {
-			return fmt.Errorf("Failed inserting storage volume record configuration: %w", err)
-		}
+	volumeID, err := result.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
 
-		return nil
-	})
+	err = storageVolumeConfigAdd(c.tx, volumeID, volumeConfig, false)
 	if err != nil {
-		volumeID = -1
+		return -1, fmt.Errorf("Failed inserting storage volume record configuration: %w", err)
 	}
 
-	return volumeID, err
+	return volumeID, nil
 }
 
 // Return the ID of a storage volume on a given storage pool of a given storage
@@ -984,3 +1012,38 @@ func (c *ClusterTx) UpdateStorageVolumeNode(ctx context.Context, projectName str
 
 	return nil
 }
+
+// GetStorageEncryptionKey returns the key used to wrap the encryption keys of storage volumes.
+// Returns a not found error if no key has been stored yet.
+func (c *ClusterTx) GetStorageEncryptionKey(ctx context.Context) (string, error) {
+	keys, err := query.SelectStrings(ctx, c.tx, "SELECT key FROM storage_encryption_keys ORDER BY id LIMIT 1")
+	if err != nil {
+		return "", fmt.Errorf("Failed loading storage encryption key: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return "", api.StatusErrorf(http.StatusNotFound, "Storage encryption key not found")
+	}
+
+	return keys[0], nil
+}
+
+// CreateStorageEncryptionKey stores the key used to wrap the encryption keys of storage volumes.
+// Returns ErrAlreadyDefined if a key is already stored, as it must never change.
+func (c *ClusterTx) CreateStorageEncryptionKey(ctx context.Context, key string) error {
+	count, err := query.Count(ctx, c.tx, "storage_encryption_keys", "")
+	if err != nil {
+		return err
+	}
+
+	if count != 0 {
+		return ErrAlreadyDefined
+	}
+
+	_, err = c.tx.ExecContext(ctx, "INSERT INTO storage_encryption_keys (key) VALUES (?)", key)
+	if err != nil {
+		return fmt.Errorf("Failed storing storage encryption key: %w", err)
+	}
+
+	return nil
+}
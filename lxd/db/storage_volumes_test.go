@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/response"
 )
 
 // Addresses of all nodes with matching volume name are returned.
@@ -48,6 +49,25 @@ func TestGetStorageVolumeNodes(t *testing.T) {
 	}, nodes)
 }
 
+// The storage encryption key is stored once and never replaced.
+func TestCreateStorageEncryptionKey(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	_, err := tx.GetStorageEncryptionKey(context.Background())
+	assert.True(t, response.IsNotFoundError(err))
+
+	err = tx.CreateStorageEncryptionKey(context.Background(), "key1")
+	require.NoError(t, err)
+
+	err = tx.CreateStorageEncryptionKey(context.Background(), "key2")
+	assert.Equal(t, db.ErrAlreadyDefined, err)
+
+	key, err := tx.GetStorageEncryptionKey(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key1", key)
+}
+
 func addPool(t *testing.T, tx *db.ClusterTx, name string) int64 {
 	stmt := `
 INSERT INTO storage_pools(name, driver, description) VALUES (?, 'dir', '')
@@ -41,6 +41,8 @@ func NICType(s *state.State, deviceProjectName string, d deviceConfig.Device) (s
 				nicType = "ovn"
 			case "physical":
 				nicType = "physical"
+			case "wireguard":
+				nicType = "bridged"
 			default:
 				return "", fmt.Errorf("Unrecognised NIC network type for network %q", d["network"])
 			}
@@ -393,7 +393,8 @@ func fillFixedInstances(fixedInstances map[int64][]instance.Instance, inst insta
 // deviceTaskBalance is used to balance the CPU load across containers running on a host.
 // It first checks if CGroup support is available and returns if it isn't.
 // It then retrieves the effective CPU list (the CPUs that are guaranteed to be online) and isolates any isolated CPUs.
-// After that, it loads all instances of containers running on the node and iterates through them.
+// After that, it loads all instances running on the node and iterates through them.
+// Virtual machines only take part if their vCPUs are pinned (counting towards the CPU usage) or placed on NUMA nodes.
 //
 // For each container, it checks its CPU limits and determines whether it is pinned to specific CPUs or can use the load-balancing mechanism.
 // If it is pinned, the function adds it to the fixedInstances map with the CPU numbers it is pinned to.
@@ -408,6 +409,7 @@ func fillFixedInstances(fixedInstances map[int64][]instance.Instance, inst insta
 // For the load-balanced containers, it sorts the available CPUs based on their usage count and assigns them to containers
 // in ascending order until the required number of CPUs have been assigned.
 // Finally, the pinning map is used to set the new CPU pinning for each container, updating it to the new balanced state.
+// For virtual machines placed on NUMA nodes, the affinity of their vCPU threads is set instead.
 //
 // Overall, this function ensures that the CPU resources of the host are utilized effectively amongst all the containers running on it.
 func deviceTaskBalance(s *state.State) {
@@ -465,7 +467,7 @@ func deviceTaskBalance(s *state.State) {
 	}
 
 	// Iterate through the instances
-	instances, err := instance.LoadNodeAll(s, instancetype.Container)
+	instances, err := instance.LoadNodeAll(s, instancetype.Any)
 	if err != nil {
 		logger.Error("Problem loading instances list", logger.Ctx{"err": err})
 		return
@@ -488,8 +490,14 @@ func deviceTaskBalance(s *state.State) {
 		}
 	}
 
+	// When balancing by usage, the measured usage is used instead of the number of instances per CPU, and
+	// VMs with floating vCPUs are balanced too.
+	balanceByUsage := s.GlobalConfig.InstancesCPUBalancing() == "usage"
+
 	fixedInstances := map[int64][]instance.Instance{}
 	balancedInstances := map[instance.Instance]int{}
+	pinnedVMs := map[instance.Instance]struct{}{}
+	floatingVMs := []instance.Instance{}
 	for _, c := range instances {
 		conf := c.ExpandedConfig()
 		cpuNodes := conf["limits.cpu.nodes"]
@@ -508,7 +516,11 @@ func deviceTaskBalance(s *state.State) {
 
 		cpulimit, ok := conf["limits.cpu"]
 		if !ok || cpulimit == "" {
-			cpulimit = effectiveCpus
+			if c.Type() == instancetype.VM {
+				cpulimit = "1"
+			} else {
+				cpulimit = effectiveCpus
+			}
 		}
 
 		// Check that the container is running.
@@ -526,6 +538,10 @@ func deviceTaskBalance(s *state.State) {
 			count = min(count, len(cpus))
 			if len(numaCpus) > 0 {
 				fillFixedInstances(fixedInstances, c, cpus, numaCpus, count, true)
+			} else if c.Type() == instancetype.VM && !balanceByUsage {
+				// Floating vCPUs of VMs without NUMA placement are left to the host scheduler.
+				floatingVMs = append(floatingVMs, c)
+				continue
 			} else {
 				balancedInstances[c] = count
 			}
@@ -541,10 +557,77 @@ func deviceTaskBalance(s *state.State) {
 			}
 
 			fillFixedInstances(fixedInstances, c, cpus, containerCpus, len(containerCpus), false)
+
+			// VMs pin each vCPU thread on start, so they only count towards the CPU usage.
+			if c.Type() == instancetype.VM {
+				pinnedVMs[c] = struct{}{}
+			}
 		}
 	}
 
 	// Balance things
+	var pinning map[instance.Instance][]string
+	if balanceByUsage {
+		pinning = deviceTaskBalanceUsage(cpus, cpusTopology, fixedInstances, balancedInstances)
+	} else {
+		pinning = deviceTaskBalanceCount(cpus, fixedInstances, balancedInstances)
+
+		// Let the vCPUs of the VMs that were balanced by usage float over all CPUs again.
+		for _, inst := range deviceTaskBalanceUsageStop(floatingVMs) {
+			vm, ok := inst.(instance.VM)
+			if !ok {
+				continue
+			}
+
+			err := vm.SetAffinity(effectiveCpusSlice)
+			if err != nil {
+				logger.Error("balance: Unable to reset vCPU affinity", logger.Ctx{"name": inst.Name(), "err": err, "value": effectiveCpus})
+			}
+		}
+	}
+	// Set the new pinning
+	for ctn, set := range pinning {
+		// Confirm the container didn't just stop
+		if ctn.InitPID() <= 0 {
+			continue
+		}
+
+		sort.Strings(set)
+
+		if ctn.Type() == instancetype.VM {
+			_, ok := pinnedVMs[ctn]
+			if ok {
+				continue
+			}
+
+			vm, ok := ctn.(instance.VM)
+			if !ok {
+				continue
+			}
+
+			err := vm.SetAffinity(set)
+			if err != nil {
+				logger.Error("balance: Unable to set vCPU affinity", logger.Ctx{"name": ctn.Name(), "err": err, "value": strings.Join(set, ",")})
+			}
+
+			continue
+		}
+
+		cg, err := ctn.CGroup()
+		if err != nil {
+			logger.Error("balance: Unable to get cgroup struct", logger.Ctx{"name": ctn.Name(), "err": err, "value": strings.Join(set, ",")})
+			continue
+		}
+
+		err = cg.SetCpuset(strings.Join(set, ","))
+		if err != nil {
+			logger.Error("balance: Unable to set cpuset", logger.Ctx{"name": ctn.Name(), "err": err, "value": strings.Join(set, ",")})
+		}
+	}
+}
+
+// deviceTaskBalanceCount balances the instances across the CPUs based on the number of instances using each CPU.
+func deviceTaskBalanceCount(cpus []int64, fixedInstances map[int64][]instance.Instance, balancedInstances map[instance.Instance]int) map[instance.Instance][]string {
 	pinning := map[instance.Instance][]string{}
 	usage := map[int64]deviceTaskCPU{}
 
@@ -602,25 +685,7 @@ func deviceTaskBalance(s *state.State) {
 		}
 	}
 
-	// Set the new pinning
-	for ctn, set := range pinning {
-		// Confirm the container didn't just stop
-		if ctn.InitPID() <= 0 {
-			continue
-		}
-
-		sort.Strings(set)
-		cg, err := ctn.CGroup()
-		if err != nil {
-			logger.Error("balance: Unable to get cgroup struct", logger.Ctx{"name": ctn.Name(), "err": err, "value": strings.Join(set, ",")})
-			continue
-		}
-
-		err = cg.SetCpuset(strings.Join(set, ","))
-		if err != nil {
-			logger.Error("balance: Unable to set cpuset", logger.Ctx{"name": ctn.Name(), "err": err, "value": strings.Join(set, ",")})
-		}
-	}
+	return pinning
 }
 
 // deviceEventListener starts the event listener for resource scheduling.
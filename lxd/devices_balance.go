@@ -0,0 +1,382 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/cgroup"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// deviceTaskBalanceInterval is the minimum time between two reassignments of the CPUs of an instance when
+// balancing by usage.
+const deviceTaskBalanceInterval = 5 * time.Minute
+
+// deviceTaskBalanceSiblingWeight is how much the load of the SMT siblings of a CPU counts towards its load.
+const deviceTaskBalanceSiblingWeight = 0.5
+
+// deviceTaskBalanceInstance tracks the CPU usage and the CPU assignment of an instance when balancing by usage.
+type deviceTaskBalanceInstance struct {
+	usage    int64     // Total CPU time used in ns at the time of the last sample.
+	sampled  time.Time // Time of the last sample.
+	load     float64   // Number of CPUs worth of usage between the last two samples (-1 if unknown).
+	cpus     []int64   // CPUs the instance is currently assigned to.
+	assigned time.Time // Time of the last reassignment.
+}
+
+// deviceTaskBalanceInstances holds the usage balancing state indexed by project prefixed instance name.
+var deviceTaskBalanceInstances = map[string]*deviceTaskBalanceInstance{}
+var deviceTaskBalanceInstancesMu sync.Mutex
+
+// deviceTaskBalanceCPUUsage returns the total CPU time in ns used by an instance.
+// For containers this is taken from the cgroup stats, for VMs from the vCPU threads.
+func deviceTaskBalanceCPUUsage(inst instance.Instance) (int64, error) {
+	if inst.Type() == instancetype.VM {
+		vm, ok := inst.(instance.VM)
+		if !ok {
+			return -1, fmt.Errorf("Instance is not a VM")
+		}
+
+		return vm.VCPUUsage()
+	}
+
+	cg, err := inst.CGroup()
+	if err != nil {
+		return -1, err
+	}
+
+	stats, err := cg.GetCPUAcctUsageAll()
+	if err != nil {
+		return -1, err
+	}
+
+	usage := int64(0)
+	for _, cpuStats := range stats {
+		usage += cpuStats.User + cpuStats.System
+	}
+
+	return usage, nil
+}
+
+// deviceTaskBalanceSample records the current CPU usage of the instance and updates its load, which is the number
+// of CPUs worth of usage since the previous sample (-1 if not known yet).
+func deviceTaskBalanceSample(inst instance.Instance, now time.Time) *deviceTaskBalanceInstance {
+	name := project.Instance(inst.Project().Name, inst.Name())
+
+	state, ok := deviceTaskBalanceInstances[name]
+	if !ok {
+		state = &deviceTaskBalanceInstance{load: -1}
+		deviceTaskBalanceInstances[name] = state
+	}
+
+	usage, err := deviceTaskBalanceCPUUsage(inst)
+	if err != nil {
+		logger.Debug("balance: Unable to get CPU usage", logger.Ctx{"name": inst.Name(), "project": inst.Project().Name, "err": err})
+		return state
+	}
+
+	elapsed := now.Sub(state.sampled)
+	if !state.sampled.IsZero() && elapsed > 0 && usage >= state.usage {
+		state.load = float64(usage-state.usage) / float64(elapsed.Nanoseconds())
+	}
+
+	state.usage = usage
+	state.sampled = now
+
+	return state
+}
+
+// deviceTaskBalancePick returns the count CPUs with the lowest load, considering the load of their SMT siblings.
+// CPUs of a single NUMA node are preferred if one has enough CPUs, picking the node with the lowest resulting load.
+func deviceTaskBalancePick(cpuLoad map[int64]float64, siblings map[int64][]int64, numaNodes map[int64][]int64, count int) []int64 {
+	score := func(cpu int64) float64 {
+		load := cpuLoad[cpu]
+		for _, sibling := range siblings[cpu] {
+			load += deviceTaskBalanceSiblingWeight * cpuLoad[sibling]
+		}
+
+		return load
+	}
+
+	// pick returns the count least loaded CPUs of the pool and their total score.
+	pick := func(pool []int64) ([]int64, float64) {
+		sorted := append([]int64{}, pool...)
+		sort.Slice(sorted, func(i, j int) bool {
+			scoreI := score(sorted[i])
+			scoreJ := score(sorted[j])
+			if scoreI != scoreJ {
+				return scoreI < scoreJ
+			}
+
+			return sorted[i] < sorted[j]
+		})
+
+		if count < len(sorted) {
+			sorted = sorted[:count]
+		}
+
+		total := 0.0
+		for _, cpu := range sorted {
+			total += score(cpu)
+		}
+
+		return sorted, total
+	}
+
+	// Try to fit the instance in a single NUMA node.
+	nodeIDs := make([]int64, 0, len(numaNodes))
+	for node := range numaNodes {
+		nodeIDs = append(nodeIDs, node)
+	}
+
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+
+	var best []int64
+	bestScore := 0.0
+	for _, node := range nodeIDs {
+		if len(numaNodes[node]) < count {
+			continue
+		}
+
+		picked, total := pick(numaNodes[node])
+		if best == nil || total < bestScore {
+			best = picked
+			bestScore = total
+		}
+	}
+
+	if best != nil {
+		return best
+	}
+
+	// Spread over all CPUs otherwise.
+	all := make([]int64, 0, len(cpuLoad))
+	for cpu := range cpuLoad {
+		all = append(all, cpu)
+	}
+
+	picked, _ := pick(all)
+
+	return picked
+}
+
+// deviceTaskBalanceUsage balances the instances across the CPUs based on their measured CPU usage rather than
+// the number of instances using each CPU. The fixed instances are accounted for but not moved. The balanced
+// instances are placed from the busiest to the least busy, and an instance isn't moved again until
+// deviceTaskBalanceInterval has passed since its last reassignment.
+func deviceTaskBalanceUsage(cpus []int64, cpusTopology *api.ResourcesCPU, fixedInstances map[int64][]instance.Instance, balancedInstances map[instance.Instance]int) map[instance.Instance][]string {
+	now := time.Now()
+
+	deviceTaskBalanceInstancesMu.Lock()
+	defer deviceTaskBalanceInstancesMu.Unlock()
+
+	// Build the SMT siblings and NUMA nodes of the available CPUs.
+	cpuLoad := make(map[int64]float64, len(cpus))
+	for _, cpu := range cpus {
+		cpuLoad[cpu] = 0
+	}
+
+	siblings := map[int64][]int64{}
+	numaNodes := map[int64][]int64{}
+	for _, socket := range cpusTopology.Sockets {
+		for _, core := range socket.Cores {
+			for _, thread := range core.Threads {
+				_, ok := cpuLoad[thread.ID]
+				if !ok {
+					continue
+				}
+
+				numaNodes[int64(thread.NUMANode)] = append(numaNodes[int64(thread.NUMANode)], thread.ID)
+
+				for _, sibling := range core.Threads {
+					if sibling.ID == thread.ID {
+						continue
+					}
+
+					_, ok := cpuLoad[sibling.ID]
+					if ok {
+						siblings[thread.ID] = append(siblings[thread.ID], sibling.ID)
+					}
+				}
+			}
+		}
+	}
+
+	seen := map[string]struct{}{}
+	pinning := map[instance.Instance][]string{}
+
+	// Account for the load of the fixed instances.
+	fixedCPUs := map[instance.Instance][]int64{}
+	for cpu, insts := range fixedInstances {
+		for _, inst := range insts {
+			fixedCPUs[inst] = append(fixedCPUs[inst], cpu)
+		}
+	}
+
+	for inst, instCPUs := range fixedCPUs {
+		seen[project.Instance(inst.Project().Name, inst.Name())] = struct{}{}
+
+		state := deviceTaskBalanceSample(inst, now)
+		load := state.load
+		if load < 0 {
+			load = float64(len(instCPUs))
+		}
+
+		for _, cpu := range instCPUs {
+			_, ok := cpuLoad[cpu]
+			if !ok {
+				logger.Errorf("Internal error: container using unavailable cpu")
+				continue
+			}
+
+			cpuLoad[cpu] += load / float64(len(instCPUs))
+			pinning[inst] = append(pinning[inst], fmt.Sprintf("%d", cpu))
+		}
+	}
+
+	// Sort the balanced instances from the busiest to the least busy.
+	type balancedInstance struct {
+		inst  instance.Instance
+		count int
+		state *deviceTaskBalanceInstance
+		load  float64
+	}
+
+	balanced := make([]balancedInstance, 0, len(balancedInstances))
+	for inst, count := range balancedInstances {
+		seen[project.Instance(inst.Project().Name, inst.Name())] = struct{}{}
+
+		state := deviceTaskBalanceSample(inst, now)
+		load := state.load
+		if load < 0 {
+			load = float64(count)
+		}
+
+		balanced = append(balanced, balancedInstance{inst: inst, count: count, state: state, load: load})
+	}
+
+	sort.Slice(balanced, func(i, j int) bool {
+		if balanced[i].load != balanced[j].load {
+			return balanced[i].load > balanced[j].load
+		}
+
+		return project.Instance(balanced[i].inst.Project().Name, balanced[i].inst.Name()) < project.Instance(balanced[j].inst.Project().Name, balanced[j].inst.Name())
+	})
+
+	// Keep the instances that were reassigned recently where they are.
+	keep := func(b balancedInstance) bool {
+		if len(b.state.cpus) != b.count || now.Sub(b.state.assigned) >= deviceTaskBalanceInterval {
+			return false
+		}
+
+		for _, cpu := range b.state.cpus {
+			_, ok := cpuLoad[cpu]
+			if !ok {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	toPlace := make([]balancedInstance, 0, len(balanced))
+	for _, b := range balanced {
+		if !keep(b) {
+			toPlace = append(toPlace, b)
+			continue
+		}
+
+		for _, cpu := range b.state.cpus {
+			cpuLoad[cpu] += b.load / float64(b.count)
+			pinning[b.inst] = append(pinning[b.inst], fmt.Sprintf("%d", cpu))
+		}
+	}
+
+	// Place the remaining instances on the least loaded CPUs.
+	for _, b := range toPlace {
+		picked := deviceTaskBalancePick(cpuLoad, siblings, numaNodes, b.count)
+		sort.Slice(picked, func(i, j int) bool { return picked[i] < picked[j] })
+
+		for _, cpu := range picked {
+			cpuLoad[cpu] += b.load / float64(b.count)
+			pinning[b.inst] = append(pinning[b.inst], fmt.Sprintf("%d", cpu))
+		}
+
+		if !deviceTaskBalanceSameCPUs(b.state.cpus, picked) {
+			b.state.cpus = picked
+			b.state.assigned = now
+		}
+	}
+
+	// Forget about the instances that are gone.
+	for name := range deviceTaskBalanceInstances {
+		_, ok := seen[name]
+		if !ok {
+			delete(deviceTaskBalanceInstances, name)
+		}
+	}
+
+	return pinning
+}
+
+// deviceTaskBalanceUsageStop forgets the usage balancing state once balancing by count again, and returns the
+// floating VMs whose vCPUs were balanced by usage, so that their affinity can be reset.
+func deviceTaskBalanceUsageStop(floatingVMs []instance.Instance) []instance.Instance {
+	deviceTaskBalanceInstancesMu.Lock()
+	defer deviceTaskBalanceInstancesMu.Unlock()
+
+	if len(deviceTaskBalanceInstances) == 0 {
+		return nil
+	}
+
+	var reset []instance.Instance
+	for _, inst := range floatingVMs {
+		state, ok := deviceTaskBalanceInstances[project.Instance(inst.Project().Name, inst.Name())]
+		if ok && len(state.cpus) > 0 {
+			reset = append(reset, inst)
+		}
+	}
+
+	deviceTaskBalanceInstances = map[string]*deviceTaskBalanceInstance{}
+
+	return reset
+}
+
+// deviceTaskBalanceSameCPUs returns whether both sorted CPU lists are identical.
+func deviceTaskBalanceSameCPUs(a []int64, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// deviceTaskBalanceTask periodically triggers a CPU rebalance when balancing by usage, as the usage of the
+// instances changes without any instance starting or stopping.
+func deviceTaskBalanceTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		if s.GlobalConfig.InstancesCPUBalancing() != "usage" {
+			return
+		}
+
+		cgroup.TaskSchedulerTrigger("daemon", "cpu_balancing", "usage")
+	}
+
+	return f, task.Every(time.Minute)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// testBalanceVM is a running VM with a given vCPU usage.
+type testBalanceVM struct {
+	instance.VM
+
+	name  string
+	usage int64
+}
+
+func (vm *testBalanceVM) Name() string {
+	return vm.name
+}
+
+func (vm *testBalanceVM) Project() api.Project {
+	return api.Project{Name: api.ProjectDefaultName}
+}
+
+func (vm *testBalanceVM) Type() instancetype.Type {
+	return instancetype.VM
+}
+
+func (vm *testBalanceVM) VCPUUsage() (int64, error) {
+	return vm.usage, nil
+}
+
+func TestDeviceTaskBalancePick(t *testing.T) {
+	// Two NUMA nodes with two cores of two threads each.
+	siblings := map[int64][]int64{0: {1}, 1: {0}, 2: {3}, 3: {2}, 4: {5}, 5: {4}, 6: {7}, 7: {6}}
+	numaNodes := map[int64][]int64{0: {0, 1, 2, 3}, 1: {4, 5, 6, 7}}
+
+	// Idle host, the lowest CPUs of the first node are used.
+	cpuLoad := map[int64]float64{0: 0, 1: 0, 2: 0, 3: 0, 4: 0, 5: 0, 6: 0, 7: 0}
+	assert.ElementsMatch(t, []int64{0, 1}, deviceTaskBalancePick(cpuLoad, siblings, numaNodes, 2))
+
+	// A busy CPU pushes away from its sibling.
+	cpuLoad[0] = 1
+	assert.Equal(t, []int64{2}, deviceTaskBalancePick(cpuLoad, siblings, numaNodes, 1))
+
+	// The least loaded NUMA node is used.
+	cpuLoad = map[int64]float64{0: 1, 1: 1, 2: 0.5, 3: 0, 4: 0.1, 5: 0.1, 6: 0.1, 7: 0.1}
+	assert.ElementsMatch(t, []int64{4, 5, 6}, deviceTaskBalancePick(cpuLoad, siblings, numaNodes, 3))
+
+	// Instances larger than a NUMA node are spread over all CPUs.
+	assert.Len(t, deviceTaskBalancePick(cpuLoad, siblings, numaNodes, 6), 6)
+	assert.NotContains(t, deviceTaskBalancePick(cpuLoad, siblings, numaNodes, 6), int64(0))
+}
+
+func TestDeviceTaskBalanceUsage(t *testing.T) {
+	deviceTaskBalanceInstances = map[string]*deviceTaskBalanceInstance{}
+
+	// Two CPUs on separate cores of a single NUMA node.
+	topology := &api.ResourcesCPU{Sockets: []api.ResourcesCPUSocket{{Cores: []api.ResourcesCPUCore{
+		{Threads: []api.ResourcesCPUThread{{ID: 0}}},
+		{Threads: []api.ResourcesCPUThread{{ID: 1}}},
+	}}}}
+
+	vm := &testBalanceVM{name: "vm1"}
+	busy := &testBalanceVM{name: "busy"}
+
+	// A new instance goes to the lowest of the idle CPUs.
+	pinning := deviceTaskBalanceUsage([]int64{0, 1}, topology, nil, map[instance.Instance]int{vm: 1})
+	assert.Equal(t, []string{"0"}, pinning[vm])
+
+	state := deviceTaskBalanceInstances[project.Instance(api.ProjectDefaultName, "vm1")]
+	require.NotNil(t, state)
+	assert.Equal(t, []int64{0}, state.cpus)
+	assigned := state.assigned
+
+	// A recently reassigned instance isn't moved, even if its CPU gets busy.
+	busy.usage += int64(10 * time.Second)
+	pinning = deviceTaskBalanceUsage([]int64{0, 1}, topology, map[int64][]instance.Instance{0: {busy}}, map[instance.Instance]int{vm: 1})
+	assert.Equal(t, []string{"0"}, pinning[vm])
+	assert.Equal(t, []string{"0"}, pinning[busy])
+	assert.Equal(t, assigned, state.assigned)
+
+	// Once the interval has passed, it moves away from the busy CPU.
+	state.assigned = time.Now().Add(-deviceTaskBalanceInterval)
+	busy.usage += int64(10 * time.Second)
+	pinning = deviceTaskBalanceUsage([]int64{0, 1}, topology, map[int64][]instance.Instance{0: {busy}}, map[instance.Instance]int{vm: 1})
+	assert.Equal(t, []string{"1"}, pinning[vm])
+	assert.Equal(t, []int64{1}, state.cpus)
+	assert.True(t, state.assigned.After(assigned))
+
+	// It's moved right away if its CPU becomes unavailable, and the instances that are gone are forgotten.
+	pinning = deviceTaskBalanceUsage([]int64{0}, topology, nil, map[instance.Instance]int{vm: 1})
+	assert.Equal(t, []string{"0"}, pinning[vm])
+	assert.NotContains(t, deviceTaskBalanceInstances, project.Instance(api.ProjectDefaultName, "busy"))
+
+	// It's moved right away if its number of CPUs changes.
+	pinning = deviceTaskBalanceUsage([]int64{0, 1}, topology, nil, map[instance.Instance]int{vm: 2})
+	assert.Equal(t, []string{"0", "1"}, pinning[vm])
+	assert.Equal(t, []int64{0, 1}, state.cpus)
+}
+
+func TestDeviceTaskBalanceUsageStop(t *testing.T) {
+	vm := &testBalanceVM{name: "vm1"}
+	other := &testBalanceVM{name: "vm2"}
+
+	deviceTaskBalanceInstances = map[string]*deviceTaskBalanceInstance{
+		project.Instance(api.ProjectDefaultName, "vm1"): {cpus: []int64{0}},
+		project.Instance(api.ProjectDefaultName, "c1"):  {cpus: []int64{1}},
+	}
+
+	// Only the VMs that were balanced by usage need their affinity reset.
+	assert.Equal(t, []instance.Instance{vm}, deviceTaskBalanceUsageStop([]instance.Instance{vm, other}))
+	assert.Empty(t, deviceTaskBalanceInstances)
+
+	assert.Nil(t, deviceTaskBalanceUsageStop([]instance.Instance{vm, other}))
+}
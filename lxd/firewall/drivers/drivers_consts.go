@@ -32,6 +32,7 @@ type ACLRule struct {
 	Action          string
 	Log             bool   // Whether or not to log matched packets.
 	LogName         string // Log label name (requires Log be true).
+	Name            string // Rule name used to report hit counters (optional).
 	Source          string
 	Destination     string
 	Protocol        string
@@ -41,6 +42,12 @@ type ACLRule struct {
 	ICMPCode        string
 }
 
+// ACLRuleCounters represents the hit counters of an ACL rule.
+type ACLRuleCounters struct {
+	Packets uint64
+	Bytes   uint64
+}
+
 // AddressForward represents a NAT address forward.
 type AddressForward struct {
 	ListenAddress net.IP
@@ -49,3 +56,18 @@ type AddressForward struct {
 	ListenPorts   []uint64
 	TargetPorts   []uint64
 }
+
+// AddressLoadBalancer represents a NAT load balancer spreading new connections randomly over its targets.
+type AddressLoadBalancer struct {
+	ListenAddress net.IP
+	Protocol      string
+	ListenPorts   []uint64
+	Targets       []AddressLoadBalancerTarget
+}
+
+// AddressLoadBalancerTarget represents a target of a NAT load balancer.
+// If no ports are set, the listen ports are used.
+type AddressLoadBalancerTarget struct {
+	Address net.IP
+	Ports   []uint64
+}
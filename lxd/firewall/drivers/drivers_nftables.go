@@ -372,6 +372,7 @@ func (d Nftables) NetworkClear(networkName string, _ bool, _ []uint) error {
 		"fwd", "pstrt", "in", "out", // Chains used for network operation rules.
 		"aclin", "aclout", "aclfwd", "acl", // Chains used by ACL rules.
 		"fwdprert", "fwdout", "fwdpstrt", // Chains used by Address Forward rules.
+		"lbprert", "lbout", "lbpstrt", // Chains used by Load Balancer rules.
 		"egress", // Chains added for limits.priority option
 	}
 
@@ -855,6 +856,11 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 		}
 	}
 
+	// Handle hit counters.
+	if rule.Name != "" {
+		args = append(args, "counter")
+	}
+
 	// Handle logging.
 	if rule.Log {
 		args = append(args, "log")
@@ -873,9 +879,63 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 
 	args = append(args, action)
 
+	// Add the rule name as a comment so its counters can be found later.
+	if rule.Name != "" {
+		args = append(args, "comment", fmt.Sprintf(`"%s"`, rule.Name))
+	}
+
 	return strings.Join(args, " "), isPartialRule, nil
 }
 
+// NetworkACLRuleCounters returns the hit counters of the named ACL rules applied to the network.
+// The counters are keyed by rule name and summed when a rule was split into several nftables rules.
+func (d Nftables) NetworkACLRuleCounters(networkName string) (map[string]ACLRuleCounters, error) {
+	chain := fmt.Sprintf("acl%s%s", nftablesChainSeparator, networkName)
+
+	// Dump ACL chain as JSON. Use -nn flags to avoid doing DNS lookups of IPs mentioned in any rules.
+	output, err := shared.RunCommandCLocale("nft", "--json", "-nn", "list", "chain", "inet", nftablesNamespace, chain)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing ACL chain %q: %w", chain, err)
+	}
+
+	// This only extracts the rule comments and counters, see man libnftables-json for more info.
+	v := &struct {
+		Nftables []struct {
+			Rule *struct {
+				Comment string `json:"comment"`
+				Expr    []struct {
+					Counter *ACLRuleCounters `json:"counter"`
+				} `json:"expr"`
+			} `json:"rule"`
+		} `json:"nftables"`
+	}{}
+
+	err = json.Unmarshal([]byte(output), v)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing ACL chain %q: %w", chain, err)
+	}
+
+	counters := make(map[string]ACLRuleCounters)
+	for _, item := range v.Nftables {
+		if item.Rule == nil || item.Rule.Comment == "" {
+			continue
+		}
+
+		for _, expr := range item.Rule.Expr {
+			if expr.Counter == nil {
+				continue
+			}
+
+			ruleCounters := counters[item.Rule.Comment]
+			ruleCounters.Packets += expr.Counter.Packets
+			ruleCounters.Bytes += expr.Counter.Bytes
+			counters[item.Rule.Comment] = ruleCounters
+		}
+	}
+
+	return counters, nil
+}
+
 // aclRuleSubjectToACLMatch converts direction (source/destination) and subject criteria list into xtables args.
 // Returns nil if none of the subjects are appropriate for the ipVersion.
 func (d Nftables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, subjectCriteria ...string) ([]string, bool, error) {
@@ -1091,3 +1151,123 @@ func (d Nftables) NetworkApplyForwards(networkName string, rules []AddressForwar
 
 	return nil
 }
+
+// NetworkApplyLoadBalancers apply network load balancer rules to firewall.
+func (d Nftables) NetworkApplyLoadBalancers(networkName string, rules []AddressLoadBalancer) error {
+	var dnatRules []map[string]any
+	var snatRules []map[string]any
+
+	for ruleIndex, rule := range rules {
+		// Validate the rule.
+		if rule.ListenAddress == nil {
+			return fmt.Errorf("Invalid rule %d, listen address is required", ruleIndex)
+		}
+
+		if rule.Protocol == "" || len(rule.ListenPorts) == 0 {
+			return fmt.Errorf("Invalid rule %d, protocol and listen port(s) are required", ruleIndex)
+		}
+
+		if len(rule.Targets) == 0 {
+			return fmt.Errorf("Invalid rule %d, at least one target is required", ruleIndex)
+		}
+
+		for targetIndex, target := range rule.Targets {
+			if target.Address == nil {
+				return fmt.Errorf("Invalid rule %d, target %d address is required", ruleIndex, targetIndex)
+			}
+
+			targetPortsLen := len(target.Ports)
+			if targetPortsLen > 1 && targetPortsLen != len(rule.ListenPorts) {
+				return fmt.Errorf("Invalid rule %d, mismatch between listen port(s) and target %d port(s) count", ruleIndex, targetIndex)
+			}
+		}
+
+		ipFamily := "ip"
+		if rule.ListenAddress.To4() == nil {
+			ipFamily = "ip6"
+		}
+
+		listenAddressStr := rule.ListenAddress.String()
+
+		for _, target := range rule.Targets {
+			targetPorts := target.Ports
+			if len(targetPorts) == 0 {
+				targetPorts = rule.ListenPorts
+			}
+
+			for _, targetPortRange := range portRangesFromSlice(targetPorts) {
+				snatRules = append(snatRules, map[string]any{
+					"ipFamily":    ipFamily,
+					"protocol":    rule.Protocol,
+					"targetHost":  target.Address.String(),
+					"targetPorts": portRangeStr(targetPortRange, "-"),
+				})
+			}
+		}
+
+		// Each target rule matches a new connection with a probability of 1/N where N is the number of
+		// targets remaining, so that connections are spread evenly across all targets.
+		for _, dnatRange := range getLoadBalancerDNATRanges(&rule) {
+			for targetIndex, target := range rule.Targets {
+				targetAddressStr := target.Address.String()
+				targetPortRange := dnatRange.targetPorts[targetIndex]
+
+				// Format the destination host/port as appropriate
+				targetDest := targetAddressStr
+				if targetPortRange[1] == 1 {
+					targetPortStr := portRangeStr(targetPortRange, ":")
+					targetDest = fmt.Sprintf("%s:%s", targetAddressStr, targetPortStr)
+					if ipFamily == "ip6" {
+						targetDest = fmt.Sprintf("[%s]:%s", targetAddressStr, targetPortStr)
+					}
+				}
+
+				dnatRule := map[string]any{
+					"ipFamily":      ipFamily,
+					"protocol":      rule.Protocol,
+					"listenAddress": listenAddressStr,
+					"listenPorts":   portRangeStr(dnatRange.listenPorts, "-"),
+					"targetDest":    targetDest,
+				}
+
+				remainingTargets := len(rule.Targets) - targetIndex
+				if remainingTargets > 1 {
+					dnatRule["numgenMod"] = remainingTargets
+				}
+
+				dnatRules = append(dnatRules, dnatRule)
+			}
+		}
+	}
+
+	tplFields := map[string]any{
+		"namespace":      nftablesNamespace,
+		"chainSeparator": nftablesChainSeparator,
+		"chainPrefix":    "lb", // Differentiate from network address forwards.
+		"family":         "inet",
+		"label":          networkName,
+		"dnatRules":      dnatRules,
+		"snatRules":      snatRules,
+	}
+
+	// Apply rules or remove chains if no rules generated.
+	if len(dnatRules) > 0 || len(snatRules) > 0 {
+		config := &strings.Builder{}
+		err := nftablesNetProxyNAT.Execute(config, tplFields)
+		if err != nil {
+			return fmt.Errorf("Failed running %q template: %w", nftablesNetProxyNAT.Name(), err)
+		}
+
+		err = shared.RunCommandWithFds(context.TODO(), strings.NewReader(config.String()), nil, "nft", "-f", "-")
+		if err != nil {
+			return err
+		}
+	} else {
+		err := d.removeChains([]string{"inet"}, networkName, "lbprert", "lbout", "lbpstrt")
+		if err != nil {
+			return fmt.Errorf("Failed clearing nftables load balancer rules for network %q: %w", networkName, err)
+		}
+	}
+
+	return nil
+}
@@ -111,6 +111,85 @@ func getOptimisedDNATRanges(forward *AddressForward) map[[2]uint64][2]uint64 {
 	return snatRules
 }
 
+// loadBalancerDNATRange is a listen port range of a load balancer along with the target port range of each
+// of its targets.
+type loadBalancerDNATRange struct {
+	listenPorts [2]uint64
+	targetPorts [][2]uint64
+}
+
+// getLoadBalancerDNATRanges returns the listen port ranges of a load balancer along with the corresponding
+// target port ranges of each of its targets.
+//
+// The optimised DNAT ranges of each target are combined, splitting the listen port ranges further where needed
+// so that each listen port range maps onto a single target port range for every target. This allows the rules
+// for all targets of a listen port range to be applied together.
+func getLoadBalancerDNATRanges(lb *AddressLoadBalancer) []loadBalancerDNATRange {
+	// Map each listen port to the optimised listen port range it belongs to, for each target.
+	targetRanges := make([]map[[2]uint64][2]uint64, 0, len(lb.Targets))
+	targetListenRanges := make([]map[uint64][2]uint64, 0, len(lb.Targets))
+	for _, target := range lb.Targets {
+		targetPorts := target.Ports
+		if len(targetPorts) == 0 {
+			targetPorts = lb.ListenPorts
+		}
+
+		dnatRanges := getOptimisedDNATRanges(&AddressForward{ListenPorts: lb.ListenPorts, TargetPorts: targetPorts})
+		listenRanges := make(map[uint64][2]uint64, len(lb.ListenPorts))
+		for listenPortRange := range dnatRanges {
+			for i := uint64(0); i < listenPortRange[1]; i++ {
+				listenRanges[listenPortRange[0]+i] = listenPortRange
+			}
+		}
+
+		targetRanges = append(targetRanges, dnatRanges)
+		targetListenRanges = append(targetListenRanges, listenRanges)
+	}
+
+	// Group consecutive listen ports that belong to the same optimised range for every target.
+	var groups [][2]uint64
+	for i, port := range lb.ListenPorts {
+		if i > 0 && port == lb.ListenPorts[i-1]+1 {
+			sameRanges := true
+			for _, listenRanges := range targetListenRanges {
+				if listenRanges[port] != listenRanges[port-1] {
+					sameRanges = false
+					break
+				}
+			}
+
+			if sameRanges {
+				groups[len(groups)-1][1]++
+				continue
+			}
+		}
+
+		groups = append(groups, [2]uint64{port, 1})
+	}
+
+	dnatRanges := make([]loadBalancerDNATRange, 0, len(groups))
+	for _, group := range groups {
+		dnatRange := loadBalancerDNATRange{
+			listenPorts: group,
+			targetPorts: make([][2]uint64, 0, len(lb.Targets)),
+		}
+
+		for i := range lb.Targets {
+			targetPortRange := targetRanges[i][targetListenRanges[i][group[0]]]
+			if targetPortRange[1] > 1 {
+				// Listen and target port ranges are identical, so the group maps onto itself.
+				targetPortRange = group
+			}
+
+			dnatRange.targetPorts = append(dnatRange.targetPorts, targetPortRange)
+		}
+
+		dnatRanges = append(dnatRanges, dnatRange)
+	}
+
+	return dnatRanges
+}
+
 // subnetMask returns the subnet mask of the given network as a string. Both IPv4 and IPv6 are handled.
 func subnetMask(ipNet *net.IPNet) string {
 	if ipNet.IP.To4() != nil {
@@ -145,3 +145,59 @@ func Test_getOptimisedSNATRanges(t *testing.T) {
 		assert.Equal(t, tt.expected, actual)
 	}
 }
+
+func Test_getLoadBalancerDNATRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		lb       *AddressLoadBalancer
+		expected []loadBalancerDNATRange
+	}{
+		{
+			name: "Same ports on all targets",
+			lb: &AddressLoadBalancer{
+				ListenPorts: []uint64{80, 81, 82},
+				Targets: []AddressLoadBalancerTarget{
+					{},
+					{Ports: []uint64{80, 81, 82}},
+				},
+			},
+			expected: []loadBalancerDNATRange{
+				{listenPorts: [2]uint64{80, 3}, targetPorts: [][2]uint64{{80, 3}, {80, 3}}},
+			},
+		},
+		{
+			name: "Single target port",
+			lb: &AddressLoadBalancer{
+				ListenPorts: []uint64{80, 81, 443},
+				Targets: []AddressLoadBalancerTarget{
+					{Ports: []uint64{8080}},
+					{},
+				},
+			},
+			expected: []loadBalancerDNATRange{
+				{listenPorts: [2]uint64{80, 2}, targetPorts: [][2]uint64{{8080, 1}, {80, 2}}},
+				{listenPorts: [2]uint64{443, 1}, targetPorts: [][2]uint64{{8080, 1}, {443, 1}}},
+			},
+		},
+		{
+			name: "Ranges split by other target",
+			lb: &AddressLoadBalancer{
+				ListenPorts: []uint64{80, 81, 82, 83},
+				Targets: []AddressLoadBalancerTarget{
+					{},
+					{Ports: []uint64{80, 81, 92, 93}},
+				},
+			},
+			expected: []loadBalancerDNATRange{
+				{listenPorts: [2]uint64{80, 2}, targetPorts: [][2]uint64{{80, 2}, {80, 2}}},
+				{listenPorts: [2]uint64{82, 1}, targetPorts: [][2]uint64{{82, 1}, {92, 1}}},
+				{listenPorts: [2]uint64{83, 1}, targetPorts: [][2]uint64{{83, 1}, {93, 1}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		actual := getLoadBalancerDNATRanges(tt.lb)
+		assert.Equal(t, tt.expected, actual, tt.name)
+	}
+}
@@ -167,6 +167,12 @@ func (d Xtables) networkForwardIPTablesComment(networkName string) string {
 	return fmt.Sprintf("LXD network-forward %s", networkName)
 }
 
+// networkLoadBalancerIPTablesComment returns the iptables comment that is added to each network load balancer
+// related rule.
+func (d Xtables) networkLoadBalancerIPTablesComment(networkName string) string {
+	return fmt.Sprintf("LXD network-load-balancer %s", networkName)
+}
+
 // networkSetupNICFilteringChain creates the NIC filtering chain if it doesn't exist, and adds the jump rules to
 // the INPUT and FORWARD filter chains. Must be called after networkSetupForwardingPolicy so that the rules are
 // prepended before the default fowarding policy rules.
@@ -681,7 +687,14 @@ func (d Xtables) aclRuleCriteriaToArgs(networkName string, ipVersion uint, rule
 		action = "accept"
 	}
 
-	actionArgs := append(args, "-j", strings.ToUpper(action))
+	actionArgs := append([]string{}, args...)
+
+	// Add the rule name as a comment to the action rule so its counters can be found later.
+	if rule.Name != "" {
+		actionArgs = append(actionArgs, "-m", "comment", "--comment", rule.Name)
+	}
+
+	actionArgs = append(actionArgs, "-j", strings.ToUpper(action))
 
 	// Handle logging.
 	var logArgs []string
@@ -697,6 +710,52 @@ func (d Xtables) aclRuleCriteriaToArgs(networkName string, ipVersion uint, rule
 	return actionArgs, logArgs, nil
 }
 
+// NetworkACLRuleCounters returns the hit counters of the named ACL rules applied to the network.
+// The counters are keyed by rule name and summed across the IPv4 and IPv6 rules.
+func (d Xtables) NetworkACLRuleCounters(networkName string) (map[string]ACLRuleCounters, error) {
+	chain := fmt.Sprintf("%s_%s", iptablesChainACLFilterPrefix, networkName)
+
+	counters := make(map[string]ACLRuleCounters)
+	for _, cmd := range []string{"iptables", "ip6tables"} {
+		// List the rules of the chain along with their packet and byte counters.
+		output, err := shared.RunCommand(cmd, "-w", "-t", "filter", "-S", chain, "-v")
+		if err != nil {
+			return nil, fmt.Errorf("Failed listing %q chain %q in table %q: %w", cmd, chain, "filter", err)
+		}
+
+		for _, line := range strings.Split(output, "\n") {
+			fields := strings.Fields(line)
+
+			var name string
+			var ruleCounters ACLRuleCounters
+			for i := 0; i < len(fields)-1; i++ {
+				switch fields[i] {
+				case "--comment":
+					name = strings.Trim(fields[i+1], `"`)
+				case "-c":
+					if i+2 >= len(fields) {
+						continue
+					}
+
+					ruleCounters.Packets, _ = strconv.ParseUint(fields[i+1], 10, 64)
+					ruleCounters.Bytes, _ = strconv.ParseUint(fields[i+2], 10, 64)
+				}
+			}
+
+			if name == "" {
+				continue
+			}
+
+			existing := counters[name]
+			existing.Packets += ruleCounters.Packets
+			existing.Bytes += ruleCounters.Bytes
+			counters[name] = existing
+		}
+	}
+
+	return counters, nil
+}
+
 // aclRuleSubjectToACLMatch converts direction (source/destination) and subject criteria list into xtables args.
 // Returns nil if none of the subjects are appropriate for the ipVersion.
 func (d Xtables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, subjectCriteria ...string) ([]string, error) {
@@ -755,10 +814,11 @@ func (d Xtables) NetworkClear(networkName string, delete bool, ipVersions []uint
 	comments := []string{
 		d.networkIPTablesComment(networkName),
 		d.networkForwardIPTablesComment(networkName),
+		d.networkLoadBalancerIPTablesComment(networkName),
 	}
 
 	for _, ipVersion := range ipVersions {
-		// Clear any rules associated to the network and network address forwards.
+		// Clear any rules associated to the network, network address forwards and load balancers.
 		err := d.iptablesClear(ipVersion, comments, "filter", "mangle", "nat")
 		if err != nil {
 			return err
@@ -1646,3 +1706,135 @@ func (d Xtables) NetworkApplyForwards(networkName string, rules []AddressForward
 	reverter.Success()
 	return nil
 }
+
+// NetworkApplyLoadBalancers apply network load balancer rules to firewall.
+func (d Xtables) NetworkApplyLoadBalancers(networkName string, rules []AddressLoadBalancer) error {
+	// Validate all rules first.
+	for i, rule := range rules {
+		if rule.ListenAddress == nil {
+			return fmt.Errorf("Invalid rule %d, listen address is required", i)
+		}
+
+		if rule.Protocol == "" || len(rule.ListenPorts) == 0 {
+			return fmt.Errorf("Invalid rule %d, protocol and listen port(s) are required", i)
+		}
+
+		if len(rule.Targets) == 0 {
+			return fmt.Errorf("Invalid rule %d, at least one target is required", i)
+		}
+
+		for targetIndex, target := range rule.Targets {
+			if target.Address == nil {
+				return fmt.Errorf("Invalid rule %d, target %d address is required", i, targetIndex)
+			}
+
+			targetPortLen := len(target.Ports)
+			if targetPortLen > 1 && targetPortLen != len(rule.ListenPorts) {
+				return fmt.Errorf("Invalid rule %d, mismatch between listen port(s) and target %d port(s) count", i, targetIndex)
+			}
+		}
+	}
+
+	comment := d.networkLoadBalancerIPTablesComment(networkName)
+
+	clearNetworkLoadBalancers := func() error {
+		for _, ipVersion := range []uint{4, 6} {
+			err := d.iptablesClear(ipVersion, []string{comment}, "nat")
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Clear any load balancer rules associated to the network.
+	err := clearNetworkLoadBalancers()
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Clear all network load balancers if we fail, otherwise they are only partially applied.
+	reverter.Add(func() {
+		err := clearNetworkLoadBalancers()
+		if err != nil {
+			logger.Error("Failed to clear firewall rules after failing to apply network load balancers", logger.Ctx{"network_name": networkName, "error": err})
+		}
+	})
+
+	for _, rule := range rules {
+		ipVersion := uint(4)
+		if rule.ListenAddress.To4() == nil {
+			ipVersion = 6
+		}
+
+		listenAddressStr := rule.ListenAddress.String()
+
+		for _, target := range rule.Targets {
+			targetPorts := target.Ports
+			if len(targetPorts) == 0 {
+				targetPorts = rule.ListenPorts
+			}
+
+			for _, targetPortRange := range portRangesFromSlice(targetPorts) {
+				targetAddressStr := target.Address.String()
+
+				// Apply MASQUERADE rule for each target range.
+				// instance <-> instance.
+				// Requires instance's bridge port has hairpin mode enabled when br_netfilter is loaded.
+				err := d.iptablesPrepend(ipVersion, comment, "nat", "POSTROUTING", "-p", rule.Protocol, "--source", targetAddressStr, "--destination", targetAddressStr, "--dport", portRangeStr(targetPortRange, ":"), "-j", "MASQUERADE")
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, dnatRange := range getLoadBalancerDNATRanges(&rule) {
+			listenPortRangeStr := portRangeStr(dnatRange.listenPorts, ":")
+
+			// Each target rule matches a new connection with a probability of 1/N where N is the number of
+			// targets remaining, so that connections are spread evenly across all targets. The rules are
+			// prepended, so they are added starting from the last target.
+			for targetIndex := len(rule.Targets) - 1; targetIndex >= 0; targetIndex-- {
+				targetAddressStr := rule.Targets[targetIndex].Address.String()
+				targetPortRange := dnatRange.targetPorts[targetIndex]
+
+				targetDest := targetAddressStr
+				if targetPortRange[1] == 1 {
+					targetPortStr := portRangeStr(targetPortRange, ":")
+					targetDest = fmt.Sprintf("%s:%s", targetAddressStr, targetPortStr)
+					if ipVersion == 6 {
+						targetDest = fmt.Sprintf("[%s]:%s", targetAddressStr, targetPortStr)
+					}
+				}
+
+				args := []string{"-p", rule.Protocol, "--destination", listenAddressStr, "--dport", listenPortRangeStr}
+
+				remainingTargets := len(rule.Targets) - targetIndex
+				if remainingTargets > 1 {
+					args = append(args, "-m", "statistic", "--mode", "random", "--probability", fmt.Sprintf("%.6f", 1/float64(remainingTargets)))
+				}
+
+				args = append(args, "-j", "DNAT", "--to-destination", targetDest)
+
+				// outbound <-> instance.
+				err := d.iptablesPrepend(ipVersion, comment, "nat", "PREROUTING", args...)
+				if err != nil {
+					return err
+				}
+
+				// host <-> instance.
+				err = d.iptablesPrepend(ipVersion, comment, "nat", "OUTPUT", args...)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
@@ -14,7 +14,9 @@ type Firewall interface {
 	NetworkSetup(networkName string, opts drivers.Opts) error
 	NetworkClear(networkName string, delete bool, ipVersions []uint) error
 	NetworkApplyACLRules(networkName string, rules []drivers.ACLRule) error
+	NetworkACLRuleCounters(networkName string) (map[string]drivers.ACLRuleCounters, error)
 	NetworkApplyForwards(networkName string, rules []drivers.AddressForward) error
+	NetworkApplyLoadBalancers(networkName string, rules []drivers.AddressLoadBalancer) error
 
 	InstanceSetupBridgeFilter(projectName string, instanceName string, deviceName string, parentName string, hostName string, hwAddr string, IPv4Nets []*net.IPNet, IPv6Nets []*net.IPNet, parentManaged bool) error
 	InstanceClearBridgeFilter(projectName string, instanceName string, deviceName string, parentName string, hostName string, hwAddr string, IPv4Nets []*net.IPNet, IPv6Nets []*net.IPNet) error
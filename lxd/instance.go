@@ -14,17 +14,20 @@ import (
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/instance/operationlock"
 	"github.com/canonical/lxd/lxd/locking"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/scriptlet"
 	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	apiScriptlet "github.com/canonical/lxd/shared/api/scriptlet"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 )
@@ -744,3 +747,32 @@ func instanceOperationLock(ctx context.Context, projectName string, instanceName
 
 	return locking.Lock(ctx, fmt.Sprintf("InstanceOperation_%s", project.Instance(projectName, instanceName)))
 }
+
+// instanceValidationScriptletRun runs the instance validation scriptlet (if enabled) against the requested
+// instance configuration and applies any config and device changes made by the scriptlet to req.
+func instanceValidationScriptletRun(ctx context.Context, s *state.State, projectName string, instanceName string, instanceType api.InstanceType, reason string, req *api.InstancePut, profiles []api.Profile) error {
+	if s.GlobalConfig.InstancesValidationScriptlet() == "" {
+		return nil
+	}
+
+	reqValidation := apiScriptlet.InstanceValidation{
+		InstancePut: *req,
+		Name:        instanceName,
+		Type:        instanceType,
+		Reason:      reason,
+		Project:     projectName,
+	}
+
+	reqValidation.ExpandedConfig = db.ExpandInstanceConfig(req.Config, profiles)
+	reqValidation.ExpandedDevices = db.ExpandInstanceDevices(deviceConfig.NewDevices(req.Devices), profiles).CloneNative()
+
+	err := scriptlet.InstanceValidationRun(ctx, logger.Log, &reqValidation)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "Failed instance validation scriptlet: %v", err)
+	}
+
+	req.Config = reqValidation.Config
+	req.Devices = reqValidation.Devices
+
+	return nil
+}
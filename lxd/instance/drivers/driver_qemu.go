@@ -85,6 +85,12 @@ const QEMUDefaultCPUCores = 1
 // QEMUDefaultMemSize is the default memory size for VMs if no limit specified.
 const QEMUDefaultMemSize = "1GiB"
 
+// qemuMemoryHotplugSlots is the number of memory slots reserved for hotplug when limits.memory.hotplug is set.
+const qemuMemoryHotplugSlots = 16
+
+// qemuMemoryDeviceIDPrefix used as part of the name given to hotplugged memory devices.
+const qemuMemoryDeviceIDPrefix = "lxd_memory"
+
 // qemuSerialChardevName is used to communicate state via qmp between Qemu and LXD.
 const qemuSerialChardevName = "qemu_serial-chardev"
 
@@ -651,6 +657,12 @@ func (d *qemu) onStop(target string) error {
 		return err
 	}
 
+	// Remove the old volume of a live storage pool move now that it isn't in use anymore.
+	err = d.deletePoolMoveSource()
+	if err != nil {
+		d.logger.Error("Failed deleting old root disk volume", logger.Ctx{"pool": d.localConfig["volatile.pool_move.source"], "err": err})
+	}
+
 	// Unload the apparmor profile
 	err = apparmor.InstanceUnload(d.state.OS, d)
 	if err != nil {
@@ -658,6 +670,9 @@ func (d *qemu) onStop(target string) error {
 		return err
 	}
 
+	// Trigger a rebalance now that the vCPUs are gone.
+	cgroup.TaskSchedulerTrigger("virtual-machine", d.name, "stopped")
+
 	// Log and emit lifecycle if not user triggered.
 	if op.GetInstanceInitiated() {
 		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceShutdown.Event(d, nil))
@@ -1182,6 +1197,12 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		}
 	}
 
+	// Remove the old volume of a live storage pool move if the instance didn't stop cleanly.
+	err = d.deletePoolMoveSource()
+	if err != nil {
+		d.logger.Warn("Failed deleting old root disk volume", logger.Ctx{"pool": d.localConfig["volatile.pool_move.source"], "err": err})
+	}
+
 	// Mount the instance's config volume.
 	mountInfo, err := d.mount()
 	if err != nil {
@@ -1482,6 +1503,7 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		"-sandbox", "on,obsolete=deny,elevateprivileges=allow,spawn=allow,resourcecontrol=deny",
 		"-readconfig", confFile,
 		"-spice", d.spiceCmdlineConfig(),
+		"-vnc", d.vncCmdlineConfig(),
 		"-pidfile", d.pidFilePath(),
 		"-D", d.LogFilePath(),
 	}
@@ -1804,6 +1826,9 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceStarted.Event(d, nil))
 	}
 
+	// Place the vCPUs on the requested NUMA nodes and rebalance the other instances.
+	cgroup.TaskSchedulerTrigger("virtual-machine", d.name, "started")
+
 	// The VM started cleanly so now enable the unexpected disconnection event to ensure the onStop hook is
 	// run if QMP unexpectedly disconnects.
 	monitor.SetOnDisconnectEvent(true)
@@ -2578,6 +2603,14 @@ func (d *qemu) spiceCmdlineConfig() string {
 	return fmt.Sprintf("unix=on,disable-ticketing=on,addr=%s", d.spicePath())
 }
 
+func (d *qemu) vncPath() string {
+	return filepath.Join(d.LogPath(), "qemu.vnc")
+}
+
+func (d *qemu) vncCmdlineConfig() string {
+	return fmt.Sprintf("unix:%s", d.vncPath())
+}
+
 // generateConfigShare generates the config share directory that will be exported to the VM via
 // a 9P share. Due to the unknown size of templates inside the images this directory is created
 // inside the VM's config volume so that it can be restricted by quota.
@@ -3505,6 +3538,14 @@ func (d *qemu) addCPUMemoryConfig(cfg *[]cfgSection, cpuInfo *cpuTopology) error
 		cpuOpts.cpuSockets = 1
 		cpuOpts.cpuThreads = 1
 		hostNodes = []uint64{0}
+
+		// Bind the memory to the NUMA nodes the vCPUs are placed on.
+		memoryHostNodes, err := d.memoryHostNodes()
+		if err != nil {
+			return err
+		}
+
+		cpuOpts.memoryHostNodes = memoryHostNodes
 	} else {
 		cpuPinning = true
 
@@ -3580,8 +3621,29 @@ func (d *qemu) addCPUMemoryConfig(cfg *[]cfgSection, cpuInfo *cpuTopology) error
 	nodeMemory := int64(memSizeMB / int64(len(hostNodes)))
 	cpuOpts.memory = nodeMemory
 
+	memOpts := qemuMemoryOpts{memSizeMB: memSizeMB}
+
+	// Configure memory hotplug.
+	if d.expandedConfig["limits.memory.hotplug"] != "" {
+		if !d.architectureSupportsMemoryHotplug() {
+			return fmt.Errorf("Memory hotplug isn't supported on this architecture")
+		}
+
+		maxMemSizeBytes, err := units.ParseByteSizeString(d.expandedConfig["limits.memory.hotplug"])
+		if err != nil {
+			return fmt.Errorf("limits.memory.hotplug invalid: %w", err)
+		}
+
+		if maxMemSizeBytes < memSizeBytes {
+			return fmt.Errorf("limits.memory.hotplug cannot be lower than limits.memory")
+		}
+
+		memOpts.maxMemSizeMB = maxMemSizeBytes / 1024 / 1024
+		memOpts.slots = qemuMemoryHotplugSlots
+	}
+
 	if cfg != nil {
-		*cfg = append(*cfg, qemuMemory(&qemuMemoryOpts{memSizeMB})...)
+		*cfg = append(*cfg, qemuMemory(&memOpts)...)
 		*cfg = append(*cfg, qemuCPU(&cpuOpts, cpuPinning)...)
 	}
 
@@ -5532,6 +5594,9 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 				if err != nil {
 					return fmt.Errorf("Failed updating cpu limit: %w", err)
 				}
+
+				// Place the hotplugged vCPUs.
+				cgroup.TaskSchedulerTrigger("virtual-machine", d.name, "changed")
 			} else if key == "limits.memory" {
 				err = d.updateMemoryLimit(value)
 				if err != nil {
@@ -5726,7 +5791,8 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 	return nil
 }
 
-// updateMemoryLimit live updates the VM's memory limit by reszing the balloon device.
+// updateMemoryLimit live updates the VM's memory limit by hotplugging memory beyond the current size if
+// limits.memory.hotplug is set and by reszing the balloon device otherwise.
 func (d *qemu) updateMemoryLimit(newLimit string) error {
 	if newLimit == "" {
 		return nil
@@ -5755,7 +5821,13 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 		return err
 	}
 
-	baseSizeMB := baseSizeBytes / 1024 / 1024
+	pluggedSizeBytes, err := monitor.GetPluggedMemorySizeBytes()
+	if err != nil {
+		return err
+	}
+
+	// The memory available to the balloon includes the hotplugged memory.
+	baseSizeMB := (baseSizeBytes + pluggedSizeBytes) / 1024 / 1024
 
 	curSizeBytes, err := monitor.GetMemoryBalloonSizeBytes()
 	if err != nil {
@@ -5767,7 +5839,15 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 	if curSizeMB == newSizeMB {
 		return nil
 	} else if baseSizeMB < newSizeMB {
-		return fmt.Errorf("Cannot increase memory size beyond boot time size when VM is running (Boot time size %dMiB, new size %dMiB)", baseSizeMB, newSizeMB)
+		if d.expandedConfig["limits.memory.hotplug"] == "" {
+			return fmt.Errorf("Cannot increase memory size beyond boot time size when VM is running (Boot time size %dMiB, new size %dMiB)", baseSizeMB, newSizeMB)
+		}
+
+		// Grow the memory by hotplugging the missing amount.
+		err = d.hotplugMemory(monitor, (newSizeMB-baseSizeMB)*1024*1024)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Set effective memory size.
@@ -5803,6 +5883,112 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 	return fmt.Errorf("Failed setting memory to %dMiB (currently %dMiB) as it was taking too long", newSizeMB, curSizeMB)
 }
 
+// hotplugMemory adds a memory device of the given size to the running VM.
+func (d *qemu) hotplugMemory(monitor *qmp.Monitor, sizeBytes int64) error {
+	// The memory state isn't carried over the hotplugged devices when migrating or restoring the VM.
+	if shared.IsTrue(d.expandedConfig["migration.stateful"]) {
+		return fmt.Errorf("Cannot hotplug memory when migration.stateful is enabled")
+	}
+
+	maxMemSizeBytes, err := units.ParseByteSizeString(d.expandedConfig["limits.memory.hotplug"])
+	if err != nil {
+		return fmt.Errorf("limits.memory.hotplug invalid: %w", err)
+	}
+
+	baseSizeBytes, err := monitor.GetMemorySizeBytes()
+	if err != nil {
+		return err
+	}
+
+	devices, err := monitor.QueryMemoryDevices()
+	if err != nil {
+		return err
+	}
+
+	pluggedSizeBytes := int64(0)
+	usedIDs := make(map[string]struct{}, len(devices))
+	for _, dev := range devices {
+		pluggedSizeBytes += dev.Data.Size
+		usedIDs[dev.Data.ID] = struct{}{}
+	}
+
+	if baseSizeBytes+pluggedSizeBytes+sizeBytes > maxMemSizeBytes {
+		return fmt.Errorf("Cannot increase memory size beyond limits.memory.hotplug (%s)", d.expandedConfig["limits.memory.hotplug"])
+	}
+
+	if len(devices) >= qemuMemoryHotplugSlots {
+		return fmt.Errorf("No memory hotplug slots left, restart the VM to apply the new memory size")
+	}
+
+	// Find a free device ID.
+	var devID string
+	for i := 0; i < qemuMemoryHotplugSlots; i++ {
+		devID = fmt.Sprintf("%s%d", qemuMemoryDeviceIDPrefix, i)
+		_, found := usedIDs[devID]
+		if !found {
+			break
+		}
+	}
+
+	memDev := map[string]any{
+		"qom-type": "memory-backend-memfd",
+		"id":       fmt.Sprintf("%s-mem", devID),
+		"size":     sizeBytes,
+		"share":    true,
+	}
+
+	// Bind the memory to the same NUMA nodes as the boot time memory.
+	memoryHostNodes, err := d.memoryHostNodes()
+	if err != nil {
+		return err
+	}
+
+	if len(memoryHostNodes) > 0 {
+		memDev["policy"] = "bind"
+		memDev["host-nodes"] = memoryHostNodes
+	}
+
+	dev := map[string]string{
+		"driver": "pc-dimm",
+		"id":     devID,
+		"memdev": fmt.Sprintf("%s-mem", devID),
+	}
+
+	err = monitor.AddMemoryDevice(memDev, dev)
+	if err != nil {
+		return fmt.Errorf("Failed hotplugging memory: %w", err)
+	}
+
+	d.logger.Debug("Hotplugged memory", logger.Ctx{"device": devID, "size": sizeBytes})
+
+	return nil
+}
+
+// memoryHostNodes returns the host NUMA nodes the VM memory should be bound to when its vCPUs aren't pinned.
+// This is based on limits.cpu.nodes and returns nil if not set.
+func (d *qemu) memoryHostNodes() ([]uint64, error) {
+	if d.expandedConfig["limits.cpu.nodes"] == "" {
+		return nil, nil
+	}
+
+	numaNodes, err := resources.ParseNumaNodeSet(d.expandedConfig["limits.cpu.nodes"])
+	if err != nil {
+		return nil, err
+	}
+
+	hostNodes := make([]uint64, 0, len(numaNodes))
+	for _, numaNode := range numaNodes {
+		hostNodes = append(hostNodes, uint64(numaNode))
+	}
+
+	return hostNodes, nil
+}
+
+// architectureSupportsMemoryHotplug returns whether the VM architecture supports DIMM memory hotplug.
+func (d *qemu) architectureSupportsMemoryHotplug() bool {
+	return shared.ValueInSlice(d.architecture, []int{osarch.ARCH_64BIT_INTEL_X86, osarch.ARCH_64BIT_ARMV8_LITTLE_ENDIAN})
+}
+
 func (d *qemu) removeUnixDevices() error {
 	// Check that we indeed have devices to remove.
 	if !shared.PathExists(d.DevicesPath()) {
@@ -6380,11 +6566,17 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 		return fmt.Errorf("Failed loading instance: %w", err)
 	}
 
+	srcConfig, err := pool.GenerateInstanceBackupConfig(d, args.Snapshots, d.op)
+	if err != nil {
+		return fmt.Errorf("Failed generating instance migration config: %w", err)
+	}
+
 	// The refresh argument passed to MigrationTypes() is always set
 	// to false here. The migration source/sender doesn't need to care whether
 	// or not it's doing a refresh as the migration sink/receiver will know
 	// this, and adjust the migration types accordingly.
-	poolMigrationTypes := pool.MigrationTypes(storagePools.InstanceContentType(d), false, args.Snapshots)
+	srcVol := pool.GetVolume(storageDrivers.VolumeTypeVM, storagePools.InstanceContentType(d), project.Instance(d.project.Name, d.name), srcConfig.Volume.Config)
+	poolMigrationTypes := storagePools.VolumeMigrationTypes(pool, srcVol, false, args.Snapshots)
 	if len(poolMigrationTypes) == 0 {
 		return fmt.Errorf("No source migration types available")
 	}
@@ -6406,11 +6598,6 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 	d.logger.Debug("Set migration offer volume size", logger.Ctx{"blockSize": blockSize})
 	offerHeader.VolumeSize = &blockSize
 
-	srcConfig, err := pool.GenerateInstanceBackupConfig(d, args.Snapshots, d.op)
-	if err != nil {
-		return fmt.Errorf("Failed generating instance migration config: %w", err)
-	}
-
 	// If we are copying snapshots, retrieve a list of snapshots from source volume.
 	if args.Snapshots {
 		offerHeader.SnapshotNames = make([]string, 0, len(srcConfig.Snapshots))
@@ -6587,6 +6774,238 @@ func (d *qemu) MigrateSend(args instance.MigrateSendArgs) error {
 	}
 }
 
+// MoveStoragePool moves the root disk of the running instance to another storage pool on the same server without
+// stopping it, by mirroring the root disk onto a new volume on the pool and switching the instance over to it.
+func (d *qemu) MoveStoragePool(poolName string) error {
+	d.logger.Debug("MoveStoragePool started", logger.Ctx{"pool": poolName})
+	defer d.logger.Debug("MoveStoragePool finished", logger.Ctx{"pool": poolName})
+
+	if !d.IsRunning() {
+		return fmt.Errorf("Instance must be running to move its root disk using block mirroring")
+	}
+
+	if d.localConfig["volatile.pool_move.source"] != "" {
+		return fmt.Errorf("Instance must be restarted before its root disk can be moved again")
+	}
+
+	srcPool, err := d.getStoragePool()
+	if err != nil {
+		return err
+	}
+
+	if srcPool.Name() == poolName {
+		return fmt.Errorf("Instance root disk is already on storage pool %q", poolName)
+	}
+
+	pool, err := storagePools.LoadByName(d.state, poolName)
+	if err != nil {
+		return err
+	}
+
+	rootDevName, rootDevConfig, err := d.getRootDiskDevice()
+	if err != nil {
+		return err
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return err
+	}
+
+	err = pool.MirrorInstance(d, srcPool, func(diskPath string) error {
+		return d.mirrorRootDisk(monitor, filesystem.PathNameEncode(rootDevName), diskPath)
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("Failed moving root disk to storage pool %q: %w", poolName, err)
+	}
+
+	d.storagePool = pool
+
+	// Point the root disk device at the new pool, overriding the profile root disk device if needed.
+	localDevices := d.localDevices.Clone()
+	localDevices[rootDevName] = deviceConfig.Device(rootDevConfig).Clone()
+	localDevices[rootDevName]["pool"] = poolName
+
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		devices, err := dbCluster.APIToDevices(localDevices.CloneNative())
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.UpdateInstanceDevices(ctx, tx.Tx(), int64(d.id), devices)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed updating root disk device: %w", err)
+	}
+
+	d.localDevices = localDevices
+	err = d.expandConfig()
+	if err != nil {
+		return err
+	}
+
+	// The old volume can only be removed once the instance has stopped using it.
+	err = d.VolatileSet(map[string]string{"volatile.pool_move.source": srcPool.Name()})
+	if err != nil {
+		return err
+	}
+
+	err = d.UpdateBackupFile()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// mirrorRootDisk copies the root disk to the disk at diskPath and switches the instance over to it.
+// The new disk is mirrored twice so that it ends up using the same block node name as the old root disk.
+func (d *qemu) mirrorRootDisk(monitor *qmp.Monitor, escapedDeviceName string, diskPath string) error {
+	rootNodeName := d.blockNodeName(escapedDeviceName)
+	rootFDSetName := fmt.Sprintf("%s%s", qemuBlockDevIDPrefix, escapedDeviceName)
+	mirrorNodeName := d.blockNodeName(escapedDeviceName + "_mirror")
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err := d.addMirrorBlockDevice(monitor, mirrorNodeName, mirrorNodeName, diskPath)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		_ = monitor.RemoveBlockDevice(mirrorNodeName)
+		_ = monitor.RemoveFDFromFDSet(mirrorNodeName)
+	})
+
+	revert.Add(func() {
+		err := monitor.BlockJobCancel(rootNodeName)
+		if err != nil {
+			d.logger.Warn("Failed cancelling block job", logger.Ctx{"err": err})
+		}
+	})
+
+	// Copy the whole root disk to the new disk. Once in sync, guest writes are sent to both disks.
+	d.logger.Debug("Root disk mirroring started", logger.Ctx{"diskPath": diskPath})
+	err = monitor.BlockDevMirrorSync(rootNodeName, mirrorNodeName, "full")
+	if err != nil {
+		return fmt.Errorf("Failed mirroring root disk: %w", err)
+	}
+
+	err = monitor.BlockJobPivot(rootNodeName, time.Minute)
+	if err != nil {
+		return fmt.Errorf("Failed switching over to mirrored root disk: %w", err)
+	}
+
+	d.logger.Debug("Root disk mirroring finished", logger.Ctx{"diskPath": diskPath})
+	revert.Success()
+
+	// The instance now uses the new disk. Replace the old root disk node with one using the new disk and switch
+	// back to it so the root disk node name stays the same. Failing to do so doesn't stop the instance from
+	// using the new disk, so only log it.
+	err = func() error {
+		err := monitor.RemoveBlockDevice(rootNodeName)
+		if err != nil {
+			return err
+		}
+
+		err = monitor.RemoveFDFromFDSet(rootFDSetName)
+		if err != nil {
+			return err
+		}
+
+		err = d.addMirrorBlockDevice(monitor, rootNodeName, rootFDSetName, diskPath)
+		if err != nil {
+			return err
+		}
+
+		// Both nodes use the same disk, so there is nothing to copy.
+		err = monitor.BlockDevMirrorSync(mirrorNodeName, rootNodeName, "none")
+		if err != nil {
+			return err
+		}
+
+		err = monitor.BlockJobPivot(mirrorNodeName, time.Minute)
+		if err != nil {
+			return err
+		}
+
+		err = monitor.RemoveBlockDevice(mirrorNodeName)
+		if err != nil {
+			return err
+		}
+
+		return monitor.RemoveFDFromFDSet(mirrorNodeName)
+	}()
+	if err != nil {
+		d.logger.Warn("Failed restoring root disk block node name after mirroring", logger.Ctx{"err": err})
+	}
+
+	return nil
+}
+
+// addMirrorBlockDevice adds a block node (not visible to the guest OS) for the disk at diskPath.
+// The host page cache is used for it as the I/O settings of the root disk are only detected at start time.
+func (d *qemu) addMirrorBlockDevice(monitor *qmp.Monitor, nodeName string, fdSetName string, diskPath string) error {
+	f, err := os.OpenFile(diskPath, unix.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("Failed opening file descriptor for disk %q: %w", diskPath, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	info, err := monitor.SendFileWithFDSet(fdSetName, f, false)
+	if err != nil {
+		return fmt.Errorf("Failed sending file descriptor of %q: %w", diskPath, err)
+	}
+
+	blockDev := map[string]any{
+		"aio": "threads",
+		"cache": map[string]any{
+			"direct":   false,
+			"no-flush": false,
+		},
+		"discard":   "unmap",
+		"driver":    "file",
+		"filename":  fmt.Sprintf("/dev/fdset/%d", info.ID),
+		"locking":   "off",
+		"node-name": nodeName,
+		"read-only": false,
+	}
+
+	if shared.IsBlockdevPath(diskPath) {
+		blockDev["driver"] = "host_device"
+	}
+
+	err = monitor.AddBlockDevice(blockDev, nil)
+	if err != nil {
+		_ = monitor.RemoveFDFromFDSet(fdSetName)
+		return fmt.Errorf("Failed adding block device for disk %q: %w", diskPath, err)
+	}
+
+	return nil
+}
+
+// deletePoolMoveSource deletes the old root disk volume left behind by a live storage pool move.
+func (d *qemu) deletePoolMoveSource() error {
+	poolName := d.localConfig["volatile.pool_move.source"]
+	if poolName == "" {
+		return nil
+	}
+
+	pool, err := storagePools.LoadByName(d.state, poolName)
+	if err != nil {
+		return err
+	}
+
+	err = pool.DeleteInstanceMirrorSource(d, nil)
+	if err != nil {
+		return err
+	}
+
+	return d.VolatileSet(map[string]string{"volatile.pool_move.source": ""})
+}
+
 // migrateSendLive performs live migration send process.
 func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName string, rootDiskSize int64, filesystemConn io.ReadWriteCloser, stateConn io.ReadWriteCloser, volSourceArgs *migration.VolumeSourceArgs) error {
 	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
@@ -6942,10 +7361,24 @@ func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 	// However, to determine the correct migration type Refresh needs to be set.
 	offerHeader.Refresh = &args.Refresh
 
+	contentType := storagePools.InstanceContentType(d)
+
+	// When refreshing, the existing volume's config determines whether the volume is encrypted.
+	var volConfig map[string]string
+	if args.Refresh {
+		dbVol, err := storagePools.VolumeDBGet(pool, d.project.Name, d.name, storageDrivers.VolumeTypeVM)
+		if err != nil {
+			return err
+		}
+
+		volConfig = dbVol.Config
+	}
+
+	vol := pool.GetVolume(storageDrivers.VolumeTypeVM, contentType, project.Instance(d.project.Name, d.name), volConfig)
+
 	// Extract the source's migration type and then match it against our pool's supported types and features.
 	// If a match is found the combined features list will be sent back to requester.
-	contentType := storagePools.InstanceContentType(d)
-	respTypes, err := migration.MatchTypes(offerHeader, storagePools.FallbackMigrationType(contentType), pool.MigrationTypes(contentType, args.Refresh, args.Snapshots))
+	respTypes, err := migration.MatchTypes(offerHeader, storagePools.FallbackMigrationType(contentType), storagePools.VolumeMigrationTypes(pool, vol, args.Refresh, args.Snapshots))
 	if err != nil {
 		return err
 	}
@@ -7407,6 +7840,8 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 		path = d.consolePath()
 	case instance.ConsoleTypeVGA:
 		path = d.spicePath()
+	case instance.ConsoleTypeVNC:
+		path = d.vncPath()
 	default:
 		return nil, nil, fmt.Errorf("Unknown protocol %q", protocol)
 	}
@@ -7432,6 +7867,112 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 	return file, chDisconnect, nil
 }
 
+// ConsoleScreenshot writes a PNG screenshot of the VM's display to the existing file at the given path.
+func (d *qemu) ConsoleScreenshot(screenshotPath string) error {
+	if !d.IsRunning() {
+		return fmt.Errorf("Instance is not running")
+	}
+
+	// QEMU only gets to write the screenshot.
+	screenshotFile, err := os.OpenFile(screenshotPath, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = screenshotFile.Close() }()
+
+	// Connect to the monitor.
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return err
+	}
+
+	// Pass the file to QEMU as it may not have access to the path.
+	info, err := monitor.SendFileWithFDSet("screenshot", screenshotFile, false)
+	if err != nil {
+		return fmt.Errorf("Failed sending screenshot file descriptor: %w", err)
+	}
+
+	defer func() { _ = monitor.RemoveFDFromFDSet("screenshot") }()
+
+	err = monitor.Screendump(fmt.Sprintf("/dev/fdset/%d", info.ID))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetAffinity restricts the vCPU threads of the VM to the given host CPUs.
+func (d *qemu) SetAffinity(set []string) error {
+	cpus := unix.CPUSet{}
+	for _, id := range set {
+		cpu, err := strconv.Atoi(id)
+		if err != nil {
+			return fmt.Errorf("Invalid CPU %q: %w", id, err)
+		}
+
+		cpus.Set(cpu)
+	}
+
+	// Connect to the monitor.
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return err
+	}
+
+	pids, err := monitor.GetCPUs()
+	if err != nil {
+		return err
+	}
+
+	for _, pid := range pids {
+		err := unix.SchedSetaffinity(pid, &cpus)
+		if err != nil {
+			return fmt.Errorf("Failed setting affinity of vCPU thread %d: %w", pid, err)
+		}
+	}
+
+	return nil
+}
+
+// VCPUUsage returns the total CPU time in ns used by the vCPU threads of the VM.
+func (d *qemu) VCPUUsage() (int64, error) {
+	// Connect to the monitor.
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return -1, err
+	}
+
+	pids, err := monitor.GetCPUs()
+	if err != nil {
+		return -1, err
+	}
+
+	usage := int64(0)
+	for _, pid := range pids {
+		// The first field of schedstat is the time spent on the CPU in ns.
+		content, err := os.ReadFile(fmt.Sprintf("/proc/%d/schedstat", pid))
+		if err != nil {
+			return -1, err
+		}
+
+		fields := strings.Fields(string(content))
+		if len(fields) == 0 {
+			return -1, fmt.Errorf("Invalid schedstat for vCPU thread %d", pid)
+		}
+
+		value, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return -1, fmt.Errorf("Failed parsing schedstat for vCPU thread %d: %w", pid, err)
+		}
+
+		usage += value
+	}
+
+	return usage, nil
+}
+
 // Exec a command inside the instance.
 func (d *qemu) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
 	revert := revert.New()
@@ -98,15 +98,22 @@ func TestQemuConfigTemplates(t *testing.T) {
 			opts     qemuMemoryOpts
 			expected string
 		}{{
-			qemuMemoryOpts{4096},
+			qemuMemoryOpts{4096, 0, 0},
 			`# Memory
 			[memory]
 			size = "4096M"`,
 		}, {
-			qemuMemoryOpts{8192},
+			qemuMemoryOpts{8192, 0, 0},
 			`# Memory
 			[memory]
 			size = "8192M"`,
+		}, {
+			qemuMemoryOpts{2048, 16384, 16},
+			`# Memory
+			[memory]
+			size = "2048M"
+			slots = "16"
+			maxmem = "16384M"`,
 		}}
 		for _, tc := range testCases {
 			runTest(tc.expected, qemuMemory(&tc.opts))
@@ -438,6 +445,40 @@ func TestQemuConfigTemplates(t *testing.T) {
 			size = "7629M"
 			share = "on"
 
+			[numa]
+			type = "node"
+			nodeid = "0"
+			memdev = "mem0"`,
+		}, {
+			qemuCPUOpts{
+				architecture:        "x86_64",
+				cpuCount:            4,
+				cpuSockets:          1,
+				cpuCores:            4,
+				cpuThreads:          1,
+				cpuNumaNodes:        []uint64{},
+				cpuNumaMapping:      []qemuNumaEntry{},
+				cpuNumaHostNodes:    []uint64{},
+				memoryHostNodes:     []uint64{1, 3},
+				hugepages:           "",
+				memory:              4096,
+				qemuMemObjectFormat: "indexed",
+			},
+			`# CPU
+			[smp-opts]
+			cpus = "4"
+			sockets = "1"
+			cores = "4"
+			threads = "1"
+
+			[object "mem0"]
+			qom-type = "memory-backend-memfd"
+			size = "4096M"
+			share = "on"
+			policy = "bind"
+			host-nodes.0 = "1"
+			host-nodes.1 = "3"
+
 			[numa]
 			type = "node"
 			nodeid = "0"
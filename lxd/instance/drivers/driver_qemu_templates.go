@@ -115,14 +115,26 @@ func qemuBase(opts *qemuBaseOpts) []cfgSection {
 }
 
 type qemuMemoryOpts struct {
-	memSizeMB int64
+	memSizeMB    int64
+	maxMemSizeMB int64
+	slots        int
 }
 
 func qemuMemory(opts *qemuMemoryOpts) []cfgSection {
+	entries := []cfgEntry{{key: "size", value: fmt.Sprintf("%dM", opts.memSizeMB)}}
+
+	// Reserve memory slots for hotplug.
+	if opts.slots > 0 {
+		entries = append(entries, []cfgEntry{
+			{key: "slots", value: fmt.Sprintf("%d", opts.slots)},
+			{key: "maxmem", value: fmt.Sprintf("%dM", opts.maxMemSizeMB)},
+		}...)
+	}
+
 	return []cfgSection{{
 		name:    "memory",
 		comment: "Memory",
-		entries: []cfgEntry{{key: "size", value: fmt.Sprintf("%dM", opts.memSizeMB)}},
+		entries: entries,
 	}}
 }
 
@@ -431,6 +443,7 @@ type qemuCPUOpts struct {
 	cpuNumaNodes        []uint64
 	cpuNumaMapping      []qemuNumaEntry
 	cpuNumaHostNodes    []uint64
+	memoryHostNodes     []uint64
 	hugepages           string
 	memory              int64
 	qemuMemObjectFormat string
@@ -506,6 +519,21 @@ func qemuCPU(opts *qemuCPUOpts, pinning bool) []cfgSection {
 		numaHostNode := qemuCPUNumaHostNode(opts, 0)
 		// unconditionally append "share = "on" to the [object "mem0"] section
 		numaHostNode[0].entries = append(numaHostNode[0].entries, share)
+
+		// bind the memory to the host NUMA nodes the instance is placed on
+		if len(opts.memoryHostNodes) > 0 {
+			numaHostNode[0].entries = append(numaHostNode[0].entries, cfgEntry{key: "policy", value: "bind"})
+
+			for i, element := range opts.memoryHostNodes {
+				hostNodesKey := "host-nodes"
+				if opts.qemuMemObjectFormat == "indexed" {
+					hostNodesKey = fmt.Sprintf("host-nodes.%d", i)
+				}
+
+				numaHostNode[0].entries = append(numaHostNode[0].entries, cfgEntry{key: hostNodesKey, value: fmt.Sprintf("%d", element)})
+			}
+		}
+
 		return append(sections, numaHostNode...)
 	}
 
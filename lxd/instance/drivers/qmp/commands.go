@@ -465,6 +465,92 @@ func (m *Monitor) SetMemoryBalloonSizeBytes(sizeBytes int64) error {
 	return m.run("balloon", args, nil)
 }
 
+// GetPluggedMemorySizeBytes returns the current size of the hotplugged memory in bytes.
+func (m *Monitor) GetPluggedMemorySizeBytes() (int64, error) {
+	// Prepare the response.
+	var resp struct {
+		Return struct {
+			PluggedMemory int64 `json:"plugged-memory"`
+		} `json:"return"`
+	}
+
+	err := m.run("query-memory-size-summary", nil, &resp)
+	if err != nil {
+		return -1, err
+	}
+
+	return resp.Return.PluggedMemory, nil
+}
+
+// MemoryDevice contains information about a hotplugged memory device.
+type MemoryDevice struct {
+	Type string `json:"type"`
+	Data struct {
+		ID     string `json:"id"`
+		Size   int64  `json:"size"`
+		Slot   int    `json:"slot"`
+		Node   int    `json:"node"`
+		Memdev string `json:"memdev"`
+	} `json:"data"`
+}
+
+// QueryMemoryDevices returns a list of hotplugged memory devices.
+func (m *Monitor) QueryMemoryDevices() ([]MemoryDevice, error) {
+	// Prepare the response.
+	var resp struct {
+		Return []MemoryDevice `json:"return"`
+	}
+
+	err := m.run("query-memory-devices", nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query memory devices: %w", err)
+	}
+
+	return resp.Return, nil
+}
+
+// AddMemoryDevice adds a memory backend object and the memory device using it.
+func (m *Monitor) AddMemoryDevice(memDev map[string]any, device map[string]string) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	err := m.run("object-add", memDev, nil)
+	if err != nil {
+		return fmt.Errorf("Failed adding memory backend: %w", err)
+	}
+
+	revert.Add(func() {
+		memDevDel := map[string]any{
+			"id": memDev["id"],
+		}
+
+		_ = m.run("object-del", memDevDel, nil)
+	})
+
+	err = m.AddDevice(device)
+	if err != nil {
+		return fmt.Errorf("Failed adding memory device: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
+// Screendump writes a PNG screenshot of the primary display to the given file.
+func (m *Monitor) Screendump(filename string) error {
+	args := map[string]any{
+		"filename": filename,
+		"format":   "png",
+	}
+
+	err := m.run("screendump", args, nil)
+	if err != nil {
+		return fmt.Errorf("Failed taking screenshot: %w", err)
+	}
+
+	return nil
+}
+
 // AddBlockDevice adds a block device.
 func (m *Monitor) AddBlockDevice(blockDev map[string]any, device map[string]string) error {
 	revert := revert.New()
@@ -934,6 +1020,13 @@ func (m *Monitor) BlockCommit(deviceNodeName string) error {
 
 // BlockDevMirror mirrors the top device to the target device.
 func (m *Monitor) BlockDevMirror(deviceNodeName string, targetNodeName string) error {
+	// Only synchronise the top level device (usually a snapshot).
+	return m.BlockDevMirrorSync(deviceNodeName, targetNodeName, "top")
+}
+
+// BlockDevMirrorSync mirrors the device to the target device using the specified sync mode ("top", "full" or "none").
+// It returns once the mirror job is ready to be completed.
+func (m *Monitor) BlockDevMirrorSync(deviceNodeName string, targetNodeName string, sync string) error {
 	var args struct {
 		Device   string `json:"device"`
 		Target   string `json:"target"`
@@ -945,9 +1038,7 @@ func (m *Monitor) BlockDevMirror(deviceNodeName string, targetNodeName string) e
 	args.Device = deviceNodeName
 	args.Target = targetNodeName
 	args.JobID = deviceNodeName
-
-	// Only synchronise the top level device (usually a snapshot).
-	args.Sync = "top"
+	args.Sync = sync
 
 	// When data is written to the source, write it (synchronously) to the target as well.
 	// In addition, data is copied in background just like in background mode.
@@ -998,3 +1089,50 @@ func (m *Monitor) BlockJobComplete(deviceNodeName string) error {
 
 	return nil
 }
+
+// BlockJobPivot completes a mirror block job that is in ready state and waits for the device to have switched
+// over to the mirror target, for up to the given timeout.
+func (m *Monitor) BlockJobPivot(deviceNodeName string, timeout time.Duration) error {
+	err := m.BlockJobComplete(deviceNodeName)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var resp struct {
+			Return []struct {
+				Device string `json:"device"`
+				Error  string `json:"error"`
+			} `json:"return"`
+		}
+
+		err := m.run("query-block-jobs", nil, &resp)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, job := range resp.Return {
+			if job.Device != deviceNodeName {
+				continue
+			}
+
+			if job.Error != "" {
+				return fmt.Errorf("Failed block job: %s", job.Error)
+			}
+
+			found = true
+		}
+
+		if !found {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timed out waiting for block job %q to complete", deviceNodeName)
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+}
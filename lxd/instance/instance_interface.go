@@ -37,6 +37,7 @@ const HookStop = "onstop"
 const (
 	ConsoleTypeConsole = "console"
 	ConsoleTypeVGA     = "vga"
+	ConsoleTypeVNC     = "vnc"
 )
 
 // TemplateTrigger trigger name.
@@ -187,6 +188,10 @@ type VM interface {
 	Instance
 
 	AgentCertificate() *x509.Certificate
+	MoveStoragePool(poolName string) error
+	ConsoleScreenshot(screenshotPath string) error
+	SetAffinity(set []string) error
+	VCPUUsage() (int64, error)
 }
 
 // CriuMigrationArgs arguments for CRIU migration.
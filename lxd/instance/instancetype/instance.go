@@ -1,6 +1,7 @@
 package instancetype
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strconv"
@@ -74,8 +75,75 @@ var HugePageSizeKeys = [...]string{"limits.hugepages.64KB", "limits.hugepages.1M
 // HugePageSizeSuffix contains the list of known hugepage size suffixes.
 var HugePageSizeSuffix = [...]string{"64KB", "1MB", "2MB", "1GB"}
 
+// IsReplicationSchedule validates the replication.schedule config key of instances and custom volumes.
+var IsReplicationSchedule = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"}))
+
+// IsReplicationRemoteFingerprint validates the replication.remote_fingerprint config key of instances and custom
+// volumes.
+func IsReplicationRemoteFingerprint(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	_, err := hex.DecodeString(value)
+	if err != nil || len(value) != 64 {
+		return fmt.Errorf("Invalid certificate fingerprint %q, expected a SHA-256 fingerprint", value)
+	}
+
+	return nil
+}
+
 // InstanceConfigKeysAny is a map of config key to validator. (keys applying to containers AND virtual machines).
 var InstanceConfigKeysAny = map[string]func(value string) error{
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.expiry)
+	// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: When scheduled backups are to be deleted
+	"backups.expiry": func(value string) error {
+		// Validate expression
+		_, err := shared.GetExpiry(time.Time{}, value)
+		return err
+	},
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.retain)
+	// Older scheduled backups are deleted once this number of scheduled backups is reached.
+	// ---
+	//  type: integer
+	//  defaultdesc: `0` (unlimited)
+	//  liveupdate: no
+	//  shortdesc: Number of scheduled backups to keep
+	"backups.retain": validate.Optional(validate.IsUint32),
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups.
+	// Use `@never` to disable a schedule that is set in a profile.
+	//
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Schedule for automatic instance backups
+	"backups.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.target)
+	// Specify a storage bucket as `<pool>/<bucket>` to upload scheduled backups to it instead of storing them on the server.
+	// The bucket must belong to the instance's project and be on a local storage pool of the cluster member that hosts the instance.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Storage bucket to upload scheduled backups to
+	"backups.target": validate.Optional(func(value string) error {
+		pool, bucket, found := strings.Cut(value, "/")
+		if !found || pool == "" || bucket == "" || strings.Contains(bucket, "/") {
+			return fmt.Errorf("Invalid backup target %q, expected <pool>/<bucket>", value)
+		}
+
+		return nil
+	}),
+
 	// lxdmeta:generate(entities=instance; group=boot; key=boot.autostart)
 	// If set to `false`, restore the last state.
 	// ---
@@ -102,6 +170,28 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What order to start the instances in
 	"boot.autostart.priority": validate.Optional(validate.IsInt64),
 
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.start_schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable scheduled starts.
+	// Use `@never` to disable a schedule that is set in a profile.
+	//
+	// See {ref}`instance-options-power-schedules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Schedule for automatically starting the instance
+	"boot.start_schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.stop_schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable scheduled stops.
+	// Use `@never` to disable a schedule that is set in a profile.
+	//
+	// See {ref}`instance-options-power-schedules` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Schedule for automatically stopping the instance
+	"boot.stop_schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+
 	// lxdmeta:generate(entities=instance; group=boot; key=boot.stop.priority)
 	// The instance with the highest value is shut down first.
 	// ---
@@ -293,6 +383,55 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Raw idmap configuration
 	"raw.idmap": validate.IsAny,
 
+	// lxdmeta:generate(entities=instance; group=replication; key=replication.remote)
+	// Specify the URL (`https://<address>[:<port>]`) of an LXD server outside of the cluster to replicate the instance to that server instead of within the cluster.
+	// The URL must be listed in the {config:option}`server-miscellaneous:replication.allowed_remote_urls` server configuration option, and the remote server must trust the certificate of this server.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: LXD server to replicate the instance to
+	"replication.remote": validate.Optional(validate.IsRequestURL),
+
+	// lxdmeta:generate(entities=instance; group=replication; key=replication.remote_fingerprint)
+	// The SHA-256 fingerprint of the certificate of the server set in `replication.remote`.
+	// Replication fails if the remote server presents a different certificate.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Certificate fingerprint of the remote replication server
+	"replication.remote_fingerprint": IsReplicationRemoteFingerprint,
+
+	// lxdmeta:generate(entities=instance; group=replication; key=replication.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable replication.
+	// Use `@never` to disable a schedule that is set in a profile.
+	//
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Schedule for the replication of the instance
+	"replication.schedule": IsReplicationSchedule,
+
+	// lxdmeta:generate(entities=instance; group=replication; key=replication.target)
+	// Specify a storage pool as `<pool>` to replicate the instance into a pool of the cluster member that hosts the instance, or as `<member>/<pool>` to replicate it into a pool of another cluster member.
+	// If `replication.remote` is set, the storage pool and cluster member are the ones of the remote server.
+	// The replica is named after the instance with a `-replica` suffix.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Storage pool to replicate the instance into
+	"replication.target": validate.Optional(func(value string) error {
+		fields := strings.Split(value, "/")
+		if len(fields) > 2 || shared.ValueInSlice("", fields) {
+			return fmt.Errorf("Invalid replication target %q, expected <pool> or <member>/<pool>", value)
+		}
+
+		return nil
+	}),
+
 	// lxdmeta:generate(entities=instance; group=security; key=security.devlxd)
 	// See {ref}`dev-lxd` for more information.
 	// ---
@@ -392,6 +531,23 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	"volatile.last_state.power": validate.IsAny,
 	"volatile.last_state.ready": validate.IsBool,
 	"volatile.apply_quota":      validate.IsAny,
+
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.pool_move.source)
+	// The storage pool that still holds the old volume of a virtual machine that was moved to another storage pool while running.
+	// The old volume is deleted when the instance stops.
+	// ---
+	//  type: string
+	//  shortdesc: Storage pool to clean up after a live storage pool move
+	"volatile.pool_move.source": validate.IsAny,
+
+	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.replication.source)
+	// Set on replicas created by scheduled replication.
+	// LXD only refreshes an existing instance named `<instance_name>-replica` if this key matches the source instance.
+	// ---
+	//  type: string
+	//  shortdesc: Name of the instance that this instance is a replica of
+	"volatile.replication.source": validate.IsAny,
+
 	// lxdmeta:generate(entities=instance; group=volatile; key=volatile.uuid)
 	// The instance UUID is globally unique across all servers and projects.
 	// ---
@@ -908,6 +1064,17 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to back the instance using huge pages
 	"limits.memory.hugepages": validate.Optional(validate.IsBool),
 
+	// lxdmeta:generate(entities=instance; group=resource-limits; key=limits.memory.hotplug)
+	// When set, memory slots are reserved when the VM starts, so that increasing `limits.memory` while the VM is running hotplugs additional memory, up to this size.
+	// If left empty, the memory of a running VM cannot grow beyond its boot time size.
+	// Memory hotplug is not supported together with `migration.stateful`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Maximum memory size the VM can grow to while running
+	"limits.memory.hotplug": validate.Optional(validate.IsSize),
+
 	// lxdmeta:generate(entities=instance; group=migration; key=migration.stateful)
 	// Enabling this option prevents the use of some features that are incompatible with it.
 	// ---
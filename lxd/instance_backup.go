@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
 	"strings"
 	"time"
 
@@ -317,6 +318,32 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 	fullName := name + shared.SnapshotDelimiter + req.Name
 	instanceOnly := req.InstanceOnly || req.ContainerOnly
 
+	// Validate the base of incremental backups.
+	if req.IncrementalFrom != "" {
+		if instanceOnly {
+			return response.BadRequest(fmt.Errorf("Incremental backups must include snapshots"))
+		}
+
+		_, err = instance.BackupLoadByName(s, projectName, name+shared.SnapshotDelimiter+req.IncrementalFrom)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed loading base backup %q: %w", req.IncrementalFrom, err))
+		}
+	}
+
+	// Validate the encryption settings.
+	_, err = backupEncryptionRecipients(req.EncryptionRecipients, req.EncryptionPassphrase)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Validate the backup target.
+	if req.Target != nil {
+		err = backupTargetValidate(s, req.Target, path.Join(project.Instance(projectName, name), req.Name))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
 	backup := func(op *operations.Operation) error {
 		args := db.InstanceBackup{
 			Name:                 fullName,
@@ -326,6 +353,10 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 			InstanceOnly:         instanceOnly,
 			OptimizedStorage:     req.OptimizedStorage,
 			CompressionAlgorithm: req.CompressionAlgorithm,
+			IncrementalFrom:      req.IncrementalFrom,
+			EncryptionRecipients: req.EncryptionRecipients,
+			EncryptionPassphrase: req.EncryptionPassphrase,
+			Target:               req.Target,
 		}
 
 		err := backupCreate(s, args, inst, op)
@@ -610,6 +641,13 @@ func instanceBackupDelete(d *Daemon, r *http.Request) response.Response {
 	}
 
 	remove := func(op *operations.Operation) error {
+		if backup.Target() != nil {
+			err := backupTargetDelete(s, projectName, backup.Target())
+			if err != nil {
+				return err
+			}
+		}
+
 		err := backup.Delete()
 		if err != nil {
 			return err
@@ -694,6 +732,10 @@ func instanceBackupExportGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	if backup.Target() != nil {
+		return response.BadRequest(fmt.Errorf("Backup was uploaded to a backup target and isn't stored on the server"))
+	}
+
 	ent := response.FileResponseEntry{
 		Path: shared.VarPath("backups", "instances", project.Instance(projectName, backup.Name())),
 	}
@@ -17,6 +17,7 @@ import (
 	liblxc "github.com/lxc/go-lxc"
 	"golang.org/x/sys/unix"
 
+	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
 	"github.com/canonical/lxd/lxd/instance"
@@ -24,6 +25,7 @@ import (
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
@@ -59,7 +61,7 @@ type consoleWs struct {
 	// terminal height
 	height int
 
-	// channel type (either console or vga)
+	// channel type (either console, vga or vnc)
 	protocol string
 }
 
@@ -80,7 +82,7 @@ func (s *consoleWs) Connect(op *operations.Operation, r *http.Request, w http.Re
 	switch s.protocol {
 	case instance.ConsoleTypeConsole:
 		return s.connectConsole(op, r, w)
-	case instance.ConsoleTypeVGA:
+	case instance.ConsoleTypeVGA, instance.ConsoleTypeVNC:
 		return s.connectVGA(op, r, w)
 	default:
 		return fmt.Errorf("Unknown protocol %q", s.protocol)
@@ -157,7 +159,7 @@ func (s *consoleWs) connectVGA(op *operations.Operation, r *http.Request, w http
 
 		logger.Debug("VGA dynamic websocket connected")
 
-		console, _, err := s.instance.Console("vga")
+		console, _, err := s.instance.Console(s.protocol)
 		if err != nil {
 			_ = conn.Close()
 			return err
@@ -193,7 +195,7 @@ func (s *consoleWs) Do(op *operations.Operation) error {
 	switch s.protocol {
 	case instance.ConsoleTypeConsole:
 		return s.doConsole(op)
-	case instance.ConsoleTypeVGA:
+	case instance.ConsoleTypeVGA, instance.ConsoleTypeVNC:
 		return s.doVGA(op)
 	default:
 		return fmt.Errorf("Unknown protocol %q", s.protocol)
@@ -469,7 +471,7 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 	}
 
 	// Basic parameter validation.
-	if !shared.ValueInSlice(post.Type, []string{instance.ConsoleTypeConsole, instance.ConsoleTypeVGA}) {
+	if !shared.ValueInSlice(post.Type, []string{instance.ConsoleTypeConsole, instance.ConsoleTypeVGA, instance.ConsoleTypeVNC}) {
 		return response.BadRequest(fmt.Errorf("Unknown console type %q", post.Type))
 	}
 
@@ -482,6 +484,10 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("VGA console is only supported by virtual machines"))
 	}
 
+	if post.Type == instance.ConsoleTypeVNC && inst.Type() != instancetype.VM {
+		return response.BadRequest(fmt.Errorf("VNC console is only supported by virtual machines"))
+	}
+
 	if !inst.IsRunning() {
 		return response.BadRequest(fmt.Errorf("Instance is not running"))
 	}
@@ -529,7 +535,7 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 //
 //	Get console log
 //
-//	Gets the console log for the instance.
+//	Gets the console log for the instance, or a screenshot of the display of a virtual machine.
 //
 //	---
 //	produces:
@@ -540,9 +546,14 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: type
+//	    description: Type of output (console or screenshot)
+//	    type: string
+//	    example: console
 //	responses:
 //	  "200":
-//	     description: Raw console log
+//	     description: Raw console log or PNG screenshot
 //	     content:
 //	       application/octet-stream:
 //	         schema:
@@ -584,6 +595,13 @@ func instanceConsoleLogGet(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	consoleType := request.QueryParam(r, "type")
+	if consoleType == "screenshot" {
+		return instanceConsoleScreenshotGet(s, r, projectName, name)
+	} else if consoleType != "" && consoleType != instance.ConsoleTypeConsole {
+		return response.BadRequest(fmt.Errorf("Unknown console type %q", consoleType))
+	}
+
 	if !liblxc.RuntimeLiblxcVersionAtLeast(liblxc.Version(), 3, 0, 0) {
 		return response.BadRequest(fmt.Errorf("Querying the console buffer requires liblxc >= 3.0"))
 	}
@@ -637,6 +655,75 @@ func instanceConsoleLogGet(d *Daemon, r *http.Request) response.Response {
 	return response.FileResponse(r, []response.FileResponseEntry{ent}, nil)
 }
 
+// instanceConsoleScreenshotGet returns a PNG screenshot of the display of a running virtual machine.
+func instanceConsoleScreenshotGet(s *state.State, r *http.Request, projectName string, name string) response.Response {
+	// The screenshot shows the console, so viewing the instance isn't enough.
+	err := s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectInstance(projectName, name), auth.EntitlementCanAccessConsole)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if inst.Type() != instancetype.VM {
+		return response.BadRequest(fmt.Errorf("Screenshots are only supported by virtual machines"))
+	}
+
+	if !inst.IsRunning() {
+		return response.BadRequest(fmt.Errorf("Instance is not running"))
+	}
+
+	v, ok := inst.(instance.VM)
+	if !ok {
+		return response.InternalError(fmt.Errorf("Failed casting instance to VM"))
+	}
+
+	// Keep the screenshot next to the other runtime files of the instance rather than in a shared temporary directory.
+	tmpFile, err := os.CreateTemp(inst.DevicesPath(), "screenshot_")
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	screenshotPath := tmpFile.Name()
+	_ = tmpFile.Close()
+
+	err = v.ConsoleScreenshot(screenshotPath)
+	if err != nil {
+		_ = os.Remove(screenshotPath)
+		return response.SmartError(err)
+	}
+
+	screenshotFile, err := os.Open(screenshotPath)
+	if err != nil {
+		_ = os.Remove(screenshotPath)
+		return response.InternalError(err)
+	}
+
+	cleanup := func() {
+		_ = screenshotFile.Close()
+		_ = os.Remove(screenshotPath)
+	}
+
+	fi, err := screenshotFile.Stat()
+	if err != nil {
+		cleanup()
+		return response.InternalError(err)
+	}
+
+	ent := response.FileResponseEntry{
+		Filename:     fmt.Sprintf("%s.png", name),
+		File:         screenshotFile,
+		FileSize:     fi.Size(),
+		FileModified: fi.ModTime(),
+		Cleanup:      cleanup,
+	}
+
+	return response.FileResponse(r, []response.FileResponseEntry{ent}, map[string]string{"Content-Type": "image/png"})
+}
+
 // swagger:operation DELETE /1.0/instances/{name}/console instances instance_console_delete
 //
 //	Clear the console log
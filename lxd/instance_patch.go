@@ -21,6 +21,7 @@ import (
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	apiScriptlet "github.com/canonical/lxd/shared/api/scriptlet"
 	"github.com/canonical/lxd/shared/osarch"
 )
 
@@ -183,7 +184,7 @@ func instancePatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
-	// Check project limits.
+	// Load the requested profiles.
 	apiProfiles := make([]api.Profile, 0, len(req.Profiles))
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		profiles, err := cluster.GetProfilesIfEnabled(ctx, tx.Tx(), projectName, req.Profiles)
@@ -200,6 +201,20 @@ func instancePatch(d *Daemon, r *http.Request) response.Response {
 			apiProfiles = append(apiProfiles, *apiProfile)
 		}
 
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Run instance validation scriptlet if enabled.
+	err = instanceValidationScriptletRun(context.TODO(), s, projectName, name, api.InstanceType(c.Type().String()), apiScriptlet.InstanceValidationReasonUpdate, &req, apiProfiles)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Check project limits.
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		return projecthelpers.AllowInstanceUpdate(tx, projectName, name, req, c.LocalConfig())
 	})
 	if err != nil {
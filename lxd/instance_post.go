@@ -441,6 +441,33 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 	return operations.OperationResponse(op)
 }
 
+// instancePostChangesConfig returns whether the config and devices provided with a move request differ from the
+// instance's local config and devices.
+func instancePostChangesConfig(inst instance.Instance, config map[string]string, devices map[string]map[string]string) bool {
+	localConfig := inst.LocalConfig()
+	for k, v := range config {
+		if localConfig[k] != v {
+			return true
+		}
+	}
+
+	localDevices := inst.LocalDevices()
+	for devName, dev := range devices {
+		localDev, found := localDevices[devName]
+		if !found || len(localDev) != len(dev) {
+			return true
+		}
+
+		for k, v := range dev {
+			if localDev[k] != v {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // Move an instance.
 func instancePostMigration(s *state.State, inst instance.Instance, newName string, newPool string, newProject string, config map[string]string, devices map[string]map[string]string, profiles []string, instanceOnly bool, stateful bool, allowInconsistent bool, op *operations.Operation) error {
 	if inst.IsSnapshot() {
@@ -451,6 +478,27 @@ func instancePostMigration(s *state.State, inst instance.Instance, newName strin
 		newProject = inst.Project().Name
 	}
 
+	// Running virtual machines that only change pool can have their root disk mirrored to the new pool
+	// without being stopped.
+	if inst.IsRunning() && stateful && inst.Type() == instancetype.VM && newPool != "" && newName == inst.Name() && newProject == inst.Project().Name && profiles == nil && !instancePostChangesConfig(inst, config, devices) {
+		snapshots, err := inst.Snapshots()
+		if err != nil {
+			return err
+		}
+
+		// Snapshots aren't attached to the running instance, so they can't be mirrored.
+		if len(snapshots) > 0 {
+			return fmt.Errorf("Running virtual machines with snapshots can't be moved to another storage pool, stop the instance or delete its snapshots first")
+		}
+
+		vm, ok := inst.(instance.VM)
+		if !ok {
+			return fmt.Errorf("Instance is not a virtual machine")
+		}
+
+		return vm.MoveStoragePool(newPool)
+	}
+
 	statefulStart := false
 	if inst.IsRunning() {
 		if stateful {
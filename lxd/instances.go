@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -14,11 +15,14 @@ import (
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
 	"github.com/canonical/lxd/lxd/db/warningtype"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/lxd/warnings"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
@@ -481,3 +485,170 @@ func instancesShutdown(s *state.State, instances []instance.Instance) {
 	wg.Wait()
 	close(instShutdownCh)
 }
+
+// Possible instance power schedule actions.
+const (
+	instancePowerScheduleStart = "start"
+	instancePowerScheduleStop  = "stop"
+)
+
+// instancePowerScheduleAction returns the power schedule action that is due now for the instance, if any.
+// A due stop schedule takes precedence over a due start schedule.
+func instancePowerScheduleAction(inst instance.Instance) string {
+	config := inst.ExpandedConfig()
+
+	stopSchedule := config["boot.stop_schedule"]
+	if stopSchedule != "" && inst.IsRunning() && snapshotIsScheduledNow(stopSchedule, int64(inst.ID())) {
+		return instancePowerScheduleStop
+	}
+
+	startSchedule := config["boot.start_schedule"]
+	if startSchedule != "" && !inst.IsRunning() && snapshotIsScheduledNow(startSchedule, int64(inst.ID())) {
+		// Don't start an instance whose stop schedule is also due now.
+		if stopSchedule != "" && snapshotIsScheduledNow(stopSchedule, int64(inst.ID())) {
+			return ""
+		}
+
+		return instancePowerScheduleStart
+	}
+
+	return ""
+}
+
+// instancesApplyPowerSchedules starts and stops the given instances according to their power schedules.
+func instancesApplyPowerSchedules(ctx context.Context, s *state.State, instances map[string][]instance.Instance) error {
+	// Stop instances first, in stop priority order.
+	stopInstances := instances[instancePowerScheduleStop]
+	sort.Sort(instanceStopList(stopInstances))
+
+	for _, inst := range stopInstances {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+		// Determine how long to wait for the instance to shutdown cleanly.
+		timeoutSeconds := 30
+		value, ok := inst.ExpandedConfig()["boot.host_shutdown_timeout"]
+		if ok {
+			timeoutSeconds, _ = strconv.Atoi(value)
+		}
+
+		err = inst.Shutdown(time.Second * time.Duration(timeoutSeconds))
+		if err != nil {
+			l.Warn("Failed scheduled shut down of instance, forcefully stopping", logger.Ctx{"err": err})
+			err = inst.Stop(false)
+			if err != nil {
+				l.Error("Failed scheduled stop of instance", logger.Ctx{"err": err})
+				continue
+			}
+		}
+
+		l.Info("Stopped instance on schedule")
+	}
+
+	// Then start instances, in autostart priority order.
+	startInstances := instances[instancePowerScheduleStart]
+	sort.Sort(instanceAutostartList(startInstances))
+
+	for _, inst := range startInstances {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+		err = inst.Start(false)
+		if err != nil {
+			l.Error("Failed scheduled start of instance", logger.Ctx{"err": err})
+			continue
+		}
+
+		l.Info("Started instance on schedule")
+	}
+
+	return nil
+}
+
+func instancesPowerScheduleTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		// Only consider instances located on the local member.
+		instances := make(map[string][]instance.Instance)
+		filter := cluster.InstanceFilter{Node: &s.ServerName}
+		err := s.DB.Cluster.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				logger.Error("Failed loading instance for power schedule task", logger.Ctx{"instance": dbInst.Name, "project": dbInst.Project, "err": err})
+				return nil
+			}
+
+			action := instancePowerScheduleAction(inst)
+			if action == "" {
+				return nil
+			}
+
+			// Don't start instances on an evacuated member.
+			if action == instancePowerScheduleStart && s.DB.Cluster.LocalNodeIsEvacuated() {
+				return nil
+			}
+
+			logger.Debug("Scheduling instance power change", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "action": action})
+			instances[action] = append(instances[action], inst)
+
+			return nil
+		}, filter)
+		if err != nil {
+			logger.Error("Failed getting instance power schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(instances) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return instancesApplyPowerSchedules(ctx, s, instances)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.InstancesPowerSchedule, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating instance power schedule operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Applying instance power schedules")
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting instance power schedule operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed applying instance power schedules", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done applying instance power schedules")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
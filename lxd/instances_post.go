@@ -13,6 +13,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	"github.com/canonical/lxd/lxd/archive"
+	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/backup"
 	"github.com/canonical/lxd/lxd/cluster"
 	"github.com/canonical/lxd/lxd/db"
@@ -576,6 +577,16 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		return response.InternalError(err)
 	}
 
+	// Encrypted backups must be decrypted by the client before being uploaded.
+	encrypted, err := backup.IsEncrypted(backupFile)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	if encrypted {
+		return response.BadRequest(backup.ErrEncrypted)
+	}
+
 	// Detect squashfs compression and convert to tarball.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
@@ -625,6 +636,17 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		return response.BadRequest(err)
 	}
 
+	// Incremental backups are applied on top of the instance they were generated from.
+	if bInfo.IncrementalFrom != "" {
+		bInfo.Project = projectName
+
+		if instanceName != "" {
+			bInfo.Name = instanceName
+		}
+
+		return createFromIncrementalBackup(s, r, bInfo, backupFile, revert)
+	}
+
 	// Check project permissions.
 	err = s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
 		req := api.InstancesPost{
@@ -756,6 +778,125 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 	return operations.OperationResponse(op)
 }
 
+// createFromIncrementalBackup applies an incremental backup onto the existing stopped instance whose latest
+// snapshot is the base snapshot of the backup.
+func createFromIncrementalBackup(s *state.State, r *http.Request, bInfo *backup.Info, backupFile *os.File, revert *revert.Reverter) response.Response {
+	// Applying an incremental backup replaces the volume and config of an existing instance, so creating
+	// instances in the project isn't enough.
+	err := s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectInstance(bInfo.Project, bInfo.Name), auth.EntitlementCanEdit)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if bInfo.Config == nil || bInfo.Config.Container == nil {
+		return response.BadRequest(fmt.Errorf("Backup doesn't contain the instance config"))
+	}
+
+	unlock, err := instanceOperationLock(s.ShutdownCtx, bInfo.Project, bInfo.Name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	revert.Add(func() {
+		unlock()
+	})
+
+	inst, err := instance.LoadByProjectAndName(s, bInfo.Project, bInfo.Name)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading instance %q to apply incremental backup to: %w", bInfo.Name, err))
+	}
+
+	if s.ServerClustered && inst.Location() != s.ServerName {
+		return response.BadRequest(fmt.Errorf("Incremental backups must be applied on the cluster member the instance %q is located on (%q)", bInfo.Name, inst.Location()))
+	}
+
+	if inst.IsRunning() {
+		return response.BadRequest(fmt.Errorf("Instance %q must be stopped to apply an incremental backup", bInfo.Name))
+	}
+
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	latestSnapshot := ""
+	if len(snapshots) > 0 {
+		_, latestSnapshot, _ = api.GetParentAndSnapshotName(snapshots[len(snapshots)-1].Name())
+	}
+
+	if latestSnapshot != bInfo.BaseSnapshot {
+		return response.BadRequest(fmt.Errorf("Latest snapshot of instance %q doesn't match the base snapshot %q of the incremental backup", bInfo.Name, bInfo.BaseSnapshot))
+	}
+
+	// Check project limits and restrictions against the instance config contained in the backup.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return project.AllowInstanceUpdate(tx, bInfo.Project, bInfo.Name, bInfo.Config.Container.InstancePut, inst.LocalConfig())
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	logger.Debug("Incremental backup file info loaded", logger.Ctx{
+		"type":            bInfo.Type,
+		"name":            bInfo.Name,
+		"project":         bInfo.Project,
+		"backend":         bInfo.Backend,
+		"optimized":       *bInfo.OptimizedStorage,
+		"snapshots":       bInfo.Snapshots,
+		"incrementalFrom": bInfo.IncrementalFrom,
+		"baseSnapshot":    bInfo.BaseSnapshot,
+	})
+
+	// Copy reverter so far so we can use it inside run after this function has finished.
+	runRevert := revert.Clone()
+
+	run := func(op *operations.Operation) error {
+		defer func() { _ = backupFile.Close() }()
+		defer runRevert.Fail() // Releases the instance operation lock on failure.
+
+		// The instance may have been started since the request was accepted.
+		if inst.IsRunning() {
+			return fmt.Errorf("Instance %q must be stopped to apply an incremental backup", bInfo.Name)
+		}
+
+		pool, err := storagePools.LoadByInstance(s, inst)
+		if err != nil {
+			return err
+		}
+
+		// Check if the backup is optimized that the source pool driver matches the target pool driver.
+		if *bInfo.OptimizedStorage && pool.Driver().Info().Name != bInfo.Backend {
+			return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
+		}
+
+		err = pool.RefreshInstanceFromBackup(inst, *bInfo, backupFile, op)
+		if err != nil {
+			return fmt.Errorf("Failed applying incremental backup: %w", err)
+		}
+
+		err = internalImportFromIncrementalBackup(s, inst, bInfo)
+		if err != nil {
+			return fmt.Errorf("Failed importing incremental backup: %w", err)
+		}
+
+		runRevert.Success()
+		unlock()
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", bInfo.Name)}
+
+	op, err := operations.OperationCreate(s, bInfo.Project, operations.OperationClassTask, operationtype.BackupRestore, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	revert.Success()
+	return operations.OperationResponse(op)
+}
+
 // swagger:operation POST /1.0/instances instances instances_post
 //
 //	Create a new instance
@@ -1047,15 +1188,6 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			return nil
 		}
 
-		if !clusterNotification {
-			// Check that the project's limits are not violated. Note this check is performed after
-			// automatically generated config values (such as ones from an InstanceType) have been set.
-			err = project.AllowInstanceCreation(tx, targetProjectName, req)
-			if err != nil {
-				return err
-			}
-		}
-
 		return nil
 	})
 	if err != nil {
@@ -1067,6 +1199,15 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// Run instance validation scriptlet if enabled. Requests forwarded by another cluster member have
+	// already been validated (and possibly modified) by the scriptlet on that member.
+	if !clusterNotification && r.Context().Value(request.CtxProtocol) != "cluster" {
+		err = instanceValidationScriptletRun(r.Context(), s, targetProjectName, req.Name, req.Type, apiScriptlet.InstanceValidationReasonCreate, &req.InstancePut, profiles)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	if s.ServerClustered && !clusterNotification && targetMemberInfo == nil {
 		// Run instance placement scriptlet if enabled and no cluster member selected yet.
 		if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
@@ -1103,6 +1244,18 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	if !clusterNotification {
+		// Check that the project's limits are not violated. Note this check is performed after
+		// automatically generated config values (such as ones from an InstanceType) have been set and
+		// after the instance validation scriptlet has had the chance to modify the request.
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return project.AllowInstanceCreation(tx, targetProjectName, req)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	if targetMemberInfo != nil && targetMemberInfo.Address != "" && targetMemberInfo.Name != s.ServerName {
 		client, err := cluster.Connect(targetMemberInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), r, false)
 		if err != nil {
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+)
+
+func (suite *containerTestSuite) TestInstancePowerScheduleAction() {
+	args := db.InstanceArgs{
+		Type:      instancetype.Container,
+		Ephemeral: false,
+		Name:      "hal9001",
+		Config: map[string]string{
+			"boot.start_schedule": "* * * * *",
+		},
+	}
+
+	c, op, _, err := instance.CreateInternal(suite.d.State(), args, true)
+	suite.Req.Nil(err)
+	op.Done(nil)
+	defer func() { _ = c.Delete(true) }()
+
+	suite.Equal(instancePowerScheduleStart, instancePowerScheduleAction(c),
+		"boot.start_schedule config '* * * * *' should start a stopped instance now")
+
+	// A due stop schedule takes precedence over a due start schedule.
+	args.Name = "hal9002"
+	args.Config["boot.stop_schedule"] = "* * * * *"
+
+	c, op, _, err = instance.CreateInternal(suite.d.State(), args, true)
+	suite.Req.Nil(err)
+	op.Done(nil)
+	defer func() { _ = c.Delete(true) }()
+
+	suite.Equal("", instancePowerScheduleAction(c),
+		"a stopped instance with a due stop schedule should not be started")
+}
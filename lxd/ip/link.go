@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/canonical/lxd/shared"
 )
@@ -459,3 +460,43 @@ func (l *Link) BridgeLinkSetHairpin(hairpin bool) error {
 
 	return nil
 }
+
+// BridgeFDBAppend appends a forwarding database entry for the given MAC address and destination.
+func (l *Link) BridgeFDBAppend(mac string, dst string) error {
+	_, err := shared.RunCommand("bridge", "fdb", "append", mac, "dev", l.Name, "dst", dst)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BridgeFDBDelete removes the forwarding database entry for the given MAC address and destination.
+func (l *Link) BridgeFDBDelete(mac string, dst string) error {
+	_, err := shared.RunCommand("bridge", "fdb", "del", mac, "dev", l.Name, "dst", dst)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BridgeFDBDestinations returns the destinations of the forwarding database entries for the given MAC address.
+func (l *Link) BridgeFDBDestinations(mac string) ([]string, error) {
+	out, err := shared.RunCommand("bridge", "fdb", "show", "dev", l.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	dsts := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != mac || fields[1] != "dst" {
+			continue
+		}
+
+		dsts = append(dsts, fields[2])
+	}
+
+	return dsts, nil
+}
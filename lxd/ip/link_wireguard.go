@@ -0,0 +1,80 @@
+package ip
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+)
+
+// Wireguard represents arguments for link device of type wireguard.
+type Wireguard struct {
+	Link
+}
+
+// WireguardPeer represents a wireguard peer.
+type WireguardPeer struct {
+	PublicKey           string
+	Endpoint            string
+	AllowedIPs          []string
+	PersistentKeepalive uint32
+}
+
+// Add adds new virtual link.
+func (w *Wireguard) Add() error {
+	return w.Link.add("wireguard", nil)
+}
+
+// SetListener sets the UDP listen port and the path to the private key file of the wireguard device.
+func (w *Wireguard) SetListener(port uint16, privateKeyPath string) error {
+	_, err := shared.RunCommand("wg", "set", w.Name, "listen-port", fmt.Sprintf("%d", port), "private-key", privateKeyPath)
+	if err != nil {
+		return fmt.Errorf("Failed setting wireguard listener: %w", err)
+	}
+
+	return nil
+}
+
+// SetPeer adds or updates a wireguard peer.
+func (w *Wireguard) SetPeer(peer WireguardPeer) error {
+	cmd := []string{"set", w.Name, "peer", peer.PublicKey}
+
+	if peer.Endpoint != "" {
+		cmd = append(cmd, "endpoint", peer.Endpoint)
+	}
+
+	if len(peer.AllowedIPs) > 0 {
+		cmd = append(cmd, "allowed-ips", strings.Join(peer.AllowedIPs, ","))
+	}
+
+	if peer.PersistentKeepalive > 0 {
+		cmd = append(cmd, "persistent-keepalive", fmt.Sprintf("%d", peer.PersistentKeepalive))
+	}
+
+	_, err := shared.RunCommand("wg", cmd...)
+	if err != nil {
+		return fmt.Errorf("Failed setting wireguard peer: %w", err)
+	}
+
+	return nil
+}
+
+// RemovePeer removes a wireguard peer.
+func (w *Wireguard) RemovePeer(publicKey string) error {
+	_, err := shared.RunCommand("wg", "set", w.Name, "peer", publicKey, "remove")
+	if err != nil {
+		return fmt.Errorf("Failed removing wireguard peer: %w", err)
+	}
+
+	return nil
+}
+
+// Peers returns the public keys of the wireguard device's peers.
+func (w *Wireguard) Peers() ([]string, error) {
+	out, err := shared.RunCommand("wg", "show", w.Name, "peers")
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing wireguard peers: %w", err)
+	}
+
+	return strings.Fields(out), nil
+}
@@ -140,6 +140,13 @@ func (c *cmdActivateifneeded) Run(cmd *cobra.Command, args []string) error {
 			_, err := lxd.ConnectLXDUnix("", nil)
 			return err
 		}
+
+		// Check for instance power schedules
+		if config["boot.start_schedule"] != "" || config["boot.stop_schedule"] != "" {
+			logger.Debugf("Daemon has scheduled instance power changes, activating...")
+			_, err := lxd.ConnectLXDUnix("", nil)
+			return err
+		}
 	}
 
 	// Check for scheduled volume snapshots
@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LatencyBuckets are the default histogram bucket upper bounds (in seconds) used for request latencies.
+var LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DurationBuckets are the default histogram bucket upper bounds (in seconds) used for long running tasks.
+var DurationBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600}
+
+// APIRequestDuration tracks the duration of API requests by method and endpoint.
+var APIRequestDuration = NewHistogram(LatencyBuckets)
+
+// OperationDuration tracks the duration of operations by operation type and final status.
+var OperationDuration = NewHistogram(DurationBuckets)
+
+// DatabaseTransactionDuration tracks the duration of cluster database transactions.
+var DatabaseTransactionDuration = NewHistogram(LatencyBuckets)
+
+// Histogram represents a set of OpenMetrics histograms sharing the same buckets, keyed by their labels.
+type Histogram struct {
+	buckets []float64
+	series  map[string]*histogramSeries
+	mu      sync.Mutex
+}
+
+type histogramSeries struct {
+	labels map[string]string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram returns a new Histogram using the given bucket upper bounds.
+func NewHistogram(buckets []float64) *Histogram {
+	sortedBuckets := make([]float64, len(buckets))
+	copy(sortedBuckets, buckets)
+	sort.Float64s(sortedBuckets)
+
+	return &Histogram{
+		buckets: sortedBuckets,
+		series:  make(map[string]*histogramSeries),
+	}
+}
+
+// histogramSeriesKey returns a stable key for the given set of labels.
+func histogramSeriesKey(labels map[string]string) string {
+	labelNames := make([]string, 0, len(labels))
+	for labelName := range labels {
+		labelNames = append(labelNames, labelName)
+	}
+
+	sort.Strings(labelNames)
+
+	var key strings.Builder
+	for _, labelName := range labelNames {
+		key.WriteString(strconv.Quote(labelName))
+		key.WriteString("=")
+		key.WriteString(strconv.Quote(labels[labelName]))
+		key.WriteString(",")
+	}
+
+	return key.String()
+}
+
+// Observe records the given value in the histogram series identified by labels.
+func (h *Histogram) Observe(labels map[string]string, value float64) {
+	key := histogramSeriesKey(labels)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	series, ok := h.series[key]
+	if !ok {
+		seriesLabels := make(map[string]string, len(labels))
+		for k, v := range labels {
+			seriesLabels[k] = v
+		}
+
+		series = &histogramSeries{
+			labels: seriesLabels,
+			counts: make([]uint64, len(h.buckets)),
+		}
+
+		h.series[key] = series
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+
+	series.count++
+	series.sum += value
+}
+
+// Samples returns the cumulative bucket, sum and count samples of all series in the histogram.
+func (h *Histogram) Samples() []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	samples := make([]Sample, 0, len(keys)*(len(h.buckets)+3))
+	for _, key := range keys {
+		series := h.series[key]
+
+		newLabels := func(extra map[string]string) map[string]string {
+			labels := make(map[string]string, len(series.labels)+len(extra))
+			for k, v := range series.labels {
+				labels[k] = v
+			}
+
+			for k, v := range extra {
+				labels[k] = v
+			}
+
+			return labels
+		}
+
+		for i, bound := range h.buckets {
+			samples = append(samples, Sample{
+				Labels: newLabels(map[string]string{"le": strconv.FormatFloat(bound, 'g', -1, 64)}),
+				Value:  float64(series.counts[i]),
+				suffix: "_bucket",
+			})
+		}
+
+		samples = append(samples,
+			Sample{
+				Labels: newLabels(map[string]string{"le": strconv.FormatFloat(math.Inf(1), 'g', -1, 64)}),
+				Value:  float64(series.count),
+				suffix: "_bucket",
+			},
+			Sample{
+				Labels: newLabels(nil),
+				Value:  series.sum,
+				suffix: "_sum",
+			},
+			Sample{
+				Labels: newLabels(nil),
+				Value:  float64(series.count),
+				suffix: "_count",
+			},
+		)
+	}
+
+	return samples
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]float64{1, 0.1})
+	h.Observe(map[string]string{"method": "GET"}, 0.05)
+	h.Observe(map[string]string{"method": "GET"}, 0.5)
+	h.Observe(map[string]string{"method": "GET"}, 2)
+
+	m := NewMetricSet(nil)
+	m.AddSamples(APIRequestDurationSeconds, h.Samples()...)
+
+	// Histograms without observations don't add any samples.
+	m.AddSamples(OperationDurationSeconds, NewHistogram(DurationBuckets).Samples()...)
+
+	expected := `# HELP lxd_api_request_duration_seconds The duration of API requests in seconds.
+# TYPE lxd_api_request_duration_seconds histogram
+lxd_api_request_duration_seconds_bucket{le="0.1",method="GET"} 1
+lxd_api_request_duration_seconds_bucket{le="1",method="GET"} 2
+lxd_api_request_duration_seconds_bucket{le="+Inf",method="GET"} 3
+lxd_api_request_duration_seconds_sum{method="GET"} 2.55
+lxd_api_request_duration_seconds_count{method="GET"} 3
+# EOF
+`
+
+	require.Equal(t, expected, m.String())
+}
@@ -53,6 +53,10 @@ func (m *MetricSet) FilterSamples(permissionChecker func(object auth.Object) boo
 
 // AddSamples adds samples of the type metricType to the MetricSet.
 func (m *MetricSet) AddSamples(metricType MetricType, samples ...Sample) {
+	if len(samples) == 0 {
+		return
+	}
+
 	for i := 0; i < len(samples); i++ {
 		// Add global labels to samples
 		for labelName, labelValue := range m.labels {
@@ -111,6 +115,12 @@ func (m *MetricSet) String() string {
 		VMs,
 	}
 
+	histogramMetrics := []MetricType{
+		APIRequestDurationSeconds,
+		OperationDurationSeconds,
+		DatabaseTransactionDurationSeconds,
+	}
+
 	for _, metricType := range metricTypes {
 		// Add HELP message as specified by OpenMetrics
 		_, err := out.WriteString(MetricHeaders[metricType] + "\n")
@@ -121,7 +131,9 @@ func (m *MetricSet) String() string {
 		metricTypeName := ""
 
 		// ProcsTotal is a gauge according to the OpenMetrics spec as its value can decrease.
-		if shared.ValueInSlice(metricType, gaugeMetrics) {
+		if shared.ValueInSlice(metricType, histogramMetrics) {
+			metricTypeName = "histogram"
+		} else if shared.ValueInSlice(metricType, gaugeMetrics) {
 			metricTypeName = "gauge"
 		} else if strings.HasSuffix(MetricNames[metricType], "_total") || strings.HasSuffix(MetricNames[metricType], "_seconds") {
 			metricTypeName = "counter"
@@ -159,9 +171,9 @@ func (m *MetricSet) String() string {
 			valueStr := strconv.FormatFloat(sample.Value, 'g', -1, 64)
 
 			if labels != "" {
-				_, err = out.WriteString(fmt.Sprintf("%s{%s} %s\n", MetricNames[metricType], labels, valueStr))
+				_, err = out.WriteString(fmt.Sprintf("%s%s{%s} %s\n", MetricNames[metricType], sample.suffix, labels, valueStr))
 			} else {
-				_, err = out.WriteString(fmt.Sprintf("%s %s\n", MetricNames[metricType], valueStr))
+				_, err = out.WriteString(fmt.Sprintf("%s%s %s\n", MetricNames[metricType], sample.suffix, valueStr))
 			}
 
 			if err != nil {
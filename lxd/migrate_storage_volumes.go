@@ -12,6 +12,7 @@ import (
 
 	"github.com/canonical/lxd/lxd/migration"
 	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
 	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
@@ -101,7 +102,8 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 	// to false here. The migration source/sender doesn't need to care whether
 	// or not it's doing a refresh as the migration sink/receiver will know
 	// this, and adjust the migration types accordingly.
-	poolMigrationTypes = pool.MigrationTypes(storageDrivers.ContentType(srcConfig.Volume.ContentType), false, !s.volumeOnly)
+	srcVol := pool.GetVolume(storageDrivers.VolumeTypeCustom, storageDrivers.ContentType(srcConfig.Volume.ContentType), project.StorageVolume(projectName, volName), srcConfig.Volume.Config)
+	poolMigrationTypes = storagePools.VolumeMigrationTypes(pool, srcVol, false, !s.volumeOnly)
 	if len(poolMigrationTypes) == 0 {
 		return fmt.Errorf("No source migration types available")
 	}
@@ -291,10 +293,23 @@ func (c *migrationSink) DoStorage(state *state.State, projectName string, poolNa
 	// Refresh needs to be set.
 	offerHeader.Refresh = &c.refresh
 
+	// When refreshing, the existing volume's config determines whether the volume is encrypted.
+	volConfig := req.Config
+	if c.refresh {
+		dbVol, err := storagePools.VolumeDBGet(pool, projectName, req.Name, storageDrivers.VolumeTypeCustom)
+		if err != nil {
+			return err
+		}
+
+		volConfig = dbVol.Config
+	}
+
+	vol := pool.GetVolume(storageDrivers.VolumeTypeCustom, contentType, project.StorageVolume(projectName, req.Name), volConfig)
+
 	// Extract the source's migration type and then match it against our pool's
 	// supported types and features. If a match is found the combined features list
 	// will be sent back to requester.
-	respTypes, err := migration.MatchTypes(offerHeader, storagePools.FallbackMigrationType(contentType), pool.MigrationTypes(contentType, c.refresh, !c.volumeOnly))
+	respTypes, err := migration.MatchTypes(offerHeader, storagePools.FallbackMigrationType(contentType), storagePools.VolumeMigrationTypes(pool, vol, c.refresh, !c.volumeOnly))
 	if err != nil {
 		return err
 	}
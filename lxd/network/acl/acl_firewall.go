@@ -2,7 +2,10 @@ package acl
 
 import (
 	"fmt"
+	"net"
+	"strings"
 
+	"github.com/canonical/lxd/lxd/db"
 	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/shared"
@@ -15,24 +18,83 @@ func FirewallApplyACLRules(s *state.State, logger logger.Logger, aclProjectName
 	var dropRules []firewallDrivers.ACLRule
 	var rejectRules []firewallDrivers.ACLRule
 	var allowRules []firewallDrivers.ACLRule
+	var peerSubnets map[db.NetworkPeer][]string
+
+	// convertSubjects replaces the network peer subjects in a list of subjects with the subnets of the target
+	// network of the peering.
+	convertSubjects := func(subjects string) (string, error) {
+		if !strings.Contains(subjects, "@") {
+			return subjects, nil
+		}
+
+		var err error
+		if peerSubnets == nil {
+			peerSubnets, err = firewallPeerSubnets(s, aclProjectName)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		var newSubjects []string
+		for _, subject := range shared.SplitNTrimSpace(subjects, ",", -1, false) {
+			peerParts := strings.SplitN(strings.TrimPrefix(subject, "@"), "/", 2)
+			if !strings.HasPrefix(subject, "@") || len(peerParts) != 2 {
+				newSubjects = append(newSubjects, subject)
+				continue
+			}
+
+			peer := db.NetworkPeer{
+				NetworkName: peerParts[0],
+				PeerName:    peerParts[1],
+			}
+
+			if peer.NetworkName != aclNet.Name {
+				return "", fmt.Errorf(`ACL requiring peer "%s/%s" cannot be applied to network %q`, peer.NetworkName, peer.PeerName, aclNet.Name)
+			}
+
+			subnets, found := peerSubnets[peer]
+			if !found {
+				return "", fmt.Errorf("Cannot find target network for peer %q", subject)
+			}
+
+			if len(subnets) == 0 {
+				return "", fmt.Errorf("Target network of peer %q has no subnets", subject)
+			}
+
+			newSubjects = append(newSubjects, subnets...)
+		}
+
+		return strings.Join(newSubjects, ","), nil
+	}
 
 	// convertACLRules converts the ACL rules to Firewall ACL rules.
-	convertACLRules := func(direction string, logPrefix string, rules ...api.NetworkACLRule) error {
+	convertACLRules := func(aclID int64, direction string, logPrefix string, rules ...api.NetworkACLRule) error {
 		for ruleIndex, rule := range rules {
 			if rule.State == "disabled" {
 				continue
 			}
 
+			source, err := convertSubjects(rule.Source)
+			if err != nil {
+				return err
+			}
+
+			destination, err := convertSubjects(rule.Destination)
+			if err != nil {
+				return err
+			}
+
 			firewallACLRule := firewallDrivers.ACLRule{
 				Direction:       direction,
 				Action:          rule.Action,
-				Source:          rule.Source,
-				Destination:     rule.Destination,
+				Source:          source,
+				Destination:     destination,
 				Protocol:        rule.Protocol,
 				SourcePort:      rule.SourcePort,
 				DestinationPort: rule.DestinationPort,
 				ICMPType:        rule.ICMPType,
 				ICMPCode:        rule.ICMPCode,
+				Name:            aclRuleName(aclID, direction, ruleIndex),
 			}
 
 			if rule.State == "logged" {
@@ -60,17 +122,17 @@ func FirewallApplyACLRules(s *state.State, logger logger.Logger, aclProjectName
 
 	// Load ACLs specified by network.
 	for _, aclName := range shared.SplitNTrimSpace(aclNet.Config["security.acls"], ",", -1, true) {
-		_, aclInfo, err := s.DB.Cluster.GetNetworkACL(aclProjectName, aclName)
+		aclID, aclInfo, err := s.DB.Cluster.GetNetworkACL(aclProjectName, aclName)
 		if err != nil {
 			return fmt.Errorf("Failed loading ACL %q for network %q: %w", aclName, aclNet.Name, err)
 		}
 
-		err = convertACLRules("ingress", logPrefix, aclInfo.Ingress...)
+		err = convertACLRules(aclID, "ingress", logPrefix, aclInfo.Ingress...)
 		if err != nil {
 			return fmt.Errorf("Failed converting ACL %q ingress rules for network %q: %w", aclInfo.Name, aclNet.Name, err)
 		}
 
-		err = convertACLRules("egress", logPrefix, aclInfo.Egress...)
+		err = convertACLRules(aclID, "egress", logPrefix, aclInfo.Egress...)
 		if err != nil {
 			return fmt.Errorf("Failed converting ACL %q egress rules for network %q: %w", aclInfo.Name, aclNet.Name, err)
 		}
@@ -102,6 +164,56 @@ func FirewallApplyACLRules(s *state.State, logger logger.Logger, aclProjectName
 	return s.Firewall.NetworkApplyACLRules(aclNet.Name, rules)
 }
 
+// firewallPeerSubnets returns the subnets of the target networks of the mutual peerings of the bridge networks in
+// the specified project.
+func firewallPeerSubnets(s *state.State, projectName string) (map[db.NetworkPeer][]string, error) {
+	peerTargetNetIDs, err := s.DB.Cluster.GetNetworkPeersTargetNetworkIDs(projectName, db.NetworkTypeBridge)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting peer connection mappings: %w", err)
+	}
+
+	peerSubnets := make(map[db.NetworkPeer][]string, len(peerTargetNetIDs))
+	for peer, targetNetID := range peerTargetNetIDs {
+		targetNetName, targetProjectName, err := s.DB.Cluster.GetNetworkNameAndProjectWithID(int(targetNetID))
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting target network of peer %q: %w", peer.PeerName, err)
+		}
+
+		_, targetNet, _, err := s.DB.Cluster.GetNetworkInAnyState(targetProjectName, targetNetName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading target network of peer %q: %w", peer.PeerName, err)
+		}
+
+		peerSubnets[peer] = firewallNetworkSubnets(targetNet.Config)
+	}
+
+	return peerSubnets, nil
+}
+
+// firewallNetworkSubnets returns the subnets and routes of a bridge network from its config.
+func firewallNetworkSubnets(netConfig map[string]string) []string {
+	subnets := []string{}
+
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		_, subnet, err := net.ParseCIDR(netConfig[key])
+		if err == nil {
+			subnets = append(subnets, subnet.String())
+		}
+	}
+
+	for _, key := range []string{"ipv4.routes", "ipv6.routes"} {
+		subnets = append(subnets, shared.SplitNTrimSpace(netConfig[key], ",", -1, true)...)
+	}
+
+	return subnets
+}
+
+// aclRuleName returns the name used to identify an ACL rule when reporting its hit counters.
+// This matches the log name used for the rule in OVN.
+func aclRuleName(aclID int64, direction string, ruleIndex int) string {
+	return fmt.Sprintf("%s%d-%s-%d", ovnACLPortGroupPrefix, aclID, direction, ruleIndex)
+}
+
 // firewallACLDefaults returns the action and logging mode to use for the specified direction's default rule.
 // If the security.acls.default.{in,e}gress.action or security.acls.default.{in,e}gress.logged settings are not
 // specified in the network config, then it returns "reject" and false respectively.
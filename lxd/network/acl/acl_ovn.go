@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/network/openvswitch"
+	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
@@ -341,9 +344,11 @@ func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.
 				return err
 			}
 
+			// Always name the rule so that its hit counters can be found.
+			ovnACLRule.LogName = fmt.Sprintf("%s-%s-%d", portGroupName, direction, ruleIndex)
+
 			if rule.State == "logged" {
 				ovnACLRule.Log = true
-				ovnACLRule.LogName = fmt.Sprintf("%s-%s-%d", portGroupName, direction, ruleIndex)
 			}
 
 			if networkSpecific {
@@ -1037,18 +1042,10 @@ func ovnParseLogEntry(input string, prefix string) string {
 	}
 
 	// Parse the ACL log entry.
-	aclEntry := map[string]string{}
-	for _, entry := range shared.SplitNTrimSpace(fields[4], ",", -1, true) {
-		pair := strings.Split(entry, "=")
-		if len(pair) != 2 {
-			continue
-		}
-
-		aclEntry[strings.Trim(pair[0], "\"")] = strings.Trim(pair[1], "\"")
-	}
+	ruleName, newEntry := ovnParseLogMessage(fields[4])
 
 	// Filter for our ACL.
-	if !strings.HasPrefix(aclEntry["name"], prefix) {
+	if newEntry == nil || !strings.HasPrefix(ruleName, prefix) {
 		return ""
 	}
 
@@ -1058,10 +1055,33 @@ func ovnParseLogEntry(input string, prefix string) string {
 		return ""
 	}
 
+	newEntry.Time = logTime.UTC().Format(time.RFC3339)
+
+	out, err := json.Marshal(newEntry)
+	if err != nil {
+		return ""
+	}
+
+	return string(out)
+}
+
+// ovnParseLogMessage parses the message of an OVN ACL log entry.
+// Returns the name of the matched ACL rule and the re-formated log entry (without its time), or nil if invalid.
+func ovnParseLogMessage(message string) (string, *ovnLogEntry) {
+	aclEntry := map[string]string{}
+	for _, entry := range shared.SplitNTrimSpace(message, ",", -1, true) {
+		pair := strings.Split(entry, "=")
+		if len(pair) != 2 {
+			continue
+		}
+
+		aclEntry[strings.Trim(pair[0], "\"")] = strings.Trim(pair[1], "\"")
+	}
+
 	// Get the protocol.
 	severityFields := strings.Split(aclEntry["severity"], " ")
 	if len(severityFields) != 2 {
-		return ""
+		return "", nil
 	}
 
 	protocol := severityFields[1]
@@ -1071,7 +1091,7 @@ func ovnParseLogEntry(input string, prefix string) string {
 	if !ok {
 		srcAddr, ok = aclEntry["ipv6_src"]
 		if !ok {
-			return ""
+			return "", nil
 		}
 	}
 
@@ -1079,13 +1099,12 @@ func ovnParseLogEntry(input string, prefix string) string {
 	if !ok {
 		dstAddr, ok = aclEntry["ipv6_dst"]
 		if !ok {
-			return ""
+			return "", nil
 		}
 	}
 
 	// Prepare the core log entry.
 	newEntry := ovnLogEntry{
-		Time:     logTime.UTC().Format(time.RFC3339),
 		Proto:    protocol,
 		Src:      srcAddr,
 		Dst:      dstAddr,
@@ -1105,10 +1124,101 @@ func ovnParseLogEntry(input string, prefix string) string {
 		newEntry.DstPort = dstPort
 	}
 
-	out, err := json.Marshal(&newEntry)
+	return aclEntry["name"], &newEntry
+}
+
+// ovnLogACLCacheDuration is how long the ACL identified by a log message's rule name is cached for.
+const ovnLogACLCacheDuration = time.Minute
+
+type ovnLogACLCacheEntry struct {
+	name        string
+	projectName string
+	expiry      time.Time
+}
+
+// ovnLogACLCache caches the name and project of ACLs by ID, so that ACL log messages don't each query the database.
+var ovnLogACLCache = map[int]ovnLogACLCacheEntry{}
+var ovnLogACLCacheLock sync.Mutex
+
+// ovnLogACL returns the name and project of the ACL with the given ID.
+// Returns empty strings if the ACL doesn't exist.
+func ovnLogACL(s *state.State, aclID int) (string, string, error) {
+	ovnLogACLCacheLock.Lock()
+	defer ovnLogACLCacheLock.Unlock()
+
+	cache, ok := ovnLogACLCache[aclID]
+	if ok && cache.expiry.After(time.Now()) {
+		return cache.name, cache.projectName, nil
+	}
+
+	aclName, aclProjectName, err := s.DB.Cluster.GetNetworkACLNameAndProjectWithID(aclID)
+	if err != nil && !response.IsNotFoundError(err) {
+		return "", "", err
+	}
+
+	// Remove expired entries so that the cache doesn't grow with deleted ACLs.
+	for id, cache := range ovnLogACLCache {
+		if cache.expiry.Before(time.Now()) {
+			delete(ovnLogACLCache, id)
+		}
+	}
+
+	ovnLogACLCache[aclID] = ovnLogACLCacheEntry{
+		name:        aclName,
+		projectName: aclProjectName,
+		expiry:      time.Now().Add(ovnLogACLCacheDuration),
+	}
+
+	return aclName, aclProjectName, nil
+}
+
+// OVNLogContext returns the structured context of an OVN ACL log message (as received over syslog).
+// Returns nil if the message doesn't belong to a rule of a LXD network ACL.
+func OVNLogContext(s *state.State, message string) map[string]string {
+	ruleName, entry := ovnParseLogMessage(message)
+	if entry == nil || !strings.HasPrefix(ruleName, ovnACLPortGroupPrefix) {
+		return nil
+	}
+
+	// Rule names have the form "lxd_acl<ID>-<direction>-<index>".
+	fields := strings.Split(strings.TrimPrefix(ruleName, ovnACLPortGroupPrefix), "-")
+	if len(fields) != 3 {
+		return nil
+	}
+
+	aclID, err := strconv.Atoi(fields[0])
 	if err != nil {
-		return ""
+		return nil
 	}
 
-	return string(out)
+	aclName, aclProjectName, err := ovnLogACL(s, aclID)
+	if err != nil || aclName == "" {
+		return nil
+	}
+
+	logContext := map[string]string{
+		"project":   aclProjectName,
+		"acl":       aclName,
+		"direction": fields[1],
+		"rule":      fields[2],
+		"action":    entry.Action,
+		"proto":     entry.Proto,
+		"src":       entry.Src,
+		"dst":       entry.Dst,
+	}
+
+	optionalFields := map[string]string{
+		"src_port":  entry.SrcPort,
+		"dst_port":  entry.DstPort,
+		"icmp_type": entry.ICMPType,
+		"icmp_code": entry.ICMPCode,
+	}
+
+	for k, v := range optionalFields {
+		if v != "" {
+			logContext[k] = v
+		}
+	}
+
+	return logContext
 }
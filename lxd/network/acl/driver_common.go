@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/lxd/cluster"
@@ -815,3 +816,217 @@ func (d *common) GetLog(clientType request.ClientType) (string, error) {
 
 	return strings.Join(logEntries, "\n") + "\n", nil
 }
+
+// GetState gets the ACL rule hit counters.
+func (d *common) GetState(clientType request.ClientType) (*api.NetworkACLState, error) {
+	counters, err := d.ruleCounters()
+	if err != nil {
+		return nil, err
+	}
+
+	aclState := &api.NetworkACLState{
+		Ingress: make([]api.NetworkACLRuleCounters, len(d.info.Ingress)),
+		Egress:  make([]api.NetworkACLRuleCounters, len(d.info.Egress)),
+	}
+
+	for i := range aclState.Ingress {
+		aclState.Ingress[i] = counters[aclRuleName(d.id, "ingress", i)]
+	}
+
+	for i := range aclState.Egress {
+		aclState.Egress[i] = counters[aclRuleName(d.id, "egress", i)]
+	}
+
+	// Aggregates the counters from the rest of the cluster.
+	if clientType == request.ClientTypeNormal {
+		// Setup notifier to reach the rest of the cluster.
+		notifier, err := cluster.NewNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return nil, err
+		}
+
+		mu := sync.Mutex{}
+		err = notifier(func(client lxd.InstanceServer) error {
+			memberState, err := client.UseProject(d.projectName).GetNetworkACLState(d.info.Name)
+			if err != nil {
+				return err
+			}
+
+			// Prevent concurrent writes to the counters.
+			mu.Lock()
+			defer mu.Unlock()
+
+			for i := 0; i < len(memberState.Ingress) && i < len(aclState.Ingress); i++ {
+				aclState.Ingress[i].Packets += memberState.Ingress[i].Packets
+				aclState.Ingress[i].Bytes += memberState.Ingress[i].Bytes
+			}
+
+			for i := 0; i < len(memberState.Egress) && i < len(aclState.Egress); i++ {
+				aclState.Egress[i].Packets += memberState.Egress[i].Packets
+				aclState.Egress[i].Bytes += memberState.Egress[i].Bytes
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return aclState, nil
+}
+
+// ruleCounters returns the hit counters of the ACL's rules on this member keyed by rule name.
+// The counters of a rule are summed across all the networks that use the ACL.
+func (d *common) ruleCounters() (map[string]api.NetworkACLRuleCounters, error) {
+	// Get a list of networks that are using this ACL (either directly or indirectly via a NIC).
+	aclNets := map[string]NetworkACLUsage{}
+	err := NetworkUsage(d.state, d.projectName, []string{d.info.Name}, aclNets)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting ACL network usage: %w", err)
+	}
+
+	counters := make(map[string]api.NetworkACLRuleCounters)
+	addCounters := func(name string, ruleCounters api.NetworkACLRuleCounters) {
+		existing := counters[name]
+		existing.Packets += ruleCounters.Packets
+		existing.Bytes += ruleCounters.Bytes
+		counters[name] = existing
+	}
+
+	namePrefix := fmt.Sprintf("%s%d-", ovnACLPortGroupPrefix, d.id)
+	usedByOVN := false
+
+	for _, aclNet := range aclNets {
+		// OVN networks share their ACL rules, so their counters are only retrieved once below.
+		if aclNet.Type == "ovn" {
+			usedByOVN = true
+			continue
+		}
+
+		netCounters, err := firewallRuleCounters(d.state, aclNet.Name)
+		if err != nil {
+			// The network may not be started on this member, in which case it has no counters.
+			d.logger.Warn("Failed getting ACL rule counters", logger.Ctx{"network": aclNet.Name, "err": err})
+			continue
+		}
+
+		for name, ruleCounters := range netCounters {
+			if strings.HasPrefix(name, namePrefix) {
+				addCounters(name, ruleCounters)
+			}
+		}
+	}
+
+	if usedByOVN {
+		ovnCounters, err := ovnRuleCounters(d.state)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, ruleCounters := range ovnCounters {
+			if strings.HasPrefix(name, namePrefix) {
+				addCounters(name, ruleCounters)
+			}
+		}
+	}
+
+	return counters, nil
+}
+
+// ruleCountersCacheDuration is how long the rule hit counters retrieved from the firewall and OVS are cached for.
+// This avoids running the firewall and OVS tools for each ACL and on each metrics scrape.
+const ruleCountersCacheDuration = 30 * time.Second
+
+type ruleCountersCacheEntry struct {
+	counters map[string]api.NetworkACLRuleCounters
+	expiry   time.Time
+}
+
+// ruleCountersCache is keyed by network name for firewall counters and by ovnACLPortGroupPrefix for OVN counters.
+var ruleCountersCache = map[string]ruleCountersCacheEntry{}
+var ruleCountersCacheLock sync.Mutex
+
+// cachedRuleCounters returns the cached rule hit counters for the key, or the ones returned by get if missing or
+// expired.
+func cachedRuleCounters(key string, get func() (map[string]api.NetworkACLRuleCounters, error)) (map[string]api.NetworkACLRuleCounters, error) {
+	ruleCountersCacheLock.Lock()
+	defer ruleCountersCacheLock.Unlock()
+
+	cache, ok := ruleCountersCache[key]
+	if ok && cache.expiry.After(time.Now()) {
+		return cache.counters, nil
+	}
+
+	counters, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	ruleCountersCache[key] = ruleCountersCacheEntry{
+		counters: counters,
+		expiry:   time.Now().Add(ruleCountersCacheDuration),
+	}
+
+	return counters, nil
+}
+
+// firewallRuleCounters returns the hit counters of all the ACL rules applied by the firewall to the network on
+// this member, keyed by rule name.
+func firewallRuleCounters(s *state.State, networkName string) (map[string]api.NetworkACLRuleCounters, error) {
+	return cachedRuleCounters(networkName, func() (map[string]api.NetworkACLRuleCounters, error) {
+		netCounters, err := s.Firewall.NetworkACLRuleCounters(networkName)
+		if err != nil {
+			return nil, err
+		}
+
+		counters := make(map[string]api.NetworkACLRuleCounters, len(netCounters))
+		for name, ruleCounters := range netCounters {
+			counters[name] = api.NetworkACLRuleCounters{Packets: ruleCounters.Packets, Bytes: ruleCounters.Bytes}
+		}
+
+		return counters, nil
+	})
+}
+
+// ovnRuleCounters returns the hit counters of all the OVN ACL rules for the OVN ports on this member, keyed by
+// rule name.
+func ovnRuleCounters(s *state.State) (map[string]api.NetworkACLRuleCounters, error) {
+	return cachedRuleCounters(ovnACLPortGroupPrefix, func() (map[string]api.NetworkACLRuleCounters, error) {
+		counters := make(map[string]api.NetworkACLRuleCounters)
+
+		// Members without OVS don't host any OVN ports and so have no counters.
+		ovs := openvswitch.NewOVS()
+		if !ovs.Installed() {
+			return counters, nil
+		}
+
+		client, err := openvswitch.NewOVN(s)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get OVN client: %w", err)
+		}
+
+		cookies, err := client.ACLRuleFlowCookies(ovnACLPortGroupPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting OVN ACL rule flows: %w", err)
+		}
+
+		// The OpenFlow counters only cover the traffic of the OVN ports on this member.
+		flowCounters, err := ovs.BridgeFlowCounters(s.GlobalConfig.NetworkOVNIntegrationBridge())
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting OVS flow counters: %w", err)
+		}
+
+		for name, ruleCookies := range cookies {
+			ruleCounters := counters[name]
+			for _, cookie := range ruleCookies {
+				ruleCounters.Packets += flowCounters[cookie].Packets
+				ruleCounters.Bytes += flowCounters[cookie].Bytes
+			}
+
+			counters[name] = ruleCounters
+		}
+
+		return counters, nil
+	})
+}
@@ -68,6 +68,8 @@ func (n *bridge) DBType() db.NetworkType {
 func (n *bridge) Info() Info {
 	info := n.common.Info()
 	info.AddressForwards = true
+	info.LoadBalancers = true
+	info.Peering = true
 
 	return info
 }
@@ -1735,6 +1737,12 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Setup network load balancers.
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
 	// Setup BGP.
 	err = n.bgpSetup(oldConfig)
 	if err != nil {
@@ -1916,6 +1924,18 @@ func (n *bridge) Update(newNetwork api.NetworkPut, targetNode string, clientType
 		}
 	}
 
+	// Refresh the ACLs of peered networks if the subnets they can refer to have changed.
+	for _, key := range []string{"ipv4.address", "ipv6.address", "ipv4.routes", "ipv6.routes"} {
+		if shared.ValueInSlice(key, changedKeys) {
+			err = n.peerApplyTargetACLs()
+			if err != nil {
+				return err
+			}
+
+			break
+		}
+	}
+
 	revert.Success()
 	return nil
 }
@@ -2698,69 +2718,9 @@ func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType reque
 	}
 
 	// Check if hairpin mode needs to be enabled on active NIC bridge ports.
-	if n.config["bridge.driver"] != "openvswitch" {
-		brNetfilterEnabled := false
-		for _, ipVersion := range []uint{4, 6} {
-			if BridgeNetfilterEnabled(ipVersion) == nil {
-				brNetfilterEnabled = true
-				break
-			}
-		}
-
-		// If br_netfilter is enabled and bridge has forwards, we enable hairpin mode on each NIC's bridge
-		// port in case any of the forwards target the NIC and the instance attempts to connect to the
-		// forward's listener. Without hairpin mode on the target of the forward will not be able to
-		// connect to the listener.
-		if brNetfilterEnabled {
-			listenAddresses, err := n.state.DB.Cluster.GetNetworkForwardListenAddresses(n.ID(), true)
-			if err != nil {
-				return fmt.Errorf("Failed loading network forwards: %w", err)
-			}
-
-			// If we are the first forward on this bridge, enable hairpin mode on active NIC ports.
-			if len(listenAddresses) <= 1 {
-				filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
-				err = n.state.DB.Cluster.InstanceList(context.TODO(), func(inst db.InstanceArgs, p api.Project) error {
-					// Get the instance's effective network project name.
-					instNetworkProject := project.NetworkProjectFromRecord(&p)
-
-					if instNetworkProject != api.ProjectDefaultName {
-						return nil // Managed bridge networks can only exist in default project.
-					}
-
-					devices := db.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
-
-					// Iterate through each of the instance's devices, looking for bridged NICs
-					// that are linked to this network.
-					for devName, devConfig := range devices {
-						if devConfig["type"] != "nic" {
-							continue
-						}
-
-						// Check whether the NIC device references our network..
-						if !NICUsesNetwork(devConfig, &api.Network{Name: n.Name()}) {
-							continue
-						}
-
-						hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", devName)]
-						if InterfaceExists(hostName) {
-							link := &ip.Link{Name: hostName}
-							err = link.BridgeLinkSetHairpin(true)
-							if err != nil {
-								return fmt.Errorf("Error enabling hairpin mode on bridge port %q: %w", link.Name, err)
-							}
-
-							n.logger.Debug("Enabled hairpin mode on NIC bridge port", logger.Ctx{"inst": inst.Name, "project": inst.Project, "device": devName, "dev": link.Name})
-						}
-					}
-
-					return nil
-				}, filter)
-				if err != nil {
-					return err
-				}
-			}
-		}
+	err = n.setupNICHairpinMode()
+	if err != nil {
+		return err
 	}
 
 	// Refresh exported BGP prefixes on local member.
@@ -2935,6 +2895,421 @@ func (n *bridge) forwardSetupFirewall() error {
 	return nil
 }
 
+// setupNICHairpinMode enables hairpin mode on active NIC bridge ports when br_netfilter is enabled and the
+// first forward or load balancer has been added to the bridge.
+func (n *bridge) setupNICHairpinMode() error {
+	if n.config["bridge.driver"] == "openvswitch" {
+		return nil
+	}
+
+	brNetfilterEnabled := false
+	for _, ipVersion := range []uint{4, 6} {
+		if BridgeNetfilterEnabled(ipVersion) == nil {
+			brNetfilterEnabled = true
+			break
+		}
+	}
+
+	// If br_netfilter is enabled and bridge has forwards or load balancers, we enable hairpin mode on each
+	// NIC's bridge port in case any of them target the NIC and the instance attempts to connect to the
+	// listener. Without hairpin mode on the target will not be able to connect to the listener.
+	if !brNetfilterEnabled {
+		return nil
+	}
+
+	forwardListenAddresses, err := n.state.DB.Cluster.GetNetworkForwardListenAddresses(n.ID(), true)
+	if err != nil {
+		return fmt.Errorf("Failed loading network forwards: %w", err)
+	}
+
+	loadBalancerListenAddresses, err := n.state.DB.Cluster.GetNetworkLoadBalancerListenAddresses(n.ID(), true)
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	// If we are not the first forward or load balancer on this bridge, hairpin mode is already enabled.
+	if len(forwardListenAddresses)+len(loadBalancerListenAddresses) > 1 {
+		return nil
+	}
+
+	filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
+	return n.state.DB.Cluster.InstanceList(context.TODO(), func(inst db.InstanceArgs, p api.Project) error {
+		// Get the instance's effective network project name.
+		instNetworkProject := project.NetworkProjectFromRecord(&p)
+
+		if instNetworkProject != api.ProjectDefaultName {
+			return nil // Managed bridge networks can only exist in default project.
+		}
+
+		devices := db.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
+
+		// Iterate through each of the instance's devices, looking for bridged NICs
+		// that are linked to this network.
+		for devName, devConfig := range devices {
+			if devConfig["type"] != "nic" {
+				continue
+			}
+
+			// Check whether the NIC device references our network..
+			if !NICUsesNetwork(devConfig, &api.Network{Name: n.Name()}) {
+				continue
+			}
+
+			hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", devName)]
+			if InterfaceExists(hostName) {
+				link := &ip.Link{Name: hostName}
+				err = link.BridgeLinkSetHairpin(true)
+				if err != nil {
+					return fmt.Errorf("Error enabling hairpin mode on bridge port %q: %w", link.Name, err)
+				}
+
+				n.logger.Debug("Enabled hairpin mode on NIC bridge port", logger.Ctx{"inst": inst.Name, "project": inst.Project, "device": devName, "dev": link.Name})
+			}
+		}
+
+		return nil
+	}, filter)
+}
+
+// LoadBalancerCreate creates a network load balancer.
+func (n *bridge) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member load balancers.
+
+	// Check if there is an existing load balancer using the same listen address.
+	_, _, err := n.state.DB.Cluster.GetNetworkLoadBalancer(context.TODO(), n.ID(), memberSpecific, loadBalancer.ListenAddress)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "A load balancer for that listen address already exists")
+	}
+
+	// Convert listen address to subnet so we can check its valid and can be used.
+	listenAddressNet, err := ParseIPToNet(loadBalancer.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("Failed parsing load balancer listen address %q: %w", loadBalancer.ListenAddress, err)
+	}
+
+	_, err = n.loadBalancerValidate(listenAddressNet.IP, &loadBalancer.NetworkLoadBalancerPut)
+	if err != nil {
+		return err
+	}
+
+	externalSubnetsInUse, err := n.getExternalSubnetInUse()
+	if err != nil {
+		return err
+	}
+
+	// Check the listen address subnet doesn't fall within any existing network external subnets.
+	for _, externalSubnetUser := range externalSubnetsInUse {
+		// Check if usage is from our own network.
+		if externalSubnetUser.networkProject == n.project && externalSubnetUser.networkName == n.name {
+			// Skip checking conflict with our own network's subnet or SNAT address.
+			// But do not allow other conflict with other usage types within our own network.
+			if externalSubnetUser.usageType == subnetUsageNetwork || externalSubnetUser.usageType == subnetUsageNetworkSNAT {
+				continue
+			}
+		}
+
+		if SubnetContains(&externalSubnetUser.subnet, listenAddressNet) || SubnetContains(listenAddressNet, &externalSubnetUser.subnet) {
+			// This error is purposefully vague so that it doesn't reveal any names of
+			// resources potentially outside of the network.
+			return fmt.Errorf("Load balancer listen address %q overlaps with another network or NIC", listenAddressNet.String())
+		}
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Create load balancer DB record.
+	loadBalancerID, err := n.state.DB.Cluster.CreateNetworkLoadBalancer(n.ID(), memberSpecific, &loadBalancer)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.DeleteNetworkLoadBalancer(n.ID(), loadBalancerID)
+		_ = n.loadBalancerSetupFirewall()
+		_ = n.loadBalancerBGPSetupPrefixes()
+	})
+
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	// Check if hairpin mode needs to be enabled on active NIC bridge ports.
+	err = n.setupNICHairpinMode()
+	if err != nil {
+		return err
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
+// LoadBalancerUpdate updates a network load balancer.
+func (n *bridge) LoadBalancerUpdate(listenAddress string, req api.NetworkLoadBalancerPut, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member load balancers.
+	curLoadBalancerID, curLoadBalancer, err := n.state.DB.Cluster.GetNetworkLoadBalancer(context.TODO(), n.ID(), memberSpecific, listenAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.loadBalancerValidate(net.ParseIP(curLoadBalancer.ListenAddress), &req)
+	if err != nil {
+		return err
+	}
+
+	curLoadBalancerEtagHash, err := util.EtagHash(curLoadBalancer.Etag())
+	if err != nil {
+		return err
+	}
+
+	newLoadBalancer := api.NetworkLoadBalancer{
+		ListenAddress:          curLoadBalancer.ListenAddress,
+		NetworkLoadBalancerPut: req,
+	}
+
+	newLoadBalancerEtagHash, err := util.EtagHash(newLoadBalancer.Etag())
+	if err != nil {
+		return err
+	}
+
+	if curLoadBalancerEtagHash == newLoadBalancerEtagHash {
+		return nil // Nothing has changed.
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = n.state.DB.Cluster.UpdateNetworkLoadBalancer(n.ID(), curLoadBalancerID, &newLoadBalancer.NetworkLoadBalancerPut)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.UpdateNetworkLoadBalancer(n.ID(), curLoadBalancerID, &curLoadBalancer.NetworkLoadBalancerPut)
+		_ = n.loadBalancerSetupFirewall()
+		_ = n.loadBalancerBGPSetupPrefixes()
+	})
+
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// LoadBalancerDelete deletes a network load balancer.
+func (n *bridge) LoadBalancerDelete(listenAddress string, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member load balancers.
+	loadBalancerID, loadBalancer, err := n.state.DB.Cluster.GetNetworkLoadBalancer(context.TODO(), n.ID(), memberSpecific, listenAddress)
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = n.state.DB.Cluster.DeleteNetworkLoadBalancer(n.ID(), loadBalancerID)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		newLoadBalancer := api.NetworkLoadBalancersPost{
+			NetworkLoadBalancerPut: loadBalancer.NetworkLoadBalancerPut,
+			ListenAddress:          loadBalancer.ListenAddress,
+		}
+
+		_, _ = n.state.DB.Cluster.CreateNetworkLoadBalancer(n.ID(), memberSpecific, &newLoadBalancer)
+		_ = n.loadBalancerSetupFirewall()
+		_ = n.loadBalancerBGPSetupPrefixes()
+	})
+
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
+// loadBalancerSetupFirewall applies all network load balancers defined for this network and this member.
+func (n *bridge) loadBalancerSetupFirewall() error {
+	memberSpecific := true // Get all load balancers for this cluster member.
+	loadBalancers, err := n.state.DB.Cluster.GetNetworkLoadBalancers(context.TODO(), n.ID(), memberSpecific)
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	var fwLoadBalancers []firewallDrivers.AddressLoadBalancer
+
+	for _, loadBalancer := range loadBalancers {
+		// Convert listen address to subnet so we can check its valid and can be used.
+		listenAddressNet, err := ParseIPToNet(loadBalancer.ListenAddress)
+		if err != nil {
+			return fmt.Errorf("Failed parsing load balancer listen address %q: %w", loadBalancer.ListenAddress, err)
+		}
+
+		portMaps, err := n.loadBalancerValidate(listenAddressNet.IP, &loadBalancer.NetworkLoadBalancerPut)
+		if err != nil {
+			return fmt.Errorf("Failed validating firewall load balancer for listen address %q: %w", loadBalancer.ListenAddress, err)
+		}
+
+		// Only forward to backends that haven't failed their health checks.
+		portMaps = loadBalancerHealthyPortMaps(n.id, loadBalancer.ListenAddress, &loadBalancer.NetworkLoadBalancerPut, portMaps)
+
+		for _, portMap := range portMaps {
+			fwLoadBalancer := firewallDrivers.AddressLoadBalancer{
+				ListenAddress: listenAddressNet.IP,
+				Protocol:      portMap.protocol,
+				ListenPorts:   portMap.listenPorts,
+			}
+
+			for _, target := range portMap.targets {
+				fwLoadBalancer.Targets = append(fwLoadBalancer.Targets, firewallDrivers.AddressLoadBalancerTarget{
+					Address: target.address,
+					Ports:   target.ports,
+				})
+			}
+
+			fwLoadBalancers = append(fwLoadBalancers, fwLoadBalancer)
+		}
+	}
+
+	err = n.state.Firewall.NetworkApplyLoadBalancers(n.name, fwLoadBalancers)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall load balancers: %w", err)
+	}
+
+	return nil
+}
+
+// LoadBalancerHealthCheck runs the due health checks of the load balancer backends on this member and
+// reapplies the firewall rules if the set of unhealthy backends has changed.
+func (n *bridge) LoadBalancerHealthCheck(ctx context.Context, leader bool) error {
+	memberSpecific := true // Health checks of bridge load balancers are run by the member they belong to.
+	loadBalancers, err := n.state.DB.Cluster.GetNetworkLoadBalancers(ctx, n.ID(), memberSpecific)
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	reapply := loadBalancerHealthCheck(ctx, n.ID(), loadBalancers)
+	if len(reapply) == 0 {
+		return nil
+	}
+
+	return n.loadBalancerSetupFirewall()
+}
+
+// PeerCreate creates a network peering.
+// Traffic between bridge networks is routed by the host whether or not they are peered, so a peering only allows
+// the subnets of the target network to be referenced in the ACL rules of this network.
+func (n *bridge) PeerCreate(peer api.NetworkPeersPost) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	peerID, mutualExists, err := n.peerCreateRecord(&peer)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.DeleteNetworkPeer(n.ID(), peerID)
+	})
+
+	if mutualExists {
+		targetNet, err := LoadByName(n.state, peer.TargetProject, peer.TargetNetwork)
+		if err != nil {
+			return fmt.Errorf("Failed loading target network: %w", err)
+		}
+
+		if targetNet.Type() != n.Type() {
+			return fmt.Errorf("Target network is not bridge interface type")
+		}
+	}
+
+	revert.Success()
+	return nil
+}
+
+// PeerUpdate updates a network peering.
+func (n *bridge) PeerUpdate(peerName string, req api.NetworkPeerPut) error {
+	return n.peerUpdate(peerName, req)
+}
+
+// PeerDelete deletes a network peering.
+func (n *bridge) PeerDelete(peerName string) error {
+	peerID, peer, err := n.state.DB.Cluster.GetNetworkPeer(n.ID(), peerName)
+	if err != nil {
+		return err
+	}
+
+	isUsed, err := n.peerIsUsed(peer.Name)
+	if err != nil {
+		return err
+	}
+
+	if isUsed {
+		return fmt.Errorf("Cannot delete a Peer that is in use")
+	}
+
+	return n.state.DB.Cluster.DeleteNetworkPeer(n.ID(), peerID)
+}
+
+// peerApplyTargetACLs reapplies the firewall ACLs on this member of the networks this network is peered with, as
+// their rules can refer to the subnets of this network.
+func (n *bridge) peerApplyTargetACLs() error {
+	peers, err := n.state.DB.Cluster.GetNetworkPeers(n.ID())
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		if peer.Status != api.NetworkStatusCreated {
+			continue
+		}
+
+		targetNet, err := LoadByName(n.state, peer.TargetProject, peer.TargetNetwork)
+		if err != nil {
+			return fmt.Errorf("Failed loading target network %q: %w", peer.TargetNetwork, err)
+		}
+
+		targetBridge, ok := targetNet.(*bridge)
+		if !ok || targetBridge.config["security.acls"] == "" || !targetBridge.isRunning() {
+			continue
+		}
+
+		aclNet := acl.NetworkACLUsage{
+			Name:   targetBridge.Name(),
+			Type:   targetBridge.Type(),
+			ID:     targetBridge.ID(),
+			Config: targetBridge.Config(),
+		}
+
+		err = acl.FirewallApplyACLRules(n.state, n.logger, targetBridge.Project(), aclNet)
+		if err != nil {
+			return fmt.Errorf("Failed applying ACLs of peer network %q: %w", targetBridge.Name(), err)
+		}
+	}
+
+	return nil
+}
+
 // Leases returns a list of leases for the bridged network. It will reach out to other cluster members as needed.
 // The projectName passed here refers to the initial project from the API request which may differ from the network's project.
 func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]api.NetworkLease, error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -17,6 +18,7 @@ import (
 	"github.com/canonical/lxd/lxd/network/acl"
 	"github.com/canonical/lxd/lxd/resources"
 	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
@@ -47,9 +49,10 @@ type forwardPortMap struct {
 }
 
 type loadBalancerPortMap struct {
-	listenPorts []uint64
-	protocol    string
-	targets     []forwardTarget
+	listenPorts    []uint64
+	protocol       string
+	targets        []forwardTarget
+	targetBackends []string
 }
 
 // subnetUsageType indicates the type of use for a subnet.
@@ -624,6 +627,11 @@ func (n *common) bgpSetup(oldConfig map[string]string) error {
 		return fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
 	}
 
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
 	return nil
 }
 
@@ -647,6 +655,12 @@ func (n *common) bgpClear(config map[string]string) error {
 		return err
 	}
 
+	// Clear existing load balancer prefixes for network.
+	err = n.state.BGP.RemovePrefixByOwner(fmt.Sprintf("network_%d_load_balancer", n.id))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1153,14 +1167,29 @@ func (n *common) loadBalancerValidate(listenAddress net.IP, forward *api.Network
 		}
 	}
 
-	// Look for any unknown config fields.
-	for k := range forward.Config {
+	// Validate config, looking for any unknown config fields.
+	configRules := map[string]func(value string) error{
+		"healthcheck.interval":      validate.Optional(validate.IsUint32),
+		"healthcheck.timeout":       validate.Optional(validate.IsUint32),
+		"healthcheck.failure_count": validate.Optional(validate.IsUint32),
+		"healthcheck.success_count": validate.Optional(validate.IsUint32),
+	}
+
+	for k, v := range forward.Config {
 		// User keys are not validated.
 		if shared.IsUserConfig(k) {
 			continue
 		}
 
-		return nil, fmt.Errorf("Invalid option %q", k)
+		validator, found := configRules[k]
+		if !found {
+			return nil, fmt.Errorf("Invalid option %q", k)
+		}
+
+		err := validator(v)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for option %q: %w", k, err)
+		}
 	}
 
 	// Validate port rules.
@@ -1218,6 +1247,24 @@ func (n *common) loadBalancerValidate(listenAddress net.IP, forward *api.Network
 			}
 		}
 
+		// Check health check settings.
+		if backendSpec.HealthCheck != "" {
+			if !shared.ValueInSlice(backendSpec.HealthCheck, loadBalancerHealthChecks) {
+				return nil, fmt.Errorf("Invalid health check for backend %q, must be one of: %s", backendSpec.Name, strings.Join(loadBalancerHealthChecks, ", "))
+			}
+
+			_, err = loadBalancerBackendHealthCheckPort(forward, &forward.Backends[backendSpecID])
+			if err != nil {
+				return nil, err
+			}
+
+			if backendSpec.HealthCheckPath != "" && (backendSpec.HealthCheck != "http" || !strings.HasPrefix(backendSpec.HealthCheckPath, "/")) {
+				return nil, fmt.Errorf("Invalid health check path for backend %q, must be an absolute path and only used with http health checks", backendSpec.Name)
+			}
+		} else if backendSpec.HealthCheckPort != "" || backendSpec.HealthCheckPath != "" {
+			return nil, fmt.Errorf("Health check port and path require a health check for backend %q", backendSpec.Name)
+		}
+
 		backendsByName[backendSpec.Name] = &target
 	}
 
@@ -1235,9 +1282,10 @@ func (n *common) loadBalancerValidate(listenAddress net.IP, forward *api.Network
 		}
 
 		portMap := loadBalancerPortMap{
-			listenPorts: make([]uint64, 0),
-			protocol:    portSpec.Protocol,
-			targets:     make([]forwardTarget, 0, len(portSpec.TargetBackend)),
+			listenPorts:    make([]uint64, 0),
+			protocol:       portSpec.Protocol,
+			targets:        make([]forwardTarget, 0, len(portSpec.TargetBackend)),
+			targetBackends: make([]string, 0, len(portSpec.TargetBackend)),
 		}
 
 		for _, pr := range listenPortRanges {
@@ -1275,6 +1323,7 @@ func (n *common) loadBalancerValidate(listenAddress net.IP, forward *api.Network
 			}
 
 			portMap.targets = append(portMap.targets, *backend)
+			portMap.targetBackends = append(portMap.targetBackends, backendName)
 		}
 
 		portMaps = append(portMaps, &portMap)
@@ -1298,6 +1347,16 @@ func (n *common) LoadBalancerDelete(listenAddress string, clientType request.Cli
 	return ErrNotImplemented
 }
 
+// LoadBalancerState returns the health of the load balancer backends as checked by this member.
+func (n *common) LoadBalancerState(loadBalancer api.NetworkLoadBalancer) (*api.NetworkLoadBalancerState, error) {
+	return loadBalancerState(n.id, &loadBalancer), nil
+}
+
+// LoadBalancerHealthCheck returns ErrNotImplemented for drivers that do not support load balancers.
+func (n *common) LoadBalancerHealthCheck(ctx context.Context, leader bool) error {
+	return ErrNotImplemented
+}
+
 // loadBalancerBGPSetupPrefixes exports external load balancer addresses as prefixes.
 func (n *common) loadBalancerBGPSetupPrefixes() error {
 	// Retrieve network forwards before clearing existing prefixes, and separate them by IP family.
@@ -1384,6 +1443,80 @@ func (n *common) PeerDelete(peerName string) error {
 	return ErrNotImplemented
 }
 
+// peerCreateRecord performs the create-time validation of a peering and creates its DB record.
+// Returns the ID of the peering and true if a mutual peering now exists on the target network.
+func (n *common) peerCreateRecord(peer *api.NetworkPeersPost) (int64, bool, error) {
+	// Default to network's project if target project not specified.
+	if peer.TargetProject == "" {
+		peer.TargetProject = n.Project()
+	}
+
+	// Target network name is required.
+	if peer.TargetNetwork == "" {
+		return -1, false, api.StatusErrorf(http.StatusBadRequest, "Target network is required")
+	}
+
+	// Check if there is an existing peer using the same name, or whether there is already a peering (in any
+	// state) to the target network.
+	peers, err := n.state.DB.Cluster.GetNetworkPeers(n.ID())
+	if err != nil {
+		return -1, false, err
+	}
+
+	for _, existingPeer := range peers {
+		if peer.Name == existingPeer.Name {
+			return -1, false, api.StatusErrorf(http.StatusConflict, "A peer for that name already exists")
+		}
+
+		if peer.TargetProject == existingPeer.TargetProject && peer.TargetNetwork == existingPeer.TargetNetwork {
+			return -1, false, api.StatusErrorf(http.StatusConflict, "A peer for that target network already exists")
+		}
+	}
+
+	// Perform general (create and update) validation.
+	err = n.peerValidate(peer.Name, &peer.NetworkPeerPut)
+	if err != nil {
+		return -1, false, err
+	}
+
+	// Create peer DB record.
+	return n.state.DB.Cluster.CreateNetworkPeer(n.ID(), peer)
+}
+
+// peerUpdate validates and stores the new config of a network peering.
+func (n *common) peerUpdate(peerName string, req api.NetworkPeerPut) error {
+	curPeerID, curPeer, err := n.state.DB.Cluster.GetNetworkPeer(n.ID(), peerName)
+	if err != nil {
+		return err
+	}
+
+	err = n.peerValidate(peerName, &req)
+	if err != nil {
+		return err
+	}
+
+	curPeerEtagHash, err := util.EtagHash(curPeer.Etag())
+	if err != nil {
+		return err
+	}
+
+	newPeer := api.NetworkPeer{
+		Name:           curPeer.Name,
+		NetworkPeerPut: req,
+	}
+
+	newPeerEtagHash, err := util.EtagHash(newPeer.Etag())
+	if err != nil {
+		return err
+	}
+
+	if curPeerEtagHash == newPeerEtagHash {
+		return nil // Nothing has changed.
+	}
+
+	return n.state.DB.Cluster.UpdateNetworkPeer(n.ID(), curPeerID, &newPeer.NetworkPeerPut)
+}
+
 // peerValidate validates the peer request.
 func (n *common) peerValidate(peerName string, peer *api.NetworkPeerPut) error {
 	err := acl.ValidName(peerName)
@@ -20,6 +20,7 @@ import (
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/dnsmasq/dhcpalloc"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/ip"
 	"github.com/canonical/lxd/lxd/locking"
@@ -866,7 +867,7 @@ func (n *ovn) setupUplinkPort(routerMAC net.HardwareAddr) (*ovnUplinkVars, error
 	switch uplinkNet.Type() {
 	case "bridge":
 		return n.setupUplinkPortBridge(uplinkNet, routerMAC)
-	case "physical":
+	case "physical", "wireguard":
 		return n.setupUplinkPortPhysical(uplinkNet, routerMAC)
 	}
 
@@ -1152,7 +1153,7 @@ func (n *ovn) startUplinkPort() error {
 	switch uplinkNet.Type() {
 	case "bridge":
 		return n.startUplinkPortBridge(uplinkNet)
-	case "physical":
+	case "physical", "wireguard":
 		return n.startUplinkPortPhysical(uplinkNet)
 	}
 
@@ -1216,7 +1217,7 @@ func (n *ovn) startUplinkPortBridgeNative(uplinkNet Network, bridgeDevice string
 	// Ensure that the veth interfaces inherit the uplink bridge's MTU (which the OVS bridge also inherits).
 	uplinkNetConfig := uplinkNet.Config()
 
-	// Uplink may have type "bridge" or "physical"
+	// Uplink may have type "bridge", "physical" or "wireguard"
 	uplinkNetMTU, hasBridgeMTU := uplinkNetConfig["bridge.mtu"]
 	if !hasBridgeMTU {
 		uplinkNetMTU = uplinkNetConfig["mtu"]
@@ -1364,14 +1365,24 @@ func (n *ovn) pingOVNRouter() {
 	}
 }
 
+// uplinkHostName returns the name of the host interface connected to the uplink network.
+// For wireguard networks this is the network's bridge, otherwise it is the physical network's parent interface.
+func (n *ovn) uplinkHostName(uplinkNet Network) string {
+	if uplinkNet.Type() == "wireguard" {
+		return uplinkNet.Name()
+	}
+
+	uplinkConfig := uplinkNet.Config()
+	return GetHostDevice(uplinkConfig["parent"], uplinkConfig["vlan"])
+}
+
 // startUplinkPortPhysical creates OVS bridge (if doesn't exist) and connects uplink interface to the OVS bridge.
 func (n *ovn) startUplinkPortPhysical(uplinkNet Network) error {
 	// Do this after gaining lock so that on failure we revert before release locking.
 	revert := revert.New()
 	defer revert.Fail()
 
-	uplinkConfig := uplinkNet.Config()
-	uplinkHostName := GetHostDevice(uplinkConfig["parent"], uplinkConfig["vlan"])
+	uplinkHostName := n.uplinkHostName(uplinkNet)
 
 	if !InterfaceExists(uplinkHostName) {
 		return fmt.Errorf("Uplink network %q is not started (interface %q is missing)", uplinkNet.Name(), uplinkHostName)
@@ -1493,7 +1504,7 @@ func (n *ovn) deleteUplinkPort() error {
 		switch uplinkNet.Type() {
 		case "bridge":
 			return n.deleteUplinkPortBridge(uplinkNet)
-		case "physical":
+		case "physical", "wireguard":
 			return n.deleteUplinkPortPhysical(uplinkNet)
 		}
 
@@ -1585,8 +1596,7 @@ func (n *ovn) deleteUplinkPortBridgeOVS(uplinkNet Network, ovsBridge string) err
 
 // deleteUplinkPortPhysical deletes uplink OVS bridge and OVN bridge mappings if not in use.
 func (n *ovn) deleteUplinkPortPhysical(uplinkNet Network) error {
-	uplinkConfig := uplinkNet.Config()
-	uplinkHostName := GetHostDevice(uplinkConfig["parent"], uplinkConfig["vlan"])
+	uplinkHostName := n.uplinkHostName(uplinkNet)
 
 	// Detect if uplink interface is a native bridge.
 	if IsNativeBridge(uplinkHostName) {
@@ -1734,7 +1744,7 @@ func (n *ovn) allowedUplinkNetworks(p *api.Project) ([]string, error) {
 
 		// Add any compatible networks to the uplink network list.
 		for _, network := range networks {
-			if network.Type == "bridge" || network.Type == "physical" {
+			if shared.ValueInSlice(network.Type, []string{"bridge", "physical", "wireguard"}) {
 				uplinkNetworkNames = append(uplinkNetworkNames, network.Name)
 			}
 		}
@@ -1810,6 +1820,16 @@ func (n *ovn) getDHCPv4Reservations() ([]shared.IPRange, error) {
 		dhcpReserveIPv4s = []shared.IPRange{{Start: routerIntPortIPv4}}
 	}
 
+	// Reserve the source address of load balancer health checks.
+	healthCheckSourceIP, err := n.loadBalancerHealthCheckSourceIP()
+	if err != nil {
+		return nil, err
+	}
+
+	if healthCheckSourceIP != nil {
+		dhcpReserveIPv4s = append(dhcpReserveIPv4s, shared.IPRange{Start: healthCheckSourceIP})
+	}
+
 	err = UsedByInstanceDevices(n.state, n.Project(), n.Name(), n.Type(), func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
 		ip := net.ParseIP(nicConfig["ipv4.address"])
 		if ip != nil {
@@ -4773,6 +4793,143 @@ func (n *ovn) loadBalancerFlattenVIPs(listenAddress net.IP, portMaps []*loadBala
 	return vips
 }
 
+// loadBalancerHealthCheckSourceIP returns the address OVN uses as the source of load balancer health checks.
+// This is the last address of the internal IPv4 subnet, which is reserved for this purpose.
+func (n *ovn) loadBalancerHealthCheckSourceIP() (net.IP, error) {
+	_, routerIntPortIPv4Net, err := n.parseRouterIntPortIPv4Net()
+	if err != nil {
+		return nil, err
+	}
+
+	if routerIntPortIPv4Net == nil {
+		return nil, nil
+	}
+
+	return dhcpalloc.GetIP(routerIntPortIPv4Net, -2), nil
+}
+
+// loadBalancerValidateHealthChecks checks the backend health checks can be run natively by OVN.
+func (n *ovn) loadBalancerValidateHealthChecks(loadBalancer *api.NetworkLoadBalancerPut) error {
+	for _, backend := range loadBalancer.Backends {
+		if backend.HealthCheck == "" {
+			continue
+		}
+
+		if backend.HealthCheck != "tcp" {
+			return fmt.Errorf("Invalid health check for backend %q, only tcp health checks are supported on OVN networks", backend.Name)
+		}
+
+		if backend.HealthCheckPort != "" {
+			return fmt.Errorf("Health check port isn't supported for backend %q, OVN checks the target ports", backend.Name)
+		}
+
+		targetAddress := net.ParseIP(backend.TargetAddress)
+		if targetAddress == nil || targetAddress.To4() == nil {
+			return fmt.Errorf("Health checks are only supported for IPv4 backends on OVN networks")
+		}
+
+		sourceIP, err := n.loadBalancerHealthCheckSourceIP()
+		if err != nil {
+			return err
+		}
+
+		if sourceIP == nil {
+			return fmt.Errorf("Health checks require the network to have an IPv4 subnet")
+		}
+	}
+
+	return nil
+}
+
+// loadBalancerVIPs returns the OVN load balancer VIPs for the port maps along with the OVN health checks of the
+// backends. Backends are checked through the internal switch port using their address, so backends that aren't
+// currently connected to the network aren't checked.
+func (n *ovn) loadBalancerVIPs(client *openvswitch.OVN, listenAddress net.IP, loadBalancer *api.NetworkLoadBalancerPut, portMaps []*loadBalancerPortMap) ([]openvswitch.OVNLoadBalancerVIP, error) {
+	vips := n.loadBalancerFlattenVIPs(listenAddress, portMaps)
+
+	backends := make(map[string]*api.NetworkLoadBalancerBackend, len(loadBalancer.Backends))
+	for i := range loadBalancer.Backends {
+		if loadBalancer.Backends[i].HealthCheck != "" {
+			backends[loadBalancer.Backends[i].Name] = &loadBalancer.Backends[i]
+		}
+	}
+
+	if len(backends) == 0 {
+		return vips, nil
+	}
+
+	sourceIP, err := n.loadBalancerHealthCheckSourceIP()
+	if err != nil {
+		return nil, err
+	}
+
+	portIPs, err := client.LogicalSwitchIPs(n.getIntSwitchName())
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting internal switch port IPs: %w", err)
+	}
+
+	ipPorts := make(map[string]openvswitch.OVNSwitchPort)
+	for portName, ips := range portIPs {
+		for _, ip := range ips {
+			ipPorts[ip.String()] = portName
+		}
+	}
+
+	healthCheck := &openvswitch.OVNLoadBalancerHealthCheck{
+		Interval:     loadBalancerHealthCheckSetting(loadBalancer.Config, "healthcheck.interval", 10),
+		Timeout:      loadBalancerHealthCheckSetting(loadBalancer.Config, "healthcheck.timeout", 5),
+		SuccessCount: loadBalancerHealthCheckSetting(loadBalancer.Config, "healthcheck.success_count", 3),
+		FailureCount: loadBalancerHealthCheckSetting(loadBalancer.Config, "healthcheck.failure_count", 3),
+	}
+
+	// VIPs are flattened in port map and listen port order.
+	vipIndex := 0
+	for _, portMap := range portMaps {
+		for range portMap.listenPorts {
+			vip := &vips[vipIndex]
+			vipIndex++
+
+			for i, backendName := range portMap.targetBackends {
+				if backends[backendName] == nil {
+					continue
+				}
+
+				vip.HealthCheck = healthCheck
+
+				portName, found := ipPorts[vip.Targets[i].Address.String()]
+				if found {
+					vip.Targets[i].HealthCheckPort = portName
+					vip.Targets[i].HealthCheckSourceIP = sourceIP
+				}
+			}
+		}
+	}
+
+	return vips, nil
+}
+
+// loadBalancerApply applies the load balancer to OVN.
+func (n *ovn) loadBalancerApply(client *openvswitch.OVN, loadBalancer *api.NetworkLoadBalancer) error {
+	listenAddress := net.ParseIP(loadBalancer.ListenAddress)
+
+	portMaps, err := n.loadBalancerValidate(listenAddress, &loadBalancer.NetworkLoadBalancerPut)
+	if err != nil {
+		return err
+	}
+
+	vips, err := n.loadBalancerVIPs(client, listenAddress, &loadBalancer.NetworkLoadBalancerPut, portMaps)
+	if err != nil {
+		return err
+	}
+
+	err = client.LoadBalancerApply(n.getLoadBalancerName(loadBalancer.ListenAddress), []openvswitch.OVNRouter{n.getRouterName()}, []openvswitch.OVNSwitch{n.getIntSwitchName()}, vips...)
+	if err != nil {
+		return fmt.Errorf("Failed applying OVN load balancer: %w", err)
+	}
+
+	return nil
+}
+
 // LoadBalancerCreate creates a network load balancer.
 func (n *ovn) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) error {
 	revert := revert.New()
@@ -4798,6 +4955,11 @@ func (n *ovn) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clie
 			return err
 		}
 
+		err = n.loadBalancerValidateHealthChecks(&loadBalancer.NetworkLoadBalancerPut)
+		if err != nil {
+			return err
+		}
+
 		// Load the project to get uplink network restrictions.
 		var p *api.Project
 		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -4878,7 +5040,10 @@ func (n *ovn) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clie
 			_ = n.loadBalancerBGPSetupPrefixes()
 		})
 
-		vips := n.loadBalancerFlattenVIPs(net.ParseIP(loadBalancer.ListenAddress), portMaps)
+		vips, err := n.loadBalancerVIPs(client, net.ParseIP(loadBalancer.ListenAddress), &loadBalancer.NetworkLoadBalancerPut, portMaps)
+		if err != nil {
+			return err
+		}
 
 		err = client.LoadBalancerApply(n.getLoadBalancerName(loadBalancer.ListenAddress), []openvswitch.OVNRouter{n.getRouterName()}, []openvswitch.OVNSwitch{n.getIntSwitchName()}, vips...)
 		if err != nil {
@@ -4926,6 +5091,11 @@ func (n *ovn) LoadBalancerUpdate(listenAddress string, req api.NetworkLoadBalanc
 			return err
 		}
 
+		err = n.loadBalancerValidateHealthChecks(&req)
+		if err != nil {
+			return err
+		}
+
 		curForwardEtagHash, err := util.EtagHash(curLoadBalancer.Etag())
 		if err != nil {
 			return err
@@ -4950,7 +5120,10 @@ func (n *ovn) LoadBalancerUpdate(listenAddress string, req api.NetworkLoadBalanc
 			return fmt.Errorf("Failed to get OVN client: %w", err)
 		}
 
-		vips := n.loadBalancerFlattenVIPs(net.ParseIP(newLoadBalancer.ListenAddress), portMaps)
+		vips, err := n.loadBalancerVIPs(client, net.ParseIP(newLoadBalancer.ListenAddress), &newLoadBalancer.NetworkLoadBalancerPut, portMaps)
+		if err != nil {
+			return err
+		}
 
 		err = client.LoadBalancerApply(n.getLoadBalancerName(newLoadBalancer.ListenAddress), []openvswitch.OVNRouter{n.getRouterName()}, []openvswitch.OVNSwitch{n.getIntSwitchName()}, vips...)
 		if err != nil {
@@ -4959,12 +5132,8 @@ func (n *ovn) LoadBalancerUpdate(listenAddress string, req api.NetworkLoadBalanc
 
 		revert.Add(func() {
 			// Apply old settings to OVN on failure.
-			portMaps, err := n.loadBalancerValidate(net.ParseIP(curLoadBalancer.ListenAddress), &curLoadBalancer.NetworkLoadBalancerPut)
-			if err == nil {
-				vips := n.loadBalancerFlattenVIPs(net.ParseIP(curLoadBalancer.ListenAddress), portMaps)
-				_ = client.LoadBalancerApply(n.getLoadBalancerName(curLoadBalancer.ListenAddress), []openvswitch.OVNRouter{n.getRouterName()}, []openvswitch.OVNSwitch{n.getIntSwitchName()}, vips...)
-				_ = n.forwardBGPSetupPrefixes()
-			}
+			_ = n.loadBalancerApply(client, curLoadBalancer)
+			_ = n.forwardBGPSetupPrefixes()
 		})
 
 		err = n.state.DB.Cluster.UpdateNetworkLoadBalancer(n.ID(), curLoadBalancerID, &newLoadBalancer.NetworkLoadBalancerPut)
@@ -5047,6 +5216,147 @@ func (n *ovn) LoadBalancerDelete(listenAddress string, clientType request.Client
 	return nil
 }
 
+// LoadBalancerHealthCheck reapplies the OVN load balancers whose health check IP to port mappings are out of date,
+// such as when a backend instance was started after the load balancer was applied. The health checks themselves
+// are run by OVN. As OVN load balancers aren't member specific, this is only done by the cluster leader.
+func (n *ovn) LoadBalancerHealthCheck(ctx context.Context, leader bool) error {
+	if !leader {
+		return nil
+	}
+
+	memberSpecific := false // OVN doesn't support per-member load balancers.
+	loadBalancers, err := n.state.DB.Cluster.GetNetworkLoadBalancers(ctx, n.ID(), memberSpecific)
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	var client *openvswitch.OVN
+	for _, loadBalancer := range loadBalancers {
+		checked := false
+		for _, backend := range loadBalancer.Backends {
+			if backend.HealthCheck != "" {
+				checked = true
+				break
+			}
+		}
+
+		if !checked {
+			continue
+		}
+
+		if client == nil {
+			client, err = openvswitch.NewOVN(n.state)
+			if err != nil {
+				return fmt.Errorf("Failed to get OVN client: %w", err)
+			}
+		}
+
+		listenAddress := net.ParseIP(loadBalancer.ListenAddress)
+		portMaps, err := n.loadBalancerValidate(listenAddress, &loadBalancer.NetworkLoadBalancerPut)
+		if err != nil {
+			return err
+		}
+
+		vips, err := n.loadBalancerVIPs(client, listenAddress, &loadBalancer.NetworkLoadBalancerPut, portMaps)
+		if err != nil {
+			return err
+		}
+
+		mappings, err := client.LoadBalancerIPPortMappings(n.getLoadBalancerName(loadBalancer.ListenAddress))
+		if err != nil {
+			return fmt.Errorf("Failed getting IP to port mappings of load balancer %q: %w", loadBalancer.ListenAddress, err)
+		}
+
+		upToDate := true
+		for _, vip := range vips {
+			for _, target := range vip.Targets {
+				if target.HealthCheckPort != "" && mappings[target.Address.String()] != fmt.Sprintf("%s:%s", target.HealthCheckPort, target.HealthCheckSourceIP.String()) {
+					upToDate = false
+				}
+			}
+		}
+
+		if upToDate {
+			continue
+		}
+
+		err = client.LoadBalancerApply(n.getLoadBalancerName(loadBalancer.ListenAddress), []openvswitch.OVNRouter{n.getRouterName()}, []openvswitch.OVNSwitch{n.getIntSwitchName()}, vips...)
+		if err != nil {
+			return fmt.Errorf("Failed applying load balancer %q: %w", loadBalancer.ListenAddress, err)
+		}
+	}
+
+	return nil
+}
+
+// LoadBalancerState returns the health of the load balancer backends as checked by OVN.
+func (n *ovn) LoadBalancerState(loadBalancer api.NetworkLoadBalancer) (*api.NetworkLoadBalancerState, error) {
+	state := &api.NetworkLoadBalancerState{
+		BackendHealth: make(map[string]api.NetworkLoadBalancerStateBackendHealth, len(loadBalancer.Backends)),
+	}
+
+	var portIPs map[openvswitch.OVNSwitchPort][]net.IP
+	var monitors []openvswitch.OVNServiceMonitor
+
+	for _, backend := range loadBalancer.Backends {
+		backendState := api.NetworkLoadBalancerStateBackendHealth{
+			Address:     backend.TargetAddress,
+			HealthCheck: backend.HealthCheck,
+			Status:      loadBalancerBackendUnchecked,
+		}
+
+		if backend.HealthCheck != "" {
+			if monitors == nil {
+				client, err := openvswitch.NewOVN(n.state)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get OVN client: %w", err)
+				}
+
+				portIPs, err = client.LogicalSwitchIPs(n.getIntSwitchName())
+				if err != nil {
+					return nil, fmt.Errorf("Failed getting internal switch port IPs: %w", err)
+				}
+
+				monitors, err = client.ServiceMonitors()
+				if err != nil {
+					return nil, fmt.Errorf("Failed getting OVN service monitors: %w", err)
+				}
+			}
+
+			backendState.Status = loadBalancerBackendUnknown
+			backendState.Error = "Backend isn't connected to the network"
+
+			online := 0
+			offline := 0
+			for _, monitor := range monitors {
+				_, found := portIPs[monitor.LogicalPort]
+				if !found || monitor.Address.String() != backend.TargetAddress {
+					continue
+				}
+
+				backendState.Error = ""
+
+				switch monitor.Status {
+				case "online":
+					online++
+				case "offline", "error":
+					offline++
+				}
+			}
+
+			if offline > 0 {
+				backendState.Status = loadBalancerBackendUnhealthy
+			} else if online > 0 {
+				backendState.Status = loadBalancerBackendHealthy
+			}
+		}
+
+		state.BackendHealth[backend.Name] = backendState
+	}
+
+	return state, nil
+}
+
 // Leases returns a list of leases for the OVN network. Those are directly extracted from the OVN database.
 func (n *ovn) Leases(projectName string, clientType request.ClientType) ([]api.NetworkLease, error) {
 	var err error
@@ -5119,43 +5429,7 @@ func (n *ovn) PeerCreate(peer api.NetworkPeersPost) error {
 	revert := revert.New()
 	defer revert.Fail()
 
-	// Perform create-time validation.
-
-	// Default to network's project if target project not specified.
-	if peer.TargetProject == "" {
-		peer.TargetProject = n.Project()
-	}
-
-	// Target network name is required.
-	if peer.TargetNetwork == "" {
-		return api.StatusErrorf(http.StatusBadRequest, "Target network is required")
-	}
-
-	// Check if there is an existing peer using the same name, or whether there is already a peering (in any
-	// state) to the target network.
-	peers, err := n.state.DB.Cluster.GetNetworkPeers(n.ID())
-	if err != nil {
-		return err
-	}
-
-	for _, existingPeer := range peers {
-		if peer.Name == existingPeer.Name {
-			return api.StatusErrorf(http.StatusConflict, "A peer for that name already exists")
-		}
-
-		if peer.TargetProject == existingPeer.TargetProject && peer.TargetNetwork == existingPeer.TargetNetwork {
-			return api.StatusErrorf(http.StatusConflict, "A peer for that target network already exists")
-		}
-	}
-
-	// Perform general (create and update) validation.
-	err = n.peerValidate(peer.Name, &peer.NetworkPeerPut)
-	if err != nil {
-		return err
-	}
-
-	// Create peer DB record.
-	peerID, mutualExists, err := n.state.DB.Cluster.CreateNetworkPeer(n.ID(), &peer)
+	peerID, mutualExists, err := n.peerCreateRecord(&peer)
 	if err != nil {
 		return err
 	}
@@ -5375,45 +5649,7 @@ func (n *ovn) peerSetup(client *openvswitch.OVN, targetOVNNet *ovn, opts openvsw
 
 // PeerUpdate updates a network peering.
 func (n *ovn) PeerUpdate(peerName string, req api.NetworkPeerPut) error {
-	revert := revert.New()
-	defer revert.Fail()
-
-	curPeerID, curPeer, err := n.state.DB.Cluster.GetNetworkPeer(n.ID(), peerName)
-	if err != nil {
-		return err
-	}
-
-	err = n.peerValidate(peerName, &req)
-	if err != nil {
-		return err
-	}
-
-	curPeerEtagHash, err := util.EtagHash(curPeer.Etag())
-	if err != nil {
-		return err
-	}
-
-	newPeer := api.NetworkPeer{
-		Name:           curPeer.Name,
-		NetworkPeerPut: req,
-	}
-
-	newPeerEtagHash, err := util.EtagHash(newPeer.Etag())
-	if err != nil {
-		return err
-	}
-
-	if curPeerEtagHash == newPeerEtagHash {
-		return nil // Nothing has changed.
-	}
-
-	err = n.state.DB.Cluster.UpdateNetworkPeer(n.ID(), curPeerID, &newPeer.NetworkPeerPut)
-	if err != nil {
-		return err
-	}
-
-	revert.Success()
-	return nil
+	return n.peerUpdate(peerName, req)
 }
 
 // PeerDelete deletes a network peering.
@@ -0,0 +1,657 @@
+package network
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/cluster/request"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/ip"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+// Default settings of the wireguard overlay.
+const (
+	wireguardDefaultPort       = 51820
+	wireguardDefaultMTU        = 1350
+	wireguardVXLANOverhead     = 70 // IPv6 (40) + UDP (8) + VXLAN (8) + Ethernet (14) headers.
+	wireguardVXLANPort         = "4789"
+	wireguardKeepaliveInterval = 25
+)
+
+// wireguardFloodMAC is the all-zeros MAC address used for the VXLAN flood entries of each peer.
+const wireguardFloodMAC = "00:00:00:00:00:00"
+
+// wireguard represents a LXD wireguard network.
+type wireguard struct {
+	common
+}
+
+// DBType returns the network type DB ID.
+func (n *wireguard) DBType() db.NetworkType {
+	return db.NetworkTypeWireguard
+}
+
+// Info returns the network driver info.
+func (n *wireguard) Info() Info {
+	info := n.common.Info()
+	info.NodeSpecificConfig = false
+
+	return info
+}
+
+// FillConfig fills requested config with any default values.
+func (n *wireguard) FillConfig(config map[string]string) error {
+	if config["wireguard.subnet"] == "" {
+		config["wireguard.subnet"] = "auto"
+	}
+
+	if config["wireguard.subnet"] == "auto" {
+		subnet, err := randomSubnetV6()
+		if err != nil {
+			return fmt.Errorf("Failed generating auto config: %w", err)
+		}
+
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return err
+		}
+
+		config["wireguard.subnet"] = ipNet.String()
+	}
+
+	return nil
+}
+
+// ValidateName validates network name.
+func (n *wireguard) ValidateName(name string) error {
+	err := validate.IsInterfaceName(name)
+	if err != nil {
+		return err
+	}
+
+	// Apply common name validation that applies to all network types.
+	return n.common.ValidateName(name)
+}
+
+// Validate network config.
+func (n *wireguard) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		"bridge.mtu":     validate.Optional(validate.IsNetworkMTU),
+		"wireguard.port": validate.Optional(validate.IsNetworkPort),
+		"wireguard.subnet": validate.Required(func(value string) error {
+			err := validate.IsNetworkV6(value)
+			if err != nil {
+				return err
+			}
+
+			_, subnet, _ := net.ParseCIDR(value)
+			ones, _ := subnet.Mask.Size()
+			if ones > 64 {
+				return fmt.Errorf("Subnet must be /64 or larger")
+			}
+
+			return nil
+		}),
+		"ipv4.gateway":                  validate.Optional(validate.IsNetworkAddressCIDRV4),
+		"ipv6.gateway":                  validate.Optional(validate.IsNetworkAddressCIDRV6),
+		"ipv4.ovn.ranges":               validate.Optional(validate.IsListOf(validate.IsNetworkRangeV4)),
+		"ipv6.ovn.ranges":               validate.Optional(validate.IsListOf(validate.IsNetworkRangeV6)),
+		"ipv4.routes":                   validate.Optional(validate.IsListOf(validate.IsNetworkV4)),
+		"ipv4.routes.anycast":           validate.Optional(validate.IsBool),
+		"ipv6.routes":                   validate.Optional(validate.IsListOf(validate.IsNetworkV6)),
+		"ipv6.routes.anycast":           validate.Optional(validate.IsBool),
+		"dns.nameservers":               validate.Optional(validate.IsListOf(validate.IsNetworkAddress)),
+		"ovn.ingress_mode":              validate.Optional(validate.IsOneOf("l2proxy", "routed")),
+		"volatile.wireguard.public_key": validate.Optional(validate.IsNotEmpty),
+	}
+
+	// Validate the configuration.
+	err := n.validate(config, rules)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isRunning returns whether the network is up.
+func (n *wireguard) isRunning() bool {
+	return InterfaceExists(n.name)
+}
+
+// wireguardDeviceName returns the name of the wireguard device of the network.
+func (n *wireguard) wireguardDeviceName() string {
+	return fmt.Sprintf("lxdwg%d", n.id)
+}
+
+// vxlanDeviceName returns the name of the VXLAN device carrying the bridge traffic over the wireguard mesh.
+func (n *wireguard) vxlanDeviceName() string {
+	return fmt.Sprintf("lxdvx%d", n.id)
+}
+
+// privateKeyPath returns the path of the file holding the local wireguard private key.
+func (n *wireguard) privateKeyPath() string {
+	return shared.VarPath("networks", n.name, "wireguard.key")
+}
+
+// setupPrivateKey generates the local wireguard private key (if missing) and returns its public key.
+func (n *wireguard) setupPrivateKey() (string, error) {
+	keyPath := n.privateKeyPath()
+
+	var privateKey []byte
+	if shared.PathExists(keyPath) {
+		content, err := os.ReadFile(keyPath)
+		if err != nil {
+			return "", fmt.Errorf("Failed reading wireguard private key: %w", err)
+		}
+
+		privateKey, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+		if err != nil || len(privateKey) != curve25519.ScalarSize {
+			return "", fmt.Errorf("Invalid wireguard private key %q", keyPath)
+		}
+	} else {
+		privateKey = make([]byte, curve25519.ScalarSize)
+		_, err := rand.Read(privateKey)
+		if err != nil {
+			return "", fmt.Errorf("Failed generating wireguard private key: %w", err)
+		}
+
+		// Clamp the key as expected by wireguard.
+		privateKey[0] &= 248
+		privateKey[31] = (privateKey[31] & 127) | 64
+
+		err = os.MkdirAll(shared.VarPath("networks", n.name), 0711)
+		if err != nil {
+			return "", err
+		}
+
+		err = os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(privateKey)+"\n"), 0600)
+		if err != nil {
+			return "", fmt.Errorf("Failed writing wireguard private key: %w", err)
+		}
+	}
+
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return "", fmt.Errorf("Failed deriving wireguard public key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(publicKey), nil
+}
+
+// tunnelAddress returns the address of the given cluster member inside the wireguard subnet.
+func (n *wireguard) tunnelAddress(memberID int64) (net.IP, error) {
+	_, subnet, err := net.ParseCIDR(n.config["wireguard.subnet"])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid wireguard subnet %q: %w", n.config["wireguard.subnet"], err)
+	}
+
+	addr := make(net.IP, net.IPv6len)
+	copy(addr, subnet.IP.To16())
+	binary.BigEndian.PutUint64(addr[8:], binary.BigEndian.Uint64(addr[8:])|uint64(memberID))
+
+	return addr, nil
+}
+
+// mtu returns the MTU of the network bridge.
+func (n *wireguard) mtu() (uint32, error) {
+	if n.config["bridge.mtu"] == "" {
+		return wireguardDefaultMTU, nil
+	}
+
+	mtu, err := strconv.ParseUint(n.config["bridge.mtu"], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid MTU %q: %w", n.config["bridge.mtu"], err)
+	}
+
+	return uint32(mtu), nil
+}
+
+// port returns the UDP port wireguard listens on.
+func (n *wireguard) port() (uint16, error) {
+	if n.config["wireguard.port"] == "" {
+		return wireguardDefaultPort, nil
+	}
+
+	port, err := strconv.ParseUint(n.config["wireguard.port"], 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid wireguard port %q: %w", n.config["wireguard.port"], err)
+	}
+
+	return uint16(port), nil
+}
+
+// Delete deletes a network.
+func (n *wireguard) Delete(clientType request.ClientType) error {
+	n.logger.Debug("Delete", logger.Ctx{"clientType": clientType})
+
+	if n.isRunning() {
+		err := n.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	return n.common.delete(clientType)
+}
+
+// Rename renames a network.
+func (n *wireguard) Rename(newName string) error {
+	n.logger.Debug("Rename", logger.Ctx{"newName": newName})
+
+	if InterfaceExists(newName) {
+		return fmt.Errorf("Network interface %q already exists", newName)
+	}
+
+	// Bring the network down.
+	if n.isRunning() {
+		err := n.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Rename common steps.
+	err := n.common.rename(newName)
+	if err != nil {
+		return err
+	}
+
+	// Bring the network up.
+	err = n.Start()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Start starts the network.
+func (n *wireguard) Start() error {
+	n.logger.Debug("Start")
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	revert.Add(func() { n.setUnavailable() })
+
+	err := n.setup()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+
+	// Ensure network is marked as available now its started.
+	n.setAvailable()
+
+	return nil
+}
+
+// setup creates the network bridge and connects it to the other cluster members through a VXLAN device running
+// over the wireguard mesh.
+func (n *wireguard) setup() error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	mtu, err := n.mtu()
+	if err != nil {
+		return err
+	}
+
+	port, err := n.port()
+	if err != nil {
+		return err
+	}
+
+	// Publish our public key in the database so the other cluster members can add us as a peer.
+	publicKey, err := n.setupPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	if n.config["volatile.wireguard.public_key"] != publicKey {
+		n.config["volatile.wireguard.public_key"] = publicKey
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetwork(n.id, n.description, n.config)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed saving wireguard public key: %w", err)
+		}
+	}
+
+	localAddress, err := n.tunnelAddress(n.state.DB.Cluster.GetNodeID())
+	if err != nil {
+		return err
+	}
+
+	// Create the bridge (keeping it if it already exists so connected instances aren't disrupted).
+	bridge := &ip.Bridge{Link: ip.Link{Name: n.name}}
+	if !InterfaceExists(n.name) {
+		err = bridge.Add()
+		if err != nil {
+			return err
+		}
+
+		revert.Add(func() { _ = bridge.Delete() })
+	}
+
+	err = bridge.SetMTU(mtu)
+	if err != nil {
+		return err
+	}
+
+	// Recreate the wireguard and VXLAN devices so that config changes are applied.
+	for _, devName := range []string{n.vxlanDeviceName(), n.wireguardDeviceName()} {
+		if InterfaceExists(devName) {
+			err = InterfaceRemove(devName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	wg := &ip.Wireguard{Link: ip.Link{Name: n.wireguardDeviceName(), MTU: mtu + wireguardVXLANOverhead}}
+	err = wg.Add()
+	if err != nil {
+		return fmt.Errorf("Failed creating wireguard device %q: %w", wg.Name, err)
+	}
+
+	revert.Add(func() { _ = wg.Delete() })
+
+	err = wg.SetListener(port, n.privateKeyPath())
+	if err != nil {
+		return err
+	}
+
+	addr := &ip.Addr{
+		DevName: wg.Name,
+		Address: fmt.Sprintf("%s/64", localAddress.String()),
+		Family:  ip.FamilyV6,
+	}
+
+	err = addr.Add()
+	if err != nil {
+		return fmt.Errorf("Failed adding address to wireguard device %q: %w", wg.Name, err)
+	}
+
+	err = wg.SetUp()
+	if err != nil {
+		return err
+	}
+
+	vxlan := &ip.Vxlan{
+		Link:    ip.Link{Name: n.vxlanDeviceName(), MTU: mtu},
+		VxlanID: fmt.Sprintf("%d", n.id),
+		DevName: wg.Name,
+		Local:   localAddress.String(),
+		DstPort: wireguardVXLANPort,
+	}
+
+	err = vxlan.Add()
+	if err != nil {
+		return fmt.Errorf("Failed creating VXLAN device %q: %w", vxlan.Name, err)
+	}
+
+	revert.Add(func() { _ = vxlan.Delete() })
+
+	err = AttachInterface(n.name, vxlan.Name)
+	if err != nil {
+		return err
+	}
+
+	err = vxlan.SetUp()
+	if err != nil {
+		return err
+	}
+
+	err = bridge.SetUp()
+	if err != nil {
+		return err
+	}
+
+	// Connect to the other cluster members.
+	var members []db.NodeInfo
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		members, err = tx.GetNodes(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting cluster members: %w", err)
+	}
+
+	memberAddresses := make(map[int64]string, len(members))
+	for _, member := range members {
+		memberAddresses[member.ID] = member.Address
+	}
+
+	err = n.refreshPeers(memberAddresses)
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// peers returns the wireguard peers of the other cluster members that have published their public key, keyed by
+// public key. Accepts maps of cluster member addresses and wireguard public keys keyed by member ID.
+func (n *wireguard) peers(localMemberID int64, memberAddresses map[int64]string, publicKeys map[int64]string) (map[string]ip.WireguardPeer, error) {
+	port, err := n.port()
+	if err != nil {
+		return nil, err
+	}
+
+	peers := make(map[string]ip.WireguardPeer, len(memberAddresses))
+	for memberID, address := range memberAddresses {
+		publicKey := publicKeys[memberID]
+		if memberID == localMemberID || publicKey == "" {
+			continue
+		}
+
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			n.logger.Warn("Skipping wireguard peer with invalid address", logger.Ctx{"memberID": memberID, "address": address, "err": err})
+			continue
+		}
+
+		peerAddress, err := n.tunnelAddress(memberID)
+		if err != nil {
+			return nil, err
+		}
+
+		peers[publicKey] = ip.WireguardPeer{
+			PublicKey:           publicKey,
+			Endpoint:            net.JoinHostPort(host, fmt.Sprintf("%d", port)),
+			AllowedIPs:          []string{fmt.Sprintf("%s/128", peerAddress.String())},
+			PersistentKeepalive: wireguardKeepaliveInterval,
+		}
+	}
+
+	return peers, nil
+}
+
+// refreshPeers configures every other cluster member that has published its public key as a wireguard peer and
+// VXLAN flood destination, and removes the peers of members that no longer exist.
+// Accepts a map of cluster member addresses keyed by member ID.
+func (n *wireguard) refreshPeers(memberAddresses map[int64]string) error {
+	var publicKeys map[int64]string
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		publicKeys, err = tx.GetNetworkNodeConfigValues(ctx, n.id, "volatile.wireguard.public_key")
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting wireguard public keys: %w", err)
+	}
+
+	peers, err := n.peers(n.state.DB.Cluster.GetNodeID(), memberAddresses, publicKeys)
+	if err != nil {
+		return err
+	}
+
+	wg := &ip.Wireguard{Link: ip.Link{Name: n.wireguardDeviceName()}}
+	vxlan := &ip.Link{Name: n.vxlanDeviceName()}
+
+	for _, peer := range peers {
+		err = wg.SetPeer(peer)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remove stale peers.
+	curPeers, err := wg.Peers()
+	if err != nil {
+		return err
+	}
+
+	for _, publicKey := range curPeers {
+		_, found := peers[publicKey]
+		if found {
+			continue
+		}
+
+		err = wg.RemovePeer(publicKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update the VXLAN flood list to match the peers.
+	curDsts, err := vxlan.BridgeFDBDestinations(wireguardFloodMAC)
+	if err != nil {
+		return fmt.Errorf("Failed getting VXLAN forwarding entries: %w", err)
+	}
+
+	wantDsts := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		peerAddress, _, _ := strings.Cut(peer.AllowedIPs[0], "/")
+		wantDsts = append(wantDsts, peerAddress)
+	}
+
+	for _, dst := range curDsts {
+		if shared.ValueInSlice(dst, wantDsts) {
+			continue
+		}
+
+		err = vxlan.BridgeFDBDelete(wireguardFloodMAC, dst)
+		if err != nil {
+			return fmt.Errorf("Failed removing VXLAN forwarding entry for %q: %w", dst, err)
+		}
+	}
+
+	for _, dst := range wantDsts {
+		if shared.ValueInSlice(dst, curDsts) {
+			continue
+		}
+
+		err = vxlan.BridgeFDBAppend(wireguardFloodMAC, dst)
+		if err != nil {
+			return fmt.Errorf("Failed adding VXLAN forwarding entry for %q: %w", dst, err)
+		}
+	}
+
+	return nil
+}
+
+// Stop stops the network.
+func (n *wireguard) Stop() error {
+	n.logger.Debug("Stop")
+
+	for _, devName := range []string{n.vxlanDeviceName(), n.wireguardDeviceName(), n.name} {
+		if !InterfaceExists(devName) {
+			continue
+		}
+
+		err := InterfaceRemove(devName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update updates the network. Accepts notification boolean indicating if this update request is coming from a
+// cluster notification, in which case do not update the database, just apply local changes needed.
+func (n *wireguard) Update(newNetwork api.NetworkPut, targetNode string, clientType request.ClientType) error {
+	n.logger.Debug("Update", logger.Ctx{"clientType": clientType, "newNetwork": newNetwork})
+
+	dbUpdateNeeded, changedKeys, oldNetwork, err := n.common.configChanged(newNetwork)
+	if err != nil {
+		return err
+	}
+
+	if !dbUpdateNeeded {
+		return nil // Nothing changed.
+	}
+
+	// If the network as a whole has not had any previous creation attempts, or the node itself is still
+	// pending, then don't apply the new settings to the node, just to the database record (ready for the
+	// actual global create request to be initiated).
+	if n.Status() == api.NetworkStatusPending || n.LocalStatus() == api.NetworkStatusPending {
+		return n.common.update(newNetwork, targetNode, clientType)
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Define a function which reverts everything.
+	revert.Add(func() {
+		// Reset changes to all nodes and database.
+		_ = n.common.update(oldNetwork, targetNode, clientType)
+	})
+
+	// Apply changes to all nodes and database.
+	err = n.common.update(newNetwork, targetNode, clientType)
+	if err != nil {
+		return err
+	}
+
+	err = n.setup()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+
+	// Notify dependent networks (those using this network as their uplink) of the changes.
+	// Do this after the network has been successfully updated so that a failure to notify a dependent network
+	// doesn't prevent the network itself from being updated.
+	if clientType == request.ClientTypeNormal && len(changedKeys) > 0 {
+		n.common.notifyDependentNetworks(changedKeys)
+	}
+
+	return nil
+}
+
+// HandleHeartbeat refreshes the wireguard peers from the cluster members in the heartbeat.
+func (n *wireguard) HandleHeartbeat(heartbeatData *cluster.APIHeartbeat) error {
+	// Make sure the network has been setup.
+	if !InterfaceExists(n.wireguardDeviceName()) {
+		return nil
+	}
+
+	memberAddresses := make(map[int64]string, len(heartbeatData.Members))
+	for _, member := range heartbeatData.Members {
+		memberAddresses[member.ID] = member.Address
+	}
+
+	return n.refreshPeers(memberAddresses)
+}
@@ -0,0 +1,102 @@
+package network
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/canonical/lxd/shared/logger"
+)
+
+func Example_wireguardValidate() {
+	n := &wireguard{common{name: "wg0", logger: logger.AddContext(logger.Ctx{})}}
+
+	configs := []map[string]string{
+		{"wireguard.subnet": "fd42:1:2:3::/64"},
+		{"wireguard.subnet": "fd42:1:2::/48", "wireguard.port": "51821", "bridge.mtu": "1400"},
+		{"wireguard.subnet": "fd42:1:2:3::/64", "user.foo": "bar"},
+		{"wireguard.subnet": "fd42:1:2:3::/80"},
+		{"wireguard.subnet": "10.0.0.0/24"},
+		{"wireguard.subnet": "fd42:1:2:3::/64", "wireguard.port": "70000"},
+		{"wireguard.subnet": "fd42:1:2:3::/64", "ipv4.address": "10.0.0.1/24"},
+	}
+
+	for _, config := range configs {
+		err := n.Validate(config)
+		if err != nil {
+			fmt.Printf("Err: %v\n", err)
+			continue
+		}
+
+		fmt.Println("Valid")
+	}
+
+	// Output: Valid
+	// Valid
+	// Valid
+	// Err: Invalid value for network "wg0" option "wireguard.subnet": Subnet must be /64 or larger
+	// Err: Invalid value for network "wg0" option "wireguard.subnet": Not an IPv6 network "10.0.0.0/24"
+	// Err: Invalid value for network "wg0" option "wireguard.port": Out of port number range (0-65535) "70000"
+	// Err: Invalid option for network "wg0" option "ipv4.address"
+}
+
+func Example_wireguardTunnelAddress() {
+	n := &wireguard{common{config: map[string]string{"wireguard.subnet": "fd42:1:2:3::/64"}}}
+
+	for _, memberID := range []int64{1, 2, 255, 65536} {
+		addr, err := n.tunnelAddress(memberID)
+		if err != nil {
+			fmt.Printf("Err: %v\n", err)
+			continue
+		}
+
+		fmt.Println(addr)
+	}
+
+	// Output: fd42:1:2:3::1
+	// fd42:1:2:3::2
+	// fd42:1:2:3::ff
+	// fd42:1:2:3::1:0
+}
+
+func Example_wireguardPeers() {
+	n := &wireguard{common{
+		config: map[string]string{"wireguard.subnet": "fd42:1:2:3::/64", "wireguard.port": "51821"},
+		logger: logger.AddContext(logger.Ctx{}),
+	}}
+
+	memberAddresses := map[int64]string{
+		1: "10.0.0.1:8443",
+		2: "10.0.0.2:8443",
+		3: "[2001:db8::3]:8443",
+		4: "10.0.0.4:8443", // No public key published yet.
+		5: "invalid",
+	}
+
+	publicKeys := map[int64]string{
+		1: "key1",
+		2: "key2",
+		3: "key3",
+		5: "key5",
+	}
+
+	peers, err := n.peers(1, memberAddresses, publicKeys)
+	if err != nil {
+		fmt.Printf("Err: %v\n", err)
+		return
+	}
+
+	publicKeysSorted := make([]string, 0, len(peers))
+	for publicKey := range peers {
+		publicKeysSorted = append(publicKeysSorted, publicKey)
+	}
+
+	sort.Strings(publicKeysSorted)
+
+	for _, publicKey := range publicKeysSorted {
+		peer := peers[publicKey]
+		fmt.Println(peer.PublicKey, peer.Endpoint, peer.AllowedIPs, peer.PersistentKeepalive)
+	}
+
+	// Output: key2 10.0.0.2:51821 [fd42:1:2:3::2/128] 25
+	// key3 [2001:db8::3]:51821 [fd42:1:2:3::3/128] 25
+}
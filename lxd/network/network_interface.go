@@ -1,6 +1,7 @@
 package network
 
 import (
+	"context"
 	"net"
 
 	"github.com/canonical/lxd/lxd/cluster"
@@ -67,6 +68,8 @@ type Network interface {
 	LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) error
 	LoadBalancerUpdate(listenAddress string, newLoadBalancer api.NetworkLoadBalancerPut, clientType request.ClientType) error
 	LoadBalancerDelete(listenAddress string, clientType request.ClientType) error
+	LoadBalancerState(loadBalancer api.NetworkLoadBalancer) (*api.NetworkLoadBalancerState, error)
+	LoadBalancerHealthCheck(ctx context.Context, leader bool) error
 
 	// Peerings.
 	PeerCreate(forward api.NetworkPeersPost) error
@@ -0,0 +1,471 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// loadBalancerHealthChecks lists the supported load balancer backend health checks.
+var loadBalancerHealthChecks = []string{"tcp", "http"}
+
+// Load balancer backend health statuses.
+const (
+	loadBalancerBackendUnchecked = "unchecked"
+	loadBalancerBackendUnknown   = "unknown"
+	loadBalancerBackendHealthy   = "healthy"
+	loadBalancerBackendUnhealthy = "unhealthy"
+)
+
+// loadBalancerKey identifies a load balancer by network and listen address.
+type loadBalancerKey struct {
+	networkID     int64
+	listenAddress string
+}
+
+// loadBalancerBackendHealth holds the health check results of a load balancer backend.
+type loadBalancerBackendHealth struct {
+	definition string // Backend settings the results relate to.
+	status     string
+	err        string
+	lastCheck  time.Time
+	failures   uint64
+	successes  uint64
+}
+
+// loadBalancerHealth holds the health check results of the backends of a load balancer.
+type loadBalancerHealth struct {
+	etag      string // Hash of the load balancer settings when last checked.
+	lastCheck time.Time
+	backends  map[string]*loadBalancerBackendHealth
+}
+
+// unhealthy returns the names of the backends that failed their health checks.
+func (h *loadBalancerHealth) unhealthy() map[string]struct{} {
+	unhealthy := make(map[string]struct{})
+	for name, backend := range h.backends {
+		if backend.status == loadBalancerBackendUnhealthy {
+			unhealthy[name] = struct{}{}
+		}
+	}
+
+	return unhealthy
+}
+
+// loadBalancerHealthMu protects loadBalancerHealthStates.
+var loadBalancerHealthMu sync.Mutex
+
+// loadBalancerHealthStates holds the health check results of the load balancers checked by this member.
+var loadBalancerHealthStates = make(map[loadBalancerKey]*loadBalancerHealth)
+
+// loadBalancerBackendHealthCheckPort returns the port to run the backend's health check against.
+// This is the health check port if set, otherwise the first target port of the backend, otherwise the first
+// listen port of the first port specification using the backend.
+func loadBalancerBackendHealthCheckPort(loadBalancer *api.NetworkLoadBalancerPut, backend *api.NetworkLoadBalancerBackend) (uint64, error) {
+	firstPort := func(portRanges string) (uint64, error) {
+		ports := shared.SplitNTrimSpace(portRanges, ",", -1, true)
+		if len(ports) < 1 {
+			return 0, fmt.Errorf("No ports specified")
+		}
+
+		port, _, err := ParsePortRange(ports[0])
+		if err != nil {
+			return 0, err
+		}
+
+		return uint64(port), nil
+	}
+
+	if backend.HealthCheckPort != "" {
+		port, err := strconv.ParseUint(backend.HealthCheckPort, 10, 16)
+		if err != nil || port == 0 {
+			return 0, fmt.Errorf("Invalid health check port %q for backend %q", backend.HealthCheckPort, backend.Name)
+		}
+
+		return port, nil
+	}
+
+	if backend.TargetPort != "" {
+		return firstPort(backend.TargetPort)
+	}
+
+	for _, portSpec := range loadBalancer.Ports {
+		if shared.ValueInSlice(backend.Name, portSpec.TargetBackend) {
+			return firstPort(portSpec.ListenPort)
+		}
+	}
+
+	return 0, fmt.Errorf("Health check port required for backend %q as it has no target port and isn't used by any port specification", backend.Name)
+}
+
+// loadBalancerBackendDefinition returns a string identifying the settings the backend's health checks depend on.
+func loadBalancerBackendDefinition(loadBalancer *api.NetworkLoadBalancerPut, backend *api.NetworkLoadBalancerBackend) string {
+	port, _ := loadBalancerBackendHealthCheckPort(loadBalancer, backend)
+
+	return fmt.Sprintf("%s|%s|%d|%s", backend.TargetAddress, backend.HealthCheck, port, backend.HealthCheckPath)
+}
+
+// loadBalancerHealthCheckSetting returns the value of a load balancer health check config key.
+func loadBalancerHealthCheckSetting(config map[string]string, key string, defaultValue uint64) uint64 {
+	value, err := strconv.ParseUint(config[key], 10, 32)
+	if err != nil || value == 0 {
+		return defaultValue
+	}
+
+	return value
+}
+
+// loadBalancerUnhealthyBackends returns the names of the load balancer's backends that failed their health checks.
+func loadBalancerUnhealthyBackends(networkID int64, listenAddress string, loadBalancer *api.NetworkLoadBalancerPut) map[string]struct{} {
+	unhealthy := make(map[string]struct{})
+
+	loadBalancerHealthMu.Lock()
+	defer loadBalancerHealthMu.Unlock()
+
+	health := loadBalancerHealthStates[loadBalancerKey{networkID: networkID, listenAddress: listenAddress}]
+	if health == nil {
+		return unhealthy
+	}
+
+	for i := range loadBalancer.Backends {
+		backend := &loadBalancer.Backends[i]
+
+		backendHealth := health.backends[backend.Name]
+		if backendHealth == nil || backendHealth.definition != loadBalancerBackendDefinition(loadBalancer, backend) {
+			continue
+		}
+
+		if backendHealth.status == loadBalancerBackendUnhealthy {
+			unhealthy[backend.Name] = struct{}{}
+		}
+	}
+
+	return unhealthy
+}
+
+// loadBalancerBackendProbe runs the health check of a load balancer backend.
+func loadBalancerBackendProbe(ctx context.Context, backend *api.NetworkLoadBalancerBackend, port uint64, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	address := net.JoinHostPort(backend.TargetAddress, strconv.FormatUint(port, 10))
+
+	switch backend.HealthCheck {
+	case "tcp":
+		dialer := net.Dialer{}
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	case "http":
+		path := backend.HealthCheckPath
+		if path == "" {
+			path = "/"
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s%s", address, path), nil)
+		if err != nil {
+			return err
+		}
+
+		client := &http.Client{
+			// Don't follow redirects, a redirect response is considered healthy.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+			// Connect directly to the backend, ignoring any proxy configured for the daemon.
+			Transport: &http.Transport{DisableKeepAlives: true},
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+
+		_ = resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("Unexpected HTTP status %q", resp.Status)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("Unsupported health check %q", backend.HealthCheck)
+}
+
+// loadBalancerHealthCheck runs the due backend health checks of the given load balancers of a network.
+// Returns the load balancers that need to be reapplied because the set of unhealthy backends has changed, or
+// because their settings have changed while they have unhealthy backends.
+func loadBalancerHealthCheck(ctx context.Context, networkID int64, loadBalancers map[int64]*api.NetworkLoadBalancer) []*api.NetworkLoadBalancer {
+	type probe struct {
+		loadBalancer *api.NetworkLoadBalancer
+		backend      *api.NetworkLoadBalancerBackend
+		port         uint64
+		timeout      time.Duration
+		err          error
+	}
+
+	var probes []*probe
+	var reapply []*api.NetworkLoadBalancer
+	unhealthyBefore := make(map[loadBalancerKey]map[string]struct{})
+	seen := make(map[string]struct{}, len(loadBalancers))
+	now := time.Now()
+
+	loadBalancerHealthMu.Lock()
+
+	for _, loadBalancer := range loadBalancers {
+		key := loadBalancerKey{networkID: networkID, listenAddress: loadBalancer.ListenAddress}
+		seen[loadBalancer.ListenAddress] = struct{}{}
+		health := loadBalancerHealthStates[key]
+
+		// Get the backends that have a health check configured.
+		definitions := make(map[string]string)
+		for i := range loadBalancer.Backends {
+			backend := &loadBalancer.Backends[i]
+			if backend.HealthCheck != "" {
+				definitions[backend.Name] = loadBalancerBackendDefinition(&loadBalancer.NetworkLoadBalancerPut, backend)
+			}
+		}
+
+		if len(definitions) == 0 {
+			if health != nil {
+				// Reapply the load balancer if it had unhealthy backends removed.
+				if len(health.unhealthy()) > 0 {
+					reapply = append(reapply, loadBalancer)
+				}
+
+				delete(loadBalancerHealthStates, key)
+			}
+
+			continue
+		}
+
+		if health == nil {
+			health = &loadBalancerHealth{backends: make(map[string]*loadBalancerBackendHealth)}
+			loadBalancerHealthStates[key] = health
+		}
+
+		unhealthyBefore[key] = health.unhealthy()
+
+		// Reset the results of backends that were removed or whose settings changed.
+		for name, backendHealth := range health.backends {
+			if definitions[name] != backendHealth.definition {
+				delete(health.backends, name)
+			}
+		}
+
+		for name, definition := range definitions {
+			if health.backends[name] == nil {
+				health.backends[name] = &loadBalancerBackendHealth{
+					definition: definition,
+					status:     loadBalancerBackendUnknown,
+				}
+			}
+		}
+
+		interval := time.Duration(loadBalancerHealthCheckSetting(loadBalancer.Config, "healthcheck.interval", 10)) * time.Second
+		if now.Sub(health.lastCheck) < interval {
+			continue
+		}
+
+		health.lastCheck = now
+		timeout := time.Duration(loadBalancerHealthCheckSetting(loadBalancer.Config, "healthcheck.timeout", 5)) * time.Second
+
+		for i := range loadBalancer.Backends {
+			backend := &loadBalancer.Backends[i]
+			if backend.HealthCheck == "" {
+				continue
+			}
+
+			port, err := loadBalancerBackendHealthCheckPort(&loadBalancer.NetworkLoadBalancerPut, backend)
+			probes = append(probes, &probe{
+				loadBalancer: loadBalancer,
+				backend:      backend,
+				port:         port,
+				timeout:      timeout,
+				err:          err,
+			})
+		}
+	}
+
+	// Forget load balancers that no longer exist.
+	for key := range loadBalancerHealthStates {
+		_, found := seen[key.listenAddress]
+		if key.networkID == networkID && !found {
+			delete(loadBalancerHealthStates, key)
+		}
+	}
+
+	loadBalancerHealthMu.Unlock()
+
+	// Run the health checks concurrently without holding the lock.
+	wg := sync.WaitGroup{}
+	for _, p := range probes {
+		if p.err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(p *probe) {
+			defer wg.Done()
+			p.err = loadBalancerBackendProbe(ctx, p.backend, p.port, p.timeout)
+		}(p)
+	}
+
+	wg.Wait()
+
+	loadBalancerHealthMu.Lock()
+	defer loadBalancerHealthMu.Unlock()
+
+	for _, p := range probes {
+		health := loadBalancerHealthStates[loadBalancerKey{networkID: networkID, listenAddress: p.loadBalancer.ListenAddress}]
+		if health == nil {
+			continue
+		}
+
+		backendHealth := health.backends[p.backend.Name]
+		if backendHealth == nil {
+			continue
+		}
+
+		backendHealth.lastCheck = now
+
+		if p.err != nil {
+			backendHealth.err = p.err.Error()
+			backendHealth.successes = 0
+			backendHealth.failures++
+
+			if backendHealth.failures >= loadBalancerHealthCheckSetting(p.loadBalancer.Config, "healthcheck.failure_count", 3) {
+				backendHealth.status = loadBalancerBackendUnhealthy
+			}
+		} else {
+			backendHealth.err = ""
+			backendHealth.failures = 0
+			backendHealth.successes++
+
+			// Backends in an unknown state already receive traffic, so mark them healthy straight away.
+			if backendHealth.status == loadBalancerBackendUnknown || backendHealth.successes >= loadBalancerHealthCheckSetting(p.loadBalancer.Config, "healthcheck.success_count", 3) {
+				backendHealth.status = loadBalancerBackendHealthy
+			}
+		}
+	}
+
+	for _, loadBalancer := range loadBalancers {
+		key := loadBalancerKey{networkID: networkID, listenAddress: loadBalancer.ListenAddress}
+		health := loadBalancerHealthStates[key]
+		if health == nil {
+			continue
+		}
+
+		etag, err := util.EtagHash(loadBalancer.Etag())
+		if err != nil {
+			etag = ""
+		}
+
+		before := unhealthyBefore[key]
+		after := health.unhealthy()
+
+		changed := len(before) != len(after)
+		for name := range after {
+			_, found := before[name]
+			if !found {
+				changed = true
+			}
+		}
+
+		// Reapply if the load balancer settings were changed elsewhere while it has unhealthy backends, as
+		// they would then have been applied without taking the health check results into account.
+		if changed || (etag != health.etag && len(after) > 0) {
+			reapply = append(reapply, loadBalancer)
+		}
+
+		health.etag = etag
+	}
+
+	return reapply
+}
+
+// loadBalancerState returns the health state of the backends of a load balancer.
+func loadBalancerState(networkID int64, loadBalancer *api.NetworkLoadBalancer) *api.NetworkLoadBalancerState {
+	state := &api.NetworkLoadBalancerState{
+		BackendHealth: make(map[string]api.NetworkLoadBalancerStateBackendHealth, len(loadBalancer.Backends)),
+	}
+
+	loadBalancerHealthMu.Lock()
+	defer loadBalancerHealthMu.Unlock()
+
+	health := loadBalancerHealthStates[loadBalancerKey{networkID: networkID, listenAddress: loadBalancer.ListenAddress}]
+
+	for i := range loadBalancer.Backends {
+		backend := &loadBalancer.Backends[i]
+
+		backendState := api.NetworkLoadBalancerStateBackendHealth{
+			Address:     backend.TargetAddress,
+			HealthCheck: backend.HealthCheck,
+			Status:      loadBalancerBackendUnchecked,
+		}
+
+		if backend.HealthCheck != "" {
+			backendState.Status = loadBalancerBackendUnknown
+
+			if health != nil {
+				backendHealth := health.backends[backend.Name]
+				if backendHealth != nil && backendHealth.definition == loadBalancerBackendDefinition(&loadBalancer.NetworkLoadBalancerPut, backend) {
+					backendState.Status = backendHealth.status
+					backendState.Error = backendHealth.err
+					backendState.LastCheck = backendHealth.lastCheck
+				}
+			}
+		}
+
+		state.BackendHealth[backend.Name] = backendState
+	}
+
+	return state
+}
+
+// loadBalancerHealthyPortMaps removes the backends that failed their health checks from the load balancer port
+// maps. Port maps whose backends have all failed keep them, so that traffic is still forwarded somewhere.
+func loadBalancerHealthyPortMaps(networkID int64, listenAddress string, loadBalancer *api.NetworkLoadBalancerPut, portMaps []*loadBalancerPortMap) []*loadBalancerPortMap {
+	unhealthy := loadBalancerUnhealthyBackends(networkID, listenAddress, loadBalancer)
+	if len(unhealthy) == 0 {
+		return portMaps
+	}
+
+	healthyPortMaps := make([]*loadBalancerPortMap, 0, len(portMaps))
+	for _, portMap := range portMaps {
+		healthyPortMap := loadBalancerPortMap{
+			listenPorts: portMap.listenPorts,
+			protocol:    portMap.protocol,
+		}
+
+		for i, backendName := range portMap.targetBackends {
+			_, found := unhealthy[backendName]
+			if found {
+				continue
+			}
+
+			healthyPortMap.targets = append(healthyPortMap.targets, portMap.targets[i])
+			healthyPortMap.targetBackends = append(healthyPortMap.targetBackends, backendName)
+		}
+
+		if len(healthyPortMap.targets) == 0 {
+			healthyPortMap = *portMap
+		}
+
+		healthyPortMaps = append(healthyPortMaps, &healthyPortMap)
+	}
+
+	return healthyPortMaps
+}
@@ -5,6 +5,7 @@ import (
 	"net"
 
 	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
 )
 
 func Example_parseIPRange() {
@@ -137,3 +138,81 @@ func Example_ipRangesOverlap() {
 	// Range1: 10.1.1.4, Range2: 10.1.1.8-10.1.1.9, overlapped: false
 	// Range1: 10.1.1.8-10.1.1.9, Range2: 10.1.1.4, overlapped: false
 }
+
+func Example_loadBalancerBackendHealthCheckPort() {
+	loadBalancer := &api.NetworkLoadBalancerPut{
+		Backends: []api.NetworkLoadBalancerBackend{
+			{Name: "c1", TargetAddress: "10.0.0.1", HealthCheck: "tcp"},
+			{Name: "c2", TargetAddress: "10.0.0.2", TargetPort: "8080-8090", HealthCheck: "tcp"},
+			{Name: "c3", TargetAddress: "10.0.0.3", TargetPort: "8080", HealthCheck: "http", HealthCheckPort: "9000"},
+			{Name: "c4", TargetAddress: "10.0.0.4", HealthCheck: "tcp"},
+		},
+		Ports: []api.NetworkLoadBalancerPort{
+			{Protocol: "tcp", ListenPort: "80,443", TargetBackend: []string{"c1", "c3"}},
+			{Protocol: "tcp", ListenPort: "8000-8010", TargetBackend: []string{"c2"}},
+		},
+	}
+
+	for i := range loadBalancer.Backends {
+		port, err := loadBalancerBackendHealthCheckPort(loadBalancer, &loadBalancer.Backends[i])
+		fmt.Println(loadBalancer.Backends[i].Name, port, err)
+	}
+
+	// Output:
+	// c1 80 <nil>
+	// c2 8080 <nil>
+	// c3 9000 <nil>
+	// c4 0 Health check port required for backend "c4" as it has no target port and isn't used by any port specification
+}
+
+func Example_loadBalancerHealthyPortMaps() {
+	loadBalancer := &api.NetworkLoadBalancerPut{
+		Backends: []api.NetworkLoadBalancerBackend{
+			{Name: "c1", TargetAddress: "10.0.0.1", HealthCheck: "tcp"},
+			{Name: "c2", TargetAddress: "10.0.0.2", HealthCheck: "tcp"},
+			{Name: "c3", TargetAddress: "10.0.0.3"},
+		},
+		Ports: []api.NetworkLoadBalancerPort{
+			{Protocol: "tcp", ListenPort: "80", TargetBackend: []string{"c1", "c2", "c3"}},
+			{Protocol: "tcp", ListenPort: "443", TargetBackend: []string{"c1"}},
+		},
+	}
+
+	portMaps := []*loadBalancerPortMap{
+		{
+			listenPorts:    []uint64{80},
+			protocol:       "tcp",
+			targets:        []forwardTarget{{address: net.ParseIP("10.0.0.1")}, {address: net.ParseIP("10.0.0.2")}, {address: net.ParseIP("10.0.0.3")}},
+			targetBackends: []string{"c1", "c2", "c3"},
+		},
+		{
+			listenPorts:    []uint64{443},
+			protocol:       "tcp",
+			targets:        []forwardTarget{{address: net.ParseIP("10.0.0.1")}},
+			targetBackends: []string{"c1"},
+		},
+	}
+
+	key := loadBalancerKey{networkID: 1, listenAddress: "192.0.2.1"}
+	loadBalancerHealthStates[key] = &loadBalancerHealth{
+		backends: map[string]*loadBalancerBackendHealth{
+			"c1": {definition: loadBalancerBackendDefinition(loadBalancer, &loadBalancer.Backends[0]), status: loadBalancerBackendUnhealthy},
+			"c2": {definition: loadBalancerBackendDefinition(loadBalancer, &loadBalancer.Backends[1]), status: loadBalancerBackendHealthy},
+		},
+	}
+
+	defer delete(loadBalancerHealthStates, key)
+
+	for _, portMap := range loadBalancerHealthyPortMaps(key.networkID, key.listenAddress, loadBalancer, portMaps) {
+		fmt.Println(portMap.listenPorts, portMap.targetBackends, len(portMap.targets))
+	}
+
+	// Changing the backend's target address discards its health check results.
+	loadBalancer.Backends[0].TargetAddress = "10.0.0.4"
+	fmt.Println(loadBalancerHealthyPortMaps(key.networkID, key.listenAddress, loadBalancer, portMaps)[0].targetBackends)
+
+	// Output:
+	// [80] [c2 c3] 2
+	// [443] [c1] 1
+	// [c1 c2 c3]
+}
@@ -130,13 +130,26 @@ type OVNACLRule struct {
 	Match     string // Match criteria. See OVN Southbound database's Logical_Flow table match column usage.
 	Priority  int    // Priority (between 0 and 32767, inclusive). Higher values take precedence.
 	Log       bool   // Whether or not to log matched packets.
-	LogName   string // Log label name (requires Log be true).
+	LogName   string // Log label name, also used to find the rule's hit counters.
 }
 
 // OVNLoadBalancerTarget represents an OVN load balancer Virtual IP target.
 type OVNLoadBalancerTarget struct {
 	Address net.IP
 	Port    uint64
+
+	// Logical switch port of the target and source address to use for health checks (only used if the VIP
+	// has a health check).
+	HealthCheckPort     OVNSwitchPort
+	HealthCheckSourceIP net.IP
+}
+
+// OVNLoadBalancerHealthCheck represents the settings of an OVN load balancer Virtual IP health check.
+type OVNLoadBalancerHealthCheck struct {
+	Interval     uint64
+	Timeout      uint64
+	SuccessCount uint64
+	FailureCount uint64
 }
 
 // OVNLoadBalancerVIP represents a OVN load balancer Virtual IP entry.
@@ -145,6 +158,16 @@ type OVNLoadBalancerVIP struct {
 	ListenAddress net.IP
 	ListenPort    uint64
 	Targets       []OVNLoadBalancerTarget
+	HealthCheck   *OVNLoadBalancerHealthCheck // Only applies to port based VIPs.
+}
+
+// OVNServiceMonitor represents the status of a target checked by an OVN load balancer health check.
+type OVNServiceMonitor struct {
+	LogicalPort OVNSwitchPort
+	Address     net.IP
+	Port        uint64
+	Protocol    string
+	Status      string // Either "online", "offline", "error" or empty if not checked yet.
 }
 
 // OVNRouterRoute represents a static route added to a logical router.
@@ -1144,6 +1167,72 @@ func (o *OVN) logicalSwitchPortACLRules(portName OVNSwitchPort) ([]string, error
 	return ruleUUIDs, nil
 }
 
+// ACLRuleFlowCookies returns the OpenFlow cookies of the flows implementing the ACL rules whose name starts with
+// namePrefix. The cookies are keyed by ACL rule name.
+func (o *OVN) ACLRuleFlowCookies(namePrefix string) (map[string][]uint64, error) {
+	output, err := o.nbctl("--format=csv", "--no-headings", "--data=bare", "--colum=_uuid,name", "find", "acl")
+	if err != nil {
+		return nil, err
+	}
+
+	// The logical flows of an ACL rule have their stage-hint set to the first 8 hex digits of the rule's UUID.
+	hintNames := make(map[string]string)
+	args := []string{"--format=csv", "--no-headings", "--data=bare"}
+	for _, line := range shared.SplitNTrimSpace(strings.TrimSpace(output), "\n", -1, true) {
+		fields := strings.SplitN(line, ",", 2)
+		if len(fields) != 2 || len(fields[0]) < 8 || !strings.HasPrefix(fields[1], namePrefix) {
+			continue
+		}
+
+		hint := fields[0][:8]
+		_, found := hintNames[hint]
+		hintNames[hint] = fields[1]
+
+		if !found {
+			if len(args) > 3 {
+				args = append(args, "--")
+			}
+
+			args = append(args, "--colum=_uuid,external_ids", "find", "logical_flow", fmt.Sprintf("external_ids:stage-hint=%s", hint))
+		}
+	}
+
+	cookies := make(map[string][]uint64)
+	if len(hintNames) == 0 {
+		return cookies, nil
+	}
+
+	output, err = o.sbctl(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range shared.SplitNTrimSpace(strings.TrimSpace(output), "\n", -1, true) {
+		// E.g. "8f3a2b1c-...,source=\"northd.c:6621\" stage-hint=\"2d5c3e4f\" stage-name=ls_out_acl"
+		lflowUUID, externalIDs, found := strings.Cut(line, ",")
+		if !found || len(lflowUUID) < 8 {
+			continue
+		}
+
+		for _, externalID := range strings.Fields(strings.ReplaceAll(externalIDs, `"`, "")) {
+			hint, found := strings.CutPrefix(externalID, "stage-hint=")
+			if !found || hintNames[hint] == "" {
+				continue
+			}
+
+			// The OpenFlow flows of a logical flow use the first 32 bits of its UUID as cookie.
+			cookie, err := strconv.ParseUint(lflowUUID[:8], 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing logical flow UUID %q: %w", lflowUUID, err)
+			}
+
+			cookies[hintNames[hint]] = append(cookies[hintNames[hint]], cookie)
+		}
+	}
+
+	return cookies, nil
+}
+
 // LogicalSwitchPorts returns a map of logical switch ports (name and UUID) for a switch.
 // Includes non-instance ports, such as the router port.
 func (o *OVN) LogicalSwitchPorts(switchName OVNSwitch) (map[OVNSwitchPort]OVNSwitchPortUUID, error) {
@@ -1802,10 +1891,11 @@ func (o *OVN) aclRuleAddAppendArgs(args []string, entityTable string, entityName
 
 		if rule.Log {
 			args = append(args, "log=true")
+		}
 
-			if rule.LogName != "" {
-				args = append(args, fmt.Sprintf("name=%s", rule.LogName))
-			}
+		// The name is set even when not logging so the rule's hit counters can be found.
+		if rule.LogName != "" {
+			args = append(args, fmt.Sprintf("name=%s", rule.LogName))
 		}
 
 		for k, v := range externalIDs {
@@ -1901,8 +1991,11 @@ func (o *OVN) LoadBalancerApply(loadBalancerName OVNLoadBalancer, routers []OVNR
 	// We have to use a separate load balancer for UDP rules so use this to keep track of whether we need it.
 	lbNames := make(map[string]struct{})
 
+	// Health checks and their IP to port mappings are added after all VIPs.
+	var healthCheckArgs []string
+
 	// Build up the commands to add VIPs to the load balancer.
-	for _, r := range vips {
+	for i, r := range vips {
 		if r.ListenAddress == nil {
 			return fmt.Errorf("Missing VIP listen address")
 		}
@@ -1911,12 +2004,35 @@ func (o *OVN) LoadBalancerApply(loadBalancerName OVNLoadBalancer, routers []OVNR
 			return fmt.Errorf("Missing VIP target(s)")
 		}
 
+		lbName := lbTCPName
 		if r.Protocol == "udp" {
-			args = append(args, "--", "lb-add", lbUDPName)
-			lbNames[lbUDPName] = struct{}{} // Record that UDP load balancer is created.
-		} else {
-			args = append(args, "--", "lb-add", lbTCPName)
-			lbNames[lbTCPName] = struct{}{} // Record that TCP load balancer is created.
+			lbName = lbUDPName
+		}
+
+		args = append(args, "--", "lb-add", lbName)
+		lbNames[lbName] = struct{}{} // Record that load balancer is created.
+
+		if r.HealthCheck != nil && r.ListenPort > 0 {
+			healthCheckID := fmt.Sprintf("@hc%d", i)
+			healthCheckArgs = append(healthCheckArgs,
+				"--", fmt.Sprintf("--id=%s", healthCheckID), "create", "load_balancer_health_check",
+				fmt.Sprintf(`vip="%s:%d"`, ipToString(r.ListenAddress), r.ListenPort),
+				fmt.Sprintf("options:interval=%d", r.HealthCheck.Interval),
+				fmt.Sprintf("options:timeout=%d", r.HealthCheck.Timeout),
+				fmt.Sprintf("options:success_count=%d", r.HealthCheck.SuccessCount),
+				fmt.Sprintf("options:failure_count=%d", r.HealthCheck.FailureCount),
+				"--", "add", "load_balancer", lbName, "health_check", healthCheckID,
+			)
+
+			for _, target := range r.Targets {
+				if target.HealthCheckPort == "" || target.HealthCheckSourceIP == nil {
+					continue
+				}
+
+				healthCheckArgs = append(healthCheckArgs,
+					"--", "set", "load_balancer", lbName, fmt.Sprintf(`ip_port_mappings:"%s"="%s:%s"`, target.Address.String(), target.HealthCheckPort, target.HealthCheckSourceIP.String()),
+				)
+			}
 		}
 
 		targetArgs := make([]string, 0, len(r.Targets))
@@ -1947,6 +2063,8 @@ func (o *OVN) LoadBalancerApply(loadBalancerName OVNLoadBalancer, routers []OVNR
 		}
 	}
 
+	args = append(args, healthCheckArgs...)
+
 	// If there are some VIP rules then associate the load balancer to the requested routers and switches.
 	if len(vips) > 0 {
 		for _, r := range routers {
@@ -1982,6 +2100,60 @@ func (o *OVN) LoadBalancerApply(loadBalancerName OVNLoadBalancer, routers []OVNR
 	return nil
 }
 
+// LoadBalancerIPPortMappings returns the health check IP to port mappings of the specified load balancer.
+func (o *OVN) LoadBalancerIPPortMappings(loadBalancerName OVNLoadBalancer) (map[string]string, error) {
+	mappings := make(map[string]string)
+
+	for _, lbName := range []string{fmt.Sprintf("%s-tcp", loadBalancerName), fmt.Sprintf("%s-udp", loadBalancerName)} {
+		output, err := o.nbctl("--format=csv", "--no-headings", "--data=bare", "--columns=ip_port_mappings", "find", "load_balancer", fmt.Sprintf("name=%s", lbName))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mapping := range shared.SplitNTrimSpace(strings.TrimSpace(output), " ", -1, true) {
+			ip, port, found := strings.Cut(mapping, "=")
+			if found {
+				mappings[ip] = port
+			}
+		}
+	}
+
+	return mappings, nil
+}
+
+// ServiceMonitors returns the targets checked by load balancer health checks along with their status.
+func (o *OVN) ServiceMonitors() ([]OVNServiceMonitor, error) {
+	output, err := o.sbctl("--format=csv", "--no-headings", "--data=bare", "--columns=logical_port,ip,port,protocol,status", "list", "service_monitor")
+	if err != nil {
+		return nil, err
+	}
+
+	lines := shared.SplitNTrimSpace(strings.TrimSpace(output), "\n", -1, true)
+	monitors := make([]OVNServiceMonitor, 0, len(lines))
+
+	for _, line := range lines {
+		fields := shared.SplitNTrimSpace(line, ",", -1, false)
+		if len(fields) != 5 {
+			continue
+		}
+
+		port, err := strconv.ParseUint(fields[2], 10, 16)
+		if err != nil {
+			continue
+		}
+
+		monitors = append(monitors, OVNServiceMonitor{
+			LogicalPort: OVNSwitchPort(fields[0]),
+			Address:     net.ParseIP(fields[1]),
+			Port:        port,
+			Protocol:    fields[3],
+			Status:      fields[4],
+		})
+	}
+
+	return monitors, nil
+}
+
 // LoadBalancerDelete deletes the specified load balancer(s).
 func (o *OVN) LoadBalancerDelete(loadBalancerNames ...OVNLoadBalancer) error {
 	var args []string
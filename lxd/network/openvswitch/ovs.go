@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"os/exec"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -335,6 +336,59 @@ func (o *OVS) BridgePortList(bridgeName string) ([]string, error) {
 	return ports, nil
 }
 
+// OVSFlowCounters represents the packet and byte counters of OpenFlow flows.
+type OVSFlowCounters struct {
+	Packets uint64
+	Bytes   uint64
+}
+
+// BridgeFlowCounters returns the counters of the OpenFlow flows on the bridge, summed by flow cookie.
+func (o *OVS) BridgeFlowCounters(bridgeName string) (map[uint64]OVSFlowCounters, error) {
+	output, err := shared.RunCommand("ovs-ofctl", "dump-flows", bridgeName)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[uint64]OVSFlowCounters)
+	for _, line := range strings.Split(output, "\n") {
+		// E.g. " cookie=0x2d5c3e4f, duration=30.1s, table=44, n_packets=12, n_bytes=1008, priority=2002,ip actions=..."
+		var cookie uint64
+		var flowCounters OVSFlowCounters
+		var err error
+
+		for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' }) {
+			key, value, found := strings.Cut(field, "=")
+			if !found {
+				continue
+			}
+
+			switch key {
+			case "cookie":
+				cookie, err = strconv.ParseUint(strings.TrimPrefix(value, "0x"), 16, 64)
+			case "n_packets":
+				flowCounters.Packets, err = strconv.ParseUint(value, 10, 64)
+			case "n_bytes":
+				flowCounters.Bytes, err = strconv.ParseUint(value, 10, 64)
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing flow %q: %w", field, err)
+			}
+		}
+
+		if cookie == 0 {
+			continue
+		}
+
+		existing := counters[cookie]
+		existing.Packets += flowCounters.Packets
+		existing.Bytes += flowCounters.Bytes
+		counters[cookie] = existing
+	}
+
+	return counters, nil
+}
+
 // HardwareOffloadingEnabled returns true if hardware offloading is enabled.
 func (o *OVS) HardwareOffloadingEnabled() bool {
 	// ovs-vsctl's get command doesn't support its --format flag, so we always get the output quoted.
@@ -1,22 +1,28 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/canonical/lxd/lxd/auth"
 	clusterRequest "github.com/canonical/lxd/lxd/cluster/request"
+	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/network"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/version"
 )
 
@@ -36,6 +42,12 @@ var networkLoadBalancerCmd = APIEndpoint{
 	Patch:  APIEndpointAction{Handler: networkLoadBalancerPut, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
 }
 
+var networkLoadBalancerStateCmd = APIEndpoint{
+	Path: "networks/{networkName}/load-balancers/{listenAddress}/state",
+
+	Get: APIEndpointAction{Handler: networkLoadBalancerStateGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
+}
+
 // API endpoints
 
 // swagger:operation GET /1.0/networks/{networkName}/load-balancers network-load-balancers network_load_balancers_get
@@ -591,3 +603,176 @@ func networkLoadBalancerPut(d *Daemon, r *http.Request) response.Response {
 
 	return response.EmptySyncResponse
 }
+
+// swagger:operation GET /1.0/networks/{networkName}/load-balancers/{listenAddress}/state network-load-balancers network_load_balancer_state_get
+//
+//	Get the network address load balancer state
+//
+//	Gets the health of the network address load balancer backends.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	responses:
+//	  "200":
+//	    description: Load Balancer state
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NetworkLoadBalancerState"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkLoadBalancerStateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	projectName, reqProject, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	networkName, err := url.PathUnescape(mux.Vars(r)["networkName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	n, err := network.LoadByName(s, projectName, networkName)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading network: %w", err))
+	}
+
+	// Check if project allows access to network.
+	if !project.NetworkAllowed(reqProject.Config, networkName, n.IsManaged()) {
+		return response.SmartError(api.StatusErrorf(http.StatusNotFound, "Network not found"))
+	}
+
+	if !n.Info().LoadBalancers {
+		return response.BadRequest(fmt.Errorf("Network driver %q does not support load balancers", n.Type()))
+	}
+
+	listenAddress, err := url.PathUnescape(mux.Vars(r)["listenAddress"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	targetMember := request.QueryParam(r, "target")
+	memberSpecific := targetMember != ""
+
+	_, loadBalancer, err := s.DB.Cluster.GetNetworkLoadBalancer(r.Context(), n.ID(), memberSpecific, listenAddress)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// The backend health of member specific load balancers is only known by the member running the health checks.
+	// OVN load balancers are checked by OVN itself, so their state can be retrieved from any member.
+	if loadBalancer.Location != "" {
+		resp := forwardedResponseToNode(s, r, loadBalancer.Location)
+		if resp != nil {
+			return resp
+		}
+	}
+
+	loadBalancerState, err := n.LoadBalancerState(*loadBalancer)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed fetching load balancer state: %w", err))
+	}
+
+	return response.SyncResponse(true, loadBalancerState)
+}
+
+// networkLoadBalancerHealthCheckTask runs the health checks of the network load balancer backends.
+// Only the networks that have load balancers with health checks on this member (or, on the cluster leader, load
+// balancers that aren't member specific) are loaded. The health checks of each load balancer are then run
+// according to its healthcheck.interval setting.
+func networkLoadBalancerHealthCheckTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		var networks map[string][]string
+		var clusterNetworks map[string][]string
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+			networks, err = tx.GetNetworksWithLoadBalancerHealthChecks(ctx, true)
+			if err != nil {
+				return err
+			}
+
+			clusterNetworks, err = tx.GetNetworksWithLoadBalancerHealthChecks(ctx, false)
+			return err
+		})
+		if err != nil {
+			logger.Warn("Failed loading networks for network load balancer health checks", logger.Ctx{"err": err})
+			return
+		}
+
+		// Health checks of load balancers that aren't member specific are only run by the cluster leader.
+		leader := true
+		if s.ServerClustered && len(clusterNetworks) > 0 {
+			leaderAddress, err := d.gateway.LeaderAddress()
+			if err != nil {
+				logger.Warn("Failed to get leader cluster member address", logger.Ctx{"err": err})
+				return
+			}
+
+			leader = leaderAddress == s.LocalConfig.ClusterAddress()
+		}
+
+		if leader {
+			for projectName, networkNames := range clusterNetworks {
+				for _, networkName := range networkNames {
+					if !shared.ValueInSlice(networkName, networks[projectName]) {
+						networks[projectName] = append(networks[projectName], networkName)
+					}
+				}
+			}
+		}
+
+		for projectName, networkNames := range networks {
+			for _, networkName := range networkNames {
+				n, err := network.LoadByName(s, projectName, networkName)
+				if err != nil {
+					logger.Warn("Failed loading network for network load balancer health checks", logger.Ctx{"project": projectName, "network": networkName, "err": err})
+					continue
+				}
+
+				err = n.LoadBalancerHealthCheck(ctx, leader)
+				if err != nil {
+					logger.Warn("Failed running network load balancer health checks", logger.Ctx{"project": projectName, "network": networkName, "err": err})
+				}
+			}
+		}
+	}
+
+	return f, task.Every(10 * time.Second)
+}
@@ -52,6 +52,36 @@ func networkUpdateForkdnsServersTask(s *state.State, heartbeatData *cluster.APIH
 	return nil
 }
 
+// networkUpdateWireguardPeersTask refreshes the peers of the wireguard networks from the heartbeat members.
+// It is run on every heartbeat so that public keys published by new members are picked up.
+func networkUpdateWireguardPeersTask(s *state.State, heartbeatData *cluster.APIHeartbeat) error {
+	// Use api.ProjectDefaultName here as wireguard networks don't support projects.
+	projectName := api.ProjectDefaultName
+
+	// Get a list of managed networks
+	networks, err := s.DB.Cluster.GetCreatedNetworks(projectName)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range networks {
+		n, err := network.LoadByName(s, projectName, name)
+		if err != nil {
+			logger.Errorf("Failed to load network %q from project %q for heartbeat", name, projectName)
+			continue
+		}
+
+		if n.Type() == "wireguard" {
+			err := n.HandleHeartbeat(heartbeatData)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // networkUpdateOVNChassis gets called on heartbeats to check if OVN needs reconfiguring.
 func networkUpdateOVNChassis(s *state.State, heartbeatData *cluster.APIHeartbeat, localAddress string) error {
 	// Check if we have at least one active OVN chassis.
@@ -12,6 +12,7 @@ import (
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/db/operationtype"
 	"github.com/canonical/lxd/lxd/events"
+	"github.com/canonical/lxd/lxd/metrics"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
@@ -233,6 +234,7 @@ func (op *Operation) done() {
 	op.onCancel = nil
 	op.onConnect = nil
 	op.finished.Cancel()
+	metrics.OperationDuration.Observe(map[string]string{"type": op.dbOpType.Description(), "status": op.status.String()}, time.Since(op.createdAt).Seconds())
 	op.lock.Unlock()
 
 	go func() {
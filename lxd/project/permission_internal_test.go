@@ -4,8 +4,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/idmap"
+	"github.com/canonical/lxd/shared/api"
 )
 
 func TestParseHostIDMapRange(t *testing.T) {
@@ -75,3 +78,78 @@ func TestParseHostIDMapRange(t *testing.T) {
 		assert.Equal(t, idmaps, expected)
 	}
 }
+
+func TestGetInstanceLimits_DeviceLimits(t *testing.T) {
+	inst := api.Instance{
+		Name:    "c1",
+		Project: "p1",
+		Type:    "container",
+		InstancePut: api.InstancePut{
+			Devices: map[string]map[string]string{
+				"root": {"type": "disk", "path": "/", "pool": "default", "limits.read": "10MB", "limits.write": "100iops"},
+				"data": {"type": "disk", "path": "/data", "pool": "default", "limits.max": "20MB"},
+				"eth0": {"type": "nic", "network": "lxdbr0", "limits.ingress": "10Mbit", "limits.egress": "5Mbit"},
+				"eth1": {"type": "nic", "network": "lxdbr0", "limits.max": "1Gbit"},
+				"eth2": {"type": "nic", "nictype": "physical", "parent": "eth0"},
+				"eth3": {"type": "nic", "network": "sriov0"},
+			},
+		},
+	}
+
+	networkTypes := map[string]string{"lxdbr0": "bridge", "sriov0": "sriov"}
+
+	limits, err := getInstanceLimits(inst, []string{"limits.network.ingress", "limits.network.egress", "limits.disk.read"}, false, networkTypes)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		"limits.network.ingress": 1010000000,
+		"limits.network.egress":  1005000000,
+		"limits.disk.read":       30000000,
+	}, limits)
+
+	// The "data" disk has a byte rate write limit, so it can't count towards the IOPS limit.
+	_, err = getInstanceLimits(inst, []string{"limits.disk.iops.write"}, false, networkTypes)
+	assert.ErrorContains(t, err, `Failed parsing "limits.write" of device "data"`)
+
+	// When skipping unset values, only the devices with valid limits are counted.
+	limits, err = getInstanceLimits(inst, []string{"limits.disk.iops.write"}, true, networkTypes)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"limits.disk.iops.write": 100}, limits)
+
+	// Devices without a limit are rejected.
+	delete(inst.Devices["eth1"], "limits.max")
+	_, err = getInstanceLimits(inst, []string{"limits.network.egress"}, false, networkTypes)
+	assert.ErrorContains(t, err, `Device "eth1" of instance "c1" in project "p1" has no "limits.egress" config`)
+}
+
+func TestGetTotalsAcrossProjectEntities_Buckets(t *testing.T) {
+	info := &projectInfo{
+		Project: api.Project{Name: "p1"},
+		Volumes: []db.StorageVolumeArgs{
+			{Name: "vol1", Config: map[string]string{"size": "1GiB"}},
+		},
+		Buckets: []db.StorageBucket{
+			{StorageBucket: api.StorageBucket{Name: "bucket1", StorageBucketPut: api.StorageBucketPut{Config: map[string]string{"size": "2GiB"}}}},
+			{StorageBucket: api.StorageBucket{Name: "bucket2", StorageBucketPut: api.StorageBucketPut{Config: map[string]string{}}}},
+		},
+	}
+
+	// Buckets without a size are refused unless unset values are skipped, the same as custom volumes.
+	_, err := getTotalsAcrossProjectEntities(info, []string{"limits.disk"}, false)
+	assert.EqualError(t, err, `Storage bucket "bucket2" in project "p1" has no "size" config set`)
+
+	totals, err := getTotalsAcrossProjectEntities(info, []string{"limits.disk"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3*1024*1024*1024), totals["limits.disk"])
+
+	info.Buckets[1].Config["size"] = "1GiB"
+	totals, err = getTotalsAcrossProjectEntities(info, []string{"limits.disk"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4*1024*1024*1024), totals["limits.disk"])
+
+	// Check the storage bucket count limit.
+	info.Project.Config = map[string]string{"limits.storage-buckets": "3"}
+	assert.NoError(t, checkStorageBucketCountLimit(info))
+
+	info.Project.Config["limits.storage-buckets"] = "2"
+	assert.EqualError(t, checkStorageBucketCountLimit(info), `Reached maximum number of storage buckets in project "p1"`)
+}
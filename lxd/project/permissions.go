@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -254,6 +255,90 @@ func AllowVolumeCreation(tx *db.ClusterTx, projectName string, req api.StorageVo
 	return nil
 }
 
+// AllowBucketCreation returns an error if any project-specific limit or
+// restriction is violated when creating a new storage bucket in a project.
+func AllowBucketCreation(tx *db.ClusterTx, projectName string, req api.StorageBucketsPost) error {
+	info, err := fetchProject(tx, projectName, true)
+	if err != nil {
+		return err
+	}
+
+	if info == nil {
+		return nil
+	}
+
+	// Check the storage buckets count limit.
+	err = checkStorageBucketCountLimit(info)
+	if err != nil {
+		return err
+	}
+
+	// If "limits.disk" is not set, there's nothing else to do.
+	if info.Project.Config["limits.disk"] == "" {
+		return nil
+	}
+
+	// Add the bucket being created.
+	info.Buckets = append(info.Buckets, db.StorageBucket{
+		StorageBucket: api.StorageBucket{
+			Name:             req.Name,
+			StorageBucketPut: req.StorageBucketPut,
+		},
+		Project: projectName,
+	})
+
+	err = checkRestrictionsAndAggregateLimits(tx, info)
+	if err != nil {
+		return fmt.Errorf("Failed checking if storage bucket creation allowed: %w", err)
+	}
+
+	return nil
+}
+
+// Check that we have not reached the maximum number of storage buckets for this project.
+func checkStorageBucketCountLimit(info *projectInfo) error {
+	value, ok := info.Project.Config["limits.storage-buckets"]
+	if !ok || value == "" {
+		return nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("Unexpected limits.storage-buckets value %q: %w", value, err)
+	}
+
+	if len(info.Buckets) >= limit {
+		return fmt.Errorf("Reached maximum number of storage buckets in project %q", info.Project.Name)
+	}
+
+	return nil
+}
+
+// AllowVolumeSnapshotCreation returns an error if any project-specific limit is
+// violated when creating count new custom volume snapshots in a project.
+func AllowVolumeSnapshotCreation(tx *db.ClusterTx, p *api.Project, count int) error {
+	value, ok := p.Config["limits.snapshots"]
+	if !ok || value == "" {
+		return nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("Unexpected limits.snapshots value %q: %w", value, err)
+	}
+
+	current, err := tx.GetCustomVolumeSnapshotsCount(context.Background(), p.Name)
+	if err != nil {
+		return err
+	}
+
+	if current+count > limit {
+		return fmt.Errorf("Reached maximum number of custom volume snapshots in project %q", p.Name)
+	}
+
+	return nil
+}
+
 // GetImageSpaceBudget returns how much disk space is left in the given project
 // for writing images.
 //
@@ -778,10 +863,52 @@ func CheckRestrictedDevicesDiskPaths(projectConfig map[string]string, sourcePath
 var allAggregateLimits = []string{
 	"limits.cpu",
 	"limits.disk",
+	"limits.disk.iops.read",
+	"limits.disk.iops.write",
+	"limits.disk.read",
+	"limits.disk.write",
 	"limits.memory",
+	"limits.network.egress",
+	"limits.network.ingress",
 	"limits.processes",
 }
 
+// aggregateDeviceLimit describes the instance device option that an aggregate project limit is derived from.
+type aggregateDeviceLimit struct {
+	deviceType string
+	deviceKey  string
+	parser     func(value string) (int64, error)
+}
+
+// aggregateDeviceLimits maps the aggregate limits that are computed from instance device options (rather than
+// instance options) to the device option they are derived from.
+var aggregateDeviceLimits = map[string]aggregateDeviceLimit{
+	"limits.disk.iops.read":  {deviceType: "disk", deviceKey: "limits.read", parser: parseDeviceDiskIOPSLimit},
+	"limits.disk.iops.write": {deviceType: "disk", deviceKey: "limits.write", parser: parseDeviceDiskIOPSLimit},
+	"limits.disk.read":       {deviceType: "disk", deviceKey: "limits.read", parser: parseDeviceDiskBytesLimit},
+	"limits.disk.write":      {deviceType: "disk", deviceKey: "limits.write", parser: parseDeviceDiskBytesLimit},
+	"limits.network.egress":  {deviceType: "nic", deviceKey: "limits.egress", parser: units.ParseBitSizeString},
+	"limits.network.ingress": {deviceType: "nic", deviceKey: "limits.ingress", parser: units.ParseBitSizeString},
+}
+
+// parseDeviceDiskBytesLimit parses a disk device limits.read or limits.write value expressed in bytes/s.
+func parseDeviceDiskBytesLimit(value string) (int64, error) {
+	if strings.HasSuffix(value, "iops") {
+		return -1, fmt.Errorf("Value must be a byte rate, not an IOPS rate")
+	}
+
+	return units.ParseByteSizeString(value)
+}
+
+// parseDeviceDiskIOPSLimit parses a disk device limits.read or limits.write value expressed in IOPS.
+func parseDeviceDiskIOPSLimit(value string) (int64, error) {
+	if !strings.HasSuffix(value, "iops") {
+		return -1, fmt.Errorf("Value must be an IOPS rate")
+	}
+
+	return strconv.ParseInt(strings.TrimSuffix(value, "iops"), 10, 64)
+}
+
 // allRestrictions lists all available 'restrict.*' config keys along with their default setting.
 var allRestrictions = map[string]string{
 	"restricted.backups":                   "block",
@@ -933,6 +1060,40 @@ func AllowVolumeUpdate(tx *db.ClusterTx, projectName, volumeName string, req api
 	return nil
 }
 
+// AllowBucketUpdate returns an error if any project-specific limit or
+// restriction is violated when updating an existing storage bucket.
+func AllowBucketUpdate(tx *db.ClusterTx, projectName string, poolName string, bucketName string, req api.StorageBucketPut) error {
+	info, err := fetchProject(tx, projectName, true)
+	if err != nil {
+		return err
+	}
+
+	if info == nil {
+		return nil
+	}
+
+	// If "limits.disk" is not set, there's nothing to do.
+	if info.Project.Config["limits.disk"] == "" {
+		return nil
+	}
+
+	// Change the bucket being updated.
+	for i, bucket := range info.Buckets {
+		if bucket.Name != bucketName || bucket.PoolName != poolName {
+			continue
+		}
+
+		info.Buckets[i].Config = req.Config
+	}
+
+	err = checkRestrictionsAndAggregateLimits(tx, info)
+	if err != nil {
+		return fmt.Errorf("Failed checking if storage bucket update allowed: %w", err)
+	}
+
+	return nil
+}
+
 // AllowProfileUpdate checks that project limits and restrictions are not
 // violated when changing a profile.
 func AllowProfileUpdate(tx *db.ClusterTx, projectName, profileName string, req api.ProfilePut) error {
@@ -1011,6 +1172,23 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 				return fmt.Errorf("Can't change %q in project %q: %w", key, projectName, err)
 			}
 
+		case "limits.storage-buckets":
+			err := validateCountLimit(len(info.Buckets), key, config[key], "storage buckets", projectName)
+			if err != nil {
+				return fmt.Errorf("Can't change %q in project %q: %w", key, projectName, err)
+			}
+
+		case "limits.snapshots":
+			count, err := tx.GetCustomVolumeSnapshotsCount(context.Background(), projectName)
+			if err != nil {
+				return err
+			}
+
+			err = validateCountLimit(count, key, config[key], "custom volume snapshots", projectName)
+			if err != nil {
+				return fmt.Errorf("Can't change %q in project %q: %w", key, projectName, err)
+			}
+
 		case "limits.processes":
 			fallthrough
 		case "limits.cpu":
@@ -1019,6 +1197,11 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 			fallthrough
 		case "limits.disk":
 			aggregateKeys = append(aggregateKeys, key)
+		default:
+			_, isDeviceLimit := aggregateDeviceLimits[key]
+			if isDeviceLimit {
+				aggregateKeys = append(aggregateKeys, key)
+			}
 		}
 	}
 
@@ -1092,6 +1275,24 @@ func validateInstanceCountLimit(instances []api.Instance, key, value, project st
 	return nil
 }
 
+// Check that a count based limit (such as limits.storage-buckets) is equal or above the current count.
+func validateCountLimit(count int, key, value, entityType, project string) error {
+	if value == "" {
+		return nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+
+	if limit < count {
+		return fmt.Errorf(`%q is too low: there currently are %d %s in project %q`, key, count, entityType, project)
+	}
+
+	return nil
+}
+
 var countConfigInstanceType = map[string]api.InstanceType{
 	"limits.containers":       api.InstanceTypeContainer,
 	"limits.virtual-machines": api.InstanceTypeVM,
@@ -1141,10 +1342,14 @@ type projectInfo struct {
 	Profiles  []api.Profile
 	Instances []api.Instance
 	Volumes   []db.StorageVolumeArgs
+	Buckets   []db.StorageBucket
+
+	// NetworkTypes maps the names of the networks available to the project to their type.
+	NetworkTypes map[string]string
 }
 
-// Fetch the given project from the database along with its profiles, instances
-// and possibly custom volumes.
+// Fetch the given project from the database along with its profiles, instances,
+// custom volumes and storage buckets.
 //
 // If the skipIfNoLimits flag is true, then profiles, instances and volumes
 // won't be loaded if the profile has no limits set on it, and nil will be
@@ -1212,11 +1417,33 @@ func fetchProject(tx *db.ClusterTx, projectName string, skipIfNoLimits bool) (*p
 		return nil, fmt.Errorf("Fetch project custom volumes from database: %w", err)
 	}
 
+	dbBuckets, err := tx.GetStoragePoolBuckets(ctx, false, db.StorageBucketFilter{Project: &projectName})
+	if err != nil {
+		return nil, fmt.Errorf("Fetch project storage buckets from database: %w", err)
+	}
+
+	buckets := make([]db.StorageBucket, 0, len(dbBuckets))
+	for _, bucket := range dbBuckets {
+		buckets = append(buckets, *bucket)
+	}
+
+	networks, err := tx.GetCreatedNetworksByProject(ctx, NetworkProjectFromRecord(project))
+	if err != nil {
+		return nil, fmt.Errorf("Fetch project networks from database: %w", err)
+	}
+
+	networkTypes := make(map[string]string, len(networks))
+	for _, network := range networks {
+		networkTypes[network.Name] = network.Type
+	}
+
 	info := &projectInfo{
-		Project:   *project,
-		Profiles:  profiles,
-		Instances: instances,
-		Volumes:   volumes,
+		Project:      *project,
+		Profiles:     profiles,
+		Instances:    instances,
+		Volumes:      volumes,
+		Buckets:      buckets,
+		NetworkTypes: networkTypes,
 	}
 
 	return info, nil
@@ -1250,7 +1477,7 @@ func expandInstancesConfigAndDevices(instances []api.Instance, profiles []api.Pr
 }
 
 // Sum of the effective values for the given limits across all project
-// entities (instances and custom volumes).
+// entities (instances, custom volumes and storage buckets).
 func getTotalsAcrossProjectEntities(info *projectInfo, keys []string, skipUnset bool) (map[string]int64, error) {
 	totals := map[string]int64{}
 
@@ -1274,11 +1501,29 @@ func getTotalsAcrossProjectEntities(info *projectInfo, keys []string, skipUnset
 
 				totals[key] += limit
 			}
+
+			for _, bucket := range info.Buckets {
+				value, ok := bucket.Config["size"]
+				if !ok || value == "" {
+					if skipUnset {
+						continue
+					}
+
+					return nil, fmt.Errorf(`Storage bucket %q in project %q has no "size" config set`, bucket.Name, info.Project.Name)
+				}
+
+				limit, err := units.ParseByteSizeString(value)
+				if err != nil {
+					return nil, fmt.Errorf(`Parse "size" for storage bucket %q in project %q: %w`, bucket.Name, info.Project.Name, err)
+				}
+
+				totals[key] += limit
+			}
 		}
 	}
 
 	for _, instance := range info.Instances {
-		limits, err := getInstanceLimits(instance, keys, skipUnset)
+		limits, err := getInstanceLimits(instance, keys, skipUnset, info.NetworkTypes)
 		if err != nil {
 			return nil, err
 		}
@@ -1292,7 +1537,8 @@ func getTotalsAcrossProjectEntities(info *projectInfo, keys []string, skipUnset
 }
 
 // Return the effective instance-level values for the limits with the given keys.
-func getInstanceLimits(instance api.Instance, keys []string, skipUnset bool) (map[string]int64, error) {
+// The networkTypes map is used to find the type of the NIC devices connected to a managed network.
+func getInstanceLimits(instance api.Instance, keys []string, skipUnset bool, networkTypes map[string]string) (map[string]int64, error) {
 	var err error
 	limits := map[string]int64{}
 
@@ -1347,6 +1593,11 @@ func getInstanceLimits(instance api.Instance, keys []string, skipUnset bool) (ma
 
 				limit += sizeStateLimit
 			}
+		} else if deviceLimit, isDeviceLimit := aggregateDeviceLimits[key]; isDeviceLimit {
+			limit, err = getInstanceDevicesLimit(instance, deviceLimit, skipUnset, networkTypes)
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			value, ok := instance.Config[key]
 			if !ok || value == "" {
@@ -1373,6 +1624,78 @@ func getInstanceLimits(instance api.Instance, keys []string, skipUnset bool) (ma
 	return limits, nil
 }
 
+// Return the sum of the device-level limit values across all of the instance's devices the limit applies to.
+// The "limits.max" device option is used when the specific device option isn't set.
+func getInstanceDevicesLimit(instance api.Instance, deviceLimit aggregateDeviceLimit, skipUnset bool, networkTypes map[string]string) (int64, error) {
+	var total int64
+
+	devNames := make([]string, 0, len(instance.Devices))
+	for devName := range instance.Devices {
+		devNames = append(devNames, devName)
+	}
+
+	sort.Strings(devNames)
+
+	for _, devName := range devNames {
+		device := instance.Devices[devName]
+		if device["type"] != deviceLimit.deviceType {
+			continue
+		}
+
+		// The cloud-init config drive isn't subject to I/O limits.
+		if device["type"] == "disk" && device["source"] == "cloud-init:config" {
+			continue
+		}
+
+		// Only some NIC types support bandwidth limits.
+		if device["type"] == "nic" && !nicSupportsLimits(device, networkTypes) {
+			continue
+		}
+
+		value := device[deviceLimit.deviceKey]
+		if value == "" {
+			value = device["limits.max"]
+		}
+
+		if value == "" {
+			if skipUnset {
+				continue
+			}
+
+			return -1, fmt.Errorf("Device %q of instance %q in project %q has no %q config, either directly or via a profile", devName, instance.Name, instance.Project, deviceLimit.deviceKey)
+		}
+
+		limit, err := deviceLimit.parser(value)
+		if err != nil {
+			if skipUnset {
+				continue
+			}
+
+			return -1, fmt.Errorf("Failed parsing %q of device %q for instance %q in project %q: %w", deviceLimit.deviceKey, devName, instance.Name, instance.Project, err)
+		}
+
+		total += limit
+	}
+
+	return total, nil
+}
+
+// nicSupportsLimits returns whether the NIC device supports the limits.ingress, limits.egress and limits.max
+// options. The NIC type is resolved from the type of the network when the device is connected to a managed network.
+func nicSupportsLimits(device map[string]string, networkTypes map[string]string) bool {
+	nicType := device["nictype"]
+	if device["network"] != "" {
+		switch networkTypes[device["network"]] {
+		case "bridge", "wireguard":
+			nicType = "bridged"
+		default:
+			nicType = networkTypes[device["network"]]
+		}
+	}
+
+	return shared.ValueInSlice(nicType, []string{"bridged", "p2p", "routed"})
+}
+
 var aggregateLimitConfigValueParsers = map[string]func(string) (int64, error){
 	"limits.memory": func(value string) (int64, error) {
 		if strings.HasSuffix(value, "%") {
@@ -1404,6 +1727,24 @@ var aggregateLimitConfigValueParsers = map[string]func(string) (int64, error){
 	"limits.disk": func(value string) (int64, error) {
 		return units.ParseByteSizeString(value)
 	},
+	"limits.disk.read": func(value string) (int64, error) {
+		return units.ParseByteSizeString(value)
+	},
+	"limits.disk.write": func(value string) (int64, error) {
+		return units.ParseByteSizeString(value)
+	},
+	"limits.disk.iops.read": func(value string) (int64, error) {
+		return strconv.ParseInt(value, 10, 64)
+	},
+	"limits.disk.iops.write": func(value string) (int64, error) {
+		return strconv.ParseInt(value, 10, 64)
+	},
+	"limits.network.ingress": func(value string) (int64, error) {
+		return units.ParseBitSizeString(value)
+	},
+	"limits.network.egress": func(value string) (int64, error) {
+		return units.ParseBitSizeString(value)
+	},
 }
 
 var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
@@ -1419,6 +1760,24 @@ var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
 	"limits.disk": func(limit int64) string {
 		return units.GetByteSizeStringIEC(limit, 1)
 	},
+	"limits.disk.read": func(limit int64) string {
+		return units.GetByteSizeStringIEC(limit, 1)
+	},
+	"limits.disk.write": func(limit int64) string {
+		return units.GetByteSizeStringIEC(limit, 1)
+	},
+	"limits.disk.iops.read": func(limit int64) string {
+		return fmt.Sprintf("%d", limit)
+	},
+	"limits.disk.iops.write": func(limit int64) string {
+		return fmt.Sprintf("%d", limit)
+	},
+	"limits.network.ingress": func(limit int64) string {
+		return fmt.Sprintf("%dbit", limit)
+	},
+	"limits.network.egress": func(limit int64) string {
+		return fmt.Sprintf("%dbit", limit)
+	},
 }
 
 // FilterUsedBy filters a UsedBy list based on project access.
@@ -37,8 +37,14 @@ func GetCurrentAllocations(ctx context.Context, tx *db.ClusterTx, projectName st
 
 	result["cpu"] = raw["limits.cpu"]
 	result["disk"] = raw["limits.disk"]
+	result["disk.read"] = raw["limits.disk.read"]
+	result["disk.write"] = raw["limits.disk.write"]
+	result["disk.iops.read"] = raw["limits.disk.iops.read"]
+	result["disk.iops.write"] = raw["limits.disk.iops.write"]
 	result["memory"] = raw["limits.memory"]
 	result["networks"] = raw["limits.networks"]
+	result["network.ingress"] = raw["limits.network.ingress"]
+	result["network.egress"] = raw["limits.network.egress"]
 	result["processes"] = raw["limits.processes"]
 
 	// Get the instance count values.
@@ -92,5 +98,40 @@ func GetCurrentAllocations(ctx context.Context, tx *db.ClusterTx, projectName st
 		Usage: int64(len(networks[projectName])),
 	}
 
+	// Get the storage bucket limit and usage.
+	overallValue, ok = info.Project.Config["limits.storage-buckets"]
+	limit = -1
+	if ok {
+		limit, err = strconv.Atoi(overallValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result["storage-buckets"] = api.ProjectStateResource{
+		Limit: int64(limit),
+		Usage: int64(len(info.Buckets)),
+	}
+
+	// Get the custom volume snapshot limit and usage.
+	overallValue, ok = info.Project.Config["limits.snapshots"]
+	limit = -1
+	if ok {
+		limit, err = strconv.Atoi(overallValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	snapshots, err := tx.GetCustomVolumeSnapshotsCount(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	result["snapshots"] = api.ProjectStateResource{
+		Limit: int64(limit),
+		Usage: int64(snapshots),
+	}
+
 	return result, nil
 }
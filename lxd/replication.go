@@ -0,0 +1,682 @@
+package main
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/osarch"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// replicaInstanceSuffix is appended to the name of an instance to get the name of its replica.
+const replicaInstanceSuffix = "-replica"
+
+// replicationLastSyncKey is the volume config key recording the start of the last successful replication.
+const replicationLastSyncKey = "volatile.replication.last_sync"
+
+// replicationSourceKey is the replica config key recording its source, so that unrelated instances and volumes
+// that happen to have the replica name are never overwritten.
+const replicationSourceKey = "volatile.replication.source"
+
+// replicaExcludedKeys are the config keys that aren't copied to replicas, so that replicas stay passive.
+var replicaExcludedKeys = []string{"backups.schedule", "boot.start_schedule", "snapshots.schedule"}
+
+// replicationParseTarget splits a replication.target value into the cluster member and storage pool names.
+// The member name is empty if the replica is to be stored on the local cluster member.
+func replicationParseTarget(value string) (string, string) {
+	member, pool, found := strings.Cut(value, "/")
+	if !found {
+		return "", member
+	}
+
+	return member, pool
+}
+
+// replicaConfig returns the config to apply to a replica based on the config of its source.
+func replicaConfig(config map[string]string) map[string]string {
+	newConfig := make(map[string]string, len(config))
+	for key, value := range config {
+		if strings.HasPrefix(key, "replication.") || key == replicationLastSyncKey || key == replicationSourceKey || shared.ValueInSlice(key, replicaExcludedKeys) {
+			continue
+		}
+
+		newConfig[key] = value
+	}
+
+	return newConfig
+}
+
+// replicationLag returns the time elapsed since the last successful replication recorded in a volume config.
+// Returns false if the volume has never been replicated.
+func replicationLag(config map[string]string, now time.Time) (time.Time, time.Duration, bool) {
+	lastSync, err := time.Parse(time.RFC3339, config[replicationLastSyncKey])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	return lastSync, now.Sub(lastSync), true
+}
+
+// replicationSetLastSync records the start of the last successful replication in the config of a volume.
+func replicationSetLastSync(s *state.State, projectName string, poolName string, volumeName string, volumeType int, lastSync time.Time) error {
+	var poolID int64
+	var dbVol *db.StorageVolume
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		poolID, err = tx.GetStoragePoolID(ctx, poolName)
+		if err != nil {
+			return err
+		}
+
+		dbVol, err = tx.GetStoragePoolVolume(ctx, poolID, projectName, volumeType, volumeName, true)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	dbVol.Config[replicationLastSyncKey] = lastSync.UTC().Format(time.RFC3339)
+
+	return s.DB.Cluster.UpdateStoragePoolVolume(projectName, volumeName, volumeType, poolID, dbVol.Description, dbVol.Config)
+}
+
+// replicationCheckSource returns an error if an existing instance or volume with the replica name isn't a replica
+// of the given source.
+func replicationCheckSource(kind string, name string, config map[string]string, source string) error {
+	if config[replicationSourceKey] != source {
+		return api.StatusErrorf(http.StatusConflict, "%s %q already exists and isn't a replica of %q", kind, name, source)
+	}
+
+	return nil
+}
+
+// replicationTargetAddress returns the address of the cluster member to store the replica on.
+// Returns an empty address if the replica is to be stored on the local cluster member.
+func replicationTargetAddress(s *state.State, member string) (string, error) {
+	if member == "" || member == s.ServerName {
+		return "", nil
+	}
+
+	if !s.ServerClustered {
+		return "", fmt.Errorf("Cluster member %q can only be used as replication target in a cluster", member)
+	}
+
+	var address string
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		node, err := tx.GetNodeByName(ctx, member)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster member %q: %w", member, err)
+		}
+
+		if node.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			return fmt.Errorf("Cluster member %q is offline", member)
+		}
+
+		address = node.Address
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return address, nil
+}
+
+// replicationRemoteConnect connects to the project of the LXD server set in replication.remote, targeting the given
+// member of that server if not empty. The server must be allowed by replication.allowed_remote_urls and present the
+// certificate set in replication.remote_fingerprint, which is returned along with the client.
+func replicationRemoteConnect(s *state.State, config map[string]string, projectName string, member string) (lxd.InstanceServer, string, error) {
+	remote := config["replication.remote"]
+
+	u, err := url.Parse(remote)
+	if err != nil {
+		return nil, "", fmt.Errorf("Invalid replication remote %q: %w", remote, err)
+	}
+
+	if u.Scheme != "https" || (u.Path != "" && u.Path != "/") {
+		return nil, "", fmt.Errorf("Replication remote %q must be an https URL without path", remote)
+	}
+
+	if !remoteURLAllowed(u, s.GlobalConfig.ReplicationAllowedRemoteURLs()) {
+		return nil, "", fmt.Errorf("Replication remote %q isn't allowed by the server configuration", remote)
+	}
+
+	fingerprint := config["replication.remote_fingerprint"]
+	if fingerprint == "" {
+		return nil, "", fmt.Errorf("Replication remote %q requires replication.remote_fingerprint to be set", remote)
+	}
+
+	remoteURL := "https://" + u.Host
+
+	cert, err := shared.GetRemoteCertificate(remoteURL, version.UserAgent)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed getting certificate of replication remote %q: %w", remote, err)
+	}
+
+	if !strings.EqualFold(shared.CertFingerprint(cert), fingerprint) {
+		return nil, "", fmt.Errorf("Certificate of replication remote %q doesn't match replication.remote_fingerprint", remote)
+	}
+
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	networkCert := s.Endpoints.NetworkCert()
+
+	client, err := lxd.ConnectLXD(remoteURL, &lxd.ConnectionArgs{
+		TLSServerCert: certificate,
+		TLSClientCert: string(networkCert.PublicKey()),
+		TLSClientKey:  string(networkCert.PrivateKey()),
+		UserAgent:     version.UserAgent,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed connecting to replication remote %q: %w", remote, err)
+	}
+
+	client = client.UseProject(projectName)
+	if member != "" {
+		client = client.UseTarget(member)
+	}
+
+	return client, certificate, nil
+}
+
+// replicationRemoteOperation returns the URL and websocket secrets of the operation created on a remote server to
+// receive a replica in push mode.
+func replicationRemoteOperation(client lxd.InstanceServer, remoteOp lxd.Operation) (string, map[string]string, error) {
+	info, err := client.GetConnectionInfo()
+	if err != nil {
+		return "", nil, err
+	}
+
+	opAPI := remoteOp.Get()
+
+	secrets := make(map[string]string, len(opAPI.Metadata))
+	for key, value := range opAPI.Metadata {
+		secret, ok := value.(string)
+		if ok {
+			secrets[key] = secret
+		}
+	}
+
+	return fmt.Sprintf("%s/1.0/operations/%s", info.URL, url.PathEscape(opAPI.ID)), secrets, nil
+}
+
+// replicateCustomVolume refreshes the replica of a custom volume, creating it if needed.
+func replicateCustomVolume(s *state.State, op *operations.Operation, v db.StorageVolumeArgs) error {
+	member, targetPoolName := replicationParseTarget(v.Config["replication.target"])
+	remote := v.Config["replication.remote"] != ""
+	if !remote && targetPoolName == v.PoolName {
+		return fmt.Errorf("Replication target pool %q is the volume's own pool", targetPoolName)
+	}
+
+	pool, err := storagePools.LoadByName(s, v.PoolName)
+	if err != nil {
+		return fmt.Errorf("Failed loading storage pool %q: %w", v.PoolName, err)
+	}
+
+	dbVol, err := storagePools.VolumeDBGet(pool, v.ProjectName, v.Name, storageDrivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	syncStart := time.Now()
+	source := v.PoolName + "/" + v.Name
+	config := replicaConfig(dbVol.Config)
+	config[replicationSourceKey] = source
+
+	req := api.StorageVolumesPost{
+		Name:        v.Name,
+		Type:        db.StoragePoolVolumeTypeNameCustom,
+		ContentType: dbVol.ContentType,
+		StorageVolumePut: api.StorageVolumePut{
+			Config:      config,
+			Description: dbVol.Description,
+		},
+	}
+
+	if remote {
+		err = replicateCustomVolumeToRemote(s, op, v, member, targetPoolName, req)
+		if err != nil {
+			return err
+		}
+
+		return replicationSetLastSync(s, v.ProjectName, v.PoolName, v.Name, db.StoragePoolVolumeTypeCustom, syncStart)
+	}
+
+	address, err := replicationTargetAddress(s, member)
+	if err != nil {
+		return err
+	}
+
+	if address == "" {
+		targetPool, err := storagePools.LoadByName(s, targetPoolName)
+		if err != nil {
+			return fmt.Errorf("Failed loading storage pool %q: %w", targetPoolName, err)
+		}
+
+		replica, err := storagePools.VolumeDBGet(targetPool, v.ProjectName, v.Name, storageDrivers.VolumeTypeCustom)
+		if err != nil && !response.IsNotFoundError(err) {
+			return err
+		}
+
+		if err == nil {
+			err = replicationCheckSource("Custom volume", v.Name, replica.Config, source)
+			if err != nil {
+				return err
+			}
+
+			err = targetPool.RefreshCustomVolume(v.ProjectName, v.ProjectName, v.Name, dbVol.Description, config, v.PoolName, v.Name, true, op)
+		} else {
+			err = targetPool.CreateCustomVolumeFromCopy(v.ProjectName, v.ProjectName, v.Name, dbVol.Description, config, v.PoolName, v.Name, true, op)
+		}
+
+		if err != nil {
+			return err
+		}
+	} else {
+		// Let the target member pull the volume, in the same way as a refreshing copy between members.
+		client, err := cluster.Connect(address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, false)
+		if err != nil {
+			return err
+		}
+
+		client = client.UseProject(v.ProjectName).UseTarget(member)
+
+		replica, _, err := client.GetStoragePoolVolume(targetPoolName, db.StoragePoolVolumeTypeNameCustom, v.Name)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		if err == nil {
+			err = replicationCheckSource("Custom volume", v.Name, replica.Config, source)
+			if err != nil {
+				return err
+			}
+		}
+
+		req.Source = api.StorageVolumeSource{
+			Type:     "copy",
+			Name:     v.Name,
+			Pool:     v.PoolName,
+			Project:  v.ProjectName,
+			Location: s.ServerName,
+			Refresh:  true,
+		}
+
+		path := fmt.Sprintf("/storage-pools/%s/volumes/%s", url.PathEscape(targetPoolName), db.StoragePoolVolumeTypeNameCustom)
+		remoteOp, _, err := client.RawOperation("POST", path, req, "")
+		if err != nil {
+			return err
+		}
+
+		err = remoteOp.Wait()
+		if err != nil {
+			return err
+		}
+	}
+
+	return replicationSetLastSync(s, v.ProjectName, v.PoolName, v.Name, db.StoragePoolVolumeTypeCustom, syncStart)
+}
+
+// replicateCustomVolumeToRemote pushes the replica of a custom volume to the LXD server set in replication.remote,
+// in the same way as a refreshing copy between servers in push mode.
+func replicateCustomVolumeToRemote(s *state.State, op *operations.Operation, v db.StorageVolumeArgs, member string, targetPoolName string, req api.StorageVolumesPost) error {
+	client, certificate, err := replicationRemoteConnect(s, v.Config, v.ProjectName, member)
+	if err != nil {
+		return err
+	}
+
+	source := req.Config[replicationSourceKey]
+	replica, _, err := client.GetStoragePoolVolume(targetPoolName, db.StoragePoolVolumeTypeNameCustom, v.Name)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	if err == nil {
+		err = replicationCheckSource("Custom volume", v.Name, replica.Config, source)
+		if err != nil {
+			return err
+		}
+	}
+
+	req.Source = api.StorageVolumeSource{
+		Type:    "migration",
+		Mode:    "push",
+		Name:    v.Name,
+		Refresh: true,
+	}
+
+	path := fmt.Sprintf("/storage-pools/%s/volumes/%s", url.PathEscape(targetPoolName), db.StoragePoolVolumeTypeNameCustom)
+	remoteOp, _, err := client.RawOperation("POST", path, req, "")
+	if err != nil {
+		return err
+	}
+
+	operationURL, secrets, err := replicationRemoteOperation(client, remoteOp)
+	if err != nil {
+		_ = remoteOp.Cancel()
+		return err
+	}
+
+	migrationSource, err := newStorageMigrationSource(false, &api.StorageVolumePostTarget{
+		Operation:   operationURL,
+		Websockets:  secrets,
+		Certificate: certificate,
+	})
+	if err != nil {
+		_ = remoteOp.Cancel()
+		return err
+	}
+
+	err = migrationSource.DoStorage(s, v.ProjectName, v.PoolName, v.Name, op)
+	if err != nil {
+		_ = remoteOp.Cancel()
+		return fmt.Errorf("Failed pushing replica of custom volume %q to %q: %w", v.Name, v.Config["replication.remote"], err)
+	}
+
+	return remoteOp.Wait()
+}
+
+// replicateInstance refreshes the replica of an instance, creating it if needed.
+func replicateInstance(s *state.State, op *operations.Operation, inst instance.Instance) error {
+	member, targetPoolName := replicationParseTarget(inst.ExpandedConfig()["replication.target"])
+	remote := inst.ExpandedConfig()["replication.remote"] != ""
+
+	rootDiskName, rootDisk, err := instancetype.GetRootDiskDevice(inst.ExpandedDevices().CloneNative())
+	if err != nil {
+		return err
+	}
+
+	if !remote && targetPoolName == rootDisk["pool"] {
+		return fmt.Errorf("Replication target pool %q is the instance's own pool", targetPoolName)
+	}
+
+	address := ""
+	if !remote {
+		address, err = replicationTargetAddress(s, member)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The replica uses the local devices of the instance, with its root disk stored on the target pool.
+	devices := inst.LocalDevices().CloneNative()
+	replicaRootDisk := map[string]string{}
+	for key, value := range rootDisk {
+		replicaRootDisk[key] = value
+	}
+
+	replicaRootDisk["pool"] = targetPoolName
+	devices[rootDiskName] = replicaRootDisk
+
+	config := map[string]string{}
+	for key, value := range replicaConfig(inst.LocalConfig()) {
+		if instancetype.InstanceIncludeWhenCopying(key, remote || address != "") {
+			config[key] = value
+		}
+	}
+
+	// Replicas must not be started along with their source.
+	config["boot.autostart"] = "false"
+	config[replicationSourceKey] = inst.Name()
+
+	replicaName := inst.Name() + replicaInstanceSuffix
+	syncStart := time.Now()
+
+	architectureName, err := osarch.ArchitectureName(inst.Architecture())
+	if err != nil {
+		return err
+	}
+
+	profileNames := make([]string, 0, len(inst.Profiles()))
+	for _, profile := range inst.Profiles() {
+		profileNames = append(profileNames, profile.Name)
+	}
+
+	req := api.InstancesPost{
+		Name: replicaName,
+		Type: api.InstanceType(inst.Type().String()),
+		InstancePut: api.InstancePut{
+			Architecture: architectureName,
+			Config:       config,
+			Description:  inst.Description(),
+			Devices:      devices,
+			Profiles:     profileNames,
+		},
+	}
+
+	if remote {
+		err = replicateInstanceToRemote(s, op, inst, member, req)
+	} else {
+		err = replicateInstanceInCluster(s, op, inst, member, address, req)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	volType, err := storagePools.InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	volDBType, err := storagePools.VolumeTypeToDBType(volType)
+	if err != nil {
+		return err
+	}
+
+	return replicationSetLastSync(s, inst.Project().Name, rootDisk["pool"], inst.Name(), volDBType, syncStart)
+}
+
+// replicateInstanceInCluster refreshes the replica of an instance on the given cluster member, or on the local
+// member if the address is empty.
+func replicateInstanceInCluster(s *state.State, op *operations.Operation, inst instance.Instance, member string, address string, req api.InstancesPost) error {
+	replica, err := instance.LoadByProjectAndName(s, inst.Project().Name, req.Name)
+	if err != nil && !response.IsNotFoundError(err) {
+		return err
+	}
+
+	if replica != nil {
+		err = replicationCheckSource("Instance", req.Name, replica.LocalConfig(), inst.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	if address == "" {
+		if replica != nil && replica.IsRunning() {
+			return fmt.Errorf("Replica %q is running", req.Name)
+		}
+
+		_, err = instanceCreateAsCopy(s, instanceCreateAsCopyOpts{
+			sourceInstance: inst,
+			targetInstance: db.InstanceArgs{
+				Project:      inst.Project().Name,
+				Architecture: inst.Architecture(),
+				Config:       req.Config,
+				Type:         inst.Type(),
+				Description:  req.Description,
+				Devices:      deviceConfig.NewDevices(req.Devices),
+				Name:         req.Name,
+				Profiles:     inst.Profiles(),
+			},
+			refresh:           true,
+			allowInconsistent: true,
+		}, op)
+
+		return err
+	}
+
+	// Let the target member pull the instance, in the same way as a refreshing copy between members.
+	client, err := cluster.Connect(address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, false)
+	if err != nil {
+		return err
+	}
+
+	req.Source = api.InstanceSource{
+		Type:              "copy",
+		Source:            inst.Name(),
+		Project:           inst.Project().Name,
+		Refresh:           true,
+		AllowInconsistent: true,
+	}
+
+	remoteOp, err := client.UseProject(inst.Project().Name).UseTarget(member).CreateInstance(req)
+	if err != nil {
+		return err
+	}
+
+	return remoteOp.Wait()
+}
+
+// replicateInstanceToRemote pushes the replica of an instance to the LXD server set in replication.remote, in the
+// same way as a refreshing copy between servers in push mode.
+func replicateInstanceToRemote(s *state.State, op *operations.Operation, inst instance.Instance, member string, req api.InstancesPost) error {
+	client, certificate, err := replicationRemoteConnect(s, inst.ExpandedConfig(), inst.Project().Name, member)
+	if err != nil {
+		return err
+	}
+
+	replica, _, err := client.GetInstance(req.Name)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	if err == nil {
+		err = replicationCheckSource("Instance", req.Name, replica.Config, inst.Name())
+		if err != nil {
+			return err
+		}
+
+		if replica.StatusCode == api.Running {
+			return fmt.Errorf("Replica %q is running", req.Name)
+		}
+	}
+
+	req.Source = api.InstanceSource{
+		Type:              "migration",
+		Mode:              "push",
+		BaseImage:         inst.LocalConfig()["volatile.base_image"],
+		Refresh:           true,
+		AllowInconsistent: true,
+	}
+
+	remoteOp, err := client.CreateInstance(req)
+	if err != nil {
+		return err
+	}
+
+	operationURL, secrets, err := replicationRemoteOperation(client, remoteOp)
+	if err != nil {
+		_ = remoteOp.Cancel()
+		return err
+	}
+
+	migrationSource, err := newMigrationSource(inst, false, false, true, "", &api.InstancePostTarget{
+		Operation:   operationURL,
+		Websockets:  secrets,
+		Certificate: certificate,
+	})
+	if err != nil {
+		_ = remoteOp.Cancel()
+		return err
+	}
+
+	err = migrationSource.Do(s, op)
+	if err != nil {
+		_ = remoteOp.Cancel()
+		return fmt.Errorf("Failed pushing replica of instance %q to %q: %w", inst.Name(), inst.ExpandedConfig()["replication.remote"], err)
+	}
+
+	return remoteOp.Wait()
+}
+
+// autoReplicateInstances replicates the instances, continuing with the next instance if one fails.
+func autoReplicateInstances(ctx context.Context, s *state.State, op *operations.Operation, instances []instance.Instance) error {
+	failed := 0
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "target": inst.ExpandedConfig()["replication.target"]})
+
+		err = replicateInstance(s, op, inst)
+		if err != nil {
+			l.Error("Failed replicating instance", logger.Ctx{"err": err})
+			failed++
+			continue
+		}
+
+		l.Debug("Replicated instance")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Failed replicating %d instance(s)", failed)
+	}
+
+	return nil
+}
+
+// autoReplicateCustomVolumes replicates the custom volumes, continuing with the next volume if one fails.
+func autoReplicateCustomVolumes(ctx context.Context, s *state.State, op *operations.Operation, volumes []db.StorageVolumeArgs) error {
+	failed := 0
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		l := logger.AddContext(logger.Ctx{"project": v.ProjectName, "pool": v.PoolName, "volume": v.Name, "target": v.Config["replication.target"]})
+
+		err = replicateCustomVolume(s, op, v)
+		if err != nil {
+			l.Error("Failed replicating custom volume", logger.Ctx{"err": err})
+			failed++
+			continue
+		}
+
+		l.Debug("Replicated custom volume")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Failed replicating %d custom volume(s)", failed)
+	}
+
+	return nil
+}
+
+func autoReplicateTask(d *Daemon) (task.Func, task.Schedule) {
+	return scheduledTask{
+		scheduleKey: "replication.schedule",
+		enabled: func(config map[string]string) bool {
+			return config["replication.target"] != ""
+		},
+		instanceOpType: operationtype.InstancesReplicate,
+		instanceRun:    autoReplicateInstances,
+		volumeOpType:   operationtype.CustomVolumesReplicate,
+		volumeRun:      autoReplicateCustomVolumes,
+	}.task(d)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clusterConfig "github.com/canonical/lxd/lxd/cluster/config"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestReplicationParseTarget(t *testing.T) {
+	member, pool := replicationParseTarget("backup")
+	assert.Equal(t, "", member)
+	assert.Equal(t, "backup", pool)
+
+	member, pool = replicationParseTarget("server2/backup")
+	assert.Equal(t, "server2", member)
+	assert.Equal(t, "backup", pool)
+}
+
+func TestReplicaConfig(t *testing.T) {
+	config := map[string]string{
+		"size":                           "10GiB",
+		"replication.schedule":           "@hourly",
+		"replication.target":             "backup",
+		"volatile.replication.last_sync": "2024-01-01T00:00:00Z",
+		"volatile.replication.source":    "default/vol1",
+		"snapshots.schedule":             "@daily",
+		"snapshots.expiry":               "1w",
+	}
+
+	assert.Equal(t, map[string]string{"size": "10GiB", "snapshots.expiry": "1w"}, replicaConfig(config))
+}
+
+func TestReplicationCheckSource(t *testing.T) {
+	assert.NoError(t, replicationCheckSource("Instance", "c1-replica", map[string]string{"volatile.replication.source": "c1"}, "c1"))
+
+	err := replicationCheckSource("Instance", "c1-replica", map[string]string{}, "c1")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+	assert.EqualError(t, err, `Instance "c1-replica" already exists and isn't a replica of "c1"`)
+
+	err = replicationCheckSource("Custom volume", "vol1", map[string]string{"volatile.replication.source": "other/vol1"}, "default/vol1")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+}
+
+func TestReplicationLag(t *testing.T) {
+	now := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+
+	_, _, found := replicationLag(map[string]string{}, now)
+	assert.False(t, found)
+
+	lastSync, lag, found := replicationLag(map[string]string{"volatile.replication.last_sync": "2024-01-01T00:00:00Z"}, now)
+	assert.True(t, found)
+	assert.Equal(t, time.Hour, lag)
+	assert.True(t, lastSync.Equal(now.Add(-time.Hour)))
+}
+
+// Replication remotes must be allowed by the server configuration and present the expected certificate.
+func TestReplicationRemoteConnect(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	err := tx.UpdateClusterConfig(map[string]string{"replication.allowed_remote_urls": server.URL})
+	require.NoError(t, err)
+
+	globalConfig, err := clusterConfig.Load(context.Background(), tx)
+	require.NoError(t, err)
+
+	s := &state.State{GlobalConfig: globalConfig}
+	fingerprint := shared.CertFingerprint(server.Certificate())
+	otherFingerprint := "0000000000000000000000000000000000000000000000000000000000000000"
+
+	_, _, err = replicationRemoteConnect(s, map[string]string{"replication.remote": "https://lxd.example.com:8443", "replication.remote_fingerprint": fingerprint}, "default", "")
+	assert.EqualError(t, err, `Replication remote "https://lxd.example.com:8443" isn't allowed by the server configuration`)
+
+	_, _, err = replicationRemoteConnect(s, map[string]string{"replication.remote": server.URL + "/1.0", "replication.remote_fingerprint": fingerprint}, "default", "")
+	assert.ErrorContains(t, err, "must be an https URL without path")
+
+	_, _, err = replicationRemoteConnect(s, map[string]string{"replication.remote": server.URL}, "default", "")
+	assert.ErrorContains(t, err, "requires replication.remote_fingerprint to be set")
+
+	_, _, err = replicationRemoteConnect(s, map[string]string{"replication.remote": server.URL, "replication.remote_fingerprint": otherFingerprint}, "default", "")
+	assert.ErrorContains(t, err, "doesn't match replication.remote_fingerprint")
+}
@@ -443,7 +443,10 @@ func (r *fileResponse) Render(w http.ResponseWriter) error {
 			rs = f
 		}
 
-		w.Header().Set("Content-Type", "application/octet-stream")
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/octet-stream")
+		}
+
 		w.Header().Set("Content-Length", fmt.Sprintf("%d", sz))
 		w.Header().Set("Content-Disposition", fmt.Sprintf("inline;filename=%s", r.files[0].Filename))
 
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// scheduledTask describes a task run on the instances and custom volumes whose schedule setting is due.
+type scheduledTask struct {
+	// Config key holding the cron schedule of the instances and custom volumes.
+	scheduleKey string
+
+	// Optional additional condition on the (expanded) config of the instances and custom volumes.
+	enabled func(config map[string]string) bool
+
+	instanceOpType operationtype.Type
+	instanceRun    func(ctx context.Context, s *state.State, op *operations.Operation, instances []instance.Instance) error
+
+	volumeOpType operationtype.Type
+	volumeRun    func(ctx context.Context, s *state.State, op *operations.Operation, volumes []db.StorageVolumeArgs) error
+}
+
+// due returns whether the config has a schedule that is due now.
+func (t scheduledTask) due(config map[string]string, seed int64) bool {
+	schedule := config[t.scheduleKey]
+	if schedule == "" || (t.enabled != nil && !t.enabled(config)) {
+		return false
+	}
+
+	return snapshotIsScheduledNow(schedule, seed)
+}
+
+// instances returns the instances on the local member that are due.
+func (t scheduledTask) instances(ctx context.Context, s *state.State) ([]instance.Instance, error) {
+	var instances []instance.Instance
+
+	filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+	err := s.DB.Cluster.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+		inst, err := instance.Load(s, dbInst, p)
+		if err != nil {
+			logger.Error("Failed loading instance for scheduled task", logger.Ctx{"instance": dbInst.Name, "project": dbInst.Project, "operation": t.instanceOpType.Description(), "err": err})
+			return nil
+		}
+
+		if !t.due(inst.ExpandedConfig(), int64(inst.ID())) {
+			return nil
+		}
+
+		logger.Debug("Scheduling instance", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "operation": t.instanceOpType.Description()})
+		instances = append(instances, inst)
+
+		return nil
+	}, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// volumes returns the custom volumes that are due and handled by the local member.
+// Remote volumes are handled by a stable random online member.
+func (t scheduledTask) volumes(ctx context.Context, s *state.State) ([]db.StorageVolumeArgs, error) {
+	var volumes []db.StorageVolumeArgs
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+		if err != nil {
+			return fmt.Errorf("Failed getting custom volumes: %w", err)
+		}
+
+		var members []db.NodeInfo
+		var onlineMemberIDs []int64
+		for _, v := range allVolumes {
+			if !t.due(v.Config, v.ID) {
+				continue
+			}
+
+			if v.NodeID < 0 {
+				if members == nil {
+					members, err = tx.GetNodes(ctx)
+					if err != nil {
+						return fmt.Errorf("Failed getting cluster members: %w", err)
+					}
+
+					for _, member := range members {
+						if !member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+							onlineMemberIDs = append(onlineMemberIDs, member.ID)
+						}
+					}
+				}
+
+				if len(members) > 1 {
+					selectedMemberID, err := util.GetStableRandomInt64FromList(v.ID, onlineMemberIDs)
+					if err != nil || selectedMemberID != tx.GetNodeID() {
+						continue
+					}
+				}
+			}
+
+			logger.Debug("Scheduling custom volume", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "operation": t.volumeOpType.Description()})
+			volumes = append(volumes, v)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return volumes, nil
+}
+
+// runOperation runs the function in a task operation of the given type and waits for it to complete.
+func (t scheduledTask) runOperation(ctx context.Context, s *state.State, opType operationtype.Type, opRun func(op *operations.Operation) error) {
+	l := logger.AddContext(logger.Ctx{"operation": opType.Description()})
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, opType, nil, nil, opRun, nil, nil, nil)
+	if err != nil {
+		l.Error("Failed creating scheduled operation", logger.Ctx{"err": err})
+		return
+	}
+
+	l.Info("Starting scheduled operation")
+
+	err = op.Start()
+	if err != nil {
+		l.Error("Failed starting scheduled operation", logger.Ctx{"err": err})
+		return
+	}
+
+	err = op.Wait(ctx)
+	if err != nil {
+		l.Error("Failed scheduled operation", logger.Ctx{"err": err})
+		return
+	}
+
+	l.Info("Done scheduled operation")
+}
+
+// task returns the task checking every minute for instances and custom volumes that are due.
+func (t scheduledTask) task(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		instances, err := t.instances(ctx, s)
+		if err != nil {
+			logger.Error("Failed getting instance schedule info", logger.Ctx{"operation": t.instanceOpType.Description(), "err": err})
+			return
+		}
+
+		volumes, err := t.volumes(ctx, s)
+		if err != nil {
+			logger.Error("Failed getting custom volume schedule info", logger.Ctx{"operation": t.volumeOpType.Description(), "err": err})
+			return
+		}
+
+		if len(instances) > 0 {
+			t.runOperation(ctx, s, t.instanceOpType, func(op *operations.Operation) error {
+				return t.instanceRun(ctx, s, op, instances)
+			})
+		}
+
+		if len(volumes) > 0 {
+			t.runOperation(ctx, s, t.volumeOpType, func(op *operations.Operation) error {
+				return t.volumeRun(ctx, s, op, volumes)
+			})
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledTaskDue(t *testing.T) {
+	backups := scheduledTask{scheduleKey: "backups.schedule"}
+	assert.True(t, backups.due(map[string]string{"backups.schedule": "* * * * *"}, 1))
+	assert.False(t, backups.due(map[string]string{"backups.schedule": "@never"}, 1))
+	assert.False(t, backups.due(map[string]string{"snapshots.schedule": "* * * * *"}, 1))
+	assert.False(t, backups.due(nil, 1))
+
+	replication := scheduledTask{
+		scheduleKey: "replication.schedule",
+		enabled: func(config map[string]string) bool {
+			return config["replication.target"] != ""
+		},
+	}
+
+	assert.False(t, replication.due(map[string]string{"replication.schedule": "* * * * *"}, 1))
+	assert.True(t, replication.due(map[string]string{"replication.schedule": "* * * * *", "replication.target": "lxd02"}, 1))
+	assert.False(t, replication.due(map[string]string{"replication.schedule": "@never", "replication.target": "lxd02"}, 1))
+}
@@ -0,0 +1,202 @@
+package scriptlet
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.starlark.net/starlark"
+
+	scriptletLoad "github.com/canonical/lxd/lxd/scriptlet/load"
+	apiScriptlet "github.com/canonical/lxd/shared/api/scriptlet"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// InstanceValidationRun runs the instance validation scriptlet against the requested instance configuration.
+// Returns an error if the scriptlet rejects the request, otherwise any config and device changes made by the
+// scriptlet are applied to req.
+func InstanceValidationRun(ctx context.Context, l logger.Logger, req *apiScriptlet.InstanceValidation) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var sb strings.Builder
+		for _, arg := range args {
+			s, err := strconv.Unquote(arg.String())
+			if err != nil {
+				s = arg.String()
+			}
+
+			sb.WriteString(s)
+		}
+
+		switch b.Name() {
+		case "log_info":
+			l.Info(fmt.Sprintf("Instance validation scriptlet: %s", sb.String()))
+		case "log_warn":
+			l.Warn(fmt.Sprintf("Instance validation scriptlet: %s", sb.String()))
+		default:
+			l.Error(fmt.Sprintf("Instance validation scriptlet: %s", sb.String()))
+		}
+
+		return starlark.None, nil
+	}
+
+	// Work on copies of the config and devices so that a rejected request is left untouched.
+	config := make(map[string]string, len(req.Config))
+	for k, v := range req.Config {
+		config[k] = v
+	}
+
+	devices := make(map[string]map[string]string, len(req.Devices))
+	for devName, dev := range req.Devices {
+		devices[devName] = make(map[string]string, len(dev))
+		for k, v := range dev {
+			devices[devName][k] = v
+		}
+	}
+
+	var rejectReason string
+
+	rejectFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var reason string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "reason", &reason)
+		if err != nil {
+			return nil, err
+		}
+
+		if reason == "" {
+			reason = "No reason given"
+		}
+
+		rejectReason = reason
+		l.Info("Instance validation scriptlet rejected request", logger.Ctx{"reason": reason})
+
+		return starlark.None, nil
+	}
+
+	setConfigFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var key, value string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "key", &key, "value", &value)
+		if err != nil {
+			return nil, err
+		}
+
+		if value == "" {
+			delete(config, key)
+		} else {
+			config[key] = value
+		}
+
+		l.Info("Instance validation scriptlet set config", logger.Ctx{"key": key, "value": value})
+
+		return starlark.None, nil
+	}
+
+	setDeviceConfigFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var devName, key, value string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "device_name", &devName, "key", &key, "value", &value)
+		if err != nil {
+			return nil, err
+		}
+
+		dev, found := devices[devName]
+		if !found {
+			dev = make(map[string]string)
+			devices[devName] = dev
+		}
+
+		if value == "" {
+			delete(dev, key)
+		} else {
+			dev[key] = value
+		}
+
+		l.Info("Instance validation scriptlet set device config", logger.Ctx{"device": devName, "key": key, "value": value})
+
+		return starlark.None, nil
+	}
+
+	removeDeviceFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var devName string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "device_name", &devName)
+		if err != nil {
+			return nil, err
+		}
+
+		delete(devices, devName)
+
+		l.Info("Instance validation scriptlet removed device", logger.Ctx{"device": devName})
+
+		return starlark.None, nil
+	}
+
+	// Remember to match the entries in scriptletLoad.InstanceValidationCompile() with this list so Starlark can
+	// perform compile time validation of functions used.
+	env := starlark.StringDict{
+		"log_info":          starlark.NewBuiltin("log_info", logFunc),
+		"log_warn":          starlark.NewBuiltin("log_warn", logFunc),
+		"log_error":         starlark.NewBuiltin("log_error", logFunc),
+		"reject":            starlark.NewBuiltin("reject", rejectFunc),
+		"set_config":        starlark.NewBuiltin("set_config", setConfigFunc),
+		"set_device_config": starlark.NewBuiltin("set_device_config", setDeviceConfigFunc),
+		"remove_device":     starlark.NewBuiltin("remove_device", removeDeviceFunc),
+	}
+
+	prog, thread, err := scriptletLoad.InstanceValidationProgram()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		thread.Cancel("Request finished")
+	}()
+
+	globals, err := prog.Init(thread, env)
+	if err != nil {
+		return fmt.Errorf("Failed initializing: %w", err)
+	}
+
+	globals.Freeze()
+
+	// Retrieve a global variable from starlark environment.
+	instanceValidation := globals["instance_validation"]
+	if instanceValidation == nil {
+		return fmt.Errorf("Scriptlet missing instance_validation function")
+	}
+
+	rv, err := StarlarkMarshal(req)
+	if err != nil {
+		return fmt.Errorf("Marshalling request failed: %w", err)
+	}
+
+	// Call starlark function from Go.
+	v, err := starlark.Call(thread, instanceValidation, nil, []starlark.Tuple{
+		{
+			starlark.String("request"),
+			rv,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to run: %w", err)
+	}
+
+	if v.Type() != "NoneType" {
+		return fmt.Errorf("Failed with unexpected return value: %v", v)
+	}
+
+	if rejectReason != "" {
+		return fmt.Errorf("Request rejected: %s", rejectReason)
+	}
+
+	req.Config = config
+	req.Devices = devices
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package scriptlet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	scriptletLoad "github.com/canonical/lxd/lxd/scriptlet/load"
+	"github.com/canonical/lxd/shared/api"
+	apiScriptlet "github.com/canonical/lxd/shared/api/scriptlet"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+const testInstanceValidationScriptlet = `
+def instance_validation(request):
+    if request.name == "forbidden":
+        reject("Forbidden name")
+        return
+
+    if request.expanded_config.get("security.secureboot") == "false":
+        set_config("security.secureboot", "true")
+
+    set_config("user.remove", "")
+    set_device_config("root", "size", "10GiB")
+    remove_device("eth0")
+`
+
+func TestInstanceValidationRun(t *testing.T) {
+	err := scriptletLoad.InstanceValidationSet(testInstanceValidationScriptlet)
+	require.NoError(t, err)
+
+	defer func() { _ = scriptletLoad.InstanceValidationSet("") }()
+
+	newRequest := func(name string) *apiScriptlet.InstanceValidation {
+		return &apiScriptlet.InstanceValidation{
+			InstancePut: api.InstancePut{
+				Config: map[string]string{
+					"user.remove": "foo",
+				},
+				Devices: map[string]map[string]string{
+					"root": {"type": "disk", "path": "/", "pool": "default"},
+					"eth0": {"type": "nic", "network": "lxdbr0"},
+				},
+			},
+			Name:    name,
+			Type:    api.InstanceTypeVM,
+			Reason:  apiScriptlet.InstanceValidationReasonCreate,
+			Project: "default",
+			ExpandedConfig: map[string]string{
+				"security.secureboot": "false",
+			},
+		}
+	}
+
+	// Check the request is modified.
+	req := newRequest("c1")
+	err = InstanceValidationRun(context.Background(), logger.Log, req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"security.secureboot": "true"}, req.Config)
+	assert.Equal(t, map[string]map[string]string{
+		"root": {"type": "disk", "path": "/", "pool": "default", "size": "10GiB"},
+	}, req.Devices)
+
+	// Check a rejected request is left untouched.
+	req = newRequest("forbidden")
+	err = InstanceValidationRun(context.Background(), logger.Log, req)
+	assert.ErrorContains(t, err, "Forbidden name")
+	assert.Equal(t, map[string]string{"user.remove": "foo"}, req.Config)
+	assert.Len(t, req.Devices, 2)
+}
@@ -12,22 +12,17 @@ import (
 // nameInstancePlacement is the name used in Starlark for the instance placement scriptlet.
 const nameInstancePlacement = "instance_placement"
 
-// InstancePlacementCompile compiles the instance placement scriptlet.
-func InstancePlacementCompile(src string) (*starlark.Program, error) {
+// nameInstanceValidation is the name used in Starlark for the instance validation scriptlet.
+const nameInstanceValidation = "instance_validation"
+
+// compile compiles a scriptlet, only allowing the specified builtin functions to be referenced.
+func compile(programName string, src string, preDeclared []string) (*starlark.Program, error) {
 	isPreDeclared := func(name string) bool {
-		return shared.ValueInSlice(name, []string{
-			"log_info",
-			"log_warn",
-			"log_error",
-			"set_target",
-			"get_cluster_member_resources",
-			"get_cluster_member_state",
-			"get_instance_resources",
-		})
+		return shared.ValueInSlice(name, preDeclared)
 	}
 
 	// Parse, resolve, and compile a Starlark source file.
-	_, mod, err := starlark.SourceProgram(nameInstancePlacement, src, isPreDeclared)
+	_, mod, err := starlark.SourceProgram(programName, src, isPreDeclared)
 	if err != nil {
 		return nil, err
 	}
@@ -35,46 +30,110 @@ func InstancePlacementCompile(src string) (*starlark.Program, error) {
 	return mod, nil
 }
 
+// InstancePlacementCompile compiles the instance placement scriptlet.
+func InstancePlacementCompile(src string) (*starlark.Program, error) {
+	return compile(nameInstancePlacement, src, []string{
+		"log_info",
+		"log_warn",
+		"log_error",
+		"set_target",
+		"get_cluster_member_resources",
+		"get_cluster_member_state",
+		"get_instance_resources",
+	})
+}
+
 // InstancePlacementValidate validates the instance placement scriptlet.
 func InstancePlacementValidate(src string) error {
 	_, err := InstancePlacementCompile(src)
 	return err
 }
 
+// InstanceValidationCompile compiles the instance validation scriptlet.
+func InstanceValidationCompile(src string) (*starlark.Program, error) {
+	return compile(nameInstanceValidation, src, []string{
+		"log_info",
+		"log_warn",
+		"log_error",
+		"reject",
+		"set_config",
+		"set_device_config",
+		"remove_device",
+	})
+}
+
+// InstanceValidationValidate validates the instance validation scriptlet.
+func InstanceValidationValidate(src string) error {
+	_, err := InstanceValidationCompile(src)
+	return err
+}
+
 var programsMu sync.Mutex
 var programs = make(map[string]*starlark.Program)
 
-// InstancePlacementSet compiles the instance placement scriptlet into memory for use with InstancePlacementRun.
+// set compiles the scriptlet using compileFunc and stores it under programName.
 // If empty src is provided the current program is deleted.
-func InstancePlacementSet(src string) error {
+func set(programName string, compileFunc func(src string) (*starlark.Program, error), src string) error {
 	if src == "" {
 		programsMu.Lock()
-		delete(programs, nameInstancePlacement)
+		delete(programs, programName)
 		programsMu.Unlock()
 	} else {
-		prog, err := InstancePlacementCompile(src)
+		prog, err := compileFunc(src)
 		if err != nil {
 			return err
 		}
 
 		programsMu.Lock()
-		programs[nameInstancePlacement] = prog
+		programs[programName] = prog
 		programsMu.Unlock()
 	}
 
 	return nil
 }
 
-// InstancePlacementProgram returns the precompiled instance placement scriptlet program.
-func InstancePlacementProgram() (*starlark.Program, *starlark.Thread, error) {
+// program returns the precompiled program stored under programName and a new thread to run it in.
+func program(programName string) (*starlark.Program, *starlark.Thread, bool) {
 	programsMu.Lock()
-	prog, found := programs[nameInstancePlacement]
+	prog, found := programs[programName]
 	programsMu.Unlock()
+	if !found {
+		return nil, nil, false
+	}
+
+	thread := &starlark.Thread{Name: programName}
+
+	return prog, thread, true
+}
+
+// InstancePlacementSet compiles the instance placement scriptlet into memory for use with InstancePlacementRun.
+// If empty src is provided the current program is deleted.
+func InstancePlacementSet(src string) error {
+	return set(nameInstancePlacement, InstancePlacementCompile, src)
+}
+
+// InstancePlacementProgram returns the precompiled instance placement scriptlet program.
+func InstancePlacementProgram() (*starlark.Program, *starlark.Thread, error) {
+	prog, thread, found := program(nameInstancePlacement)
 	if !found {
 		return nil, nil, fmt.Errorf("Instance placement scriptlet not loaded")
 	}
 
-	thread := &starlark.Thread{Name: nameInstancePlacement}
+	return prog, thread, nil
+}
+
+// InstanceValidationSet compiles the instance validation scriptlet into memory for use with InstanceValidationRun.
+// If empty src is provided the current program is deleted.
+func InstanceValidationSet(src string) error {
+	return set(nameInstanceValidation, InstanceValidationCompile, src)
+}
+
+// InstanceValidationProgram returns the precompiled instance validation scriptlet program.
+func InstanceValidationProgram() (*starlark.Program, *starlark.Thread, error) {
+	prog, thread, found := program(nameInstanceValidation)
+	if !found {
+		return nil, nil, fmt.Errorf("Instance validation scriptlet not loaded")
+	}
 
 	return prog, thread, nil
 }
@@ -37,6 +37,7 @@ import (
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/rsync"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/storage/drivers"
 	"github.com/canonical/lxd/lxd/storage/filesystem"
@@ -141,6 +142,33 @@ func (b *lxdBackend) isStatusReady() error {
 	return nil
 }
 
+// checkCustomVolumeSnapshotsLimit returns an error if creating count custom volume snapshots would exceed the
+// snapshot count limit of the project.
+func (b *lxdBackend) checkCustomVolumeSnapshotsLimit(projectName string, count int) error {
+	if count <= 0 {
+		return nil
+	}
+
+	err := b.state.DB.Cluster.Transaction(b.state.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := cluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		p, err := dbProject.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		return project.AllowVolumeSnapshotCreation(tx, p, count)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed checking volume snapshot creation allowed: %w", err)
+	}
+
+	return nil
+}
+
 // ToAPI returns the storage pool as an API representation.
 func (b *lxdBackend) ToAPI() api.StoragePool {
 	return b.db
@@ -807,46 +835,9 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 
 		postHookRevert.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, inst.Name(), volType) })
 
-		for i, backupFileSnap := range srcBackup.Snapshots {
-			var volumeSnapDescription string
-			var volumeSnapConfig map[string]string
-			var volumeSnapExpiryDate time.Time
-			var volumeSnapCreationDate time.Time
-
-			// Check if snapshot volume config is available for restore and matches snapshot name.
-			if srcBackup.Config != nil {
-				if len(srcBackup.Config.Snapshots) >= i-1 && srcBackup.Config.Snapshots[i] != nil && srcBackup.Config.Snapshots[i].Name == backupFileSnap {
-					// Use instance snapshot's creation date if snap info available.
-					volumeSnapCreationDate = srcBackup.Config.Snapshots[i].CreatedAt
-				}
-
-				if len(srcBackup.Config.VolumeSnapshots) >= i-1 && srcBackup.Config.VolumeSnapshots[i] != nil && srcBackup.Config.VolumeSnapshots[i].Name == backupFileSnap {
-					// If the backup restore interface provides volume snapshot config use it,
-					// otherwise use default volume config for the storage pool.
-					volumeSnapDescription = srcBackup.Config.VolumeSnapshots[i].Description
-					volumeSnapConfig = srcBackup.Config.VolumeSnapshots[i].Config
-
-					if srcBackup.Config.VolumeSnapshots[i].ExpiresAt != nil {
-						volumeSnapExpiryDate = *srcBackup.Config.VolumeSnapshots[i].ExpiresAt
-					}
-
-					// Use volume's creation date if available.
-					if !srcBackup.Config.VolumeSnapshots[i].CreatedAt.IsZero() {
-						volumeSnapCreationDate = srcBackup.Config.VolumeSnapshots[i].CreatedAt
-					}
-				}
-			}
-
-			newSnapshotName := drivers.GetSnapshotVolumeName(inst.Name(), backupFileSnap)
-
-			// Validate config and create database entry for new storage volume.
-			// Strip unsupported config keys (in case the export was made from a different type of storage pool).
-			err = VolumeDBCreate(b, inst.Project().Name, newSnapshotName, volumeSnapDescription, volType, true, volumeSnapConfig, volumeSnapCreationDate, volumeSnapExpiryDate, contentType, true, true)
-			if err != nil {
-				return err
-			}
-
-			postHookRevert.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, newSnapshotName, volType) })
+		err = b.createInstanceSnapshotVolumesDBFromBackup(inst, srcBackup, volType, contentType, postHookRevert)
+		if err != nil {
+			return err
 		}
 
 		// Generate the effective root device volume for instance.
@@ -936,6 +927,129 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 	return postHook, revertHook, nil
 }
 
+// RefreshInstanceFromBackup applies an incremental backup file onto an existing instance's storage volume,
+// restoring the snapshots it contains and bringing the volume in line with the backup. The instance must
+// be stopped and its latest snapshot must be the base snapshot of the incremental backup.
+func (b *lxdBackend) RefreshInstanceFromBackup(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "snapshots": srcBackup.Snapshots, "baseSnapshot": srcBackup.BaseSnapshot, "optimizedStorage": *srcBackup.OptimizedStorage})
+	l.Debug("RefreshInstanceFromBackup started")
+	defer l.Debug("RefreshInstanceFromBackup finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	if srcBackup.BaseSnapshot == "" {
+		return fmt.Errorf("Backup isn't incremental")
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	contentType := InstanceContentType(inst)
+
+	// Load storage volume from database.
+	dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return err
+	}
+
+	// Generate the effective root device volume for instance.
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(volType, contentType, volStorageName, dbVol.Config)
+	err = b.applyInstanceRootDiskOverrides(inst, &vol)
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Apply the backup onto the existing storage volume(s).
+	volPostHook, revertHook, err := b.driver.CreateVolumeFromBackup(vol, srcBackup, srcData, op)
+	if err != nil {
+		return err
+	}
+
+	if revertHook != nil {
+		revert.Add(revertHook)
+	}
+
+	if len(srcBackup.Snapshots) > 0 {
+		err = b.ensureInstanceSnapshotSymlink(inst.Type(), inst.Project().Name, inst.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	err = b.createInstanceSnapshotVolumesDBFromBackup(inst, srcBackup, volType, contentType, revert)
+	if err != nil {
+		return err
+	}
+
+	// If the driver returned a post hook, run it now.
+	if volPostHook != nil {
+		err = volPostHook(vol)
+		if err != nil {
+			return err
+		}
+	}
+
+	revert.Success()
+	return nil
+}
+
+// createInstanceSnapshotVolumesDBFromBackup creates the database records for the instance snapshot volumes
+// restored from a backup, adding their removal to the reverter.
+func (b *lxdBackend) createInstanceSnapshotVolumesDBFromBackup(inst instance.Instance, srcBackup backup.Info, volType drivers.VolumeType, contentType drivers.ContentType, reverter *revert.Reverter) error {
+	for i, backupFileSnap := range srcBackup.Snapshots {
+		var volumeSnapDescription string
+		var volumeSnapConfig map[string]string
+		var volumeSnapExpiryDate time.Time
+		var volumeSnapCreationDate time.Time
+
+		// Check if snapshot volume config is available for restore and matches snapshot name.
+		if srcBackup.Config != nil {
+			if len(srcBackup.Config.Snapshots) >= i-1 && srcBackup.Config.Snapshots[i] != nil && srcBackup.Config.Snapshots[i].Name == backupFileSnap {
+				// Use instance snapshot's creation date if snap info available.
+				volumeSnapCreationDate = srcBackup.Config.Snapshots[i].CreatedAt
+			}
+
+			if len(srcBackup.Config.VolumeSnapshots) >= i-1 && srcBackup.Config.VolumeSnapshots[i] != nil && srcBackup.Config.VolumeSnapshots[i].Name == backupFileSnap {
+				// If the backup restore interface provides volume snapshot config use it,
+				// otherwise use default volume config for the storage pool.
+				volumeSnapDescription = srcBackup.Config.VolumeSnapshots[i].Description
+				volumeSnapConfig = srcBackup.Config.VolumeSnapshots[i].Config
+
+				if srcBackup.Config.VolumeSnapshots[i].ExpiresAt != nil {
+					volumeSnapExpiryDate = *srcBackup.Config.VolumeSnapshots[i].ExpiresAt
+				}
+
+				// Use volume's creation date if available.
+				if !srcBackup.Config.VolumeSnapshots[i].CreatedAt.IsZero() {
+					volumeSnapCreationDate = srcBackup.Config.VolumeSnapshots[i].CreatedAt
+				}
+			}
+		}
+
+		newSnapshotName := drivers.GetSnapshotVolumeName(inst.Name(), backupFileSnap)
+
+		// Validate config and create database entry for new storage volume.
+		// Strip unsupported config keys (in case the export was made from a different type of storage pool).
+		err := VolumeDBCreate(b, inst.Project().Name, newSnapshotName, volumeSnapDescription, volType, true, volumeSnapConfig, volumeSnapCreationDate, volumeSnapExpiryDate, contentType, true, true)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, newSnapshotName, volType) })
+	}
+
+	return nil
+}
+
 // CreateInstanceFromCopy copies an instance volume and optionally its snapshots to new volume(s).
 func (b *lxdBackend) CreateInstanceFromCopy(inst instance.Instance, src instance.Instance, snapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "src": src.Name(), "snapshots": snapshots})
@@ -1063,10 +1177,12 @@ func (b *lxdBackend) CreateInstanceFromCopy(inst instance.Instance, src instance
 		// be able to negotiate a common transfer method between pool types.
 		l.Debug("CreateInstanceFromCopy cross-pool mode detected")
 
+		srcVol := srcPool.GetVolume(volType, contentType, project.Instance(src.Project().Name, src.Name()), srcConfig.Volume.Config)
+
 		// Negotiate the migration type to use.
-		offeredTypes := srcPool.MigrationTypes(contentType, false, snapshots)
+		offeredTypes := VolumeMigrationTypes(srcPool, srcVol, false, snapshots)
 		offerHeader := migration.TypesToHeader(offeredTypes...)
-		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, false, snapshots))
+		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), VolumeMigrationTypes(b, vol, false, snapshots))
 		if err != nil {
 			return fmt.Errorf("Failed to negotiate copy migration type: %w", err)
 		}
@@ -1260,6 +1376,11 @@ func (b *lxdBackend) RefreshCustomVolume(projectName string, srcProjectName stri
 			snapshotNames = append(snapshotNames, allSnapshots[syncSourceSnapIndex].Name)
 			srcConfig.VolumeSnapshots = append(srcConfig.VolumeSnapshots, allSnapshots[syncSourceSnapIndex])
 		}
+
+		err = b.checkCustomVolumeSnapshotsLimit(projectName, len(srcConfig.VolumeSnapshots))
+		if err != nil {
+			return err
+		}
 	}
 
 	volStorageName := project.StorageVolume(projectName, volName)
@@ -1304,9 +1425,9 @@ func (b *lxdBackend) RefreshCustomVolume(projectName string, srcProjectName stri
 		l.Debug("RefreshCustomVolume cross-pool mode detected")
 
 		// Negotiate the migration type to use.
-		offeredTypes := srcPool.MigrationTypes(contentType, true, snapshots)
+		offeredTypes := VolumeMigrationTypes(srcPool, srcVol, true, snapshots)
 		offerHeader := migration.TypesToHeader(offeredTypes...)
-		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, true, snapshots))
+		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), VolumeMigrationTypes(b, vol, true, snapshots))
 		if err != nil {
 			return fmt.Errorf("Failed to negotiate copy migration type: %w", err)
 		}
@@ -1543,9 +1664,9 @@ func (b *lxdBackend) RefreshInstance(inst instance.Instance, src instance.Instan
 		l.Debug("RefreshInstance cross-pool mode detected")
 
 		// Negotiate the migration type to use.
-		offeredTypes := srcPool.MigrationTypes(contentType, true, snapshots)
+		offeredTypes := VolumeMigrationTypes(srcPool, srcVol, true, snapshots)
 		offerHeader := migration.TypesToHeader(offeredTypes...)
-		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, true, snapshots))
+		migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), VolumeMigrationTypes(b, vol, true, snapshots))
 		if err != nil {
 			return fmt.Errorf("Failed to negotiate copy migration type: %w", err)
 		}
@@ -1821,7 +1942,7 @@ func (b *lxdBackend) CreateInstanceFromMigration(inst instance.Instance, conn io
 		volumeConfig = dbVol.Config
 		volumeDescription = dbVol.Description
 	} else if srcInfo != nil && srcInfo.Config != nil && srcInfo.Config.Volume != nil {
-		volumeConfig = srcInfo.Config.Volume.Config
+		volumeConfig = migrationVolumeConfig(srcInfo.Config.Volume.Config)
 		volumeDescription = srcInfo.Config.Volume.Description
 	} else {
 		volumeConfig = make(map[string]string)
@@ -2250,6 +2371,15 @@ func (b *lxdBackend) UpdateInstance(inst instance.Instance, newDesc string, newC
 		return err
 	}
 
+	// Keep the wrapped encryption key of encrypted volumes, it cannot be changed or removed.
+	if curVol.Config[drivers.EncryptionKeyConfigKey] != "" {
+		if newConfig == nil {
+			newConfig = map[string]string{}
+		}
+
+		newConfig[drivers.EncryptionKeyConfigKey] = curVol.Config[drivers.EncryptionKeyConfigKey]
+	}
+
 	// Apply config changes if there are any.
 	changedConfig, userOnly := b.detectChangedConfig(curVol.Config, newConfig)
 	if len(changedConfig) != 0 {
@@ -2268,6 +2398,12 @@ func (b *lxdBackend) UpdateInstance(inst instance.Instance, newDesc string, newC
 			return fmt.Errorf(`Instance volume "block.filesystem" property cannot be changed`)
 		}
 
+		// Check that the volume's block.encryption property isn't being changed.
+		_, found := changedConfig["block.encryption"]
+		if found {
+			return fmt.Errorf(`Instance volume "block.encryption" property cannot be changed`)
+		}
+
 		// Load storage volume from database.
 		dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
 		if err != nil {
@@ -2486,9 +2622,200 @@ func (b *lxdBackend) CleanupInstancePaths(inst instance.Instance, op *operations
 	return nil
 }
 
+// MirrorInstance moves the volume of a running VM instance from srcPool to this pool without stopping it.
+// A new volume is created on this pool, the instance's config filesystem is copied onto it and then the mirror
+// function is called with the path of the new root disk so that the running instance can copy its root disk to
+// it and switch over. Once mirrored, the instance's volume record is moved to this pool. The source volume is
+// still in use by the instance and must be removed with DeleteInstanceMirrorSource once it has stopped.
+func (b *lxdBackend) MirrorInstance(inst instance.Instance, srcPool Pool, mirror func(diskPath string) error, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "srcPool": srcPool.Name()})
+	l.Debug("MirrorInstance started")
+	defer l.Debug("MirrorInstance finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	if inst.Type() != instancetype.VM {
+		return fmt.Errorf("Only virtual machines can be moved using block mirroring")
+	}
+
+	if b.driver.Info().Remote {
+		return fmt.Errorf("Virtual machines can only be moved using block mirroring to local storage pools")
+	}
+
+	srcBackend, ok := srcPool.(*lxdBackend)
+	if !ok {
+		return fmt.Errorf("Source pool is not a lxdBackend")
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	contentType := InstanceContentType(inst)
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+
+	// Snapshots can't be mirrored as they aren't attached to the running instance.
+	dbVolSnaps, err := VolumeDBSnapshotsGet(srcBackend, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return err
+	}
+
+	if len(dbVolSnaps) > 0 {
+		return fmt.Errorf("Instances with snapshots can't be moved using block mirroring")
+	}
+
+	dbVol, err := VolumeDBGet(srcBackend, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return err
+	}
+
+	// Get the size of the source root disk as the new root disk needs to match it.
+	srcVol := srcBackend.GetVolume(volType, contentType, volStorageName, dbVol.Config)
+	srcDiskPath, err := srcBackend.driver.GetVolumeDiskPath(srcVol)
+	if err != nil {
+		return err
+	}
+
+	srcDiskSize, err := drivers.BlockDiskSizeBytes(srcDiskPath)
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Generate and validate the config of the new volume without creating its database record yet, as the
+	// instance volume record on the source pool is used until the root disk has been mirrored.
+	volumeConfig := make(map[string]string)
+	err = b.applyInstanceRootDiskInitialValues(inst, volumeConfig)
+	if err != nil {
+		return err
+	}
+
+	vol := b.GetVolume(volType, contentType, volStorageName, volumeConfig)
+	err = b.driver.FillVolumeConfig(vol)
+	if err != nil {
+		return err
+	}
+
+	err = b.driver.ValidateVolume(vol, true)
+	if err != nil {
+		return err
+	}
+
+	// Generate the effective root device volume for instance, sized to match the source root disk.
+	vol = b.GetVolume(volType, contentType, volStorageName, util.CopyConfig(volumeConfig))
+	err = b.applyInstanceRootDiskOverrides(inst, &vol)
+	if err != nil {
+		return err
+	}
+
+	vol.SetConfigSize(fmt.Sprintf("%d", srcDiskSize))
+
+	err = b.driver.CreateVolume(vol, nil, op)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = b.driver.DeleteVolume(vol, op) })
+
+	// The new volume stays mounted for the running instance.
+	err = b.driver.MountVolume(vol, op)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _, _ = b.driver.UnmountVolume(vol, false, op) })
+
+	diskPath, err := b.driver.GetVolumeDiskPath(vol)
+	if err != nil {
+		return err
+	}
+
+	// Copy the config filesystem, excluding the root disk files of file backed volumes.
+	l.Debug("Copying instance config filesystem", logger.Ctx{"srcPath": srcVol.MountPath(), "path": vol.MountPath()})
+	rsyncArgs := []string{}
+	for _, path := range []string{srcDiskPath, diskPath} {
+		if strings.HasPrefix(path, srcVol.MountPath()+"/") || strings.HasPrefix(path, vol.MountPath()+"/") {
+			rsyncArgs = append(rsyncArgs, "--exclude", filepath.Base(path))
+		}
+	}
+
+	_, err = rsync.LocalCopy(shared.AddSlash(srcVol.MountPath()), vol.MountPath(), "", false, rsyncArgs...)
+	if err != nil {
+		return fmt.Errorf("Failed copying instance config filesystem: %w", err)
+	}
+
+	// Have the instance mirror its root disk and switch over to the new volume.
+	err = mirror(diskPath)
+	if err != nil {
+		return err
+	}
+
+	// The instance now uses the new volume, so don't remove it even if updating the records fails.
+	revert.Success()
+
+	// Move the volume record to this pool.
+	err = VolumeDBMove(srcBackend, b, inst.Project().Name, inst.Name(), dbVol.Description, volType, volumeConfig, inst.CreationDate(), contentType)
+	if err != nil {
+		return err
+	}
+
+	err = b.ensureInstanceSymlink(inst.Type(), inst.Project().Name, inst.Name(), vol.MountPath())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteInstanceMirrorSource deletes the instance volume left on this pool after the instance was moved to
+// another pool using MirrorInstance. It must only be called once the instance has stopped using it.
+func (b *lxdBackend) DeleteInstanceMirrorSource(inst instance.Instance, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+	l.Debug("DeleteInstanceMirrorSource started")
+	defer l.Debug("DeleteInstanceMirrorSource finished")
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	contentType := InstanceContentType(inst)
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+
+	// There's no need to pass config as it's not needed when deleting a volume.
+	vol := b.GetVolume(volType, contentType, volStorageName, nil)
+
+	volExists, err := b.driver.HasVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if !volExists {
+		return nil
+	}
+
+	_, err = b.driver.UnmountVolume(vol, false, op)
+	if err != nil {
+		return err
+	}
+
+	err = b.driver.DeleteVolume(vol, op)
+	if err != nil {
+		return fmt.Errorf("Error deleting storage volume: %w", err)
+	}
+
+	return nil
+}
+
 // BackupInstance creates an instance backup.
-func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, op *operations.Operation) error {
-	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots})
+func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, baseSnapshot string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots, "baseSnapshot": baseSnapshot})
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
 
@@ -2534,7 +2861,28 @@ func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancew
 		}
 	}
 
-	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, op)
+	// Incremental backups only include the snapshots taken after the base snapshot.
+	if baseSnapshot != "" {
+		if !snapshots {
+			return fmt.Errorf("Incremental backups must include snapshots")
+		}
+
+		baseIndex := -1
+		for i, snapName := range snapNames {
+			if snapName == baseSnapshot {
+				baseIndex = i
+				break
+			}
+		}
+
+		if baseIndex < 0 {
+			return fmt.Errorf("Base snapshot %q not found", baseSnapshot)
+		}
+
+		snapNames = snapNames[baseIndex+1:]
+	}
+
+	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, baseSnapshot, op)
 	if err != nil {
 		return err
 	}
@@ -4518,6 +4866,11 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(projectName string, srcProjectNa
 		}
 	}
 
+	err = b.checkCustomVolumeSnapshotsLimit(projectName, len(snapshotNames))
+	if err != nil {
+		return err
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -4584,10 +4937,12 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(projectName string, srcProjectNa
 	// to negotiate a common transfer method between pool types.
 	l.Debug("CreateCustomVolumeFromCopy cross-pool mode detected")
 
+	vol := b.GetVolume(drivers.VolumeTypeCustom, contentType, project.StorageVolume(projectName, volName), config)
+
 	// Negotiate the migration type to use.
-	offeredTypes := srcPool.MigrationTypes(contentType, false, snapshots)
+	offeredTypes := VolumeMigrationTypes(srcPool, srcVol, false, snapshots)
 	offerHeader := migration.TypesToHeader(offeredTypes...)
-	migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, false, snapshots))
+	migrationTypes, err := migration.MatchTypes(offerHeader, FallbackMigrationType(contentType), VolumeMigrationTypes(b, vol, false, snapshots))
 	if err != nil {
 		return fmt.Errorf("Failed to negotiate copy migration type: %w", err)
 	}
@@ -4692,6 +5047,107 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(projectName string, srcProjectNa
 	return nil
 }
 
+// CreateCustomVolumeFromClone creates a custom volume as a copy-on-write clone of an existing custom volume in
+// the same pool, possibly in another project. The source volume cannot be deleted or renamed while it has clones.
+func (b *lxdBackend) CreateCustomVolumeFromClone(projectName string, srcProjectName string, volName string, desc string, config map[string]string, srcVolName string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "srcProjectName": srcProjectName, "volName": volName, "desc": desc, "config": config, "srcVolName": srcVolName})
+	l.Debug("CreateCustomVolumeFromClone started")
+	defer l.Debug("CreateCustomVolumeFromClone finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	if srcProjectName == "" {
+		srcProjectName = projectName
+	}
+
+	if shared.IsSnapshot(srcVolName) {
+		return fmt.Errorf("Clones can only be created from volumes, not snapshots")
+	}
+
+	// Check source volume exists and is custom type, and get its config.
+	srcConfig, err := b.GenerateCustomVolumeBackupConfig(srcProjectName, srcVolName, false, op)
+	if err != nil {
+		return fmt.Errorf("Failed generating volume clone config: %w", err)
+	}
+
+	// Use the source volume's config if not supplied.
+	if config == nil {
+		config = util.CopyConfig(srcConfig.Volume.Config)
+	}
+
+	// Use the source volume's description if not supplied.
+	if desc == "" {
+		desc = srcConfig.Volume.Description
+	}
+
+	contentDBType, err := VolumeContentTypeNameToContentType(srcConfig.Volume.ContentType)
+	if err != nil {
+		return err
+	}
+
+	// Get the source volume's content type.
+	contentType, err := VolumeDBContentTypeToContentType(contentDBType)
+	if err != nil {
+		return err
+	}
+
+	srcVol := b.GetVolume(drivers.VolumeTypeCustom, contentType, project.StorageVolume(srcProjectName, srcVolName), srcConfig.Volume.Config)
+	vol := b.GetVolume(drivers.VolumeTypeCustom, contentType, project.StorageVolume(projectName, volName), config)
+
+	// A clone shares the data of its source, which an encrypted volume can't do with a key of its own.
+	if srcVol.IsEncrypted() {
+		return fmt.Errorf("Encrypted volumes cannot be cloned")
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Validate config and create database entry for new storage volume.
+	err = VolumeDBCreate(b, projectName, volName, desc, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), false, true)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = VolumeDBDelete(b, projectName, volName, vol.Type()) })
+
+	err = b.driver.CloneVolume(vol, srcVol, op)
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotSupported) {
+			return fmt.Errorf("Storage pool %q does not support copy-on-write clones of volumes", b.name)
+		}
+
+		return err
+	}
+
+	revert.Add(func() { _ = b.driver.DeleteVolume(vol, op) })
+
+	// Record the dependency on the source volume.
+	vol.Config()[CloneSourceConfigKey] = fmt.Sprintf("%s/%s", srcProjectName, srcVolName)
+
+	err = b.state.DB.Cluster.UpdateStoragePoolVolume(projectName, volName, db.StoragePoolVolumeTypeCustom, b.ID(), desc, vol.Config())
+	if err != nil {
+		return err
+	}
+
+	eventCtx := logger.Ctx{"type": vol.Type()}
+	if !b.Driver().Info().Remote {
+		eventCtx["location"] = b.state.ServerName
+	}
+
+	err = b.state.Authorizer.AddStoragePoolVolume(b.state.ShutdownCtx, projectName, b.Name(), string(vol.Type()), volName)
+	if err != nil {
+		logger.Error("Failed to add storage volume to authorizer", logger.Ctx{"name": volName, "type": vol.Type(), "pool": b.Name(), "project": projectName, "error": err})
+	}
+
+	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeCreated.Event(vol, string(vol.Type()), projectName, op, eventCtx))
+
+	revert.Success()
+	return nil
+}
+
 // migrationIndexHeaderSend sends the migration index header to target and waits for confirmation of receipt.
 func (b *lxdBackend) migrationIndexHeaderSend(l logger.Logger, indexHeaderVersion uint32, conn io.ReadWriteCloser, info *migration.Info) (*migration.InfoResponse, error) {
 	infoResp := migration.InfoResponse{}
@@ -4863,7 +5319,7 @@ func (b *lxdBackend) CreateCustomVolumeFromMigration(projectName string, conn io
 	if dbVol != nil {
 		volumeConfig = dbVol.Config
 	} else {
-		volumeConfig = args.Config
+		volumeConfig = migrationVolumeConfig(args.Config)
 	}
 
 	// Check if the volume exists on storage.
@@ -4907,6 +5363,11 @@ func (b *lxdBackend) CreateCustomVolumeFromMigration(projectName string, conn io
 		return err
 	}
 
+	err = b.checkCustomVolumeSnapshotsLimit(projectName, len(args.Snapshots))
+	if err != nil {
+		return err
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -4997,6 +5458,16 @@ func (b *lxdBackend) RenameCustomVolume(projectName string, volName string, newV
 		return fmt.Errorf("New volume name cannot be a snapshot")
 	}
 
+	// Clones refer to their source volume by name.
+	clones, err := VolumeClones(b, projectName, volName)
+	if err != nil {
+		return err
+	}
+
+	if len(clones) > 0 {
+		return fmt.Errorf("Cannot rename volume %q as it has clones: %s", volName, volumeClonesDescription(projectName, clones))
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -5034,7 +5505,7 @@ func (b *lxdBackend) RenameCustomVolume(projectName string, volName string, newV
 		backupRow := br // Local var for revert.
 		_, backupName, _ := api.GetParentAndSnapshotName(backupRow.Name)
 		newVolBackupName := drivers.GetSnapshotVolumeName(newVolName, backupName)
-		volBackup := backup.NewVolumeBackup(b.state, projectName, b.name, volName, backupRow.ID, backupRow.Name, backupRow.CreationDate, backupRow.ExpiryDate, backupRow.VolumeOnly, backupRow.OptimizedStorage)
+		volBackup := backup.NewVolumeBackup(b.state, projectName, b.name, volName, backupRow.ID, backupRow.Name, backupRow.CreationDate, backupRow.ExpiryDate, backupRow.VolumeOnly, backupRow.OptimizedStorage, backupRow.Target)
 		err = volBackup.Rename(newVolBackupName)
 		if err != nil {
 			return fmt.Errorf("Failed renaming backup %q to %q: %w", backupRow.Name, newVolBackupName, err)
@@ -5137,6 +5608,24 @@ func (b *lxdBackend) UpdateCustomVolume(projectName string, volName string, newD
 		return err
 	}
 
+	// Keep the wrapped encryption key of encrypted volumes and the source of cloned volumes, they can only be
+	// set by LXD and cannot be changed or removed.
+	for _, key := range []string{drivers.EncryptionKeyConfigKey, CloneSourceConfigKey} {
+		if newConfig[key] != "" && newConfig[key] != curVol.Config[key] {
+			return fmt.Errorf("The %q config key cannot be changed", key)
+		}
+
+		if curVol.Config[key] == "" {
+			continue
+		}
+
+		if newConfig == nil {
+			newConfig = map[string]string{}
+		}
+
+		newConfig[key] = curVol.Config[key]
+	}
+
 	// Validate config.
 	newVol := b.GetVolume(drivers.VolumeTypeCustom, contentType, volStorageName, newConfig)
 	err = b.driver.ValidateVolume(newVol, false)
@@ -5157,6 +5646,12 @@ func (b *lxdBackend) UpdateCustomVolume(projectName string, volName string, newD
 			return fmt.Errorf("Custom volume 'block.filesystem' property cannot be changed")
 		}
 
+		// Check that the volume's block.encryption property isn't being changed.
+		_, found := changedConfig["block.encryption"]
+		if found {
+			return fmt.Errorf("Custom volume 'block.encryption' property cannot be changed")
+		}
+
 		// Check for config changing that is not allowed when running instances are using it.
 		if changedConfig["security.shifted"] != "" {
 			err = VolumeUsedByInstanceDevices(b.state, b.name, projectName, &curVol.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
@@ -5259,6 +5754,16 @@ func (b *lxdBackend) DeleteCustomVolume(projectName string, volName string, op *
 		return fmt.Errorf("Volume name cannot be a snapshot")
 	}
 
+	// Clones share their data with their source volume.
+	clones, err := VolumeClones(b, projectName, volName)
+	if err != nil {
+		return err
+	}
+
+	if len(clones) > 0 {
+		return fmt.Errorf("Cannot delete volume %q as it has clones: %s", volName, volumeClonesDescription(projectName, clones))
+	}
+
 	// Retrieve a list of snapshots.
 	snapshots, err := VolumeDBSnapshotsGet(b, projectName, volName, drivers.VolumeTypeCustom)
 	if err != nil {
@@ -6677,7 +7182,7 @@ func (b *lxdBackend) BackupCustomVolume(projectName string, volName string, tarW
 
 	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), volStorageName, volume.Config)
 
-	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, op)
+	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, "", op)
 	if err != nil {
 		return err
 	}
@@ -6791,6 +7296,11 @@ func (b *lxdBackend) CreateCustomVolumeFromBackup(srcBackup backup.Info, srcData
 		return fmt.Errorf("Failed checking volume creation allowed: %w", err)
 	}
 
+	err = b.checkCustomVolumeSnapshotsLimit(srcBackup.Project, len(srcBackup.Config.VolumeSnapshots))
+	if err != nil {
+		return err
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
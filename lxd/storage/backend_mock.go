@@ -116,6 +116,10 @@ func (b *mockBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io
 	return nil, nil, nil
 }
 
+func (b *mockBackend) RefreshInstanceFromBackup(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) CreateInstanceFromCopy(inst instance.Instance, src instance.Instance, snapshots bool, allowInconsistent bool, op *operations.Operation) error {
 	return nil
 }
@@ -172,6 +176,14 @@ func (b *mockBackend) CleanupInstancePaths(inst instance.Instance, op *operation
 	return nil
 }
 
+func (b *mockBackend) MirrorInstance(inst instance.Instance, srcPool Pool, mirror func(diskPath string) error, op *operations.Operation) error {
+	return nil
+}
+
+func (b *mockBackend) DeleteInstanceMirrorSource(inst instance.Instance, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) RefreshCustomVolume(projectName string, srcProjectName string, volName string, desc string, config map[string]string, srcPoolName, srcVolName string, srcVolOnly bool, op *operations.Operation) error {
 	return nil
 }
@@ -180,7 +192,7 @@ func (b *mockBackend) RefreshInstance(inst instance.Instance, src instance.Insta
 	return nil
 }
 
-func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, op *operations.Operation) error {
+func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, baseSnapshot string, op *operations.Operation) error {
 	return nil
 }
 
@@ -284,6 +296,10 @@ func (b *mockBackend) CreateCustomVolumeFromCopy(projectName string, srcProjectN
 	return nil
 }
 
+func (b *mockBackend) CreateCustomVolumeFromClone(projectName string, srcProjectName string, volName string, desc string, config map[string]string, srcVolName string, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) RenameCustomVolume(projectName string, volName string, newName string, op *operations.Operation) error {
 	return nil
 }
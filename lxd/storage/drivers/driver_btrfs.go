@@ -94,6 +94,7 @@ func (d *btrfs) Info() Info {
 		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
 		OptimizedImages:              true,
 		OptimizedBackups:             true,
+		OptimizedIncrementalBackups:  true,
 		OptimizedBackupHeader:        true,
 		PreservesInodes:              !d.state.OS.RunningInUserNS,
 		Remote:                       d.isRemote(),
@@ -152,7 +152,7 @@ func (d *btrfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Op
 func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
 	// Handle the non-optimized tarballs through the generic unpacker.
 	if !*srcBackup.OptimizedStorage {
-		return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+		return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcBackup.BaseSnapshot, srcData, op)
 	}
 
 	volExists, err := d.HasVolume(vol)
@@ -160,7 +160,12 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 		return nil, nil, err
 	}
 
-	if volExists {
+	// Incremental backups are received on top of the existing volume, whose latest snapshot must be the base
+	// snapshot of the backup.
+	incremental := srcBackup.BaseSnapshot != ""
+	if incremental && !volExists {
+		return nil, nil, fmt.Errorf("Cannot restore incremental backup, volume doesn't exist on target")
+	} else if !incremental && volExists {
 		return nil, nil, fmt.Errorf("Cannot restore volume, already exists on target")
 	}
 
@@ -176,8 +181,10 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 			_ = d.DeleteVolumeSnapshot(snapVol, op)
 		}
 
-		// And lastly the main volume.
-		_ = d.DeleteVolume(vol, op)
+		// And lastly the main volume (unless it existed before).
+		if !incremental {
+			_ = d.DeleteVolume(vol, op)
+		}
 	}
 	// Only execute the revert function if we have had an error internally.
 	revert.Add(revertHook)
@@ -364,6 +371,38 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 		return nil, nil, err
 	}
 
+	// The main volume of an incremental backup replaces the existing one, so move the existing one out of
+	// the way until the unpacked one is in place.
+	previousVolPath := ""
+	if incremental {
+		tmpPreviousDir, err := os.MkdirTemp(GetVolumeMountPath(d.name, vol.volType, ""), "backup.")
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to create temporary directory: %w", err)
+		}
+
+		err = os.Chmod(tmpPreviousDir, 0100)
+		if err != nil {
+			_ = os.Remove(tmpPreviousDir)
+			return nil, nil, fmt.Errorf("Failed to chmod temporary directory %q: %w", tmpPreviousDir, err)
+		}
+
+		previousVolPath = filepath.Join(tmpPreviousDir, "previous")
+		err = os.Rename(vol.MountPath(), previousVolPath)
+		if err != nil {
+			_ = os.Remove(tmpPreviousDir)
+			return nil, nil, fmt.Errorf("Failed moving existing volume %q: %w", vol.MountPath(), err)
+		}
+
+		revert.Add(func() {
+			if d.isSubvolume(vol.MountPath()) {
+				_ = d.deleteSubvolume(vol.MountPath(), true)
+			}
+
+			_ = os.Rename(previousVolPath, vol.MountPath())
+			_ = os.Remove(tmpPreviousDir)
+		})
+	}
+
 	for _, copyOp := range copyOps {
 		err = d.setSubvolumeReadonlyProperty(copyOp.src, false)
 		if err != nil {
@@ -399,6 +438,16 @@ func (d *btrfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcDat
 		}
 	}
 
+	// Remove the volume replaced by the incremental backup.
+	if previousVolPath != "" {
+		err = d.deleteSubvolume(previousVolPath, true)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed deleting replaced volume %q: %w", previousVolPath, err)
+		}
+
+		_ = os.Remove(filepath.Dir(previousVolPath))
+	}
+
 	revert.Success()
 	return nil, revertHook, nil
 }
@@ -499,6 +548,12 @@ func (d *btrfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bo
 	return nil
 }
 
+// CloneVolume creates a copy-on-write clone of a volume.
+func (d *btrfs) CloneVolume(vol Volume, srcVol Volume, op *operations.Operation) error {
+	// Copies of volumes are already subvolume snapshots which share their data with the source.
+	return d.CreateVolumeFromCopy(vol, srcVol, false, false, op)
+}
+
 // CreateVolumeFromMigration creates a volume being sent via a migration.
 func (d *btrfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
 	// Handle simple rsync and block_and_rsync through generic.
@@ -1484,8 +1539,7 @@ func (d *btrfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volS
 }
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
-// This driver does not support optimized backups.
-func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, op *operations.Operation) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -1504,12 +1558,18 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 			vol.mountCustomPath = snapshotPath
 		}
 
-		return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+		return genericVFSBackupVolume(d, vol, tarWriter, snapshots, baseSnapshot, op)
 	}
 
 	// Optimized backup.
 
-	if len(snapshots) > 0 {
+	if baseSnapshot != "" {
+		// Check the base snapshot and requested snapshots exist in storage.
+		err := vol.SnapshotsExist(append([]string{baseSnapshot}, snapshots...), op)
+		if err != nil {
+			return err
+		}
+	} else if len(snapshots) > 0 {
 		// Check requested snapshot match those in storage.
 		err := vol.SnapshotsMatch(snapshots, op)
 		if err != nil {
@@ -1658,7 +1718,13 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 	}
 
 	// Backup snapshots if populated.
+	// For incremental backups, the first subvolume is sent relative to the base snapshot.
 	lastVolPath := "" // Used as parent for differential exports.
+	if baseSnapshot != "" {
+		baseVol, _ := vol.NewSnapshot(baseSnapshot)
+		lastVolPath = baseVol.MountPath()
+	}
+
 	for _, snapName := range snapshots {
 		snapVol, _ := vol.NewSnapshot(snapName)
 
@@ -110,6 +110,9 @@ func (d *ceph) rbdCreateVolume(vol Volume, size string) error {
 		return err
 	}
 
+	// Leave room for the LUKS header on encrypted volumes.
+	sizeBytes = luksRawSize(vol, sizeBytes)
+
 	cmd := []string{
 		"--id", d.config["ceph.user.name"],
 		"--cluster", d.config["ceph.cluster_name"],
@@ -187,6 +190,14 @@ func (d *ceph) rbdMapVolume(vol Volume) (string, error) {
 // rbdUnmapVolume unmaps a given RBD storage volume.
 // This is a precondition in order to delete an RBD storage volume can.
 func (d *ceph) rbdUnmapVolume(vol Volume, unmapUntilEINVAL bool) error {
+	// Close the LUKS device first as it holds the RBD device open.
+	if vol.IsEncrypted() {
+		_, err := d.luksClose(vol)
+		if err != nil {
+			return err
+		}
+	}
+
 	busyCount := 0
 	rbdVol := d.getRBDVolumeName(vol, "", false, false)
 
@@ -160,6 +160,11 @@ func (d *ceph) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Ope
 		if err != nil {
 			return err
 		}
+	} else if vol.IsEncrypted() {
+		err = d.luksFormat(vol, devPath)
+		if err != nil {
+			return err
+		}
 	}
 
 	// For VMs, also create the filesystem volume.
@@ -315,12 +320,38 @@ func (d *ceph) getVolumeSize(volumeName string) (int64, error) {
 
 // CreateVolumeFromBackup re-creates a volume from its exported state.
 func (d *ceph) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
-	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcBackup.BaseSnapshot, srcData, op)
 }
 
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	return d.createVolumeFromCopy(vol, srcVol, copySnapshots, allowInconsistent, false, op)
+}
+
+// CloneVolume creates a copy-on-write clone of a volume using an RBD clone, regardless of ceph.rbd.clone_copy.
+func (d *ceph) CloneVolume(vol Volume, srcVol Volume, op *operations.Operation) error {
+	return d.createVolumeFromCopy(vol, srcVol, false, false, true, op)
+}
+
+// createVolumeFromCopy copies a volume within the pool. If clone is true, the new volume is always created as a
+// clone of a snapshot of the source volume.
+func (d *ceph) createVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, clone bool, op *operations.Operation) error {
 	var err error
+
+	// Encrypted volumes are copied through their decrypted devices, as the copy may not share the key of the source.
+	if vol.IsEncrypted() || srcVol.IsEncrypted() {
+		var srcSnapshots []Volume
+
+		if copySnapshots && !srcVol.IsSnapshot() {
+			srcSnapshots, err = srcVol.Snapshots(op)
+			if err != nil {
+				return err
+			}
+		}
+
+		return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -366,7 +397,7 @@ func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots boo
 	if vol.IsVMBlock() {
 		srcFSVol := srcVol.NewVMBlockFilesystemVolume()
 		fsVol := vol.NewVMBlockFilesystemVolume()
-		err := d.CreateVolumeFromCopy(fsVol, srcFSVol, copySnapshots, false, op)
+		err := d.createVolumeFromCopy(fsVol, srcFSVol, copySnapshots, false, clone, op)
 		if err != nil {
 			return err
 		}
@@ -386,8 +417,8 @@ func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots boo
 
 	// Copy without snapshots.
 	if !copySnapshots || len(snapshots) == 0 {
-		// If lightweight clone mode isn't enabled, perform a full copy of the volume.
-		if shared.IsFalse(d.config["ceph.rbd.clone_copy"]) {
+		// If lightweight clone mode isn't enabled, perform a full copy of the volume (unless cloning).
+		if !clone && shared.IsFalse(d.config["ceph.rbd.clone_copy"]) {
 			_, err = shared.RunCommand(
 				"rbd",
 				"--id", d.config["ceph.user.name"],
@@ -833,7 +864,17 @@ func (d *ceph) FillVolumeConfig(vol Volume) error {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *ceph) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
-		// lxdmeta:generate(entities=storage-ceph,storage-lvm; group=volume-conf; key=block.filesystem)
+		// lxdmeta:generate(entities=storage-ceph,storage-dir,storage-lvm,storage-zfs; group=volume-conf; key=block.encryption)
+		// The only supported value is `luks2`.
+		// If set, the volume is encrypted with a random key of its own, which LXD stores wrapped with a key kept in the LXD database.
+		// Encryption can't be enabled or disabled after the volume is created.
+		// ---
+		//  type: string
+		//  condition: virtual machine and custom volumes with content type `block`
+		//  defaultdesc: same as `volume.block.encryption`
+		//  shortdesc: Encrypt the storage volume with LUKS
+		"block.encryption": validate.Optional(validate.IsOneOf("luks2")),
+		// lxdmeta:generate(entities=storage-ceph,storage-lun,storage-lvm; group=volume-conf; key=block.filesystem)
 		// Valid options are: `btrfs`, `ext4`, `xfs`
 		// If not set, `ext4` is assumed.
 		// ---
@@ -842,7 +883,7 @@ func (d *ceph) commonVolumeRules() map[string]func(value string) error {
 		//  defaultdesc: same as `volume.block.filesystem`
 		//  shortdesc: File system of the storage volume
 		"block.filesystem": validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
-		// lxdmeta:generate(entities=storage-ceph,storage-lvm; group=volume-conf; key=block.mount_options)
+		// lxdmeta:generate(entities=storage-ceph,storage-lun,storage-lvm; group=volume-conf; key=block.mount_options)
 		//
 		// ---
 		//  type: string
@@ -855,7 +896,12 @@ func (d *ceph) commonVolumeRules() map[string]func(value string) error {
 
 // ValidateVolume validates the supplied volume config.
 func (d *ceph) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
-	return d.validateVolume(vol, d.commonVolumeRules(), removeUnknownKeys)
+	err := d.validateVolume(vol, d.commonVolumeRules(), removeUnknownKeys)
+	if err != nil {
+		return err
+	}
+
+	return luksValidateVolume(vol)
 }
 
 // UpdateVolume applies config changes to the volume.
@@ -970,6 +1016,9 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 		return nil
 	}
 
+	// The RBD volume of an encrypted volume also holds the LUKS header.
+	sizeBytes = luksRawSize(vol, sizeBytes)
+
 	ourMap, devPath, err := d.getRBDMappedDevPath(vol, true)
 	if err != nil {
 		return err
@@ -1055,9 +1104,21 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 			return err
 		}
 
+		if vol.IsEncrypted() {
+			err = d.luksResize(vol)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
 		// expected the caller will do all necessary post resize actions themselves).
 		if vol.IsVMBlock() && !allowUnsafeResize {
+			devPath, err := d.GetVolumeDiskPath(vol)
+			if err != nil {
+				return err
+			}
+
 			err = d.moveGPTAltHeader(devPath)
 			if err != nil {
 				return err
@@ -1072,7 +1133,11 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 func (d *ceph) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
 		_, devPath, err := d.getRBDMappedDevPath(vol, false)
-		return devPath, err
+		if err != nil || !vol.IsEncrypted() {
+			return devPath, err
+		}
+
+		return d.luksOpen(vol, devPath)
 	}
 
 	return "", ErrNotSupported
@@ -1376,6 +1441,11 @@ func (d *ceph) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *mi
 		return ErrNotSupported
 	}
 
+	// Encrypted volumes are sent decrypted so that the target can encrypt them with a key of its own.
+	if vol.IsEncrypted() {
+		return fmt.Errorf("Encrypted volumes cannot be migrated using rbd export-diff/import-diff")
+	}
+
 	// Handle rbd export-diff/import-diff migration.
 	if volSrcArgs.MultiSync || volSrcArgs.FinalSync {
 		// This is not needed if the migration is performed using rbd export-diff/import-diff.
@@ -1471,8 +1541,8 @@ func (d *ceph) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *mi
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *ceph) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
-	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+func (d *ceph) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, baseSnapshot, op)
 }
 
 // CreateVolumeSnapshot creates a snapshot of a volume.
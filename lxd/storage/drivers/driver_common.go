@@ -123,6 +123,11 @@ func (d *common) fillVolumeConfig(vol *Volume, excludedKeys ...string) error {
 			continue
 		}
 
+		// block.encryption is only relevant for instance and custom block volumes.
+		if (vol.contentType != ContentTypeBlock || vol.volType == VolumeTypeImage) && volKey == "block.encryption" {
+			continue
+		}
+
 		if vol.config[volKey] == "" {
 			vol.config[volKey] = d.config[k]
 		}
@@ -327,6 +332,11 @@ func (d *common) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots b
 	return ErrNotSupported
 }
 
+// CloneVolume creates a copy-on-write clone of an existing storage volume.
+func (d *common) CloneVolume(vol Volume, srcVol Volume, op *operations.Operation) error {
+	return ErrNotSupported
+}
+
 // CreateVolumeFromMigration creates a new volume (with or without snapshots) from a migration data stream.
 func (d *common) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
 	return ErrNotSupported
@@ -409,7 +419,7 @@ func (d *common) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *common) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *common) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, op *operations.Operation) error {
 	return ErrNotSupported
 }
 
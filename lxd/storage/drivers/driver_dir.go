@@ -112,7 +112,7 @@ func (d *dir) Delete(op *operations.Operation) error {
 
 // Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
 func (d *dir) Validate(config map[string]string) error {
-	return d.validatePool(config, nil, nil)
+	return d.validatePool(config, nil, d.commonVolumeRules())
 }
 
 // Update applies any driver changes required from a configuration change.
@@ -2,8 +2,12 @@ package drivers
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/canonical/lxd/lxd/storage/quota"
+	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/units"
@@ -135,3 +139,76 @@ func (d *dir) setQuota(path string, volID int64, sizeBytes int64) error {
 	// Set the project quota size.
 	return quota.SetProjectQuota(path, projectID, sizeBytes)
 }
+
+// openEncryptedVolume opens the LUKS device stored in the disk image file of an encrypted volume and returns the
+// path of the decrypted device. If the disk image file doesn't exist yet, it is created and formatted first.
+func (d *dir) openEncryptedVolume(vol Volume) (string, error) {
+	mapperPath := filepath.Join("/dev/mapper", d.luksMapperName(vol))
+	if shared.PathExists(mapperPath) {
+		return mapperPath, nil
+	}
+
+	rootBlockPath, err := genericVFSGetVolumeDiskPath(vol)
+	if err != nil {
+		return "", err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	format := !shared.PathExists(rootBlockPath)
+	if format {
+		sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+		if err != nil {
+			return "", err
+		}
+
+		_, err = ensureVolumeBlockFile(vol, rootBlockPath, luksRawSize(vol, sizeBytes), false)
+		if err != nil {
+			return "", err
+		}
+
+		revert.Add(func() { _ = os.Remove(rootBlockPath) })
+	}
+
+	loopDevPath, err := loopDeviceSetup(rootBlockPath)
+	if err != nil {
+		return "", err
+	}
+
+	// Have the loop device detached once the LUKS device is closed.
+	defer func() { _ = loopDeviceAutoDetach(loopDevPath) }()
+
+	if format {
+		err = d.luksFormat(vol, loopDevPath)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	mapperPath, err = d.luksOpen(vol, loopDevPath)
+	if err != nil {
+		return "", err
+	}
+
+	revert.Success()
+	return mapperPath, nil
+}
+
+// resizeEncryptedVolume makes the opened LUKS device of an encrypted volume pick up the new size of its disk
+// image file.
+func (d *dir) resizeEncryptedVolume(vol Volume, rootBlockPath string) error {
+	out, err := shared.RunCommand("losetup", "--associated", rootBlockPath, "--noheadings", "--output", "NAME")
+	if err != nil {
+		return err
+	}
+
+	for _, loopDevPath := range strings.Fields(out) {
+		err = loopDeviceSetCapacity(loopDevPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.luksResize(vol)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/units"
+	"github.com/canonical/lxd/shared/validate"
 )
 
 // CreateVolume creates an empty volume and can optionally fill it by executing the supplied
@@ -49,6 +50,10 @@ func (d *dir) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 		if err != nil {
 			return err
 		}
+
+		if vol.IsEncrypted() {
+			defer func() { _, _ = d.luksClose(vol) }()
+		}
 	} else if vol.volType != VolumeTypeBucket {
 		// Filesystem quotas only used with non-block volume types.
 		revertFunc, err := d.setupInitialQuota(vol)
@@ -79,7 +84,12 @@ func (d *dir) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 
 		// Ignore ErrCannotBeShrunk when setting size this just means the filler run above has needed to
 		// increase the volume size beyond the default block volume size.
-		_, err = ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, false)
+		if vol.IsEncrypted() {
+			err = d.SetVolumeQuota(vol, vol.ConfigSize(), false, op)
+		} else {
+			_, err = ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, false)
+		}
+
 		if err != nil && !errors.Is(err, ErrCannotBeShrunk) {
 			return err
 		}
@@ -100,7 +110,7 @@ func (d *dir) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 // CreateVolumeFromBackup restores a backup tarball onto the storage device.
 func (d *dir) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
 	// Run the generic backup unpacker
-	postHook, revertHook, err := genericVFSBackupUnpack(d.withoutGetVolID(), d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+	postHook, revertHook, err := genericVFSBackupUnpack(d.withoutGetVolID(), d.state.OS, vol, srcBackup.Snapshots, srcBackup.BaseSnapshot, srcData, op)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -235,9 +245,16 @@ func (d *dir) FillVolumeConfig(vol Volume) error {
 	return nil
 }
 
+// commonVolumeRules returns validation rules which are common for pool and volume.
+func (d *dir) commonVolumeRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		"block.encryption": validate.Optional(validate.IsOneOf("luks2")),
+	}
+}
+
 // ValidateVolume validates the supplied volume config. Optionally removes invalid keys from the volume's config.
 func (d *dir) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
-	err := d.validateVolume(vol, nil, removeUnknownKeys)
+	err := d.validateVolume(vol, d.commonVolumeRules(), removeUnknownKeys)
 	if err != nil {
 		return err
 	}
@@ -246,7 +263,7 @@ func (d *dir) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		return fmt.Errorf("Size cannot be specified for buckets")
 	}
 
-	return nil
+	return luksValidateVolume(vol)
 }
 
 // UpdateVolume applies config changes to the volume.
@@ -308,20 +325,35 @@ func (d *dir) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			return nil
 		}
 
-		rootBlockPath, err := d.GetVolumeDiskPath(vol)
+		rootBlockPath, err := genericVFSGetVolumeDiskPath(vol)
 		if err != nil {
 			return err
 		}
 
-		resized, err := ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, allowUnsafeResize)
+		// Make room for the LUKS header of encrypted volumes.
+		resized, err := ensureVolumeBlockFile(vol, rootBlockPath, luksRawSize(vol, sizeBytes), allowUnsafeResize)
 		if err != nil {
 			return err
 		}
 
+		if resized && vol.IsEncrypted() {
+			err = d.resizeEncryptedVolume(vol, rootBlockPath)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Move the GPT alt header to end of disk if needed and resize has taken place (not needed in
 		// unsafe resize mode as it is expected the caller will do all necessary post resize actions
 		// themselves).
 		if vol.IsVMBlock() && resized && !allowUnsafeResize {
+			if vol.IsEncrypted() {
+				rootBlockPath, err = d.GetVolumeDiskPath(vol)
+				if err != nil {
+					return err
+				}
+			}
+
 			err = d.moveGPTAltHeader(rootBlockPath)
 			if err != nil {
 				return err
@@ -358,6 +390,10 @@ func (d *dir) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 
 // GetVolumeDiskPath returns the location of a disk volume.
 func (d *dir) GetVolumeDiskPath(vol Volume) (string, error) {
+	if vol.IsEncrypted() {
+		return d.openEncryptedVolume(vol)
+	}
+
 	return genericVFSGetVolumeDiskPath(vol)
 }
 
@@ -404,6 +440,11 @@ func (d *dir) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operat
 		return false, ErrInUse
 	}
 
+	// Close the LUKS device of encrypted volumes.
+	if vol.IsEncrypted() && !keepBlockDev {
+		return d.luksClose(vol)
+	}
+
 	return false, nil
 }
 
@@ -419,8 +460,8 @@ func (d *dir) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *mig
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *dir) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
-	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+func (d *dir) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, baseSnapshot, op)
 }
 
 // CreateVolumeSnapshot creates a snapshot of a volume.
@@ -458,13 +499,14 @@ func (d *dir) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation) err
 	}
 
 	if snapVol.IsVMBlock() || (snapVol.contentType == ContentTypeBlock && snapVol.volType == VolumeTypeCustom) {
+		// Encrypted volumes are copied as is, as the snapshot shares the key of its parent.
 		parentVol := NewVolume(d, d.name, snapVol.volType, snapVol.contentType, parentName, nil, d.config)
-		srcDevPath, err := d.GetVolumeDiskPath(parentVol)
+		srcDevPath, err := genericVFSGetVolumeDiskPath(parentVol)
 		if err != nil {
 			return err
 		}
 
-		targetDevPath, err := d.GetVolumeDiskPath(snapVol)
+		targetDevPath, err := genericVFSGetVolumeDiskPath(snapVol)
 		if err != nil {
 			return err
 		}
@@ -556,6 +598,14 @@ func (d *dir) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (b
 			return false, ErrInUse
 		}
 
+		// Close the LUKS device of encrypted snapshots.
+		if snapVol.IsEncrypted() {
+			_, err = d.luksClose(snapVol)
+			if err != nil {
+				return false, err
+			}
+		}
+
 		snapPath := snapVol.MountPath()
 		return forceUnmount(snapPath)
 	}
@@ -599,12 +649,13 @@ func (d *dir) RestoreVolume(vol Volume, snapshotName string, op *operations.Oper
 
 	// Restore block volume.
 	if vol.IsVMBlock() || (vol.contentType == ContentTypeBlock && vol.volType == VolumeTypeCustom) {
-		srcDevPath, err := d.GetVolumeDiskPath(snapVol)
+		// Encrypted volumes are copied as is, as the snapshot shares the key of its parent.
+		srcDevPath, err := genericVFSGetVolumeDiskPath(snapVol)
 		if err != nil {
 			return err
 		}
 
-		targetDevPath, err := d.GetVolumeDiskPath(vol)
+		targetDevPath, err := genericVFSGetVolumeDiskPath(vol)
 		if err != nil {
 			return err
 		}
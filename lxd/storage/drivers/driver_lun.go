@@ -0,0 +1,242 @@
+package drivers
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+var lunLoaded bool
+
+// lun is a remote driver storing each volume on a LUN mapped from an iSCSI or NVMe over TCP target.
+// The LUNs must be provisioned on the target beforehand. LXD claims a free LUN for each new volume and
+// records the ownership in a GPT partition table on it, so that all cluster members agree on which LUN
+// holds which volume.
+type lun struct {
+	common
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *lun) load() error {
+	// Register the patches.
+	d.patches = map[string]func() error{
+		"storage_lvm_skipactivation":                         nil,
+		"storage_missing_snapshot_records":                   nil,
+		"storage_delete_old_snapshot_records":                nil,
+		"storage_zfs_drop_block_volume_filesystem_extension": nil,
+		"storage_prefix_bucket_names_with_project":           nil,
+	}
+
+	// Done if previously loaded.
+	if lunLoaded {
+		return nil
+	}
+
+	// Validate the required binaries.
+	for _, tool := range []string{"sgdisk", "partx", "wipefs"} {
+		_, err := exec.LookPath(tool)
+		if err != nil {
+			return fmt.Errorf("Required tool '%s' is missing", tool)
+		}
+	}
+
+	lunLoaded = true
+	return nil
+}
+
+// isRemote returns true indicating this driver uses remote storage.
+func (d *lun) isRemote() bool {
+	return true
+}
+
+// Info returns info about the driver and its environment.
+func (d *lun) Info() Info {
+	return Info{
+		Name:                         "lun",
+		Version:                      "1",
+		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		BlockBacking:                 true,
+		RunningCopyFreeze:            true,
+		DirectIO:                     true,
+		IOUring:                      true,
+		MountedRoot:                  false,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *lun) FillConfig() error {
+	if d.config["lun.mode"] == "" {
+		d.config["lun.mode"] = "iscsi"
+	}
+
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *lun) Create() error {
+	err := d.FillConfig()
+	if err != nil {
+		return err
+	}
+
+	// Config validation.
+	if d.config["lun.target.address"] == "" {
+		return fmt.Errorf(`The "lun.target.address" property must be set`)
+	}
+
+	if d.config["lun.target.name"] == "" {
+		return fmt.Errorf(`The "lun.target.name" property must be set`)
+	}
+
+	// Check that the target can be reached.
+	connected, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	if connected {
+		defer func() { _, _ = d.disconnect() }()
+	}
+
+	devices, err := d.devices()
+	if err != nil {
+		return err
+	}
+
+	if len(devices) == 0 {
+		return fmt.Errorf("The target %q doesn't expose any LUN", d.config["lun.target.name"])
+	}
+
+	// Refuse to take over LUNs still holding volumes of a previous pool with the same name.
+	for _, device := range devices {
+		for _, partition := range device.Partitions {
+			if strings.HasPrefix(partition.Name, d.poolLabelPrefix()) {
+				return fmt.Errorf("LUN %q already holds volumes of a storage pool named %q", device.Path, d.name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the storage pool from the storage device.
+func (d *lun) Delete(op *operations.Operation) error {
+	_, err := d.connect()
+	if err != nil {
+		return err
+	}
+
+	devices, err := d.devices()
+	if err != nil {
+		return err
+	}
+
+	// Release any LUN left behind by the pool.
+	for _, device := range devices {
+		for _, partition := range device.Partitions {
+			if strings.HasPrefix(partition.Name, d.poolLabelPrefix()) {
+				d.releaseDevice(device.Path, device.Partitions)
+				break
+			}
+		}
+	}
+
+	// On delete, wipe everything in the directory.
+	err = wipeDirectory(GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	_, err = d.disconnect()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
+func (d *lun) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-lun; group=pool-conf; key=lun.mode)
+		// Possible values are `iscsi` and `nvme` (NVMe over TCP).
+		// ---
+		//  type: string
+		//  defaultdesc: `iscsi`
+		//  shortdesc: Protocol used to access the target
+		"lun.mode": validate.Optional(validate.IsOneOf("iscsi", "nvme")),
+		// lxdmeta:generate(entities=storage-lun; group=pool-conf; key=lun.target.address)
+		// Specify the address of the target portal, optionally followed by the port.
+		// If no port is specified, the default port of the protocol is used (`3260` for iSCSI and `4420` for NVMe over TCP).
+		// ---
+		//  type: string
+		//  shortdesc: Address of the target
+		"lun.target.address": validate.Optional(validate.IsListenAddress(true, false, false)),
+		// lxdmeta:generate(entities=storage-lun; group=pool-conf; key=lun.target.name)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: IQN of the iSCSI target or NQN of the NVMe subsystem
+		"lun.target.name": validate.IsAny,
+	}
+
+	return d.validatePool(config, rules, d.commonVolumeRules())
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *lun) Update(changedConfig map[string]string) error {
+	for _, key := range []string{"lun.mode", "lun.target.address", "lun.target.name"} {
+		_, changed := changedConfig[key]
+		if changed {
+			return fmt.Errorf("Config key %q cannot be changed", key)
+		}
+	}
+
+	return nil
+}
+
+// Mount connects to the target.
+func (d *lun) Mount() (bool, error) {
+	return d.connect()
+}
+
+// Unmount disconnects from the target.
+func (d *lun) Unmount() (bool, error) {
+	return d.disconnect()
+}
+
+// GetResources returns the pool resource usage information.
+// As every volume uses a LUN of its own, a LUN is considered used as soon as it has been claimed.
+func (d *lun) GetResources() (*api.ResourcesStoragePool, error) {
+	devices, err := d.devices()
+	if err != nil {
+		return nil, err
+	}
+
+	res := api.ResourcesStoragePool{}
+
+	for _, device := range devices {
+		sizeBytes, err := BlockDiskSizeBytes(device.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Space.Total += uint64(sizeBytes)
+
+		if len(device.Partitions) > 0 {
+			res.Space.Used += uint64(sizeBytes)
+		}
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,685 @@
+package drivers
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+)
+
+// lunPartitionLabelPrefix is the prefix of the GPT partition names created by the lun driver.
+const lunPartitionLabelPrefix = "lxd_"
+
+// lunNVMeNamespaceRegex matches the name of NVMe namespace block devices (excluding per-path devices).
+var lunNVMeNamespaceRegex = regexp.MustCompile(`^nvme[0-9]+n[0-9]+$`)
+
+// lunPartition represents a GPT partition on a LUN.
+type lunPartition struct {
+	Number int
+	Start  int64
+	End    int64
+	Name   string
+}
+
+// lunDevice represents a LUN mapped from the target along with its GPT partitions.
+type lunDevice struct {
+	Path       string
+	Partitions []lunPartition
+}
+
+// mode returns the transport used to access the target.
+func (d *lun) mode() string {
+	if d.config["lun.mode"] == "" {
+		return "iscsi"
+	}
+
+	return d.config["lun.mode"]
+}
+
+// targetPortal returns the host and port of the target.
+func (d *lun) targetPortal() (string, string) {
+	host, port, err := net.SplitHostPort(d.config["lun.target.address"])
+	if err != nil {
+		host = strings.Trim(d.config["lun.target.address"], "[]")
+		port = ""
+	}
+
+	if port == "" {
+		if d.mode() == "nvme" {
+			port = "4420"
+		} else {
+			port = "3260"
+		}
+	}
+
+	return host, port
+}
+
+// connected checks whether this host is connected to the target.
+func (d *lun) connected() (bool, error) {
+	if d.mode() == "nvme" {
+		controllers, err := lunNVMeControllers(d.config["lun.target.name"])
+		if err != nil {
+			return false, err
+		}
+
+		return len(controllers) > 0, nil
+	}
+
+	sessions, err := filepath.Glob("/sys/class/iscsi_session/session*/targetname")
+	if err != nil {
+		return false, err
+	}
+
+	for _, session := range sessions {
+		targetName, err := os.ReadFile(session)
+		if err != nil {
+			continue
+		}
+
+		if strings.TrimSpace(string(targetName)) == d.config["lun.target.name"] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// connect logs into the target if not already connected. Returns true if a new connection was made.
+func (d *lun) connect() (bool, error) {
+	isConnected, err := d.connected()
+	if err != nil {
+		return false, err
+	}
+
+	if isConnected {
+		return false, nil
+	}
+
+	host, port := d.targetPortal()
+
+	if d.mode() == "nvme" {
+		_, err := exec.LookPath("nvme")
+		if err != nil {
+			return false, fmt.Errorf("Required tool 'nvme' is missing")
+		}
+
+		_, err = shared.RunCommand("nvme", "connect", "--transport=tcp", fmt.Sprintf("--traddr=%s", host), fmt.Sprintf("--trsvcid=%s", port), fmt.Sprintf("--nqn=%s", d.config["lun.target.name"]))
+		if err != nil {
+			return false, fmt.Errorf("Failed to connect to NVMe target %q: %w", d.config["lun.target.name"], err)
+		}
+
+		return true, nil
+	}
+
+	_, err = exec.LookPath("iscsiadm")
+	if err != nil {
+		return false, fmt.Errorf("Required tool 'iscsiadm' is missing")
+	}
+
+	portal := net.JoinHostPort(host, port)
+
+	_, err = shared.RunCommand("iscsiadm", "--mode", "discovery", "--type", "sendtargets", "--portal", portal)
+	if err != nil {
+		return false, fmt.Errorf("Failed to discover iSCSI targets on %q: %w", portal, err)
+	}
+
+	_, err = shared.RunCommand("iscsiadm", "--mode", "node", "--targetname", d.config["lun.target.name"], "--portal", portal, "--login")
+	if err != nil {
+		return false, fmt.Errorf("Failed to log into iSCSI target %q: %w", d.config["lun.target.name"], err)
+	}
+
+	return true, nil
+}
+
+// disconnect logs out of the target if connected. Returns true if a connection was closed.
+func (d *lun) disconnect() (bool, error) {
+	isConnected, err := d.connected()
+	if err != nil {
+		return false, err
+	}
+
+	if !isConnected {
+		return false, nil
+	}
+
+	if d.mode() == "nvme" {
+		_, err = shared.RunCommand("nvme", "disconnect", fmt.Sprintf("--nqn=%s", d.config["lun.target.name"]))
+		if err != nil {
+			return false, fmt.Errorf("Failed to disconnect from NVMe target %q: %w", d.config["lun.target.name"], err)
+		}
+
+		return true, nil
+	}
+
+	host, port := d.targetPortal()
+	_, err = shared.RunCommand("iscsiadm", "--mode", "node", "--targetname", d.config["lun.target.name"], "--portal", net.JoinHostPort(host, port), "--logout")
+	if err != nil {
+		return false, fmt.Errorf("Failed to log out of iSCSI target %q: %w", d.config["lun.target.name"], err)
+	}
+
+	return true, nil
+}
+
+// rescan asks the initiator to look for LUNs that were added to the target since the connection was made.
+func (d *lun) rescan() error {
+	if d.mode() == "nvme" {
+		controllers, err := lunNVMeControllers(d.config["lun.target.name"])
+		if err != nil {
+			return err
+		}
+
+		for _, controller := range controllers {
+			_, err = shared.RunCommand("nvme", "ns-rescan", filepath.Join("/dev", controller))
+			if err != nil {
+				return fmt.Errorf("Failed to rescan NVMe controller %q: %w", controller, err)
+			}
+		}
+
+		return nil
+	}
+
+	host, port := d.targetPortal()
+	_, err := shared.RunCommand("iscsiadm", "--mode", "node", "--targetname", d.config["lun.target.name"], "--portal", net.JoinHostPort(host, port), "--rescan")
+	if err != nil {
+		return fmt.Errorf("Failed to rescan iSCSI target %q: %w", d.config["lun.target.name"], err)
+	}
+
+	return nil
+}
+
+// lunNVMeControllers returns the names of the NVMe controllers connected to the subsystem with the given NQN.
+func lunNVMeControllers(nqn string) ([]string, error) {
+	paths, err := filepath.Glob("/sys/class/nvme/nvme*/subsysnqn")
+	if err != nil {
+		return nil, err
+	}
+
+	controllers := []string{}
+	for _, path := range paths {
+		subsysNQN, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		if strings.TrimSpace(string(subsysNQN)) == nqn {
+			controllers = append(controllers, filepath.Base(filepath.Dir(path)))
+		}
+	}
+
+	return controllers, nil
+}
+
+// devicePaths returns the paths of the block devices of all the LUNs exposed by the target.
+func (d *lun) devicePaths() ([]string, error) {
+	devices := []string{}
+
+	if d.mode() == "nvme" {
+		// Namespaces are listed under the subsystem with native multipath and under the controller without.
+		paths, err := filepath.Glob("/sys/class/nvme-subsystem/*/subsysnqn")
+		if err != nil {
+			return nil, err
+		}
+
+		controllers, err := lunNVMeControllers(d.config["lun.target.name"])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, controller := range controllers {
+			paths = append(paths, filepath.Join("/sys/class/nvme", controller, "subsysnqn"))
+		}
+
+		for _, path := range paths {
+			subsysNQN, err := os.ReadFile(path)
+			if err != nil || strings.TrimSpace(string(subsysNQN)) != d.config["lun.target.name"] {
+				continue
+			}
+
+			entries, err := os.ReadDir(filepath.Dir(path))
+			if err != nil {
+				return nil, err
+			}
+
+			for _, entry := range entries {
+				if !lunNVMeNamespaceRegex.MatchString(entry.Name()) {
+					continue
+				}
+
+				devPath := filepath.Join("/dev", entry.Name())
+				if !shared.ValueInSlice(devPath, devices) && shared.IsBlockdevPath(devPath) {
+					devices = append(devices, devPath)
+				}
+			}
+		}
+	} else {
+		host, port := d.targetPortal()
+
+		// Only match the LUNs of the target on the configured portal.
+		pattern := fmt.Sprintf("/dev/disk/by-path/ip-*:%s-iscsi-%s-lun-*", port, d.config["lun.target.name"])
+		links, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		addrs, err := net.LookupHost(host)
+		if err != nil {
+			addrs = []string{host}
+		}
+
+		for _, link := range links {
+			// Skip partitions of the LUNs.
+			if strings.Contains(filepath.Base(link), "-part") {
+				continue
+			}
+
+			match := false
+			for _, addr := range addrs {
+				if strings.HasPrefix(filepath.Base(link), fmt.Sprintf("ip-%s:", addr)) || strings.HasPrefix(filepath.Base(link), fmt.Sprintf("ip-[%s]:", addr)) {
+					match = true
+					break
+				}
+			}
+
+			if !match {
+				continue
+			}
+
+			devPath, err := filepath.EvalSymlinks(link)
+			if err != nil {
+				continue
+			}
+
+			if !shared.ValueInSlice(devPath, devices) {
+				devices = append(devices, devPath)
+			}
+		}
+	}
+
+	sort.Strings(devices)
+
+	return devices, nil
+}
+
+// devices returns all the LUNs exposed by the target along with their partitions.
+func (d *lun) devices() ([]lunDevice, error) {
+	devPaths, err := d.devicePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]lunDevice, 0, len(devPaths))
+	for _, devPath := range devPaths {
+		partitions, err := lunReadPartitions(devPath)
+		if err != nil {
+			return nil, err
+		}
+
+		devices = append(devices, lunDevice{Path: devPath, Partitions: partitions})
+	}
+
+	return devices, nil
+}
+
+// lunReadPartitions returns the GPT partitions of a device.
+func lunReadPartitions(devPath string) ([]lunPartition, error) {
+	out, err := shared.RunCommand("sgdisk", "--print", devPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read partition table of %q: %w", devPath, err)
+	}
+
+	return lunParsePartitions(out)
+}
+
+// lunParsePartitions parses the partition list printed by sgdisk --print.
+func lunParsePartitions(out string) ([]lunPartition, error) {
+	partitions := []lunPartition{}
+	inTable := false
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+
+		if len(fields) > 0 && fields[0] == "Number" {
+			inTable = true
+			continue
+		}
+
+		// Each partition is listed as: number, start, end, size (value and unit), code and name.
+		if !inTable || len(fields) < 6 {
+			continue
+		}
+
+		number, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid partition number %q", fields[0])
+		}
+
+		start, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid partition start %q", fields[1])
+		}
+
+		end, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid partition end %q", fields[2])
+		}
+
+		name := ""
+		if len(fields) > 6 {
+			name = strings.Join(fields[6:], " ")
+		}
+
+		partitions = append(partitions, lunPartition{Number: number, Start: start, End: end, Name: name})
+	}
+
+	return partitions, nil
+}
+
+// lunWWID returns the world wide identifier of a LUN, which identifies it on all the cluster members.
+func lunWWID(devPath string) (string, error) {
+	name := filepath.Base(devPath)
+
+	// NVMe namespaces expose their identifier directly, SCSI disks through their device.
+	for _, path := range []string{filepath.Join("/sys/class/block", name, "wwid"), filepath.Join("/sys/class/block", name, "device", "wwid")} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		wwid := strings.TrimSpace(string(content))
+		if wwid != "" {
+			return wwid, nil
+		}
+	}
+
+	return "", fmt.Errorf("Failed to get the WWID of %q", devPath)
+}
+
+// lunPartitionPath returns the path of the block device of a partition.
+func lunPartitionPath(devPath string, number int) string {
+	last := devPath[len(devPath)-1]
+	if last >= '0' && last <= '9' {
+		return fmt.Sprintf("%sp%d", devPath, number)
+	}
+
+	return fmt.Sprintf("%s%d", devPath, number)
+}
+
+// lunSectorSize returns the logical sector size of a device.
+func lunSectorSize(devPath string) (int64, error) {
+	content, err := os.ReadFile(filepath.Join("/sys/class/block", filepath.Base(devPath), "queue", "logical_block_size"))
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get sector size of %q: %w", devPath, err)
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+}
+
+// lunUpdatePartitions makes the kernel aware of changes to the partition table of a device.
+// This works even if other partitions of the device are in use.
+func lunUpdatePartitions(devPath string) error {
+	_, err := shared.RunCommand("partx", "--update", devPath)
+	if err != nil {
+		return fmt.Errorf("Failed to update the partitions of %q: %w", devPath, err)
+	}
+
+	return nil
+}
+
+// poolLabelPrefix returns the prefix of the partition labels of this pool.
+func (d *lun) poolLabelPrefix() string {
+	poolHash := sha256.Sum256([]byte(d.name))
+	return fmt.Sprintf("%s%x_", lunPartitionLabelPrefix, poolHash[:4])
+}
+
+// partitionLabel returns the GPT partition label of a volume.
+// GPT partition names are limited to 36 characters, so a hash of the volume is used.
+func (d *lun) partitionLabel(volType VolumeType, contentType ContentType, volName string) string {
+	volHash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s", volType, contentType, volName)))
+	label := fmt.Sprintf("%s%x", d.poolLabelPrefix(), volHash)
+
+	return label[:36]
+}
+
+// findVolume returns the LUN and partition holding a volume.
+func (d *lun) findVolume(vol Volume) (*lunDevice, *lunPartition, error) {
+	label := d.partitionLabel(vol.volType, vol.contentType, vol.name)
+
+	devices, err := d.devices()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range devices {
+		for j := range devices[i].Partitions {
+			if devices[i].Partitions[j].Name == label {
+				return &devices[i], &devices[i].Partitions[j], nil
+			}
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// volumeDevPath returns the path of the block device of a volume.
+func (d *lun) volumeDevPath(vol Volume) (string, error) {
+	device, partition, err := d.findVolume(vol)
+	if err != nil {
+		return "", err
+	}
+
+	if partition == nil {
+		return "", fmt.Errorf("Volume %q not found on any LUN of the target", vol.name)
+	}
+
+	devPath := lunPartitionPath(device.Path, partition.Number)
+
+	// The partition may have been created by another cluster member.
+	if !shared.PathExists(devPath) {
+		err = lunUpdatePartitions(device.Path)
+		if err != nil {
+			return "", err
+		}
+
+		if !tryExists(devPath) {
+			return "", fmt.Errorf("Partition %q of volume %q didn't appear", devPath, vol.name)
+		}
+	}
+
+	return devPath, nil
+}
+
+// claimDevice finds a free LUN that can hold the given volumes, records its claim in the database and creates a
+// partition for each of the volumes. Returns the path of the claimed LUN.
+func (d *lun) claimDevice(vols []Volume) (string, error) {
+	sizes := make([]int64, 0, len(vols))
+	var totalSize int64
+
+	for _, vol := range vols {
+		sizeBytes, err := d.volumeSizeBytes(vol)
+		if err != nil {
+			return "", err
+		}
+
+		sizes = append(sizes, sizeBytes)
+		totalSize += sizeBytes
+	}
+
+	err := d.rescan()
+	if err != nil {
+		return "", err
+	}
+
+	devices, err := d.devices()
+	if err != nil {
+		return "", err
+	}
+
+	// Find the free LUNs that are large enough, leaving 2MiB for the partition tables and alignment.
+	type lunCandidate struct {
+		path string
+		wwid string
+		size int64
+	}
+
+	candidates := []lunCandidate{}
+	for _, device := range devices {
+		if len(device.Partitions) > 0 {
+			continue
+		}
+
+		// Never claim LUNs that contain any kind of data signature.
+		signatures, err := shared.RunCommand("wipefs", "--no-act", "--noheadings", device.Path)
+		if err != nil || strings.TrimSpace(signatures) != "" {
+			continue
+		}
+
+		devSize, err := BlockDiskSizeBytes(device.Path)
+		if err != nil {
+			return "", err
+		}
+
+		if devSize-2*1024*1024 < totalSize {
+			continue
+		}
+
+		wwid, err := lunWWID(device.Path)
+		if err != nil {
+			return "", err
+		}
+
+		candidates = append(candidates, lunCandidate{path: device.Path, wwid: wwid, size: devSize})
+	}
+
+	// Prefer the smallest LUNs.
+	sort.SliceStable(candidates, func(i int, j int) bool { return candidates[i].size < candidates[j].size })
+
+	// Record the claim of the LUN in the database, so that other cluster members creating volumes at the same
+	// time pick another LUN.
+	var claimed string
+
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		poolID, err := tx.GetStoragePoolID(ctx, d.name)
+		if err != nil {
+			return err
+		}
+
+		for _, candidate := range candidates {
+			err = tx.CreateStoragePoolLUN(ctx, poolID, candidate.wwid)
+			if errors.Is(err, db.ErrAlreadyDefined) {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			claimed = candidate.path
+
+			return nil
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed claiming LUN: %w", err)
+	}
+
+	if claimed == "" {
+		return "", fmt.Errorf("No free LUN of at least %d bytes is available on the target", totalSize)
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	revert.Add(func() { d.releaseDevice(claimed, nil) })
+
+	args := []string{"--clear"}
+	for i, vol := range vols {
+		label := d.partitionLabel(vol.volType, vol.contentType, vol.name)
+		args = append(args,
+			fmt.Sprintf("--new=%d:0:+%dK", i+1, sizes[i]/1024),
+			fmt.Sprintf("--change-name=%d:%s", i+1, label),
+			fmt.Sprintf("--typecode=%d:8300", i+1),
+		)
+	}
+
+	args = append(args, claimed)
+
+	_, err = shared.RunCommand("sgdisk", args...)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create partitions on %q: %w", claimed, err)
+	}
+
+	err = lunUpdatePartitions(claimed)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range vols {
+		if !tryExists(lunPartitionPath(claimed, i+1)) {
+			return "", fmt.Errorf("Partition %q didn't appear", lunPartitionPath(claimed, i+1))
+		}
+	}
+
+	revert.Success()
+
+	return claimed, nil
+}
+
+// unrecordDevice removes the claim of the LUN with the given WWID from the database.
+func (d *lun) unrecordDevice(wwid string) {
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		poolID, err := tx.GetStoragePoolID(ctx, d.name)
+		if err != nil {
+			return err
+		}
+
+		return tx.DeleteStoragePoolLUN(ctx, poolID, wwid)
+	})
+	if err != nil {
+		d.logger.Warn("Failed to remove the claim of the LUN", logger.Ctx{"wwid": wwid, "err": err})
+	}
+}
+
+// releaseDevice wipes the partition table of a LUN so that it can be claimed again.
+func (d *lun) releaseDevice(devPath string, partitions []lunPartition) {
+	// Discard or at least wipe the headers of the partitions so no data is leaked to the next user of the LUN.
+	for _, partition := range partitions {
+		partPath := lunPartitionPath(devPath, partition.Number)
+		if !shared.PathExists(partPath) {
+			continue
+		}
+
+		_, err := shared.RunCommand("blkdiscard", partPath)
+		if err != nil {
+			_ = wipeBlockHeaders(partPath)
+		}
+	}
+
+	_, err := shared.RunCommand("sgdisk", "--zap-all", devPath)
+	if err != nil {
+		d.logger.Warn("Failed to wipe the partition table of the LUN", logger.Ctx{"dev": devPath, "err": err})
+	}
+
+	_ = lunUpdatePartitions(devPath)
+
+	wwid, err := lunWWID(devPath)
+	if err != nil {
+		d.logger.Warn("Failed to get the WWID of the LUN", logger.Ctx{"dev": devPath, "err": err})
+		return
+	}
+
+	d.unrecordDevice(wwid)
+}
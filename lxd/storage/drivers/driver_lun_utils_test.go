@@ -0,0 +1,77 @@
+package drivers
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_lunParsePartitions(t *testing.T) {
+	out := `Disk /dev/sdb: 20971520 sectors, 10.0 GiB
+Model: lun0
+Sector size (logical/physical): 512/512 bytes
+Disk identifier (GUID): 3F6D5C50-8F7A-4E0D-9E1B-6C1A1B9A2C3D
+Partition table holds up to 128 entries
+Main partition table begins at sector 2 and ends at sector 33
+First usable sector is 34, last usable sector is 20971486
+Partitions will be aligned on 2048-sector boundaries
+Total free space is 2014 sectors (1007.0 KiB)
+
+Number  Start (sector)    End (sector)  Size       Code  Name
+   1            2048          206847   100.0 MiB   8300  lxd_0a1b2c3d_abcdef
+   2          206848        20969471   9.9 GiB     8300  lxd_0a1b2c3d_012345
+`
+
+	partitions, err := lunParsePartitions(out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(partitions) != 2 {
+		t.Fatalf("Expected 2 partitions, got %d", len(partitions))
+	}
+
+	want := lunPartition{Number: 2, Start: 206848, End: 20969471, Name: "lxd_0a1b2c3d_012345"}
+	if partitions[1] != want {
+		t.Errorf("Unexpected partition: %+v, want %+v", partitions[1], want)
+	}
+
+	partitions, err = lunParsePartitions("Creating new GPT entries in memory.\n")
+	if err != nil || len(partitions) != 0 {
+		t.Errorf("Expected no partitions on blank LUN, got %+v (%v)", partitions, err)
+	}
+}
+
+func Test_lunPartitionPath(t *testing.T) {
+	tests := map[string]string{
+		"/dev/sdb":       "/dev/sdb1",
+		"/dev/nvme0n1":   "/dev/nvme0n1p1",
+		"/dev/mmcblk0":   "/dev/mmcblk0p1",
+		"/dev/disk/vdaa": "/dev/disk/vdaa1",
+	}
+
+	for devPath, want := range tests {
+		got := lunPartitionPath(devPath, 1)
+		if got != want {
+			t.Errorf("lunPartitionPath(%q) = %q, want %q", devPath, got, want)
+		}
+	}
+}
+
+func Test_lunPartitionLabel(t *testing.T) {
+	d := &lun{common{name: "default"}}
+
+	label := d.partitionLabel(VolumeTypeCustom, ContentTypeFS, strings.Repeat("a", 200))
+
+	// GPT partition names are limited to 36 characters.
+	if len(label) > 36 {
+		t.Errorf("Label %q is longer than 36 characters", label)
+	}
+
+	if !strings.HasPrefix(label, d.poolLabelPrefix()) {
+		t.Errorf("Label %q doesn't start with the pool prefix %q", label, d.poolLabelPrefix())
+	}
+
+	if label == d.partitionLabel(VolumeTypeCustom, ContentTypeBlock, strings.Repeat("a", 200)) {
+		t.Errorf("Labels of filesystem and block volumes must differ")
+	}
+}
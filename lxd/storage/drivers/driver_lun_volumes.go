@@ -0,0 +1,633 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/canonical/lxd/lxd/backup"
+	"github.com/canonical/lxd/lxd/instancewriter"
+	"github.com/canonical/lxd/lxd/locking"
+	"github.com/canonical/lxd/lxd/migration"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/storage/filesystem"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/units"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+// volumeSizeBytes returns the size in bytes of the partition to create for a volume.
+func (d *lun) volumeSizeBytes(vol Volume) (int64, error) {
+	sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+	if err != nil {
+		return -1, err
+	}
+
+	return d.roundVolumeBlockSizeBytes(sizeBytes), nil
+}
+
+// CreateVolume creates an empty volume and can optionally fill it by executing the supplied filler function.
+func (d *lun) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Operation) error {
+	revert := revert.New()
+	defer revert.Fail()
+
+	// VMs store their filesystem volume on the same LUN as their block volume.
+	vols := []Volume{vol}
+	if vol.IsVMBlock() {
+		vols = []Volume{vol.NewVMBlockFilesystemVolume(), vol}
+	}
+
+	unlock, err := locking.Lock(context.TODO(), OperationLockName("claimDevice", d.name, "", "", ""))
+	if err != nil {
+		return err
+	}
+
+	devPath, err := d.claimDevice(vols)
+	unlock()
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = d.DeleteVolume(vol, op) })
+
+	for _, v := range vols {
+		if v.contentType != ContentTypeFS {
+			continue
+		}
+
+		err = v.EnsureMountPath()
+		if err != nil {
+			return err
+		}
+
+		volDevPath, err := d.volumeDevPath(v)
+		if err != nil {
+			return err
+		}
+
+		fsType := v.ConfigBlockFilesystem()
+		_, err = makeFSType(volDevPath, fsType, nil)
+		if err != nil {
+			return fmt.Errorf("Failed making %q filesystem on %q: %w", fsType, volDevPath, err)
+		}
+	}
+
+	d.logger.Debug("Claimed LUN for volume", logger.Ctx{"volName": vol.name, "dev": devPath})
+
+	err = vol.MountTask(func(mountPath string, op *operations.Operation) error {
+		// Run the volume filler function if supplied.
+		if filler != nil && filler.Fill != nil {
+			var err error
+			var devPath string
+
+			if IsContentBlock(vol.contentType) {
+				// Get the device path.
+				devPath, err = d.GetVolumeDiskPath(vol)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Allow filler to resize initial volume as needed.
+			// This is safe because if for some reason an error occurs the volume will be discarded rather
+			// than leaving a corrupt filesystem.
+			err = d.runFiller(vol, devPath, filler, true)
+			if err != nil {
+				return err
+			}
+
+			// Move the GPT alt header to end of disk if needed.
+			if vol.IsVMBlock() {
+				err = d.moveGPTAltHeader(devPath)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if vol.contentType == ContentTypeFS {
+			// Run EnsureMountPath again after mounting and filling to ensure the mount directory has
+			// the correct permissions set.
+			err = vol.EnsureMountPath()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, op)
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// CreateVolumeFromBackup restores a backup tarball onto the storage device.
+func (d *lun) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
+	if len(srcBackup.Snapshots) > 0 {
+		return nil, nil, fmt.Errorf("Volume snapshots aren't supported by the lun driver: %w", ErrNotSupported)
+	}
+
+	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcBackup.BaseSnapshot, srcData, op)
+}
+
+// CreateVolumeFromCopy provides same-pool volume copying functionality.
+// Volume snapshots aren't supported, so copySnapshots can't be set unless copying from a snapshot.
+func (d *lun) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	if copySnapshots && !srcVol.IsSnapshot() {
+		return fmt.Errorf("Volume snapshots aren't supported by the lun driver: %w", ErrNotSupported)
+	}
+
+	return genericVFSCopyVolume(d, nil, vol, srcVol, nil, false, allowInconsistent, op)
+}
+
+// CreateVolumeFromMigration creates a volume being sent via a migration.
+func (d *lun) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
+	if len(volTargetArgs.Snapshots) > 0 {
+		return fmt.Errorf("Volume snapshots aren't supported by the lun driver: %w", ErrNotSupported)
+	}
+
+	return genericVFSCreateVolumeFromMigration(d, nil, vol, conn, volTargetArgs, preFiller, op)
+}
+
+// RefreshVolume provides same-pool volume and specific snapshots syncing functionality.
+func (d *lun) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error {
+	if len(srcSnapshots) > 0 {
+		return fmt.Errorf("Volume snapshots aren't supported by the lun driver: %w", ErrNotSupported)
+	}
+
+	return genericVFSCopyVolume(d, nil, vol, srcVol, nil, true, allowInconsistent, op)
+}
+
+// DeleteVolume deletes a volume of the storage device and releases the LUN it was stored on.
+func (d *lun) DeleteVolume(vol Volume, op *operations.Operation) error {
+	device, partition, err := d.findVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if partition != nil {
+		if vol.contentType == ContentTypeFS {
+			_, err = d.UnmountVolume(vol, false, op)
+			if err != nil {
+				return err
+			}
+		}
+
+		// For VMs, unmount the filesystem volume stored on the same LUN.
+		if vol.IsVMBlock() {
+			fsVol := vol.NewVMBlockFilesystemVolume()
+			_, err = d.UnmountVolume(fsVol, false, op)
+			if err != nil {
+				return err
+			}
+		}
+
+		d.releaseDevice(device.Path, device.Partitions)
+	}
+
+	mountPath := vol.MountPath()
+	if vol.contentType == ContentTypeFS && shared.PathExists(mountPath) {
+		err = os.RemoveAll(mountPath)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Failed to remove '%s': %w", mountPath, err)
+		}
+	}
+
+	// For VMs, also remove the mount path of the filesystem volume.
+	if vol.IsVMBlock() {
+		fsVolMountPath := vol.NewVMBlockFilesystemVolume().MountPath()
+		err = os.RemoveAll(fsVolMountPath)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Failed to remove '%s': %w", fsVolMountPath, err)
+		}
+	}
+
+	return nil
+}
+
+// HasVolume indicates whether a specific volume exists on the storage pool.
+func (d *lun) HasVolume(vol Volume) (bool, error) {
+	_, partition, err := d.findVolume(vol)
+	if err != nil {
+		return false, err
+	}
+
+	return partition != nil, nil
+}
+
+// FillVolumeConfig populate volume with default config.
+func (d *lun) FillVolumeConfig(vol Volume) error {
+	// Copy volume.* configuration options from pool.
+	// Exclude "block.filesystem" and "block.mount_options" as they depend on volume type (handled below).
+	err := d.fillVolumeConfig(&vol, "block.filesystem", "block.mount_options")
+	if err != nil {
+		return err
+	}
+
+	// Only validate filesystem config keys for filesystem volumes or VM block volumes (which have an
+	// associated filesystem volume).
+	if vol.ContentType() == ContentTypeFS || vol.IsVMBlock() {
+		// Inherit filesystem from pool if not set.
+		if vol.config["block.filesystem"] == "" {
+			vol.config["block.filesystem"] = d.config["volume.block.filesystem"]
+		}
+
+		// Default filesystem if neither volume nor pool specify an override.
+		if vol.config["block.filesystem"] == "" {
+			// Unchangeable volume property: Set unconditionally.
+			vol.config["block.filesystem"] = DefaultFilesystem
+		}
+
+		// Inherit filesystem mount options from pool if not set.
+		if vol.config["block.mount_options"] == "" {
+			vol.config["block.mount_options"] = d.config["volume.block.mount_options"]
+		}
+
+		// Default filesystem mount options if neither volume nor pool specify an override.
+		if vol.config["block.mount_options"] == "" {
+			// Unchangeable volume property: Set unconditionally.
+			vol.config["block.mount_options"] = "discard"
+		}
+	}
+
+	return nil
+}
+
+// commonVolumeRules returns validation rules which are common for pool and volume.
+func (d *lun) commonVolumeRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		"block.filesystem":    validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
+		"block.mount_options": validate.IsAny,
+	}
+}
+
+// ValidateVolume validates the supplied volume config.
+func (d *lun) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
+	return d.validateVolume(vol, d.commonVolumeRules(), removeUnknownKeys)
+}
+
+// UpdateVolume applies config changes to the volume.
+func (d *lun) UpdateVolume(vol Volume, changedConfig map[string]string) error {
+	newSize, sizeChanged := changedConfig["size"]
+	if sizeChanged {
+		err := d.SetVolumeQuota(vol, newSize, false, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetVolumeUsage returns the disk space used by the volume.
+func (d *lun) GetVolumeUsage(vol Volume) (int64, error) {
+	// If mounted, use the filesystem stats for pretty accurate usage information.
+	if vol.contentType == ContentTypeFS && filesystem.IsMountPoint(vol.MountPath()) {
+		var stat unix.Statfs_t
+
+		err := unix.Statfs(vol.MountPath(), &stat)
+		if err != nil {
+			return -1, err
+		}
+
+		return int64(stat.Blocks-stat.Bfree) * int64(stat.Bsize), nil
+	}
+
+	return -1, ErrNotSupported
+}
+
+// SetVolumeQuota resizes the partition of the volume on its LUN.
+// The size of a volume is limited by the size of the LUN it is stored on.
+func (d *lun) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
+	// Do nothing if size isn't specified.
+	if size == "" || size == "0" {
+		return nil
+	}
+
+	sizeBytes, err := units.ParseByteSizeString(size)
+	if err != nil {
+		return err
+	}
+
+	sizeBytes = d.roundVolumeBlockSizeBytes(sizeBytes)
+
+	device, partition, err := d.findVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if partition == nil {
+		return fmt.Errorf("Volume %q not found on any LUN of the target", vol.name)
+	}
+
+	devPath, err := d.volumeDevPath(vol)
+	if err != nil {
+		return err
+	}
+
+	oldSizeBytes, err := BlockDiskSizeBytes(devPath)
+	if err != nil {
+		return fmt.Errorf("Error getting current size: %w", err)
+	}
+
+	// Do nothing if volume is already specified size (+/- 512 bytes).
+	if oldSizeBytes+512 > sizeBytes && oldSizeBytes-512 < sizeBytes {
+		return nil
+	}
+
+	// Only the last partition of a LUN can be grown.
+	if sizeBytes > oldSizeBytes && partition.Number != len(device.Partitions) {
+		return fmt.Errorf("Volume %q cannot be grown as it isn't the last volume on its LUN", vol.name)
+	}
+
+	// Check that the LUN is large enough.
+	lunSizeBytes, err := BlockDiskSizeBytes(device.Path)
+	if err != nil {
+		return err
+	}
+
+	sectorSize, err := lunSectorSize(device.Path)
+	if err != nil {
+		return err
+	}
+
+	// Leave 1MiB at the end of the LUN for the backup GPT header.
+	if partition.Start*sectorSize+sizeBytes > lunSizeBytes-1024*1024 {
+		return fmt.Errorf("Volume size is larger than its LUN (%d bytes)", lunSizeBytes)
+	}
+
+	inUse := vol.MountInUse()
+
+	// Resize filesystem if needed.
+	if vol.contentType == ContentTypeFS {
+		fsType := vol.ConfigBlockFilesystem()
+
+		if sizeBytes < oldSizeBytes {
+			if !filesystemTypeCanBeShrunk(fsType) {
+				return fmt.Errorf("Filesystem %q cannot be shrunk: %w", fsType, ErrCannotBeShrunk)
+			}
+
+			if inUse {
+				return ErrInUse // We don't allow online shrinking of filesytem volumes.
+			}
+
+			// Shrink filesystem first. Pass allowUnsafeResize to allow disabling of filesystem
+			// resize safety checks.
+			err = shrinkFileSystem(fsType, devPath, vol, sizeBytes, allowUnsafeResize)
+			if err != nil {
+				return err
+			}
+
+			// Shrink the partition.
+			err = d.resizePartition(device.Path, *partition, sizeBytes)
+			if err != nil {
+				return err
+			}
+		} else if sizeBytes > oldSizeBytes {
+			// Grow the partition first.
+			err = d.resizePartition(device.Path, *partition, sizeBytes)
+			if err != nil {
+				return err
+			}
+
+			// Grow the filesystem to fill the partition.
+			err = growFileSystem(fsType, devPath, vol)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// Only perform pre-resize checks if we are not in "unsafe" mode.
+		// In unsafe mode we expect the caller to know what they are doing and understand the risks.
+		if !allowUnsafeResize {
+			if sizeBytes < oldSizeBytes {
+				return fmt.Errorf("Block volumes cannot be shrunk: %w", ErrCannotBeShrunk)
+			}
+
+			if inUse {
+				return ErrInUse // We don't allow online resizing of block volumes.
+			}
+		}
+
+		err = d.resizePartition(device.Path, *partition, sizeBytes)
+		if err != nil {
+			return err
+		}
+
+		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
+		// expected the caller will do all necessary post resize actions themselves).
+		if vol.IsVMBlock() && !allowUnsafeResize {
+			err = d.moveGPTAltHeader(devPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resizePartition recreates a partition with the same start and name but a different size.
+func (d *lun) resizePartition(devPath string, partition lunPartition, sizeBytes int64) error {
+	_, err := shared.RunCommand("sgdisk",
+		fmt.Sprintf("--delete=%d", partition.Number),
+		fmt.Sprintf("--new=%d:%d:+%dK", partition.Number, partition.Start, sizeBytes/1024),
+		fmt.Sprintf("--change-name=%d:%s", partition.Number, partition.Name),
+		fmt.Sprintf("--typecode=%d:8300", partition.Number),
+		devPath)
+	if err != nil {
+		return fmt.Errorf("Failed to resize partition %d of %q: %w", partition.Number, devPath, err)
+	}
+
+	return lunUpdatePartitions(devPath)
+}
+
+// GetVolumeDiskPath returns the location of a root disk block device.
+func (d *lun) GetVolumeDiskPath(vol Volume) (string, error) {
+	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
+		return d.volumeDevPath(vol)
+	}
+
+	return "", ErrNotSupported
+}
+
+// MountVolume mounts a volume and increments ref counter. Please call UnmountVolume() when done with the volume.
+func (d *lun) MountVolume(vol Volume, op *operations.Operation) error {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	if vol.contentType == ContentTypeFS {
+		mountPath := vol.MountPath()
+		if !filesystem.IsMountPoint(mountPath) {
+			volDevPath, err := d.volumeDevPath(vol)
+			if err != nil {
+				return err
+			}
+
+			err = vol.EnsureMountPath()
+			if err != nil {
+				return err
+			}
+
+			fsType := vol.ConfigBlockFilesystem()
+
+			if vol.mountFilesystemProbe {
+				fsType, err = fsProbe(volDevPath)
+				if err != nil {
+					return fmt.Errorf("Failed probing filesystem: %w", err)
+				}
+			}
+
+			mountFlags, mountOptions := filesystem.ResolveMountOptions(strings.Split(vol.ConfigBlockMountOptions(), ","))
+			err = TryMount(volDevPath, mountPath, fsType, mountFlags, mountOptions)
+			if err != nil {
+				return err
+			}
+
+			d.logger.Debug("Mounted LUN volume", logger.Ctx{"volName": vol.name, "dev": volDevPath, "path": mountPath, "options": mountOptions})
+		}
+	} else if vol.contentType == ContentTypeBlock {
+		// For VMs, mount the filesystem volume.
+		if vol.IsVMBlock() {
+			fsVol := vol.NewVMBlockFilesystemVolume()
+			err = d.MountVolume(fsVol, op)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	vol.MountRefCountIncrement() // From here on it is up to caller to call UnmountVolume() when done.
+	return nil
+}
+
+// UnmountVolume unmounts volume if mounted and not in use. Returns true if this unmounted the volume.
+// keepBlockDev indicates if backing block device should be not be deactivated when volume is unmounted.
+// As the LUNs stay connected while the pool is mounted, the block devices are never deactivated.
+func (d *lun) UnmountVolume(vol Volume, keepBlockDev bool, op *operations.Operation) (bool, error) {
+	unlock, err := vol.MountLock()
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	ourUnmount := false
+	mountPath := vol.MountPath()
+
+	refCount := vol.MountRefCountDecrement()
+
+	if vol.contentType == ContentTypeFS && filesystem.IsMountPoint(mountPath) {
+		if refCount > 0 {
+			d.logger.Debug("Skipping unmount as in use", logger.Ctx{"volName": vol.name, "refCount": refCount})
+			return false, ErrInUse
+		}
+
+		err = TryUnmount(mountPath, 0)
+		if err != nil {
+			return false, err
+		}
+
+		d.logger.Debug("Unmounted LUN volume", logger.Ctx{"volName": vol.name, "path": mountPath})
+		ourUnmount = true
+	} else if vol.contentType == ContentTypeBlock {
+		// For VMs, unmount the filesystem volume.
+		if vol.IsVMBlock() {
+			fsVol := vol.NewVMBlockFilesystemVolume()
+			ourUnmount, err = d.UnmountVolume(fsVol, false, op)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return ourUnmount, nil
+}
+
+// RenameVolume renames a volume by changing the label of its partition.
+func (d *lun) RenameVolume(vol Volume, newVolName string, op *operations.Operation) error {
+	return vol.UnmountTask(func(op *operations.Operation) error {
+		revert := revert.New()
+		defer revert.Fail()
+
+		vols := []Volume{vol}
+		if vol.IsVMBlock() {
+			vols = append(vols, vol.NewVMBlockFilesystemVolume())
+		}
+
+		for _, v := range vols {
+			device, partition, err := d.findVolume(v)
+			if err != nil {
+				return err
+			}
+
+			if partition == nil {
+				return fmt.Errorf("Volume %q not found on any LUN of the target", v.name)
+			}
+
+			newLabel := d.partitionLabel(v.volType, v.contentType, newVolName)
+			_, err = shared.RunCommand("sgdisk", fmt.Sprintf("--change-name=%d:%s", partition.Number, newLabel), device.Path)
+			if err != nil {
+				return fmt.Errorf("Failed to rename partition %d of %q: %w", partition.Number, device.Path, err)
+			}
+
+			oldLabel := partition.Name
+			devPath := device.Path
+			partNumber := partition.Number
+			revert.Add(func() {
+				_, _ = shared.RunCommand("sgdisk", fmt.Sprintf("--change-name=%d:%s", partNumber, oldLabel), devPath)
+			})
+
+			// Rename the mount path.
+			if v.contentType == ContentTypeFS {
+				srcVolumePath := GetVolumeMountPath(d.name, v.volType, v.name)
+				dstVolumePath := GetVolumeMountPath(d.name, v.volType, newVolName)
+				if shared.PathExists(srcVolumePath) {
+					err = os.Rename(srcVolumePath, dstVolumePath)
+					if err != nil {
+						return fmt.Errorf("Failed to rename '%s' to '%s': %w", srcVolumePath, dstVolumePath, err)
+					}
+
+					revert.Add(func() { _ = os.Rename(dstVolumePath, srcVolumePath) })
+				}
+			}
+		}
+
+		revert.Success()
+		return nil
+	}, false, op)
+}
+
+// MigrateVolume sends a volume for migration.
+func (d *lun) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *migration.VolumeSourceArgs, op *operations.Operation) error {
+	if len(volSrcArgs.Snapshots) > 0 {
+		return fmt.Errorf("Volume snapshots aren't supported by the lun driver: %w", ErrNotSupported)
+	}
+
+	return genericVFSMigrateVolume(d, d.state, vol, conn, volSrcArgs, op)
+}
+
+// BackupVolume copies a volume to a specified target path.
+// This driver does not support optimized backups.
+func (d *lun) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, _ bool, snapshots []string, baseSnapshot string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, baseSnapshot, op)
+}
+
+// VolumeSnapshots returns a list of snapshots for the volume (in no particular order).
+// Snapshots aren't supported by this driver, so the list is always empty.
+func (d *lun) VolumeSnapshots(vol Volume, op *operations.Operation) ([]string, error) {
+	return []string{}, nil
+}
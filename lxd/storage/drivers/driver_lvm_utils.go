@@ -324,6 +324,9 @@ func (d *lvm) createLogicalVolume(vgName, thinPoolName string, vol Volume, makeT
 		return err
 	}
 
+	// Make room for the LUKS header of encrypted volumes.
+	lvSizeBytes = luksRawSize(vol, lvSizeBytes)
+
 	lvFullName := d.lvmFullVolumeName(vol.volType, vol.contentType, vol.name)
 
 	args := []string{
@@ -374,6 +377,11 @@ func (d *lvm) createLogicalVolume(vgName, thinPoolName string, vol Volume, makeT
 		if err != nil {
 			return fmt.Errorf("Error making filesystem on LVM logical volume: %w", err)
 		}
+	} else if vol.IsEncrypted() {
+		err = d.luksFormat(vol, volDevPath)
+		if err != nil {
+			return err
+		}
 	}
 
 	isRecent, err := d.lvmVersionIsAtLeast(lvmVersion, "2.02.99")
@@ -806,6 +814,14 @@ func (d *lvm) deactivateVolume(vol Volume) (bool, error) {
 	}
 
 	if shared.PathExists(volDevPath) {
+		// Close the LUKS device first as it holds the logical volume open.
+		if vol.IsEncrypted() {
+			_, err := d.luksClose(vol)
+			if err != nil {
+				return false, err
+			}
+		}
+
 		// Keep trying to deactivate a few times in case the device is still being flushed.
 		var err error
 		for i := 0; i < 20; i++ {
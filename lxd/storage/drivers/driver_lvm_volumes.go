@@ -120,7 +120,7 @@ func (d *lvm) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 
 // CreateVolumeFromBackup restores a backup tarball onto the storage device.
 func (d *lvm) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
-	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+	return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcBackup.BaseSnapshot, srcData, op)
 }
 
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
@@ -137,7 +137,8 @@ func (d *lvm) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 	}
 
 	// We can use optimised copying when the pool is backed by an LVM thinpool.
-	if d.usesThinpool() {
+	// Encrypted volumes are copied through their decrypted devices instead, as the copy uses its own key.
+	if d.usesThinpool() && !vol.IsEncrypted() && !srcVol.IsEncrypted() {
 		err = d.copyThinpoolVolume(vol, srcVol, srcSnapshots, false)
 		if err != nil {
 			return err
@@ -157,6 +158,15 @@ func (d *lvm) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 	return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
 }
 
+// CloneVolume creates a copy-on-write clone of a volume using a thin snapshot.
+func (d *lvm) CloneVolume(vol Volume, srcVol Volume, op *operations.Operation) error {
+	if !d.usesThinpool() {
+		return ErrNotSupported
+	}
+
+	return d.copyThinpoolVolume(vol, srcVol, nil, false)
+}
+
 // CreateVolumeFromMigration creates a volume being sent via a migration.
 func (d *lvm) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
 	return genericVFSCreateVolumeFromMigration(d, nil, vol, conn, volTargetArgs, preFiller, op)
@@ -165,7 +175,8 @@ func (d *lvm) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, vol
 // RefreshVolume provides same-pool volume and specific snapshots syncing functionality.
 func (d *lvm) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, allowInconsistent bool, op *operations.Operation) error {
 	// We can use optimised copying when the pool is backed by an LVM thinpool.
-	if d.usesThinpool() {
+	// Encrypted volumes are copied through their decrypted devices instead, as the copy uses its own key.
+	if d.usesThinpool() && !vol.IsEncrypted() && !srcVol.IsEncrypted() {
 		return d.copyThinpoolVolume(vol, srcVol, srcSnapshots, true)
 	}
 
@@ -292,6 +303,7 @@ func (d *lvm) FillVolumeConfig(vol Volume) error {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *lvm) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
+		"block.encryption":    validate.Optional(validate.IsOneOf("luks2")),
 		"block.mount_options": validate.IsAny,
 		"block.filesystem":    validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
 		// lxdmeta:generate(entities=storage-lvm; group=volume-conf; key=lvm.stripes)
@@ -326,7 +338,7 @@ func (d *lvm) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		return fmt.Errorf("lvm.stripes.size cannot be used with thin pool volumes")
 	}
 
-	return nil
+	return luksValidateVolume(vol)
 }
 
 // UpdateVolume applies config changes to the volume.
@@ -399,6 +411,9 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 		return err
 	}
 
+	// Make room for the LUKS header of encrypted volumes.
+	sizeBytes = luksRawSize(vol, sizeBytes)
+
 	// Read actual size of current volume.
 	volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
 	oldSizeBytes, err := d.logicalVolumeSize(volDevPath)
@@ -501,10 +516,23 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			return err
 		}
 
+		// Resize the opened LUKS device to the new size of the logical volume.
+		if vol.IsEncrypted() {
+			err = d.luksResize(vol)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
 		// expected the caller will do all necessary post resize actions themselves).
 		if vol.IsVMBlock() && !allowUnsafeResize {
-			err = d.moveGPTAltHeader(volDevPath)
+			devPath, err := d.GetVolumeDiskPath(vol)
+			if err != nil {
+				return err
+			}
+
+			err = d.moveGPTAltHeader(devPath)
 			if err != nil {
 				return err
 			}
@@ -518,6 +546,11 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 func (d *lvm) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
 		volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
+
+		if vol.IsEncrypted() {
+			return d.luksOpen(vol, volDevPath)
+		}
+
 		return volDevPath, nil
 	}
 
@@ -843,8 +876,8 @@ func (d *lvm) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *mig
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *lvm) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, _ bool, snapshots []string, op *operations.Operation) error {
-	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+func (d *lvm) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, _ bool, snapshots []string, baseSnapshot string, op *operations.Operation) error {
+	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, baseSnapshot, op)
 }
 
 // CreateVolumeSnapshot creates a snapshot of a volume.
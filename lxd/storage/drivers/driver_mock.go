@@ -90,6 +90,11 @@ func (d *mock) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots boo
 	return nil
 }
 
+// CloneVolume creates a copy-on-write clone of a volume.
+func (d *mock) CloneVolume(vol Volume, srcVol Volume, op *operations.Operation) error {
+	return nil
+}
+
 // CreateVolumeFromMigration creates a volume being sent via a migration.
 func (d *mock) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, op *operations.Operation) error {
 	return nil
@@ -176,7 +181,7 @@ func (d *mock) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *mi
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *mock) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *mock) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, op *operations.Operation) error {
 	return nil
 }
 
@@ -0,0 +1,272 @@
+package drivers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/migration"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/storage/filesystem"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+// nfsDefaultVersion is the NFS protocol version used when nfs.version isn't set.
+const nfsDefaultVersion = "4.2"
+
+// nfs is a remote driver storing volumes as directories on an NFS export.
+// It reuses the volume handling of the dir driver and only manages the mount of the export.
+type nfs struct {
+	dir
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *nfs) load() error {
+	// Register the patches.
+	d.patches = map[string]func() error{
+		"storage_lvm_skipactivation":                         nil,
+		"storage_missing_snapshot_records":                   nil,
+		"storage_delete_old_snapshot_records":                nil,
+		"storage_zfs_drop_block_volume_filesystem_extension": nil,
+		"storage_prefix_bucket_names_with_project":           nil,
+	}
+
+	return nil
+}
+
+// isRemote returns true indicating this driver uses remote storage.
+func (d *nfs) isRemote() bool {
+	return true
+}
+
+// Info returns info about the driver and its environment.
+func (d *nfs) Info() Info {
+	return Info{
+		Name:                         "nfs",
+		Version:                      "1",
+		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		VolumeMultiNode:              true,
+		BlockBacking:                 false,
+		RunningCopyFreeze:            true,
+		DirectIO:                     true,
+		MountedRoot:                  true,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *nfs) FillConfig() error {
+	if d.config["nfs.version"] == "" {
+		d.config["nfs.version"] = nfsDefaultVersion
+	}
+
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *nfs) Create() error {
+	err := d.FillConfig()
+	if err != nil {
+		return err
+	}
+
+	// Config validation.
+	if d.config["source"] == "" {
+		return fmt.Errorf("Missing required source (HOST:PATH)")
+	}
+
+	// Mount the export on a temporary mountpoint.
+	mountPoint, cleanup, err := d.tempMount()
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	// Check that the existing export is empty.
+	ok, _ := shared.PathIsEmpty(mountPoint)
+	if !ok {
+		return fmt.Errorf("Only empty NFS exports can be used as a LXD storage pool")
+	}
+
+	return nil
+}
+
+// Delete clears any local and remote data related to this driver instance.
+func (d *nfs) Delete(op *operations.Operation) error {
+	// Make sure the export is mounted so that its content can be removed.
+	_, err := d.Mount()
+	if err != nil {
+		return err
+	}
+
+	// On delete, wipe everything in the directory.
+	err = wipeDirectory(GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	// Make sure the existing pool is unmounted.
+	_, err = d.Unmount()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
+func (d *nfs) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-nfs; group=pool-conf; key=nfs.version)
+		// Use `3` for NFSv3 servers. Any other value mounts the export using NFSv4 with the given minor version.
+		// ---
+		//  type: string
+		//  defaultdesc: `4.2`
+		//  shortdesc: NFS protocol version used to mount the export
+		"nfs.version": validate.Optional(validate.IsOneOf("3", "4", "4.0", "4.1", "4.2")),
+		// lxdmeta:generate(entities=storage-nfs; group=pool-conf; key=nfs.mount_options)
+		// Specify a comma-separated list of additional NFS mount options, for example `proto=tcp,hard,timeo=600`.
+		// ---
+		//  type: string
+		//  shortdesc: Additional mount options for the NFS export
+		"nfs.mount_options": validate.IsAny,
+	}
+
+	err := d.validatePool(config, rules, nil)
+	if err != nil {
+		return err
+	}
+
+	if config["source"] != "" {
+		_, _, err = nfsParseSource(config["source"])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *nfs) Update(changedConfig map[string]string) error {
+	for _, key := range []string{"source", "nfs.version", "nfs.mount_options"} {
+		_, changed := changedConfig[key]
+		if changed && filesystem.IsMountPoint(GetPoolMountPath(d.name)) {
+			return fmt.Errorf("Config key %q cannot be changed while the pool is mounted", key)
+		}
+	}
+
+	return nil
+}
+
+// Mount mounts the storage pool.
+func (d *nfs) Mount() (bool, error) {
+	// Check if already mounted.
+	if filesystem.IsMountPoint(GetPoolMountPath(d.name)) {
+		return false, nil
+	}
+
+	err := d.mountExport(GetPoolMountPath(d.name))
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Unmount unmounts the storage pool.
+func (d *nfs) Unmount() (bool, error) {
+	return forceUnmount(GetPoolMountPath(d.name))
+}
+
+// MigrationTypes returns the supported migration types and options supported by the driver.
+func (d *nfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
+	var transportType migration.MigrationFSType
+	var rsyncFeatures []string
+
+	// Do not pass compression argument to rsync if the associated
+	// config key, that is rsync.compression, is set to false.
+	if shared.IsFalse(d.Config()["rsync.compression"]) {
+		rsyncFeatures = []string{"delete", "bidirectional"}
+	} else {
+		rsyncFeatures = []string{"delete", "compress", "bidirectional"}
+	}
+
+	if IsContentBlock(contentType) {
+		transportType = migration.MigrationFSType_BLOCK_AND_RSYNC
+	} else {
+		transportType = migration.MigrationFSType_RSYNC
+	}
+
+	// Do not support xattr transfer on NFS.
+	return []migration.Type{
+		{
+			FSType:   transportType,
+			Features: rsyncFeatures,
+		},
+	}
+}
+
+// tempMount mounts the NFS export on a temporary mountpoint.
+// It returns the mountpoint and a function that unmounts and removes it.
+func (d *nfs) tempMount() (string, func(), error) {
+	mountPath, err := os.MkdirTemp("", "lxd_nfs_")
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to create temporary directory under: %w", err)
+	}
+
+	err = os.Chmod(mountPath, 0700)
+	if err != nil {
+		_ = os.RemoveAll(mountPath)
+		return "", nil, fmt.Errorf("Failed to chmod '%s': %w", mountPath, err)
+	}
+
+	mountPoint := filepath.Join(mountPath, "mount")
+	err = os.Mkdir(mountPoint, 0700)
+	if err != nil {
+		_ = os.RemoveAll(mountPath)
+		return "", nil, fmt.Errorf("Failed to create directory '%s': %w", mountPoint, err)
+	}
+
+	err = d.mountExport(mountPoint)
+	if err != nil {
+		_ = os.RemoveAll(mountPath)
+		return "", nil, err
+	}
+
+	cleanup := func() {
+		_, _ = forceUnmount(mountPoint)
+		_ = os.RemoveAll(mountPath)
+	}
+
+	return mountPoint, cleanup, nil
+}
+
+// mountExport mounts the NFS export from the pool's source on the given path.
+func (d *nfs) mountExport(path string) error {
+	host, _, err := nfsParseSource(d.config["source"])
+	if err != nil {
+		return err
+	}
+
+	addr, err := nfsResolveHost(host)
+	if err != nil {
+		return err
+	}
+
+	version := d.config["nfs.version"]
+	if version == "" {
+		version = nfsDefaultVersion
+	}
+
+	fsType, options := nfsMountOptions(version, addr, d.config["nfs.mount_options"])
+
+	return TryMount(d.config["source"], path, fsType, 0, options)
+}
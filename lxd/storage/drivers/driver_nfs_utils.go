@@ -0,0 +1,84 @@
+package drivers
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// nfsParseSource splits an NFS source of the form HOST:PATH into its host and path.
+// IPv6 addresses must be enclosed in square brackets, for example [2001:db8::1]:/export.
+func nfsParseSource(source string) (string, string, error) {
+	var host string
+	var path string
+
+	if strings.HasPrefix(source, "[") {
+		end := strings.Index(source, "]:")
+		if end < 0 {
+			return "", "", fmt.Errorf("Invalid NFS source %q: Expected [HOST]:PATH", source)
+		}
+
+		host = source[1:end]
+		path = source[end+2:]
+
+		if net.ParseIP(host) == nil {
+			return "", "", fmt.Errorf("Invalid NFS source %q: Invalid IPv6 address %q", source, host)
+		}
+	} else {
+		var found bool
+
+		host, path, found = strings.Cut(source, ":")
+		if !found {
+			return "", "", fmt.Errorf("Invalid NFS source %q: Expected HOST:PATH", source)
+		}
+
+		if strings.Contains(host, "/") {
+			return "", "", fmt.Errorf("Invalid NFS source %q: Invalid host %q", source, host)
+		}
+	}
+
+	if host == "" {
+		return "", "", fmt.Errorf("Invalid NFS source %q: Missing host", source)
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return "", "", fmt.Errorf("Invalid NFS source %q: Path must be absolute", source)
+	}
+
+	return host, path, nil
+}
+
+// nfsResolveHost returns the IP address of an NFS server.
+// The kernel doesn't resolve host names itself, so the address is passed through the addr= mount option.
+func nfsResolveHost(host string) (string, error) {
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		return "", fmt.Errorf("Failed to resolve NFS server %q: %w", host, err)
+	}
+
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("Failed to resolve NFS server %q: No address found", host)
+	}
+
+	return addrs[0], nil
+}
+
+// nfsMountOptions returns the filesystem type and mount options for the given NFS version, server address and
+// additional user supplied options.
+func nfsMountOptions(version string, addr string, extraOptions string) (string, string) {
+	fsType := "nfs4"
+	if version == "3" {
+		fsType = "nfs"
+	}
+
+	options := []string{fmt.Sprintf("vers=%s", version), fmt.Sprintf("addr=%s", addr)}
+	if extraOptions != "" {
+		options = append(options, extraOptions)
+	}
+
+	return fsType, strings.Join(options, ",")
+}
@@ -0,0 +1,56 @@
+package drivers
+
+import (
+	"testing"
+)
+
+func Test_nfsParseSource(t *testing.T) {
+	tests := []struct {
+		source   string
+		wantHost string
+		wantPath string
+		wantErr  bool
+	}{
+		{"nfs.example.com:/srv/lxd", "nfs.example.com", "/srv/lxd", false},
+		{"192.0.2.10:/", "192.0.2.10", "/", false},
+		{"[2001:db8::1]:/srv/lxd", "2001:db8::1", "/srv/lxd", false},
+		{"nfs.example.com", "", "", true},
+		{"nfs.example.com:srv/lxd", "", "", true},
+		{":/srv/lxd", "", "", true},
+		{"/srv/lxd:/foo", "", "", true},
+		{"[2001:db8::1]/srv/lxd", "", "", true},
+		{"[nfs.example.com]:/srv/lxd", "", "", true},
+	}
+
+	for _, tt := range tests {
+		host, path, err := nfsParseSource(tt.source)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("nfsParseSource(%q) expected an error", tt.source)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("nfsParseSource(%q) unexpected error: %v", tt.source, err)
+			continue
+		}
+
+		if host != tt.wantHost || path != tt.wantPath {
+			t.Errorf("nfsParseSource(%q) = %q, %q, want %q, %q", tt.source, host, path, tt.wantHost, tt.wantPath)
+		}
+	}
+}
+
+func Test_nfsMountOptions(t *testing.T) {
+	fsType, options := nfsMountOptions("4.2", "192.0.2.10", "")
+	if fsType != "nfs4" || options != "vers=4.2,addr=192.0.2.10" {
+		t.Errorf("Unexpected NFSv4 mount: %q %q", fsType, options)
+	}
+
+	fsType, options = nfsMountOptions("3", "192.0.2.10", "nolock,proto=tcp")
+	if fsType != "nfs" || options != "vers=3,addr=192.0.2.10,nolock,proto=tcp" {
+		t.Errorf("Unexpected NFSv3 mount: %q %q", fsType, options)
+	}
+}
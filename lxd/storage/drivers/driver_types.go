@@ -12,6 +12,7 @@ type Info struct {
 	OptimizedImages              bool         // Whether driver stores images as separate volume.
 	OptimizedBackups             bool         // Whether driver supports optimized volume backups.
 	OptimizedBackupHeader        bool         // Whether driver generates an optimised backup header file in backup.
+	OptimizedIncrementalBackups  bool         // Whether driver supports optimized incremental instance backups.
 	PreservesInodes              bool         // Whether driver preserves inodes when volumes are moved hosts.
 	BlockBacking                 bool         // Whether driver uses block devices as backing store.
 	RunningCopyFreeze            bool         // Whether instance should be frozen during snapshot if running.
@@ -164,10 +164,13 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 			return err
 		}
 
+		// Make room for the LUKS header of encrypted volumes.
+		sizeBytes = luksRawSize(vol, sizeBytes)
+
 		var opts []string
 
-		if vol.contentType == ContentTypeFS {
-			// Use volmode=dev so volume is visible as we need to run makeFSType.
+		if vol.contentType == ContentTypeFS || vol.IsEncrypted() {
+			// Use volmode=dev so volume is visible as we need to run makeFSType or format it as LUKS device.
 			opts = []string{"volmode=dev"}
 		} else {
 			// Use volmode=none so volume is invisible until mounted.
@@ -229,6 +232,24 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 				return err
 			}
 
+			err = d.setDatasetProperties(d.dataset(vol, false), "volmode=none")
+			if err != nil {
+				return err
+			}
+		} else if vol.IsEncrypted() {
+			// Wait half a second to give udev a chance to kick in.
+			time.Sleep(500 * time.Millisecond)
+
+			devPath, err := d.getVolumeDiskPathFromDataset(d.dataset(vol, false))
+			if err != nil {
+				return err
+			}
+
+			err = d.luksFormat(vol, devPath)
+			if err != nil {
+				return err
+			}
+
 			err = d.setDatasetProperties(d.dataset(vol, false), "volmode=none")
 			if err != nil {
 				return err
@@ -341,7 +362,7 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
 	// Handle the non-optimized tarballs through the generic unpacker.
 	if !*srcBackup.OptimizedStorage {
-		return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcData, op)
+		return genericVFSBackupUnpack(d, d.state.OS, vol, srcBackup.Snapshots, srcBackup.BaseSnapshot, srcData, op)
 	}
 
 	volExists, err := d.HasVolume(vol)
@@ -349,7 +370,12 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 		return nil, nil, err
 	}
 
-	if volExists {
+	// Incremental backups are received on top of the existing volume, whose latest snapshot must be the base
+	// snapshot of the backup.
+	incremental := srcBackup.BaseSnapshot != ""
+	if incremental && !volExists {
+		return nil, nil, fmt.Errorf("Cannot restore incremental backup, volume doesn't exist on target")
+	} else if !incremental && volExists {
 		return nil, nil, fmt.Errorf("Cannot restore volume, already exists on target")
 	}
 
@@ -365,8 +391,10 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 			_ = d.DeleteVolumeSnapshot(snapVol, op)
 		}
 
-		// And lastly the main volume.
-		_ = d.DeleteVolume(vol, op)
+		// And lastly the main volume (unless it existed before).
+		if !incremental {
+			_ = d.DeleteVolume(vol, op)
+		}
 	}
 
 	// Only execute the revert function if we have had an error internally.
@@ -548,8 +576,33 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, op *operations.Operation) error {
+	return d.createVolumeFromCopy(vol, srcVol, copySnapshots, allowInconsistent, false, op)
+}
+
+// CloneVolume creates a copy-on-write clone of a volume using zfs clone, regardless of zfs.clone_copy.
+func (d *zfs) CloneVolume(vol Volume, srcVol Volume, op *operations.Operation) error {
+	return d.createVolumeFromCopy(vol, srcVol, false, false, true, op)
+}
+
+// createVolumeFromCopy copies a volume within the pool. If clone is true, the new volume is always created as a
+// clone of a snapshot of the source volume.
+func (d *zfs) createVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool, allowInconsistent bool, clone bool, op *operations.Operation) error {
 	var err error
 
+	// Encrypted volumes are copied through their decrypted devices, as the copy may not share the key of the source.
+	if vol.IsEncrypted() || srcVol.IsEncrypted() {
+		var srcSnapshots []Volume
+
+		if copySnapshots && !srcVol.IsSnapshot() {
+			srcSnapshots, err = srcVol.Snapshots(op)
+			if err != nil {
+				return err
+			}
+		}
+
+		return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, false, allowInconsistent, op)
+	}
+
 	// Revert handling
 	revert := revert.New()
 	defer revert.Fail()
@@ -570,7 +623,7 @@ func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 		srcFSVol := srcVol.NewVMBlockFilesystemVolume()
 		fsVol := vol.NewVMBlockFilesystemVolume()
 
-		err = d.CreateVolumeFromCopy(fsVol, srcFSVol, copySnapshots, false, op)
+		err = d.createVolumeFromCopy(fsVol, srcFSVol, copySnapshots, false, clone, op)
 		if err != nil {
 			return err
 		}
@@ -617,7 +670,7 @@ func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 		}
 
 		// If zfs.clone_copy is disabled delete the snapshot at the end.
-		if shared.IsFalse(d.config["zfs.clone_copy"]) || len(snapshots) > 0 {
+		if !clone && (shared.IsFalse(d.config["zfs.clone_copy"]) || len(snapshots) > 0) {
 			// Delete the snapshot at the end.
 			defer func() {
 				// Delete snapshot (or mark for deferred deletion if cannot be deleted currently).
@@ -646,8 +699,8 @@ func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 	// Delete the volume created on failure.
 	revert.Add(func() { _ = d.DeleteVolume(vol, op) })
 
-	// If zfs.clone_copy is disabled or source volume has snapshots, then use full copy mode.
-	if shared.IsFalse(d.config["zfs.clone_copy"]) || len(snapshots) > 0 {
+	// If zfs.clone_copy is disabled or source volume has snapshots, then use full copy mode (unless cloning).
+	if !clone && (shared.IsFalse(d.config["zfs.clone_copy"]) || len(snapshots) > 0) {
 		snapName := strings.SplitN(srcSnapshot, "@", 2)[1]
 
 		// Send/receive the snapshot.
@@ -1215,6 +1268,11 @@ func (d *zfs) RefreshVolume(vol Volume, srcVol Volume, srcSnapshots []Volume, al
 	var targetSnapshots []Volume
 	var srcSnapshotsAll []Volume
 
+	// Encrypted volumes are copied through their decrypted devices, as the copy may not share the key of the source.
+	if vol.IsEncrypted() || srcVol.IsEncrypted() {
+		return genericVFSCopyVolume(d, nil, vol, srcVol, srcSnapshots, true, allowInconsistent, op)
+	}
+
 	if !srcVol.IsSnapshot() {
 		// Get target snapshots
 		targetSnapshots, err = vol.Snapshots(op)
@@ -1512,6 +1570,7 @@ func (d *zfs) HasVolume(vol Volume) (bool, error) {
 // commonVolumeRules returns validation rules which are common for pool and volume.
 func (d *zfs) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
+		"block.encryption": validate.Optional(validate.IsOneOf("luks2")),
 		// lxdmeta:generate(entities=storage-zfs; group=volume-conf; key=block.filesystem)
 		// Valid options are: `btrfs`, `ext4`, `xfs`
 		// If not set, `ext4` is assumed.
@@ -1599,7 +1658,12 @@ func (d *zfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		delete(commonRules, "block.mount_options")
 	}
 
-	return d.validateVolume(vol, commonRules, removeUnknownKeys)
+	err := d.validateVolume(vol, commonRules, removeUnknownKeys)
+	if err != nil {
+		return err
+	}
+
+	return luksValidateVolume(vol)
 }
 
 // UpdateVolume applies config changes to the volume.
@@ -1703,6 +1767,9 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 
 		sizeBytes = d.roundVolumeBlockSizeBytes(sizeBytes)
 
+		// Make room for the LUKS header of encrypted volumes.
+		sizeBytes = luksRawSize(vol, sizeBytes)
+
 		oldSizeBytesStr, err := d.getDatasetProperty(d.dataset(vol, false), "volsize")
 		if err != nil {
 			return err
@@ -1805,6 +1872,14 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			if err != nil {
 				return err
 			}
+
+			// Resize the opened LUKS device to the new size of the volume.
+			if vol.IsEncrypted() {
+				err = d.luksResize(vol)
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as
@@ -1914,7 +1989,16 @@ func (d *zfs) getVolumeDiskPathFromDataset(dataset string) (string, error) {
 
 // GetVolumeDiskPath returns the location of a root disk block device.
 func (d *zfs) GetVolumeDiskPath(vol Volume) (string, error) {
-	return d.getVolumeDiskPathFromDataset(d.dataset(vol, false))
+	devPath, err := d.getVolumeDiskPathFromDataset(d.dataset(vol, false))
+	if err != nil {
+		return "", err
+	}
+
+	if vol.IsEncrypted() {
+		return d.luksOpen(vol, devPath)
+	}
+
+	return devPath, nil
 }
 
 // ListVolumes returns a list of LXD volumes in storage pool.
@@ -2083,11 +2167,19 @@ func (d *zfs) deactivateVolume(vol Volume) (bool, error) {
 	}
 
 	if current == "dev" {
-		devPath, err := d.GetVolumeDiskPath(vol)
+		devPath, err := d.getVolumeDiskPathFromDataset(dataset)
 		if err != nil {
 			return false, fmt.Errorf("Failed locating zvol for deactivation: %w", err)
 		}
 
+		// Close the LUKS device first as it holds the zvol open.
+		if vol.IsEncrypted() {
+			_, err = d.luksClose(vol)
+			if err != nil {
+				return false, err
+			}
+		}
+
 		// We cannot wait longer than the operationlock.TimeoutShutdown to avoid continuing
 		// the unmount process beyond the ongoing request.
 		waitDuration := time.Minute * 5
@@ -2440,6 +2532,11 @@ func (d *zfs) MigrateVolume(vol Volume, conn io.ReadWriteCloser, volSrcArgs *mig
 		return ErrNotSupported
 	}
 
+	// Encrypted volumes are sent decrypted so that the target can encrypt them with a key of its own.
+	if vol.IsEncrypted() {
+		return fmt.Errorf("Encrypted volumes cannot be migrated using zfs send/receive")
+	}
+
 	// Handle zfs send/receive migration.
 	if volSrcArgs.MultiSync || volSrcArgs.FinalSync {
 		// This is not needed if the migration is performed using zfs send/receive.
@@ -2681,7 +2778,7 @@ func (d *zfs) readonlySnapshot(vol Volume) (string, revert.Hook, error) {
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, op *operations.Operation) error {
+func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, op *operations.Operation) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -2700,12 +2797,23 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 			vol.mountCustomPath = snapshotPath
 		}
 
-		return genericVFSBackupVolume(d, vol, tarWriter, snapshots, op)
+		return genericVFSBackupVolume(d, vol, tarWriter, snapshots, baseSnapshot, op)
 	}
 
 	// Optimized backup.
 
-	if len(snapshots) > 0 {
+	// The optimized backup would contain the encrypted data, which can only be restored where the volume key can be unwrapped.
+	if vol.IsEncrypted() {
+		return fmt.Errorf("Optimized backups of encrypted volumes are not supported")
+	}
+
+	if baseSnapshot != "" {
+		// Check the base snapshot and requested snapshots exist in storage.
+		err := vol.SnapshotsExist(append([]string{baseSnapshot}, snapshots...), op)
+		if err != nil {
+			return err
+		}
+	} else if len(snapshots) > 0 {
 		// Check requested snapshot match those in storage.
 		err := vol.SnapshotsMatch(snapshots, op)
 		if err != nil {
@@ -2716,7 +2824,7 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 	// Backup VM config volumes first.
 	if vol.IsVMBlock() {
 		fsVol := vol.NewVMBlockFilesystemVolume()
-		err := d.BackupVolume(fsVol, tarWriter, optimized, snapshots, op)
+		err := d.BackupVolume(fsVol, tarWriter, optimized, snapshots, baseSnapshot, op)
 		if err != nil {
 			return err
 		}
@@ -2776,13 +2884,19 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 	}
 
 	// Handle snapshots.
+	// For incremental backups, the first stream is generated relative to the base snapshot.
 	finalParent := ""
+	if baseSnapshot != "" {
+		baseVol, _ := vol.NewSnapshot(baseSnapshot)
+		finalParent = d.dataset(baseVol, false)
+	}
+
 	if len(snapshots) > 0 {
 		for i, snapName := range snapshots {
 			snapshot, _ := vol.NewSnapshot(snapName)
 
 			// Figure out parent and current subvolumes.
-			parent := ""
+			parent := finalParent
 			if i > 0 {
 				oldSnapshot, _ := vol.NewSnapshot(snapshots[i-1])
 				parent = d.dataset(oldSnapshot, false)
@@ -3215,6 +3329,14 @@ func (d *zfs) UnmountVolumeSnapshot(snapVol Volume, op *operations.Operation) (b
 				return false, ErrInUse
 			}
 
+			// Close the LUKS device first as it holds the snapshot device open.
+			if snapVol.IsEncrypted() {
+				_, err = d.luksClose(snapVol)
+				if err != nil {
+					return false, err
+				}
+			}
+
 			err := d.setDatasetProperties(parentDataset, "snapdev=hidden")
 			if err != nil {
 				return false, err
@@ -1,12 +1,14 @@
 package drivers
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/canonical/lxd/lxd/archive"
@@ -27,6 +29,9 @@ import (
 // genericVolumeBlockExtension extension used for generic block volume disk files.
 const genericVolumeBlockExtension = "img"
 
+// genericVolumeDeletedExtension extension used for the list of files deleted since the base of incremental backups.
+const genericVolumeDeletedExtension = "deleted"
+
 // genericVolumeDiskFile used to indicate the file name used for block volume disk files.
 const genericVolumeDiskFile = "root.img"
 
@@ -471,8 +476,17 @@ func genericVFSGetVolumeDiskPath(vol Volume) (string, error) {
 }
 
 // genericVFSBackupVolume is a generic BackupVolume implementation for VFS-only drivers.
-func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.InstanceTarWriter, snapshots []string, op *operations.Operation) error {
-	if len(snapshots) > 0 {
+// If baseSnapshot is set an incremental backup is generated, in which the filesystem content of each volume only
+// includes the files that changed since the volume preceding it (starting with the base snapshot), along with a list
+// of the files that were deleted. Block volumes are always copied in full.
+func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.InstanceTarWriter, snapshots []string, baseSnapshot string, op *operations.Operation) error {
+	if baseSnapshot != "" {
+		// Check the base snapshot and requested snapshots exist in storage.
+		err := vol.SnapshotsExist(append([]string{baseSnapshot}, snapshots...), op)
+		if err != nil {
+			return err
+		}
+	} else if len(snapshots) > 0 {
 		// Check requested snapshot match those in storage.
 		err := vol.SnapshotsMatch(snapshots, op)
 		if err != nil {
@@ -481,11 +495,61 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 	}
 
 	// Define a function that can copy a volume into the backup target location.
-	backupVolume := func(v Volume, prefix string) error {
+	// If parentMountPath is set, only the files that changed since the parent volume are copied.
+	backupVolume := func(v Volume, prefix string, parentMountPath string) error {
 		return v.MountTask(func(mountPath string, op *operations.Operation) error {
 			// Reset hard link cache as we are copying a new volume (instance or snapshot).
 			tarWriter.ResetHardLinkMap()
 
+			// walkVolume copies the filesystem content of the volume into the backup, skipping the excluded
+			// files and, for incremental backups, those unchanged since the parent volume.
+			walkVolume := func(mountPath string, ignoreGrowth bool, exclude []string) error {
+				err := filepath.Walk(mountPath, func(srcPath string, fi os.FileInfo, err error) error {
+					if err != nil {
+						if os.IsNotExist(err) {
+							logger.Warnf("File vanished during export: %q, skipping", srcPath)
+							return nil
+						}
+
+						return fmt.Errorf("Error walking file during export: %q: %w", srcPath, err)
+					}
+
+					// Skip any exluded files.
+					if len(exclude) > 0 && shared.StringHasPrefix(srcPath, exclude...) {
+						return nil
+					}
+
+					relPath := strings.TrimPrefix(srcPath, mountPath)
+
+					// Skip files that haven't changed since the parent volume.
+					if parentMountPath != "" && genericVFSFileUnchanged(filepath.Join(parentMountPath, relPath), fi) {
+						return nil
+					}
+
+					name := filepath.Join(prefix, relPath)
+
+					// Write the file to the tarball with ignoreGrowth enabled if requested so that if
+					// the source file grows during copy we only copy up to the original size.
+					// This means that the file in the tarball may be inconsistent.
+					err = tarWriter.WriteFile(name, srcPath, fi, ignoreGrowth)
+					if err != nil {
+						return fmt.Errorf("Error adding %q as %q to tarball: %w", srcPath, name, err)
+					}
+
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+
+				if parentMountPath == "" {
+					return nil
+				}
+
+				// Record the files deleted since the parent volume.
+				return genericVFSBackupWriteDeleted(tarWriter, prefix, parentMountPath, mountPath, exclude)
+			}
+
 			if v.contentType == ContentTypeBlock {
 				blockPath, err := d.GetVolumeDiskPath(v)
 				if err != nil {
@@ -514,24 +578,7 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 					logMsg := "Copying virtual machine config volume"
 
 					d.Logger().Debug(logMsg, logger.Ctx{"sourcePath": mountPath, "prefix": prefix})
-					err = filepath.Walk(mountPath, func(srcPath string, fi os.FileInfo, err error) error {
-						if err != nil {
-							return err
-						}
-
-						// Skip any exluded files.
-						if shared.StringHasPrefix(srcPath, exclude...) {
-							return nil
-						}
-
-						name := filepath.Join(prefix, strings.TrimPrefix(srcPath, mountPath))
-						err = tarWriter.WriteFile(name, srcPath, fi, false)
-						if err != nil {
-							return fmt.Errorf("Error adding %q as %q to tarball: %w", srcPath, name, err)
-						}
-
-						return nil
-					})
+					err = walkVolume(mountPath, false, exclude)
 					if err != nil {
 						return err
 					}
@@ -574,7 +621,7 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 					logMsg = "Copying custom filesystem volume"
 				}
 
-				d.Logger().Debug(logMsg, logger.Ctx{"sourcePath": mountPath, "prefix": prefix})
+				d.Logger().Debug(logMsg, logger.Ctx{"sourcePath": mountPath, "prefix": prefix, "incremental": parentMountPath != ""})
 
 				// Follow the target if mountPath is a symlink.
 				// Functions like filepath.Walk() won't list any directory content otherwise.
@@ -587,34 +634,45 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 					}
 				}
 
-				return filepath.Walk(mountPath, func(srcPath string, fi os.FileInfo, err error) error {
-					if err != nil {
-						if os.IsNotExist(err) {
-							logger.Warnf("File vanished during export: %q, skipping", srcPath)
-							return nil
-						}
-
-						return fmt.Errorf("Error walking file during export: %q: %w", srcPath, err)
-					}
+				return walkVolume(mountPath, true, nil)
+			}
 
-					name := filepath.Join(prefix, strings.TrimPrefix(srcPath, mountPath))
+			return nil
+		}, op)
+	}
 
-					// Write the file to the tarball with ignoreGrowth enabled so that if the
-					// source file grows during copy we only copy up to the original size.
-					// This means that the file in the tarball may be inconsistent.
-					err = tarWriter.WriteFile(name, srcPath, fi, true)
-					if err != nil {
-						return fmt.Errorf("Error adding %q as %q to tarball: %w", srcPath, name, err)
-					}
+	// Define a function that copies a volume into the backup target location, only including the changes since
+	// the parent volume if one is specified.
+	backupVolumeIncremental := func(v Volume, prefix string, parentVol *Volume) error {
+		if parentVol == nil {
+			return backupVolume(v, prefix, "")
+		}
 
-					return nil
-				})
+		return parentVol.MountTask(func(parentMountPath string, op *operations.Operation) error {
+			// Follow the target if parentMountPath is a symlink.
+			target, err := os.Readlink(parentMountPath)
+			if err == nil {
+				_, err = os.Stat(target)
+				if err == nil {
+					parentMountPath = target
+				}
 			}
 
-			return nil
+			return backupVolume(v, prefix, parentMountPath)
 		}, op)
 	}
 
+	// For incremental backups each volume is compared with the one preceding it, starting with the base snapshot.
+	var parentVol *Volume
+	if baseSnapshot != "" {
+		baseVol, err := vol.NewSnapshot(baseSnapshot)
+		if err != nil {
+			return err
+		}
+
+		parentVol = &baseVol
+	}
+
 	// Handle snapshots.
 	if len(snapshots) > 0 {
 		snapshotsPrefix := "backup/snapshots"
@@ -631,10 +689,14 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 				return err
 			}
 
-			err = backupVolume(snapVol, prefix)
+			err = backupVolumeIncremental(snapVol, prefix, parentVol)
 			if err != nil {
 				return err
 			}
+
+			if parentVol != nil {
+				parentVol = &snapVol
+			}
 		}
 	}
 
@@ -646,7 +708,7 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 		prefix = "backup/volume"
 	}
 
-	err := backupVolume(vol, prefix)
+	err := backupVolumeIncremental(vol, prefix, parentVol)
 	if err != nil {
 		return err
 	}
@@ -654,12 +716,167 @@ func genericVFSBackupVolume(d Driver, vol Volume, tarWriter *instancewriter.Inst
 	return nil
 }
 
+// genericVFSFileUnchanged returns true if fi describes a regular file that is identical in type, permissions,
+// ownership, size and modification time to the file at parentPath.
+func genericVFSFileUnchanged(parentPath string, fi os.FileInfo) bool {
+	if !fi.Mode().IsRegular() {
+		return false
+	}
+
+	parentFi, err := os.Lstat(parentPath)
+	if err != nil {
+		return false
+	}
+
+	if parentFi.Mode() != fi.Mode() || parentFi.Size() != fi.Size() || !parentFi.ModTime().Equal(fi.ModTime()) {
+		return false
+	}
+
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	parentStat, parentOk := parentFi.Sys().(*syscall.Stat_t)
+	if !ok || !parentOk {
+		return false
+	}
+
+	return stat.Uid == parentStat.Uid && stat.Gid == parentStat.Gid
+}
+
+// genericVFSBackupWriteDeleted writes the list of files present in parentMountPath but not in mountPath to the
+// backup tarball as a NUL separated list of paths relative to the volume root, alongside the volume content.
+func genericVFSBackupWriteDeleted(tarWriter *instancewriter.InstanceTarWriter, prefix string, parentMountPath string, mountPath string, exclude []string) error {
+	var deleted bytes.Buffer
+
+	err := filepath.Walk(parentMountPath, func(srcPath string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("Error walking file during export: %q: %w", srcPath, err)
+		}
+
+		relPath := strings.TrimPrefix(srcPath, parentMountPath)
+		if relPath == "" {
+			return nil
+		}
+
+		if len(exclude) > 0 && shared.StringHasPrefix(filepath.Join(mountPath, relPath), exclude...) {
+			return nil
+		}
+
+		_, err = os.Lstat(filepath.Join(mountPath, relPath))
+		if err == nil {
+			return nil
+		}
+
+		if !os.IsNotExist(err) {
+			return err
+		}
+
+		deleted.WriteString(strings.TrimPrefix(relPath, string(os.PathSeparator)))
+		deleted.WriteByte(0)
+
+		// There is no need to list the content of deleted directories.
+		if fi.IsDir() {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fi := instancewriter.FileInfo{
+		FileName:    fmt.Sprintf("%s.%s", prefix, genericVolumeDeletedExtension),
+		FileSize:    int64(deleted.Len()),
+		FileMode:    0600,
+		FileModTime: time.Now(),
+	}
+
+	err = tarWriter.WriteFileFromReader(&deleted, &fi)
+	if err != nil {
+		return fmt.Errorf("Error adding deleted files list %q to tarball: %w", fi.FileName, err)
+	}
+
+	return nil
+}
+
+// genericVFSBackupApplyDeleted removes the files listed as deleted for srcPrefix in an incremental backup tarball
+// from the volume mounted at mountPath.
+func genericVFSBackupApplyDeleted(r io.ReadSeeker, unpacker []string, sysOS *sys.OS, srcPrefix string, mountPath string) error {
+	_, err := r.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	tr, cancelFunc, err := archive.CompressedTarReader(context.Background(), r, unpacker, sysOS, mountPath)
+	if err != nil {
+		return err
+	}
+
+	defer cancelFunc()
+
+	srcFile := fmt.Sprintf("%s.%s", srcPrefix, genericVolumeDeletedExtension)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil // No files were deleted.
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if hdr.Name != srcFile {
+			continue
+		}
+
+		deleted, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("Failed reading deleted files list %q: %w", srcFile, err)
+		}
+
+		for _, relPath := range strings.Split(string(deleted), "\x00") {
+			if relPath == "" {
+				continue
+			}
+
+			targetPath := filepath.Join(mountPath, relPath)
+			if !strings.HasPrefix(targetPath, mountPath+string(os.PathSeparator)) {
+				return fmt.Errorf("Invalid deleted file path %q in backup", relPath)
+			}
+
+			// Don't follow symlinks in parent directories as they may point outside of the volume.
+			parentPath := filepath.Dir(targetPath)
+			for parentPath != mountPath {
+				fi, err := os.Lstat(parentPath)
+				if err != nil || fi.Mode()&os.ModeSymlink != 0 {
+					break
+				}
+
+				parentPath = filepath.Dir(parentPath)
+			}
+
+			if parentPath != mountPath {
+				continue // Parent is missing or a symlink, nothing to remove inside the volume.
+			}
+
+			err = os.RemoveAll(targetPath)
+			if err != nil {
+				return fmt.Errorf("Failed removing deleted file %q: %w", targetPath, err)
+			}
+		}
+
+		return nil
+	}
+}
+
 // genericVFSBackupUnpack unpacks a non-optimized backup tarball through a storage driver.
 // Returns a post hook function that should be called once the database entries for the restored backup have been
 // created and a revert function that can be used to undo the actions this function performs should something
 // subsequently fail. For VolumeTypeCustom volumes, a nil post hook is returned as it is expected that the DB
 // record be created before the volume is unpacked due to differences in the archive format that allows this.
-func genericVFSBackupUnpack(d Driver, sysOS *sys.OS, vol Volume, snapshots []string, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
+// If baseSnapshot is set the tarball is treated as an incremental backup and is applied on top of the existing
+// volume once restored to the base snapshot.
+func genericVFSBackupUnpack(d Driver, sysOS *sys.OS, vol Volume, snapshots []string, baseSnapshot string, srcData io.ReadSeeker, op *operations.Operation) (VolumePostHook, revert.Hook, error) {
 	// Define function to unpack a volume from a backup tarball file.
 	unpackVolume := func(r io.ReadSeeker, tarArgs []string, unpacker []string, srcPrefix string, mountPath string) error {
 		volTypeName := "container"
@@ -669,10 +886,12 @@ func genericVFSBackupUnpack(d Driver, sysOS *sys.OS, vol Volume, snapshots []str
 			volTypeName = "custom"
 		}
 
-		// Clear the volume ready for unpack.
-		err := wipeDirectory(mountPath)
-		if err != nil {
-			return fmt.Errorf("Error clearing volume before unpack: %w", err)
+		// Clear the volume ready for unpack, unless applying an incremental backup on top of it.
+		if baseSnapshot == "" {
+			err := wipeDirectory(mountPath)
+			if err != nil {
+				return fmt.Errorf("Error clearing volume before unpack: %w", err)
+			}
 		}
 
 		// Unpack the filesystem parts of the volume (for containers and custom filesystem volumes that is
@@ -728,6 +947,14 @@ func genericVFSBackupUnpack(d Driver, sysOS *sys.OS, vol Volume, snapshots []str
 			if err != nil {
 				return fmt.Errorf("Error starting unpack: %w", err)
 			}
+
+			// Remove the files deleted since the previous volume in the incremental backup.
+			if baseSnapshot != "" {
+				err = genericVFSBackupApplyDeleted(r, unpacker, sysOS, srcPrefix, mountPath)
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		// Extract block file to block volume.
@@ -819,17 +1046,29 @@ func genericVFSBackupUnpack(d Driver, sysOS *sys.OS, vol Volume, snapshots []str
 		return nil, nil, err
 	}
 
-	if volExists {
-		return nil, nil, fmt.Errorf("Cannot restore volume, already exists on target")
-	}
+	if baseSnapshot != "" {
+		if !volExists {
+			return nil, nil, fmt.Errorf("Cannot restore incremental backup, volume doesn't exist on target")
+		}
 
-	// Create new empty volume.
-	err = d.CreateVolume(vol, nil, nil)
-	if err != nil {
-		return nil, nil, err
-	}
+		// Reset the volume to the base snapshot the incremental backup was generated from.
+		err = d.RestoreVolume(vol, baseSnapshot, op)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed restoring volume to base snapshot %q: %w", baseSnapshot, err)
+		}
+	} else {
+		if volExists {
+			return nil, nil, fmt.Errorf("Cannot restore volume, already exists on target")
+		}
 
-	revert.Add(func() { _ = d.DeleteVolume(vol, op) })
+		// Create new empty volume.
+		err = d.CreateVolume(vol, nil, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		revert.Add(func() { _ = d.DeleteVolume(vol, op) })
+	}
 
 	if len(snapshots) > 0 {
 		// Create new snapshots directory.
@@ -0,0 +1,88 @@
+package drivers
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/instancewriter"
+)
+
+// Test genericVFSFileUnchanged.
+func TestGenericVFSFileUnchanged(t *testing.T) {
+	parentPath := t.TempDir()
+	mountPath := t.TempDir()
+
+	modTime := time.Now().Add(-time.Hour)
+
+	writeFile := func(dir string, name string, content string) os.FileInfo {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+		fi, err := os.Lstat(path)
+		require.NoError(t, err)
+
+		return fi
+	}
+
+	writeFile(parentPath, "same", "foo")
+	fi := writeFile(mountPath, "same", "foo")
+	assert.True(t, genericVFSFileUnchanged(filepath.Join(parentPath, "same"), fi))
+
+	writeFile(parentPath, "resized", "foo")
+	fi = writeFile(mountPath, "resized", "foobar")
+	assert.False(t, genericVFSFileUnchanged(filepath.Join(parentPath, "resized"), fi))
+
+	fi = writeFile(mountPath, "new", "foo")
+	assert.False(t, genericVFSFileUnchanged(filepath.Join(parentPath, "new"), fi))
+
+	// Directories are always considered changed.
+	require.NoError(t, os.Mkdir(filepath.Join(parentPath, "dir"), 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(mountPath, "dir"), 0755))
+	fi, err := os.Lstat(filepath.Join(mountPath, "dir"))
+	require.NoError(t, err)
+	assert.False(t, genericVFSFileUnchanged(filepath.Join(parentPath, "dir"), fi))
+}
+
+// Test genericVFSBackupWriteDeleted.
+func TestGenericVFSBackupWriteDeleted(t *testing.T) {
+	parentPath := t.TempDir()
+	mountPath := t.TempDir()
+
+	for _, dir := range []string{"kept", "removed", "removed/sub"} {
+		require.NoError(t, os.Mkdir(filepath.Join(parentPath, dir), 0755))
+	}
+
+	for _, file := range []string{"kept/file", "kept/old", "removed/file", "removed/sub/file"} {
+		require.NoError(t, os.WriteFile(filepath.Join(parentPath, file), nil, 0644))
+	}
+
+	require.NoError(t, os.Mkdir(filepath.Join(mountPath, "kept"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(mountPath, "kept", "file"), nil, 0644))
+
+	var buf bytes.Buffer
+	tarWriter := instancewriter.NewInstanceTarWriter(&buf, nil)
+	require.NoError(t, genericVFSBackupWriteDeleted(tarWriter, "backup/container", parentPath, mountPath, nil))
+	require.NoError(t, tarWriter.Close())
+
+	tr := tar.NewReader(&buf)
+	hdr, err := tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "backup/container.deleted", hdr.Name)
+
+	content, err := io.ReadAll(tr)
+	require.NoError(t, err)
+
+	// Deleted directories are listed without their content.
+	deleted := strings.Split(strings.TrimSuffix(string(content), "\x00"), "\x00")
+	assert.ElementsMatch(t, []string{"kept/old", "removed"}, deleted)
+}
@@ -11,7 +11,9 @@ var drivers = map[string]func() driver{
 	"cephfs":     func() driver { return &cephfs{} },
 	"cephobject": func() driver { return &cephobject{} },
 	"dir":        func() driver { return &dir{} },
+	"lun":        func() driver { return &lun{} },
 	"lvm":        func() driver { return &lvm{} },
+	"nfs":        func() driver { return &nfs{} },
 	"zfs":        func() driver { return &zfs{} },
 }
 
@@ -0,0 +1,307 @@
+package drivers
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// luksHeaderSize is the space reserved at the start of a LUKS2 device for its header.
+const luksHeaderSize = 16 * 1024 * 1024
+
+// luksKeySize is the size of the random per-volume keys.
+const luksKeySize = 64
+
+// EncryptionKeyConfigKey is the volume config key holding the wrapped per-volume encryption key.
+const EncryptionKeyConfigKey = "volatile.encryption.key"
+
+// encryptionWrappingKeySize is the size of the key used to wrap the per-volume keys.
+const encryptionWrappingKeySize = 32
+
+// encryptionWrappingKeyCache holds the wrapping key once loaded, as it never changes.
+var encryptionWrappingKeyCache []byte
+var encryptionWrappingKeyCacheLock sync.Mutex
+
+// EncryptionWrappingKey returns the key used to wrap the per-volume encryption keys, generating it on first use.
+// The key is stored in the cluster database so that all cluster members share it, and it doesn't change when
+// the server or cluster certificate is rotated.
+func EncryptionWrappingKey(s *state.State) ([]byte, error) {
+	encryptionWrappingKeyCacheLock.Lock()
+	defer encryptionWrappingKeyCacheLock.Unlock()
+
+	if encryptionWrappingKeyCache != nil {
+		return encryptionWrappingKeyCache, nil
+	}
+
+	// Concurrently generated keys are discarded in favour of the first one stored, so read the key back in a
+	// separate transaction.
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.GetStorageEncryptionKey(ctx)
+		if err == nil || !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		key := make([]byte, encryptionWrappingKeySize)
+
+		_, err = rand.Read(key)
+		if err != nil {
+			return err
+		}
+
+		err = tx.CreateStorageEncryptionKey(ctx, base64.StdEncoding.EncodeToString(key))
+		if err != nil && !errors.Is(err, db.ErrAlreadyDefined) {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating storage encryption key: %w", err)
+	}
+
+	var encodedKey string
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		encodedKey, err = tx.GetStorageEncryptionKey(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != encryptionWrappingKeySize {
+		return nil, fmt.Errorf("Invalid storage encryption key")
+	}
+
+	encryptionWrappingKeyCache = key
+
+	return key, nil
+}
+
+// NewEncryptionKey generates a random per-volume encryption key and returns it wrapped with the wrapping key.
+func NewEncryptionKey(wrappingKey []byte) (string, error) {
+	key := make([]byte, luksKeySize)
+
+	_, err := rand.Read(key)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(wrappingKey)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, key, nil)), nil
+}
+
+// UnwrapEncryptionKey returns the per-volume encryption key from its form wrapped with the wrapping key.
+func UnwrapEncryptionKey(wrappingKey []byte, wrappedKey string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(wrappingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("Invalid encryption key: too short")
+	}
+
+	key, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unwrap encryption key, it was not created by this server or cluster: %w", err)
+	}
+
+	return key, nil
+}
+
+// luksValidateVolume checks that block.encryption is only set on volumes that can be encrypted.
+func luksValidateVolume(vol Volume) error {
+	if vol.config["block.encryption"] == "" || vol.IsEncrypted() {
+		return nil
+	}
+
+	return fmt.Errorf("Encryption is only supported on virtual machine and custom block volumes")
+}
+
+// luksRawSize returns the size of the underlying device needed to expose sizeBytes on an encrypted volume.
+func luksRawSize(vol Volume, sizeBytes int64) int64 {
+	if !vol.IsEncrypted() || sizeBytes <= 0 {
+		return sizeBytes
+	}
+
+	return sizeBytes + luksHeaderSize
+}
+
+// luksMapperName returns the device mapper name used for the opened LUKS device of a volume.
+func (d *common) luksMapperName(vol Volume) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s/%s", d.name, vol.volType, vol.contentType, vol.name)))
+
+	return fmt.Sprintf("lxd-luks-%x", hash[:16])
+}
+
+// luksKey returns the unwrapped encryption key of a volume.
+func (d *common) luksKey(vol Volume) ([]byte, error) {
+	wrappedKey := vol.config[EncryptionKeyConfigKey]
+	if wrappedKey == "" {
+		return nil, fmt.Errorf("Encrypted volume %q has no encryption key", vol.name)
+	}
+
+	wrappingKey, err := EncryptionWrappingKey(d.state)
+	if err != nil {
+		return nil, err
+	}
+
+	return UnwrapEncryptionKey(wrappingKey, wrappedKey)
+}
+
+// luksRun runs cryptsetup with the given key available to it as /proc/self/fd/3.
+func (d *common) luksRun(key []byte, args ...string) error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = r.Close() }()
+
+	// The key is much smaller than the pipe buffer, so this doesn't block.
+	_, err = w.Write(key)
+	_ = w.Close()
+	if err != nil {
+		return err
+	}
+
+	_, err = shared.RunCommandInheritFds(context.TODO(), []*os.File{r}, "cryptsetup", args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// luksFormat formats the block device of a volume as a LUKS2 device using the volume's encryption key.
+func (d *common) luksFormat(vol Volume, devPath string) error {
+	key, err := d.luksKey(vol)
+	if err != nil {
+		return err
+	}
+
+	err = d.luksRun(key, "luksFormat", "--batch-mode", "--type", vol.config["block.encryption"], "--key-file", "/proc/self/fd/3", devPath)
+	if err != nil {
+		return fmt.Errorf("Failed to format %q as LUKS device: %w", devPath, err)
+	}
+
+	d.logger.Debug("Formatted LUKS device", logger.Ctx{"volName": vol.name, "dev": devPath})
+
+	return nil
+}
+
+// luksOpen opens the LUKS device stored on devPath if not already open and returns the path of the decrypted
+// device.
+func (d *common) luksOpen(vol Volume, devPath string) (string, error) {
+	name := d.luksMapperName(vol)
+	mapperPath := filepath.Join("/dev/mapper", name)
+
+	if shared.PathExists(mapperPath) {
+		return mapperPath, nil
+	}
+
+	key, err := d.luksKey(vol)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"open", "--type", "luks", "--key-file", "/proc/self/fd/3"}
+
+	if vol.IsSnapshot() {
+		// Snapshots are only ever read from, and their devices may be read-only.
+		args = append(args, "--readonly")
+	} else {
+		// Pass discards through so that thin provisioned storage can reclaim freed space.
+		args = append(args, "--allow-discards")
+	}
+
+	err = d.luksRun(key, append(args, devPath, name)...)
+	if err != nil {
+		return "", fmt.Errorf("Failed to open LUKS device %q: %w", devPath, err)
+	}
+
+	d.logger.Debug("Opened LUKS device", logger.Ctx{"volName": vol.name, "dev": devPath, "path": mapperPath})
+
+	return mapperPath, nil
+}
+
+// luksClose closes the LUKS device of a volume if open. Returns true if closed, false if not.
+func (d *common) luksClose(vol Volume) (bool, error) {
+	name := d.luksMapperName(vol)
+
+	if !shared.PathExists(filepath.Join("/dev/mapper", name)) {
+		return false, nil
+	}
+
+	_, err := shared.RunCommand("cryptsetup", "close", name)
+	if err != nil {
+		return false, fmt.Errorf("Failed to close LUKS device of volume %q: %w", vol.name, err)
+	}
+
+	d.logger.Debug("Closed LUKS device", logger.Ctx{"volName": vol.name})
+
+	return true, nil
+}
+
+// luksResize resizes the opened LUKS device of a volume to the size of its underlying block device.
+func (d *common) luksResize(vol Volume) error {
+	name := d.luksMapperName(vol)
+
+	if !shared.PathExists(filepath.Join("/dev/mapper", name)) {
+		return nil // The new size is picked up when the device is next opened.
+	}
+
+	key, err := d.luksKey(vol)
+	if err != nil {
+		return err
+	}
+
+	err = d.luksRun(key, "resize", "--key-file", "/proc/self/fd/3", name)
+	if err != nil {
+		return fmt.Errorf("Failed to resize LUKS device of volume %q: %w", vol.name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package drivers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func Test_encryptionKeyWrapping(t *testing.T) {
+	wrappingKey := make([]byte, encryptionWrappingKeySize)
+	_, err := rand.Read(wrappingKey)
+	if err != nil {
+		t.Fatalf("Failed generating wrapping key: %v", err)
+	}
+
+	wrappedKey, err := NewEncryptionKey(wrappingKey)
+	if err != nil {
+		t.Fatalf("Failed generating encryption key: %v", err)
+	}
+
+	unwrappedKey, err := UnwrapEncryptionKey(wrappingKey, wrappedKey)
+	if err != nil {
+		t.Fatalf("Failed unwrapping encryption key: %v", err)
+	}
+
+	if len(unwrappedKey) != luksKeySize {
+		t.Errorf("Unexpected key size %d, want %d", len(unwrappedKey), luksKeySize)
+	}
+
+	otherWrappedKey, err := NewEncryptionKey(wrappingKey)
+	if err != nil {
+		t.Fatalf("Failed generating encryption key: %v", err)
+	}
+
+	otherKey, err := UnwrapEncryptionKey(wrappingKey, otherWrappedKey)
+	if err != nil {
+		t.Fatalf("Failed unwrapping encryption key: %v", err)
+	}
+
+	if bytes.Equal(unwrappedKey, otherKey) {
+		t.Error("Expected each volume to get a different key")
+	}
+
+	otherWrappingKey := make([]byte, encryptionWrappingKeySize)
+	_, err = rand.Read(otherWrappingKey)
+	if err != nil {
+		t.Fatalf("Failed generating wrapping key: %v", err)
+	}
+
+	_, err = UnwrapEncryptionKey(otherWrappingKey, wrappedKey)
+	if err == nil {
+		t.Error("Expected unwrapping with another wrapping key to fail")
+	}
+}
+
+func Test_luksRawSize(t *testing.T) {
+	encrypted := map[string]string{"block.encryption": "luks2"}
+
+	tests := []struct {
+		name string
+		vol  Volume
+		size int64
+		want int64
+	}{
+		{"Unencrypted block volume", NewVolume(nil, "testpool", VolumeTypeCustom, ContentTypeBlock, "testvol", nil, nil), 1024, 1024},
+		{"Encrypted custom block volume", NewVolume(nil, "testpool", VolumeTypeCustom, ContentTypeBlock, "testvol", encrypted, nil), 1024, 1024 + luksHeaderSize},
+		{"Encrypted VM volume", NewVolume(nil, "testpool", VolumeTypeVM, ContentTypeBlock, "testvol", encrypted, nil), 1024, 1024 + luksHeaderSize},
+		{"Encrypted volume without size", NewVolume(nil, "testpool", VolumeTypeCustom, ContentTypeBlock, "testvol", encrypted, nil), 0, 0},
+		{"Image volume", NewVolume(nil, "testpool", VolumeTypeImage, ContentTypeBlock, "testvol", encrypted, nil), 1024, 1024},
+		{"Filesystem volume", NewVolume(nil, "testpool", VolumeTypeCustom, ContentTypeFS, "testvol", encrypted, nil), 1024, 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := luksRawSize(tt.vol, tt.size)
+			if got != tt.want {
+				t.Errorf("luksRawSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
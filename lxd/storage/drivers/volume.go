@@ -373,6 +373,26 @@ func (v Volume) SnapshotsMatch(snapNames []string, op *operations.Operation) err
 	return nil
 }
 
+// SnapshotsExist checks that all the snapshots named exist in storage, ignoring any other snapshots the volume has.
+func (v Volume) SnapshotsExist(snapNames []string, op *operations.Operation) error {
+	if v.IsSnapshot() {
+		return fmt.Errorf("Volume is a snapshot")
+	}
+
+	snapshots, err := v.driver.VolumeSnapshots(v, op)
+	if err != nil {
+		return err
+	}
+
+	for _, snapName := range snapNames {
+		if !shared.ValueInSlice(snapName, snapshots) {
+			return fmt.Errorf("Snapshot %q expected but not in storage", snapName)
+		}
+	}
+
+	return nil
+}
+
 // IsBlockBacked indicates whether storage device is block backed.
 func (v Volume) IsBlockBacked() bool {
 	return v.driver.isBlockBacked(v) || v.mountFilesystemProbe
@@ -398,6 +418,11 @@ func (v Volume) IsCustomBlock() bool {
 	return (v.volType == VolumeTypeCustom && v.contentType == ContentTypeBlock)
 }
 
+// IsEncrypted returns true if volume is an instance or custom block volume encrypted with LUKS.
+func (v Volume) IsEncrypted() bool {
+	return (v.IsVMBlock() || v.IsCustomBlock()) && v.volType != VolumeTypeImage && v.config["block.encryption"] != ""
+}
+
 // NewVMBlockFilesystemVolume returns a copy of the volume with the content type set to ContentTypeFS and the
 // config "size" property set to "size.state" or DefaultVMBlockFilesystemSize if not set.
 func (v Volume) NewVMBlockFilesystemVolume() Volume {
@@ -33,6 +33,10 @@ import (
 	"github.com/canonical/lxd/shared/validate"
 )
 
+// CloneSourceConfigKey is the custom volume config key recording the volume (as "<project>/<volume>") that the
+// volume was cloned from.
+const CloneSourceConfigKey = "volatile.clone.source"
+
 // ConfigDiff returns a diff of the provided configs. Additionally, it returns whether or not
 // only user properties have been changed.
 func ConfigDiff(oldConfig map[string]string, newConfig map[string]string) ([]string, bool) {
@@ -226,9 +230,52 @@ func VolumeDBCreate(pool Pool, projectName string, volumeName string, volumeDesc
 		return fmt.Errorf("Pool is not a lxdBackend")
 	}
 
+	vol, volDBType, volDBContentType, err := volumeDBConfig(p, projectName, volumeName, volumeType, snapshot, volumeConfig, contentType, removeUnknownKeys, hasSource)
+	if err != nil {
+		return err
+	}
+
+	// Create the database entry for the storage volume.
+	if snapshot {
+		_, err = p.state.DB.Cluster.CreateStorageVolumeSnapshot(projectName, volumeName, volumeDescription, volDBType, pool.ID(), vol.Config(), creationDate, expiryDate)
+	} else {
+		_, err = p.state.DB.Cluster.CreateStoragePoolVolume(projectName, volumeName, volumeDescription, volDBType, pool.ID(), vol.Config(), volDBContentType, creationDate)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error inserting volume %q for project %q in pool %q of type %q into database %q", volumeName, projectName, pool.Name(), volumeType, err)
+	}
+
+	return nil
+}
+
+// VolumeDBMove moves the record of a volume without snapshots from srcPool to pool in a single transaction.
+// The volumeConfig is handled as in VolumeDBCreate.
+func VolumeDBMove(srcPool Pool, pool Pool, projectName string, volumeName string, volumeDescription string, volumeType drivers.VolumeType, volumeConfig map[string]string, creationDate time.Time, contentType drivers.ContentType) error {
+	p, ok := pool.(*lxdBackend)
+	if !ok {
+		return fmt.Errorf("Pool is not a lxdBackend")
+	}
+
+	vol, volDBType, volDBContentType, err := volumeDBConfig(p, projectName, volumeName, volumeType, false, volumeConfig, contentType, false, true)
+	if err != nil {
+		return err
+	}
+
+	err = p.state.DB.Cluster.MoveStoragePoolVolume(projectName, volumeName, volumeDescription, volDBType, srcPool.ID(), pool.ID(), vol.Config(), volDBContentType, creationDate)
+	if err != nil {
+		return fmt.Errorf("Error moving volume %q for project %q from pool %q to pool %q in database: %w", volumeName, projectName, srcPool.Name(), pool.Name(), err)
+	}
+
+	return nil
+}
+
+// volumeDBConfig fills and validates the config of a volume about to be stored in the database.
+// It returns the volume along with its database type and content type.
+func volumeDBConfig(p *lxdBackend, projectName string, volumeName string, volumeType drivers.VolumeType, snapshot bool, volumeConfig map[string]string, contentType drivers.ContentType, removeUnknownKeys bool, hasSource bool) (drivers.Volume, int, int, error) {
 	// Prevent using this function to create storage volume bucket records.
 	if volumeType == drivers.VolumeTypeBucket {
-		return fmt.Errorf("Cannot store volume using bucket type")
+		return drivers.Volume{}, -1, -1, fmt.Errorf("Cannot store volume using bucket type")
 	}
 
 	// If the volumeType represents an instance type then check that the volumeConfig doesn't contain any of
@@ -237,7 +284,7 @@ func VolumeDBCreate(pool Pool, projectName string, volumeName string, volumeDesc
 		for _, k := range instanceDiskVolumeEffectiveFields {
 			_, found := volumeConfig[k]
 			if found {
-				return fmt.Errorf("Instance disk effective override field %q should not be stored in volume config", k)
+				return drivers.Volume{}, -1, -1, fmt.Errorf("Instance disk effective override field %q should not be stored in volume config", k)
 			}
 		}
 	}
@@ -245,12 +292,12 @@ func VolumeDBCreate(pool Pool, projectName string, volumeName string, volumeDesc
 	// Convert the volume type to our internal integer representation.
 	volDBType, err := VolumeTypeToDBType(volumeType)
 	if err != nil {
-		return err
+		return drivers.Volume{}, -1, -1, err
 	}
 
 	volDBContentType, err := VolumeContentTypeToDBContentType(contentType)
 	if err != nil {
-		return err
+		return drivers.Volume{}, -1, -1, err
 	}
 
 	// Make sure that we don't pass a nil to the next function.
@@ -260,38 +307,119 @@ func VolumeDBCreate(pool Pool, projectName string, volumeName string, volumeDesc
 
 	volType, err := VolumeDBTypeToType(volDBType)
 	if err != nil {
-		return err
+		return drivers.Volume{}, -1, -1, err
 	}
 
-	vol := drivers.NewVolume(pool.Driver(), pool.Name(), volType, contentType, volumeName, volumeConfig, pool.Driver().Config())
+	vol := drivers.NewVolume(p.Driver(), p.Name(), volType, contentType, volumeName, volumeConfig, p.Driver().Config())
 
 	// Set source indicator.
 	vol.SetHasSource(hasSource)
 
 	// Fill default config.
-	err = pool.Driver().FillVolumeConfig(vol)
+	err = p.Driver().FillVolumeConfig(vol)
 	if err != nil {
-		return err
+		return drivers.Volume{}, -1, -1, err
+	}
+
+	// Clone dependencies are only recorded by CreateCustomVolumeFromClone, don't carry them over from the
+	// source of a copy, migration or backup.
+	delete(volumeConfig, CloneSourceConfigKey)
+
+	// Encrypted volumes need a key that this server can unwrap. Snapshots share the key of their parent volume,
+	// other volumes get a new key unless they already have one (such as when recovering a volume).
+	if vol.IsEncrypted() {
+		if snapshot {
+			parentName, _, _ := api.GetParentAndSnapshotName(volumeName)
+
+			parentVol, err := VolumeDBGet(p, projectName, parentName, volumeType)
+			if err != nil {
+				return drivers.Volume{}, -1, -1, err
+			}
+
+			volumeConfig[drivers.EncryptionKeyConfigKey] = parentVol.Config[drivers.EncryptionKeyConfigKey]
+		} else {
+			wrappingKey, err := drivers.EncryptionWrappingKey(p.state)
+			if err != nil {
+				return drivers.Volume{}, -1, -1, err
+			}
+
+			if volumeConfig[drivers.EncryptionKeyConfigKey] == "" {
+				volumeConfig[drivers.EncryptionKeyConfigKey], err = drivers.NewEncryptionKey(wrappingKey)
+				if err != nil {
+					return drivers.Volume{}, -1, -1, fmt.Errorf("Failed generating encryption key: %w", err)
+				}
+			} else {
+				// Never replace an existing key, the volume data may still depend on it.
+				_, err = drivers.UnwrapEncryptionKey(wrappingKey, volumeConfig[drivers.EncryptionKeyConfigKey])
+				if err != nil {
+					return drivers.Volume{}, -1, -1, err
+				}
+			}
+		}
 	}
 
 	// Validate config.
-	err = pool.Driver().ValidateVolume(vol, removeUnknownKeys)
+	err = p.Driver().ValidateVolume(vol, removeUnknownKeys)
 	if err != nil {
-		return err
+		return drivers.Volume{}, -1, -1, err
 	}
 
-	// Create the database entry for the storage volume.
-	if snapshot {
-		_, err = p.state.DB.Cluster.CreateStorageVolumeSnapshot(projectName, volumeName, volumeDescription, volDBType, pool.ID(), vol.Config(), creationDate, expiryDate)
-	} else {
-		_, err = p.state.DB.Cluster.CreateStoragePoolVolume(projectName, volumeName, volumeDescription, volDBType, pool.ID(), vol.Config(), volDBContentType, creationDate)
+	return vol, volDBType, volDBContentType, nil
+}
+
+// VolumeClones returns the custom volumes in the pool (as "<project>/<volume>") that were cloned from the given
+// custom volume.
+func VolumeClones(pool Pool, projectName string, volumeName string) ([]string, error) {
+	p, ok := pool.(*lxdBackend)
+	if !ok {
+		return nil, fmt.Errorf("Pool is not a lxdBackend")
 	}
 
+	source := fmt.Sprintf("%s/%s", projectName, volumeName)
+	volTypeCustom := db.StoragePoolVolumeTypeCustom
+
+	var clones []string
+	err := p.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		volumes, err := tx.GetStoragePoolVolumes(ctx, pool.ID(), false, db.StorageVolumeFilter{Type: &volTypeCustom})
+		if err != nil {
+			return err
+		}
+
+		for _, vol := range volumes {
+			if vol.Config[CloneSourceConfigKey] == source {
+				clones = append(clones, fmt.Sprintf("%s/%s", vol.Project, vol.Name))
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("Error inserting volume %q for project %q in pool %q of type %q into database %q", volumeName, projectName, pool.Name(), volumeType, err)
+		return nil, fmt.Errorf("Failed loading clones of volume %q: %w", volumeName, err)
 	}
 
-	return nil
+	return clones, nil
+}
+
+// volumeClonesDescription describes the given clones (as returned by VolumeClones) to a user of the given project.
+// Only the clones in that project are named, the ones in other projects are only counted.
+func volumeClonesDescription(projectName string, clones []string) string {
+	var names []string
+	otherProjects := 0
+	for _, clone := range clones {
+		cloneProject, cloneName, _ := strings.Cut(clone, "/")
+		if cloneProject != projectName {
+			otherProjects++
+			continue
+		}
+
+		names = append(names, fmt.Sprintf("%q", cloneName))
+	}
+
+	if otherProjects > 0 {
+		names = append(names, fmt.Sprintf("%d in other projects", otherProjects))
+	}
+
+	return strings.Join(names, ", ")
 }
 
 // VolumeDBDelete deletes a volume from the database.
@@ -443,7 +571,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  defaultdesc: auto (20% of free disk space, >= 5 GiB and <= 30 GiB)
 		//  shortdesc: Size of the storage pool (for loop-based pools)
 
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs; group=volume-conf; key=size)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=size)
 		//
 		// ---
 		//  type: string
@@ -465,7 +593,52 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  defaultdesc: same as `volume.size`
 		//  shortdesc: Size/quota of the storage bucket
 		"size": validate.Optional(validate.IsSize),
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs; group=volume-conf; key=snapshots.expiry)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=backups.expiry)
+		// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.expiry`
+		//  shortdesc: When scheduled backups are to be deleted
+		"backups.expiry": func(value string) error {
+			// Validate expression
+			_, err := shared.GetExpiry(time.Time{}, value)
+			return err
+		},
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=backups.retain)
+		// Older scheduled backups are deleted once this number of scheduled backups is reached.
+		// ---
+		//  type: integer
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.retain` or `0` (unlimited)
+		//  shortdesc: Number of scheduled backups to keep
+		"backups.retain": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=backups.schedule)
+		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups (the default).
+		// Use `@never` to disable a schedule that is set in the storage pool.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.schedule`
+		//  shortdesc: Schedule for automatic volume backups
+		"backups.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=backups.target)
+		// Specify a storage bucket as `<pool>/<bucket>` to upload scheduled backups to it instead of storing them on the server.
+		// The bucket must belong to the volume's project and be on a local storage pool of the cluster member creating the backup.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.target`
+		//  shortdesc: Storage bucket to upload scheduled backups to
+		"backups.target": validate.Optional(func(value string) error {
+			pool, bucket, found := strings.Cut(value, "/")
+			if !found || pool == "" || bucket == "" || strings.Contains(bucket, "/") {
+				return fmt.Errorf("Invalid backup target %q, expected <pool>/<bucket>", value)
+			}
+
+			return nil
+		}),
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=snapshots.expiry)
 		// Specify an expression like `1M 2H 3d 4w 5m 6y`.
 		// ---
 		//  type: string
@@ -477,7 +650,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 			_, err := shared.GetExpiry(time.Time{}, value)
 			return err
 		},
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs; group=volume-conf; key=snapshots.schedule)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=snapshots.schedule)
 		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic snapshots (the default).
 		// ---
 		//  type: string
@@ -485,7 +658,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  defaultdesc: same as `snapshots.schedule`
 		//  shortdesc: Schedule for automatic volume snapshots
 		"snapshots.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs; group=volume-conf; key=snapshots.pattern)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=snapshots.pattern)
 		// You can specify a naming template that is used for scheduled snapshots and unnamed snapshots.
 		//
 		// {{snapshot_pattern_detail}}
@@ -499,7 +672,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 
 	// security.shifted and security.unmapped are only relevant for custom filesystem volumes.
 	if (vol == nil) || (vol != nil && vol.Type() == drivers.VolumeTypeCustom && vol.ContentType() == drivers.ContentTypeFS) {
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs; group=volume-conf; key=security.shifted)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=security.shifted)
 		// Enabling this option allows attaching the volume to multiple isolated instances.
 		// ---
 		//  type: bool
@@ -507,7 +680,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  defaultdesc: same as `volume.security.shifted` or `false`
 		//  shortdesc: Enable ID shifting overlay
 		rules["security.shifted"] = validate.Optional(validate.IsBool)
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs; group=volume-conf; key=security.unmapped)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=security.unmapped)
 		//
 		// ---
 		//  type: bool
@@ -553,6 +726,13 @@ func validatePoolCommonRules() map[string]func(string) error {
 		//  type: string
 		//  shortdesc: Path to an existing block device, loop file, or LVM volume group
 
+		// lxdmeta:generate(entities=storage-nfs; group=pool-conf; key=source)
+		// Use the `HOST:PATH` format, for example `nfs.example.com:/srv/lxd`.
+		// IPv6 addresses must be enclosed in square brackets.
+		// ---
+		//  type: string
+		//  shortdesc: NFS export to use
+
 		// lxdmeta:generate(entities=storage-zfs; group=pool-conf; key=source)
 		//
 		// ---
@@ -568,7 +748,7 @@ func validatePoolCommonRules() map[string]func(string) error {
 		//  shortdesc: Whether to wipe the block device before creating the pool
 		"source.wipe":             validate.Optional(validate.IsBool),
 		"volatile.initial_source": validate.IsAny,
-		// lxdmeta:generate(entities=storage-dir,storage-lvm; group=pool-conf; key=rsync.bwlimit)
+		// lxdmeta:generate(entities=storage-dir,storage-lun,storage-lvm,storage-nfs; group=pool-conf; key=rsync.bwlimit)
 		// When `rsync` must be used to transfer storage entities, this option specifies the upper limit
 		// to be placed on the socket I/O.
 		// ---
@@ -576,13 +756,22 @@ func validatePoolCommonRules() map[string]func(string) error {
 		//  defaultdesc: `0` (no limit)
 		//  shortdesc: Upper limit on the socket I/O for `rsync`
 		"rsync.bwlimit": validate.Optional(validate.IsSize),
-		// lxdmeta:generate(entities=storage-dir,storage-lvm; group=pool-conf; key=rsync.compression)
+		// lxdmeta:generate(entities=storage-dir,storage-lun,storage-lvm,storage-nfs; group=pool-conf; key=rsync.compression)
 		//
 		// ---
 		//  type: bool
 		//  defaultdesc: `true`
 		//  shortdesc: Whether to use compression while migrating storage pools
 		"rsync.compression": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=storage-btrfs,storage-ceph,storage-cephfs,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=pool-conf; key=warning_threshold)
+		// Specify the percentage of used space above which LXD raises a low space warning for the storage pool.
+		// This overrides the {config:option}`server-miscellaneous:storage.warning_threshold` server option for the pool.
+		// Set this option to `0` to disable low space warnings for the pool.
+		// ---
+		//  type: integer
+		//  defaultdesc: value of `storage.warning_threshold`
+		//  shortdesc: Pool usage percentage that triggers a low space warning
+		"warning_threshold": validate.Optional(validate.IsInRange(0, 100)),
 	}
 
 	// Add to pool config rules (prefixed with volume.*) which are common for pool and volume.
@@ -603,6 +792,11 @@ func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error
 		rules["volatile.idmap.next"] = validate.IsAny
 	}
 
+	// volatile.encryption.key holds the wrapped key of encrypted volumes.
+	if vol.IsEncrypted() {
+		rules[drivers.EncryptionKeyConfigKey] = validate.IsAny
+	}
+
 	// block.mount_options and block.filesystem settings are only relevant for drivers that are block backed
 	// and when there is a filesystem to actually mount. This includes filesystem volumes and VM Block volumes,
 	// as they have an associated config filesystem volume that shares the config.
@@ -614,6 +808,62 @@ func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error
 		rules["block.filesystem"] = validate.IsAny
 	}
 
+	// replication.* settings are only relevant for custom volumes (instances use their own config).
+	if vol.Type() == drivers.VolumeTypeCustom {
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=replication.schedule)
+		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable replication (the default).
+		// `@never` also disables replication.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  shortdesc: Schedule for the replication of the volume
+		rules["replication.schedule"] = instancetype.IsReplicationSchedule
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=replication.remote)
+		// Specify the URL (`https://<address>[:<port>]`) of an LXD server outside of the cluster to replicate the volume to that server instead of within the cluster.
+		// The URL must be listed in the {config:option}`server-miscellaneous:replication.allowed_remote_urls` server configuration option, and the remote server must trust the certificate of this server.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  shortdesc: LXD server to replicate the volume to
+		rules["replication.remote"] = validate.Optional(validate.IsRequestURL)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=replication.remote_fingerprint)
+		// The SHA-256 fingerprint of the certificate of the server set in `replication.remote`.
+		// Replication fails if the remote server presents a different certificate.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  shortdesc: Certificate fingerprint of the remote replication server
+		rules["replication.remote_fingerprint"] = instancetype.IsReplicationRemoteFingerprint
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lun,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=replication.target)
+		// Specify a storage pool as `<pool>` to replicate the volume into a pool of the cluster member that holds the volume, or as `<member>/<pool>` to replicate it into a pool of another cluster member.
+		// If `replication.remote` is set, the storage pool and cluster member are the ones of the remote server.
+		// The replica has the same name as the volume.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  shortdesc: Storage pool to replicate the volume into
+		rules["replication.target"] = validate.Optional(func(value string) error {
+			fields := strings.Split(value, "/")
+			if len(fields) > 2 || shared.ValueInSlice("", fields) {
+				return fmt.Errorf("Invalid replication target %q, expected <pool> or <member>/<pool>", value)
+			}
+
+			return nil
+		})
+	}
+
+	// volatile.clone.source records the custom volume that a custom volume was cloned from.
+	// volatile.replication.source records the custom volume that a custom volume is a replica of.
+	if vol.Type() == drivers.VolumeTypeCustom {
+		rules[CloneSourceConfigKey] = validate.IsAny
+		rules["volatile.replication.source"] = validate.IsAny
+	}
+
+	// volatile.replication.last_sync records the last successful replication of custom and instance volumes.
+	if vol.Type() == drivers.VolumeTypeCustom || vol.Type() == drivers.VolumeTypeContainer || vol.Type() == drivers.VolumeTypeVM {
+		rules["volatile.replication.last_sync"] = validate.Optional(validate.IsAny)
+	}
+
 	// volatile.rootfs.size is only used for image volumes.
 	if vol.Type() == drivers.VolumeTypeImage {
 		rules["volatile.rootfs.size"] = validate.Optional(validate.IsInt64)
@@ -1084,6 +1334,47 @@ func FallbackMigrationType(contentType drivers.ContentType) migration.MigrationF
 	return migration.MigrationFSType_RSYNC
 }
 
+// migrationVolumeConfig returns the config to create a volume received through migration with.
+// Encrypted volumes are only ever transferred decrypted, so the wrapped encryption key of the source isn't kept and
+// the new volume gets its own key.
+func migrationVolumeConfig(config map[string]string) map[string]string {
+	newConfig := make(map[string]string, len(config))
+	for k, v := range config {
+		if k == drivers.EncryptionKeyConfigKey {
+			continue
+		}
+
+		newConfig[k] = v
+	}
+
+	return newConfig
+}
+
+// VolumeMigrationTypes returns the migration types of the pool that can be used to transfer the volume.
+// Encrypted volumes can only be transferred decrypted, so if the volume is encrypted (or would be once created
+// on the pool due to its "volume.block.encryption" setting) only the generic migration types are returned.
+func VolumeMigrationTypes(pool Pool, vol drivers.Volume, refresh bool, copySnapshots bool) []migration.Type {
+	migrationTypes := pool.MigrationTypes(vol.ContentType(), refresh, copySnapshots)
+
+	config := map[string]string{"block.encryption": pool.Driver().Config()["volume.block.encryption"]}
+	for k, v := range vol.Config() {
+		config[k] = v
+	}
+
+	if !pool.GetVolume(vol.Type(), vol.ContentType(), vol.Name(), config).IsEncrypted() {
+		return migrationTypes
+	}
+
+	genericTypes := make([]migration.Type, 0, len(migrationTypes))
+	for _, migrationType := range migrationTypes {
+		if migrationType.FSType == migration.MigrationFSType_RSYNC || migrationType.FSType == migration.MigrationFSType_BLOCK_AND_RSYNC {
+			genericTypes = append(genericTypes, migrationType)
+		}
+	}
+
+	return genericTypes
+}
+
 // RenderSnapshotUsage can be used as an optional argument to Instance.Render() to return snapshot usage.
 // As this is a relatively expensive operation it is provided as an optional feature rather than on by default.
 func RenderSnapshotUsage(s *state.State, snapInst instance.Instance) func(response any) error {
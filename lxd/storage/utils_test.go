@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// newTestCloneBackend returns a backend of a new pool holding volume vol1 of the default project, which has the
+// clones p1/clone1 and default/clone2, and volume vol2 without clones.
+func newTestCloneBackend(t *testing.T) (*lxdBackend, func()) {
+	dbCluster, cleanup := db.NewTestCluster(t)
+
+	err := dbCluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Name: "p1"})
+		return err
+	})
+	require.NoError(t, err)
+
+	poolID, err := dbCluster.CreateStoragePool("pool1", "", "zfs", nil)
+	require.NoError(t, err)
+
+	otherPoolID, err := dbCluster.CreateStoragePool("pool2", "", "zfs", nil)
+	require.NoError(t, err)
+
+	volumes := []struct {
+		poolID  int64
+		project string
+		name    string
+		source  string
+	}{
+		{poolID, "default", "vol1", ""},
+		{poolID, "default", "vol2", ""},
+		{poolID, "p1", "clone1", "default/vol1"},
+		{poolID, "default", "clone2", "default/vol1"},
+		{poolID, "p1", "vol1", ""},
+		{otherPoolID, "default", "clone3", "default/vol1"},
+	}
+
+	for _, vol := range volumes {
+		config := map[string]string{}
+		if vol.source != "" {
+			config[CloneSourceConfigKey] = vol.source
+		}
+
+		_, err = dbCluster.CreateStoragePoolVolume(vol.project, vol.name, "", db.StoragePoolVolumeTypeCustom, vol.poolID, config, db.StoragePoolVolumeContentTypeFS, time.Now())
+		require.NoError(t, err)
+	}
+
+	b := &lxdBackend{
+		id:     poolID,
+		name:   "pool1",
+		state:  &state.State{DB: &db.DB{Cluster: dbCluster}},
+		logger: logger.AddContext(logger.Ctx{"pool": "pool1"}),
+	}
+
+	return b, cleanup
+}
+
+// Only the clones in the same pool of the volume in the given project are returned.
+func TestVolumeClones(t *testing.T) {
+	b, cleanup := newTestCloneBackend(t)
+	defer cleanup()
+
+	clones, err := VolumeClones(b, "default", "vol1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"p1/clone1", "default/clone2"}, clones)
+
+	clones, err = VolumeClones(b, "default", "vol2")
+	require.NoError(t, err)
+	assert.Empty(t, clones)
+
+	clones, err = VolumeClones(b, "p1", "vol1")
+	require.NoError(t, err)
+	assert.Empty(t, clones)
+}
+
+// A volume with clones can't be deleted or renamed.
+func TestCustomVolumeWithClones(t *testing.T) {
+	b, cleanup := newTestCloneBackend(t)
+	defer cleanup()
+
+	err := b.DeleteCustomVolume("default", "vol1", nil)
+	assert.EqualError(t, err, `Cannot delete volume "vol1" as it has clones: "clone2", 1 in other projects`)
+
+	err = b.RenameCustomVolume("default", "vol1", "vol3", nil)
+	assert.EqualError(t, err, `Cannot rename volume "vol1" as it has clones: "clone2", 1 in other projects`)
+}
+
+// The clone source of a volume can't be set or changed by users.
+func TestUpdateCustomVolumeCloneSource(t *testing.T) {
+	b, cleanup := newTestCloneBackend(t)
+	defer cleanup()
+
+	err := b.UpdateCustomVolume("default", "vol2", "", map[string]string{CloneSourceConfigKey: "p1/vol1"}, nil)
+	assert.EqualError(t, err, `The "volatile.clone.source" config key cannot be changed`)
+
+	err = b.UpdateCustomVolume("p1", "clone1", "", map[string]string{CloneSourceConfigKey: "p1/vol1"}, nil)
+	assert.EqualError(t, err, `The "volatile.clone.source" config key cannot be changed`)
+}
+
+// Only the clones in the project of the user are named.
+func TestVolumeClonesDescription(t *testing.T) {
+	assert.Equal(t, `"clone1", "clone2"`, volumeClonesDescription("p1", []string{"p1/clone1", "p1/clone2"}))
+	assert.Equal(t, `"clone1", 2 in other projects`, volumeClonesDescription("p1", []string{"p1/clone1", "p2/clone2", "default/clone3"}))
+	assert.Equal(t, `1 in other projects`, volumeClonesDescription("default", []string{"p1/clone1"}))
+}
@@ -394,6 +394,14 @@ func storagePoolBucketsPost(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Failed loading storage pool: %w", err))
 	}
 
+	// Check that the project's limits are not violated.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return project.AllowBucketCreation(tx, bucketProjectName, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -568,6 +576,14 @@ func storagePoolBucketPut(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	// Check that the project's limits are not violated.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return project.AllowBucketUpdate(tx, bucketProjectName, poolName, bucketName, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	err = pool.UpdateBucket(bucketProjectName, bucketName, req, nil)
 	if err != nil {
 		return response.SmartError(fmt.Errorf("Failed updating storage bucket: %w", err))
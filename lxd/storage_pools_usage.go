@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/response"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// storagePoolUsageWindow is how far back storage pool usage samples are kept for forecasting.
+const storagePoolUsageWindow = 24 * time.Hour
+
+// storagePoolUsageMinSpan is the minimum time covered by the samples before a forecast is made.
+const storagePoolUsageMinSpan = time.Hour
+
+// storagePoolUsageSample is a single measurement of the used space of a storage pool.
+type storagePoolUsageSample struct {
+	time time.Time
+	used uint64
+}
+
+// storagePoolUsageSamples holds the recent usage samples of each storage pool indexed by pool name.
+var storagePoolUsageSamples = map[string][]storagePoolUsageSample{}
+var storagePoolUsageSamplesMu sync.Mutex
+
+// storagePoolUsageForecast estimates how long it takes for the pool to be full by fitting a line through the
+// usage samples. Returns false if there isn't enough data or if the pool usage isn't growing.
+func storagePoolUsageForecast(samples []storagePoolUsageSample, total uint64) (time.Duration, bool) {
+	if len(samples) < 2 || total == 0 {
+		return 0, false
+	}
+
+	first := samples[0]
+	last := samples[len(samples)-1]
+	if last.time.Sub(first.time) < storagePoolUsageMinSpan {
+		return 0, false
+	}
+
+	// Least squares fit of the used space (bytes) over time (seconds since the first sample).
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.time.Sub(first.time).Seconds()
+		y := float64(sample.used)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	n := float64(len(samples))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, false
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+	if slope <= 0 {
+		return 0, false
+	}
+
+	if last.used >= total {
+		return 0, true
+	}
+
+	seconds := float64(total-last.used) / slope
+
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// storagePoolUsageWarning returns the low space warning message for the given pool usage, or an empty string if
+// no warning should be raised. A warning is raised when the used percentage reaches the threshold or when the
+// forecasted time until the pool is full is within the sample window.
+func storagePoolUsageWarning(used uint64, total uint64, threshold int64, timeToFull time.Duration, forecast bool) string {
+	if threshold <= 0 || total == 0 {
+		return ""
+	}
+
+	usedStr := units.GetByteSizeStringIEC(int64(used), 2)
+	totalStr := units.GetByteSizeStringIEC(int64(total), 2)
+
+	percent := used * 100 / total
+	if percent >= uint64(threshold) {
+		return fmt.Sprintf("Storage pool usage is at %d%% (%s of %s), threshold is %d%%", percent, usedStr, totalStr, threshold)
+	}
+
+	if forecast && timeToFull <= storagePoolUsageWindow {
+		return fmt.Sprintf("Storage pool is forecast to be full in %s (%s of %s used)", timeToFull.Round(time.Minute), usedStr, totalStr)
+	}
+
+	return ""
+}
+
+// storagePoolWarningThreshold returns the low space warning threshold of a storage pool. This is the value of the
+// pool's warning_threshold config key if set, or the server's storage.warning_threshold otherwise.
+func storagePoolWarningThreshold(poolConfig map[string]string, serverThreshold int64) int64 {
+	threshold, err := strconv.ParseInt(poolConfig["warning_threshold"], 10, 64)
+	if err != nil {
+		return serverThreshold
+	}
+
+	return threshold
+}
+
+// storagePoolUsageTask samples the usage of the storage pools and raises or resolves low space warnings.
+func storagePoolUsageTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		serverThreshold := s.GlobalConfig.StorageWarningThreshold()
+
+		// Usage of remote storage pools is shared by all members, so it is only checked by the cluster leader.
+		leader := true
+		if s.ServerClustered {
+			leaderAddress, err := d.gateway.LeaderAddress()
+			if err != nil {
+				logger.Warn("Failed to get leader cluster member address", logger.Ctx{"err": err})
+				return
+			}
+
+			leader = leaderAddress == s.LocalConfig.ClusterAddress()
+		}
+
+		poolNames, err := s.DB.Cluster.GetCreatedStoragePoolNames()
+		if err != nil {
+			if !response.IsNotFoundError(err) {
+				logger.Warn("Failed loading storage pools for usage check", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+
+		now := time.Now()
+		samples := make(map[string][]storagePoolUsageSample, len(poolNames))
+
+		storagePoolUsageSamplesMu.Lock()
+		defer storagePoolUsageSamplesMu.Unlock()
+
+		for _, poolName := range poolNames {
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				logger.Warn("Failed loading storage pool for usage check", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			threshold := storagePoolWarningThreshold(pool.Driver().Config(), serverThreshold)
+			if threshold <= 0 || (pool.Driver().Info().Remote && !leader) {
+				_ = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningtype.StoragePoolLowSpace, cluster.TypeStoragePool, int(pool.ID()))
+				continue
+			}
+
+			res, err := pool.GetResources()
+			if err != nil {
+				logger.Debug("Failed getting storage pool usage", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			// Keep the samples within the window and add the new one.
+			for _, sample := range storagePoolUsageSamples[poolName] {
+				if now.Sub(sample.time) <= storagePoolUsageWindow {
+					samples[poolName] = append(samples[poolName], sample)
+				}
+			}
+
+			samples[poolName] = append(samples[poolName], storagePoolUsageSample{time: now, used: res.Space.Used})
+
+			timeToFull, forecast := storagePoolUsageForecast(samples[poolName], res.Space.Total)
+			msg := storagePoolUsageWarning(res.Space.Used, res.Space.Total, threshold, timeToFull, forecast)
+			if msg != "" {
+				logger.Warn("Storage pool is low on space", logger.Ctx{"pool": poolName, "used": res.Space.Used, "total": res.Space.Total})
+				_ = s.DB.Cluster.UpsertWarningLocalNode("", cluster.TypeStoragePool, int(pool.ID()), warningtype.StoragePoolLowSpace, msg)
+			} else {
+				_ = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningtype.StoragePoolLowSpace, cluster.TypeStoragePool, int(pool.ID()))
+			}
+		}
+
+		// Drop the samples of pools that no longer exist or aren't checked anymore.
+		storagePoolUsageSamples = samples
+	}
+
+	return f, task.Every(10 * time.Minute)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoragePoolUsageForecast(t *testing.T) {
+	now := time.Now()
+
+	// Not enough samples.
+	_, ok := storagePoolUsageForecast([]storagePoolUsageSample{{time: now, used: 10}}, 100)
+	assert.False(t, ok)
+
+	// Samples not covering enough time.
+	_, ok = storagePoolUsageForecast([]storagePoolUsageSample{{time: now, used: 10}, {time: now.Add(time.Minute), used: 20}}, 100)
+	assert.False(t, ok)
+
+	// Usage not growing.
+	_, ok = storagePoolUsageForecast([]storagePoolUsageSample{{time: now, used: 50}, {time: now.Add(2 * time.Hour), used: 40}}, 100)
+	assert.False(t, ok)
+
+	// Usage growing by 10 bytes per hour, with 50 bytes left.
+	samples := []storagePoolUsageSample{}
+	for i := 0; i <= 5; i++ {
+		samples = append(samples, storagePoolUsageSample{time: now.Add(time.Duration(i) * time.Hour), used: uint64(i * 10)})
+	}
+
+	timeToFull, ok := storagePoolUsageForecast(samples, 100)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Hour, timeToFull.Round(time.Minute))
+}
+
+func TestStoragePoolWarningThreshold(t *testing.T) {
+	// Server threshold used if the pool doesn't override it.
+	assert.Equal(t, int64(90), storagePoolWarningThreshold(map[string]string{}, 90))
+
+	// Pool threshold overrides the server threshold.
+	assert.Equal(t, int64(75), storagePoolWarningThreshold(map[string]string{"warning_threshold": "75"}, 90))
+	assert.Equal(t, int64(80), storagePoolWarningThreshold(map[string]string{"warning_threshold": "80"}, 0))
+
+	// Pool warnings disabled.
+	assert.Equal(t, int64(0), storagePoolWarningThreshold(map[string]string{"warning_threshold": "0"}, 90))
+}
+
+func TestStoragePoolUsageWarning(t *testing.T) {
+	// Below threshold and no forecast.
+	assert.Equal(t, "", storagePoolUsageWarning(50, 100, 90, 0, false))
+
+	// Threshold disabled.
+	assert.Equal(t, "", storagePoolUsageWarning(99, 100, 0, time.Hour, true))
+
+	// Above threshold.
+	assert.Contains(t, storagePoolUsageWarning(95, 100, 90, 0, false), "usage is at 95%")
+
+	// Forecast to be full within the window.
+	assert.Contains(t, storagePoolUsageWarning(50, 100, 90, 5*time.Hour, true), "forecast to be full in 5h0m0s")
+
+	// Forecast to be full beyond the window.
+	assert.Equal(t, "", storagePoolUsageWarning(50, 100, 90, 48*time.Hour, true))
+}
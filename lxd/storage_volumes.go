@@ -660,13 +660,22 @@ func storagePoolVolumesTypePost(d *Daemon, r *http.Request) response.Response {
 		return response.Conflict(fmt.Errorf("Volume by that name already exists"))
 	}
 
+	err = storageVolumeCloneValidate(req, poolName)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	target := request.QueryParam(r, "target")
 
 	// Check if we need to switch to migration
 	serverName := s.ServerName
 	var nodeAddress string
 
-	if s.ServerClustered && target != "" && !req.Source.Refresh && (req.Source.Location != "" && serverName != req.Source.Location) {
+	if s.ServerClustered && target != "" && (req.Source.Location != "" && serverName != req.Source.Location) {
+		if req.Source.Clone {
+			return response.BadRequest(fmt.Errorf("Clones can only be created from volumes on the same cluster member"))
+		}
+
 		err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 			nodeInfo, err := tx.GetNodeByName(ctx, req.Source.Location)
 			if err != nil {
@@ -780,6 +789,28 @@ func doCustomVolumeRefresh(s *state.State, r *http.Request, requestProjectName s
 	return operations.OperationResponse(op)
 }
 
+// storageVolumeCloneValidate checks that a request to create a clone of a custom volume can be satisfied.
+// Clones share their data with the source volume, so they can only be created from a volume in the same pool.
+func storageVolumeCloneValidate(req api.StorageVolumesPost, poolName string) error {
+	if !req.Source.Clone {
+		return nil
+	}
+
+	if req.Source.Type != "copy" {
+		return fmt.Errorf("Clones can only be created with the copy source type")
+	}
+
+	if req.Source.Pool != poolName {
+		return fmt.Errorf("Clones can only be created from volumes in the same storage pool")
+	}
+
+	if req.Source.Refresh {
+		return fmt.Errorf("Clones cannot be refreshed")
+	}
+
+	return nil
+}
+
 func doVolumeCreateOrCopy(s *state.State, r *http.Request, requestProjectName string, projectName string, poolName string, req *api.StorageVolumesPost) response.Response {
 	var run func(op *operations.Operation) error
 
@@ -806,6 +837,10 @@ func doVolumeCreateOrCopy(s *state.State, r *http.Request, requestProjectName st
 			return pool.CreateCustomVolume(projectName, req.Name, req.Description, req.Config, contentType, op)
 		}
 
+		if req.Source.Clone {
+			return pool.CreateCustomVolumeFromClone(projectName, req.Source.Project, req.Name, req.Description, req.Config, req.Source.Name, op)
+		}
+
 		return pool.CreateCustomVolumeFromCopy(projectName, req.Source.Project, req.Name, req.Description, req.Config, req.Source.Pool, req.Source.Name, !req.Source.VolumeOnly, op)
 	}
 
@@ -2324,6 +2359,16 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		return response.InternalError(err)
 	}
 
+	// Encrypted backups must be decrypted by the client before being uploaded.
+	encrypted, err := backup.IsEncrypted(backupFile)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	if encrypted {
+		return response.BadRequest(backup.ErrEncrypted)
+	}
+
 	// Detect squashfs compression and convert to tarball.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
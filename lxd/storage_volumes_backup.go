@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
 	"strings"
 	"time"
 
@@ -209,7 +210,7 @@ func storagePoolVolumeTypeCustomBackupsGet(d *Daemon, r *http.Request) response.
 	backups := make([]*backup.VolumeBackup, len(volumeBackups))
 
 	for i, b := range volumeBackups {
-		backups[i] = backup.NewVolumeBackup(s, projectName, poolName, volumeName, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+		backups[i] = backup.NewVolumeBackup(s, projectName, poolName, volumeName, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage, b.Target)
 	}
 
 	resultString := []string{}
@@ -403,6 +404,20 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 	fullName := volumeName + shared.SnapshotDelimiter + req.Name
 	volumeOnly := req.VolumeOnly
 
+	// Validate the encryption settings.
+	_, err = backupEncryptionRecipients(req.EncryptionRecipients, req.EncryptionPassphrase)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Validate the backup target.
+	if req.Target != nil {
+		err = backupTargetValidate(s, req.Target, path.Join(project.StorageVolume(projectName, volumeName), req.Name))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
 	backup := func(op *operations.Operation) error {
 		args := db.StoragePoolVolumeBackup{
 			Name:                 fullName,
@@ -412,6 +427,9 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			VolumeOnly:           volumeOnly,
 			OptimizedStorage:     req.OptimizedStorage,
 			CompressionAlgorithm: req.CompressionAlgorithm,
+			EncryptionRecipients: req.EncryptionRecipients,
+			EncryptionPassphrase: req.EncryptionPassphrase,
+			Target:               req.Target,
 		}
 
 		err := volumeBackupCreate(s, args, projectName, poolName, volumeName)
@@ -769,6 +787,13 @@ func storagePoolVolumeTypeCustomBackupDelete(d *Daemon, r *http.Request) respons
 	}
 
 	remove := func(op *operations.Operation) error {
+		if backup.Target() != nil {
+			err := backupTargetDelete(s, projectName, backup.Target())
+			if err != nil {
+				return err
+			}
+		}
+
 		err := backup.Delete()
 		if err != nil {
 			return err
@@ -874,11 +899,15 @@ func storagePoolVolumeTypeCustomBackupExportGet(d *Daemon, r *http.Request) resp
 	fullName := volumeName + shared.SnapshotDelimiter + backupName
 
 	// Ensure the volume exists
-	_, err = storagePoolVolumeBackupLoadByName(s, projectName, poolName, fullName)
+	backup, err := storagePoolVolumeBackupLoadByName(s, projectName, poolName, fullName)
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	if backup.Target() != nil {
+		return response.BadRequest(fmt.Errorf("Backup was uploaded to a backup target and isn't stored on the server"))
+	}
+
 	ent := response.FileResponseEntry{
 		Path: shared.VarPath("backups", "custom", poolName, project.StorageVolume(projectName, fullName)),
 	}
@@ -141,6 +141,11 @@ func storagePoolVolumeSnapshotsTypePost(d *Daemon, r *http.Request) response.Res
 			return err
 		}
 
+		err = project.AllowVolumeSnapshotCreation(tx, p, 1)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -1174,6 +1179,9 @@ func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d *Daemon) (task.Func, t
 				return fmt.Errorf("Failed getting volumes for auto custom volume snapshot task: %w", err)
 			}
 
+			// Number of snapshots scheduled in this run by project, for the snapshot count limit.
+			scheduledSnapshots := map[string]int{}
+
 			for _, v := range allVolumes {
 				err = project.AllowSnapshotCreation(projects[v.ProjectName])
 				if err != nil {
@@ -1190,6 +1198,14 @@ func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d *Daemon) (task.Func, t
 					continue
 				}
 
+				err = project.AllowVolumeSnapshotCreation(tx, projects[v.ProjectName], scheduledSnapshots[v.ProjectName]+1)
+				if err != nil {
+					logger.Warn("Skipping scheduled custom volume snapshot", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+					continue
+				}
+
+				scheduledSnapshots[v.ProjectName]++
+
 				if v.NodeID < 0 {
 					// Keep a separate list of remote volumes in order to select a member to
 					// perform the snapshot later.
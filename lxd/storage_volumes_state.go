@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -158,5 +159,24 @@ func storagePoolVolumeTypeStateGet(d *Daemon, r *http.Request) response.Response
 		state.Usage.Total = usage.Total
 	}
 
+	// Add the replication state if the volume has been replicated.
+	volType, err := storagePools.VolumeDBTypeToType(volumeType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	dbVol, err := storagePools.VolumeDBGet(pool, projectName, volumeName, volType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lastSync, lag, found := replicationLag(dbVol.Config, time.Now())
+	if found {
+		state.Replication = &api.StorageVolumeStateReplication{
+			LastSync: lastSync,
+			Lag:      int64(lag.Seconds()),
+		}
+	}
+
 	return response.SyncResponse(true, state)
 }
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestStorageVolumeCloneValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		source api.StorageVolumeSource
+		err    string
+	}{
+		{"Copy", api.StorageVolumeSource{Type: "copy", Pool: "other", Refresh: true}, ""},
+		{"Clone", api.StorageVolumeSource{Type: "copy", Pool: "pool1", Clone: true}, ""},
+		{"Clone from migration", api.StorageVolumeSource{Type: "migration", Pool: "pool1", Clone: true}, "Clones can only be created with the copy source type"},
+		{"Clone from another pool", api.StorageVolumeSource{Type: "copy", Pool: "other", Clone: true}, "Clones can only be created from volumes in the same storage pool"},
+		{"Clone refresh", api.StorageVolumeSource{Type: "copy", Pool: "pool1", Clone: true, Refresh: true}, "Clones cannot be refreshed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := storageVolumeCloneValidate(api.StorageVolumesPost{Name: "vol1", Source: tt.source}, "pool1")
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
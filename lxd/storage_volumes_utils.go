@@ -140,7 +140,7 @@ func storagePoolVolumeBackupLoadByName(s *state.State, projectName, poolName, ba
 	}
 
 	volumeName := strings.Split(backupName, "/")[0]
-	backup := backup.NewVolumeBackup(s, projectName, poolName, volumeName, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+	backup := backup.NewVolumeBackup(s, projectName, poolName, volumeName, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage, b.Target)
 
 	return backup, nil
 }
@@ -11,13 +11,15 @@ import (
 	"golang.org/x/sys/unix"
 
 	"github.com/canonical/lxd/lxd/events"
+	"github.com/canonical/lxd/lxd/network/acl"
+	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/revert"
 )
 
 // StartSyslogListener starts the log monitor.
-func StartSyslogListener(ctx context.Context, eventServer *events.Server) error {
+func StartSyslogListener(ctx context.Context, eventServer *events.Server, stateFunc func() *state.State) error {
 	var listenConfig net.ListenConfig
 
 	sockFile := shared.VarPath("syslog.socket")
@@ -129,6 +131,13 @@ func StartSyslogListener(ctx context.Context, eventServer *events.Server) error
 				event.Context["application"] = applicationName
 			}
 
+			// Add the structured details of network ACL log entries.
+			if moduleName == "acl_log" {
+				for k, v := range acl.OVNLogContext(stateFunc(), message) {
+					event.Context[k] = v
+				}
+			}
+
 			err = eventServer.Send("", api.EventTypeOVN, event)
 			if err != nil {
 				continue
@@ -37,7 +37,7 @@ func AvailableStorageDrivers(supportedDrivers []api.ServerStorageDriverInfo, poo
 			continue
 		}
 
-		if poolType == PoolTypeAny && (driver.Name == "cephfs" || driver.Name == "cephobject") {
+		if poolType == PoolTypeAny && (driver.Name == "cephfs" || driver.Name == "cephobject" || driver.Name == "nfs" || driver.Name == "lun") {
 			continue
 		}
 
@@ -35,6 +35,29 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_compression_algorithm
 	CompressionAlgorithm string `json:"compression_algorithm" yaml:"compression_algorithm"`
+
+	// Name of a previous backup of the same instance to make this backup incremental from
+	// Example: backup0
+	//
+	// API extension: backup_incremental
+	IncrementalFrom string `json:"incremental_from" yaml:"incremental_from"`
+
+	// X25519 public keys (age recipients) to encrypt the backup tarball to
+	// Example: ["age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"]
+	//
+	// API extension: backup_encryption
+	EncryptionRecipients []string `json:"encryption_recipients" yaml:"encryption_recipients"`
+
+	// Passphrase to encrypt the backup tarball with
+	// Example: my-secret-passphrase
+	//
+	// API extension: backup_encryption
+	EncryptionPassphrase string `json:"encryption_passphrase" yaml:"encryption_passphrase"`
+
+	// S3 bucket to upload the backup tarball to instead of storing it on the server
+	//
+	// API extension: backup_s3_target
+	Target *BackupTarget `json:"target" yaml:"target"`
 }
 
 // InstanceBackup represents a LXD instance backup.
@@ -66,6 +89,17 @@ type InstanceBackup struct {
 	// Whether to use a pool-optimized binary format (instead of plain tarball)
 	// Example: true
 	OptimizedStorage bool `json:"optimized_storage" yaml:"optimized_storage"`
+
+	// Name of the backup this backup is incremental from (empty for a full backup)
+	// Example: backup0
+	//
+	// API extension: backup_incremental
+	IncrementalFrom string `json:"incremental_from" yaml:"incremental_from"`
+
+	// S3 bucket the backup tarball was uploaded to (without credentials)
+	//
+	// API extension: backup_s3_target
+	Target *BackupTarget `json:"target" yaml:"target"`
 }
 
 // InstanceBackupPost represents the fields available for the renaming of a instance backup.
@@ -78,3 +112,34 @@ type InstanceBackupPost struct {
 	// Example: backup1
 	Name string `json:"name" yaml:"name"`
 }
+
+// BackupTarget represents an S3 bucket a backup tarball is uploaded to.
+//
+// swagger:model
+//
+// API extension: backup_s3_target.
+type BackupTarget struct {
+	// URL of the S3 endpoint (empty to use a storage bucket of the project on a local storage pool)
+	// Example: https://s3.example.com
+	URL string `json:"url" yaml:"url"`
+
+	// Storage pool of the storage bucket (only used without URL)
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Name of the bucket
+	// Example: backups
+	BucketName string `json:"bucket_name" yaml:"bucket_name"`
+
+	// Name of the object the backup tarball is uploaded as (defaults to the backup name)
+	// Example: c1/backup0
+	ObjectName string `json:"object_name" yaml:"object_name"`
+
+	// Access key of the bucket (only used with URL)
+	// Example: 33UgkaIBLBIxb7O1
+	AccessKey string `json:"access_key,omitempty" yaml:"access_key,omitempty"`
+
+	// Secret key of the bucket (only used with URL)
+	// Example: kDQD6AOgwHgaQI1UIJBJpPaiLgZuJbq0
+	SecretKey string `json:"secret_key,omitempty" yaml:"secret_key,omitempty"`
+}
@@ -22,7 +22,7 @@ type InstanceConsolePost struct {
 	// Example: 24
 	Height int `json:"height" yaml:"height"`
 
-	// Type of console to attach to (console or vga)
+	// Type of console to attach to (console, vga or vnc)
 	// Example: console
 	//
 	// API extension: console_vga_type
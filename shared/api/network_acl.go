@@ -155,3 +155,31 @@ type NetworkACLsPost struct {
 	NetworkACLPost `yaml:",inline"`
 	NetworkACLPut  `yaml:",inline"`
 }
+
+// NetworkACLState represents the state of an ACL.
+//
+// swagger:model
+//
+// API extension: network_acl_stats.
+type NetworkACLState struct {
+	// Hit counters of the ingress rules (in the same order as the rules)
+	Ingress []NetworkACLRuleCounters `json:"ingress" yaml:"ingress"`
+
+	// Hit counters of the egress rules (in the same order as the rules)
+	Egress []NetworkACLRuleCounters `json:"egress" yaml:"egress"`
+}
+
+// NetworkACLRuleCounters represents the hit counters of an ACL rule.
+//
+// swagger:model
+//
+// API extension: network_acl_stats.
+type NetworkACLRuleCounters struct {
+	// Number of packets that matched the rule
+	// Example: 1024
+	Packets uint64 `json:"packets" yaml:"packets"`
+
+	// Number of bytes that matched the rule
+	// Example: 65536
+	Bytes uint64 `json:"bytes" yaml:"bytes"`
+}
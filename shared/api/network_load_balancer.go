@@ -3,6 +3,7 @@ package api
 import (
 	"net"
 	"strings"
+	"time"
 )
 
 // NetworkLoadBalancerBackend represents a target backend specification in a network load balancer
@@ -26,6 +27,24 @@ type NetworkLoadBalancerBackend struct {
 	// TargetAddress to forward ListenPorts to
 	// Example: 198.51.100.2
 	TargetAddress string `json:"target_address" yaml:"target_address"`
+
+	// HealthCheck to run against the backend (either tcp or http, empty to disable)
+	// Example: http
+	//
+	// API extension: network_load_balancer_health_check
+	HealthCheck string `json:"health_check,omitempty" yaml:"health_check,omitempty"`
+
+	// HealthCheckPort to run the health check against (defaults to the first target port)
+	// Example: 8080
+	//
+	// API extension: network_load_balancer_health_check
+	HealthCheckPort string `json:"health_check_port,omitempty" yaml:"health_check_port,omitempty"`
+
+	// HealthCheckPath to request for http health checks (defaults to /)
+	// Example: /healthz
+	//
+	// API extension: network_load_balancer_health_check
+	HealthCheckPath string `json:"health_check_path,omitempty" yaml:"health_check_path,omitempty"`
 }
 
 // Normalise normalises the fields in the load balancer backend so that they are comparable with ones stored.
@@ -45,6 +64,10 @@ func (p *NetworkLoadBalancerBackend) Normalise() {
 	}
 
 	p.TargetPort = strings.Join(subjects, ",")
+
+	p.HealthCheck = strings.TrimSpace(p.HealthCheck)
+	p.HealthCheckPort = strings.TrimSpace(p.HealthCheckPort)
+	p.HealthCheckPath = strings.TrimSpace(p.HealthCheckPath)
 }
 
 // NetworkLoadBalancerPort represents a port specification in a network load balancer
@@ -157,3 +180,40 @@ func (f *NetworkLoadBalancer) Etag() []any {
 func (f *NetworkLoadBalancer) Writable() NetworkLoadBalancerPut {
 	return f.NetworkLoadBalancerPut
 }
+
+// NetworkLoadBalancerState is used for showing current state of a load balancer
+//
+// swagger:model
+//
+// API extension: network_load_balancer_health_check.
+type NetworkLoadBalancerState struct {
+	// Health of the load balancer backends, keyed by backend name
+	BackendHealth map[string]NetworkLoadBalancerStateBackendHealth `json:"backend_health" yaml:"backend_health"`
+}
+
+// NetworkLoadBalancerStateBackendHealth represents the health of a load balancer backend
+//
+// swagger:model
+//
+// API extension: network_load_balancer_health_check.
+type NetworkLoadBalancerStateBackendHealth struct {
+	// Target address of the backend
+	// Example: 198.51.100.2
+	Address string `json:"address" yaml:"address"`
+
+	// Health check run against the backend (empty if none)
+	// Example: http
+	HealthCheck string `json:"health_check" yaml:"health_check"`
+
+	// Health status of the backend (one of "unchecked", "unknown", "healthy" or "unhealthy")
+	// Example: healthy
+	Status string `json:"status" yaml:"status"`
+
+	// Error returned by the last health check (empty if it succeeded)
+	// Example: dial tcp 198.51.100.2:80: connect: connection refused
+	Error string `json:"error" yaml:"error"`
+
+	// Time of the last health check
+	// Example: 2024-01-15T10:00:00Z
+	LastCheck time.Time `json:"last_check" yaml:"last_check"`
+}
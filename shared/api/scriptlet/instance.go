@@ -31,3 +31,27 @@ type InstancePlacement struct {
 	Reason  string `json:"reason"`
 	Project string `json:"project"`
 }
+
+// InstanceValidationReasonCreate is when a new instance request is received.
+const InstanceValidationReasonCreate = "create"
+
+// InstanceValidationReasonUpdate is when an update request for an existing instance is received.
+const InstanceValidationReasonUpdate = "update"
+
+// InstanceValidation represents the instance validation request.
+//
+// API extension: instances_validation_scriptlet.
+type InstanceValidation struct {
+	api.InstancePut `yaml:",inline"`
+
+	Name    string           `json:"name"`
+	Type    api.InstanceType `json:"type"`
+	Reason  string           `json:"reason"`
+	Project string           `json:"project"`
+
+	// Instance configuration with the profiles applied (read-only).
+	ExpandedConfig map[string]string `json:"expanded_config"`
+
+	// Instance devices with the profiles applied (read-only).
+	ExpandedDevices map[string]map[string]string `json:"expanded_devices"`
+}
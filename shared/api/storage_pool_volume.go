@@ -242,6 +242,12 @@ type StorageVolumeSource struct {
 	//
 	// API extension: cluster_internal_custom_volume_copy
 	Location string `json:"location" yaml:"location"`
+
+	// Whether to create a copy-on-write clone of the source volume (for copy)
+	// Example: false
+	//
+	// API extension: storage_volume_clone
+	Clone bool `json:"clone" yaml:"clone"`
 }
 
 // Writable converts a full StorageVolume struct into a StorageVolumePut struct (filters read-only fields).
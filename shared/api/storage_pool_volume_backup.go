@@ -29,6 +29,11 @@ type StoragePoolVolumeBackup struct {
 	// Whether to use a pool-optimized binary format (instead of plain tarball)
 	// Example: true
 	OptimizedStorage bool `json:"optimized_storage" yaml:"optimized_storage"`
+
+	// S3 bucket the backup tarball was uploaded to (without credentials)
+	//
+	// API extension: backup_s3_target
+	Target *BackupTarget `json:"target" yaml:"target"`
 }
 
 // StoragePoolVolumeBackupsPost represents the fields available for a new LXD volume backup
@@ -56,6 +61,23 @@ type StoragePoolVolumeBackupsPost struct {
 	// What compression algorithm to use
 	// Example: gzip
 	CompressionAlgorithm string `json:"compression_algorithm" yaml:"compression_algorithm"`
+
+	// X25519 public keys (age recipients) to encrypt the backup tarball to
+	// Example: ["age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"]
+	//
+	// API extension: backup_encryption
+	EncryptionRecipients []string `json:"encryption_recipients" yaml:"encryption_recipients"`
+
+	// Passphrase to encrypt the backup tarball with
+	// Example: my-secret-passphrase
+	//
+	// API extension: backup_encryption
+	EncryptionPassphrase string `json:"encryption_passphrase" yaml:"encryption_passphrase"`
+
+	// S3 bucket to upload the backup tarball to instead of storing it on the server
+	//
+	// API extension: backup_s3_target
+	Target *BackupTarget `json:"target" yaml:"target"`
 }
 
 // StoragePoolVolumeBackupPost represents the fields available for the renaming of a volume backup
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // StorageVolumeState represents the live state of the volume
 //
 // swagger:model
@@ -8,6 +12,11 @@ package api
 type StorageVolumeState struct {
 	// Volume usage
 	Usage *StorageVolumeStateUsage `json:"usage" yaml:"usage"`
+
+	// Volume replication state
+	//
+	// API extension: storage_volume_replication
+	Replication *StorageVolumeStateReplication `json:"replication,omitempty" yaml:"replication,omitempty"`
 }
 
 // StorageVolumeStateUsage represents the disk usage of a volume
@@ -26,3 +35,18 @@ type StorageVolumeStateUsage struct {
 	// API extension: storage_volume_state_total
 	Total int64 `json:"total" yaml:"total"`
 }
+
+// StorageVolumeStateReplication represents the replication state of a volume
+//
+// swagger:model
+//
+// API extension: storage_volume_replication.
+type StorageVolumeStateReplication struct {
+	// Start of the last successful replication
+	// Example: 2021-03-23T20:00:00-04:00
+	LastSync time.Time `json:"last_sync" yaml:"last_sync"`
+
+	// Age of the replica in seconds (time elapsed since the start of the last successful replication)
+	// Example: 3600
+	Lag int64 `json:"lag" yaml:"lag"`
+}
@@ -0,0 +1,15 @@
+// Package encryption provides helpers for the age file encryption format (https://age-encryption.org/v1) used
+// to encrypt backups. Encryption and decryption are done with the filippo.io/age package.
+package encryption
+
+import (
+	"bytes"
+)
+
+// Magic is the first line of an encrypted file.
+const Magic = "age-encryption.org/v1"
+
+// IsEncrypted returns whether the given file header is the header of an encrypted file.
+func IsEncrypted(header []byte) bool {
+	return bytes.HasPrefix(header, []byte(Magic+"\n"))
+}
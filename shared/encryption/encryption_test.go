@@ -0,0 +1,34 @@
+package encryption
+
+import (
+	"bytes"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsEncrypted(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, identity.Recipient())
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("backup"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.True(t, IsEncrypted(buf.Bytes()))
+	assert.True(t, IsEncrypted(buf.Bytes()[:len(Magic)+1]))
+
+	// Compressed or plain tarballs aren't encrypted.
+	assert.False(t, IsEncrypted([]byte{0x1f, 0x8b, 0x08, 0x00}))
+	assert.False(t, IsEncrypted([]byte("backup/index.yaml")))
+
+	// The armored format and truncated headers aren't supported.
+	assert.False(t, IsEncrypted([]byte("-----BEGIN AGE ENCRYPTED FILE-----\n")))
+	assert.False(t, IsEncrypted([]byte(Magic)))
+}
@@ -396,6 +396,32 @@ var APIExtensions = []string{
 	"metrics_instances_count",
 	"server_instance_type_info",
 	"resources_disk_mounted",
+	"instances_validation_scriptlet",
+	"project_limits_io",
+	"project_limits_storage",
+	"instance_power_schedules",
+	"metrics_api_histograms",
+	"backup_incremental",
+	"backup_encryption",
+	"backup_s3_target",
+	"backup_schedule",
+	"instance_live_storage_move",
+	"network_load_balancer_bridge",
+	"network_load_balancer_health_check",
+	"network_acl_stats",
+	"network_wireguard",
+	"storage_driver_nfs",
+	"storage_driver_lun",
+	"storage_volume_replication",
+	"storage_volume_encryption",
+	"storage_volume_clone",
+	"storage_pool_low_space_warning",
+	"vm_memory_hotplug",
+	"console_vnc_type",
+	"console_screenshot",
+	"vm_limits_cpu_nodes",
+	"instances_cpu_balancing",
+	"network_peer_bridge",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
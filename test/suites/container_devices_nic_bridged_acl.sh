@@ -185,6 +185,37 @@ test_container_devices_nic_bridged_acl() {
   # Check can't delete ACL that is in use.
   ! lxc network acl delete "${brName}A" || false
 
+  # Check network peer subjects are converted to the subnets of the peer network.
+  lxc network create "${brName}P" ipv4.address=198.51.100.1/24 ipv6.address=none
+  lxc network peer create "${brName}" peer1 "${brName}P"
+  lxc network peer show "${brName}" peer1 | grep -xF "status: Pending"
+  ! lxc network acl rule add "${brName}A" egress action=allow destination="@${brName}/peer1" protocol=tcp destination_port=8080 || false
+  lxc network peer create "${brName}P" peer1 "${brName}"
+  lxc network peer show "${brName}" peer1 | grep -xF "status: Created"
+  lxc network acl rule add "${brName}A" egress action=allow destination="@${brName}/peer1" protocol=tcp destination_port=8080
+
+  if [ "$firewallDriver" = "xtables" ]; then
+      iptables -S "lxd_acl_${brName}" | grep -F -- "-d 198.51.100.0/24"
+  else
+      nft -nn list chain inet lxd "acl.${brName}" | grep -F "198.51.100.0/24"
+  fi
+
+  # Check the rules follow changes to the subnet of the peer network.
+  lxc network set "${brName}P" ipv4.address=198.51.101.1/24
+
+  if [ "$firewallDriver" = "xtables" ]; then
+      iptables -S "lxd_acl_${brName}" | grep -F -- "-d 198.51.101.0/24"
+  else
+      nft -nn list chain inet lxd "acl.${brName}" | grep -F "198.51.101.0/24"
+  fi
+
+  # Check can't delete peer that is in use.
+  ! lxc network peer delete "${brName}" peer1 || false
+  lxc network acl rule remove "${brName}A" egress destination="@${brName}/peer1"
+  lxc network peer delete "${brName}" peer1
+  lxc network peer delete "${brName}P" peer1
+  lxc network delete "${brName}P"
+
   lxc delete -f "${ctPrefix}A"
   lxc profile delete "${ctPrefix}"
   lxc network delete "${brName}"
@@ -0,0 +1,47 @@
+test_storage_driver_lun() {
+  # shellcheck disable=2039,3043
+  local lxd_backend
+
+  lxd_backend=$(storage_backend "$LXD_DIR")
+  if [ "$lxd_backend" != "dir" ]; then
+    return
+  fi
+
+  # The target must expose at least three empty LUNs of 1GiB or more.
+  if [ -z "${LXD_LUN_TARGET_ADDRESS:-}" ] || [ -z "${LXD_LUN_TARGET_NAME:-}" ]; then
+    echo "==> SKIP: The lun storage driver test requires LXD_LUN_TARGET_ADDRESS and LXD_LUN_TARGET_NAME to be set"
+    return
+  fi
+
+  # Invalid options.
+  ! lxc storage create lun lun || false
+  ! lxc storage create lun lun lun.target.address="${LXD_LUN_TARGET_ADDRESS}" || false
+  ! lxc storage create lun lun lun.target.address="${LXD_LUN_TARGET_ADDRESS}" lun.target.name="${LXD_LUN_TARGET_NAME}" lun.mode=fc || false
+
+  lxc storage create lun lun lun.mode="${LXD_LUN_MODE:-iscsi}" lun.target.address="${LXD_LUN_TARGET_ADDRESS}" lun.target.name="${LXD_LUN_TARGET_NAME}"
+
+  # The target can't be changed once the pool exists.
+  ! lxc storage set lun lun.target.name=foo || false
+
+  # Custom volumes.
+  lxc storage volume create lun vol1 size=64MiB
+  lxc storage volume set lun vol1 size=128MiB
+  lxc storage volume rename lun vol1 vol2
+  lxc storage volume create lun vol3 --type=block size=64MiB
+  ! lxc storage volume snapshot lun vol2 snap0 || false
+  lxc storage volume delete lun vol2
+  lxc storage volume delete lun vol3
+
+  # Instances.
+  ensure_import_testimage
+  lxc init testimage c1 -s lun
+  lxc start c1
+  lxc exec c1 -- touch /foo
+  lxc stop -f c1
+  lxc move c1 c2
+  lxc start c2
+  lxc exec c2 -- test -e /foo
+  lxc delete -f c2
+
+  lxc storage delete lun
+}
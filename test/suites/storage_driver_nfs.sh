@@ -0,0 +1,62 @@
+test_storage_driver_nfs() {
+  # shellcheck disable=2039,3043
+  local lxd_backend nfs_dir
+
+  lxd_backend=$(storage_backend "$LXD_DIR")
+  if [ "$lxd_backend" != "dir" ]; then
+    return
+  fi
+
+  if ! command -v exportfs >/dev/null 2>&1 || [ ! -e /proc/fs/nfsd/threads ] || [ "$(cat /proc/fs/nfsd/threads)" = "0" ]; then
+    echo "==> SKIP: The nfs storage driver test requires a running kernel NFS server"
+    return
+  fi
+
+  # Export an empty directory from the local kernel NFS server.
+  nfs_dir=$(mktemp -d -p "${TEST_DIR}" XXXX)
+  chmod +x "${TEST_DIR}" "${nfs_dir}"
+  exportfs -o rw,sync,no_subtree_check,no_root_squash,insecure,fsid="$(uuidgen)" "127.0.0.1:${nfs_dir}"
+
+  # Invalid sources and options.
+  ! lxc storage create nfs nfs || false
+  ! lxc storage create nfs nfs source="${nfs_dir}" || false
+  ! lxc storage create nfs nfs source="127.0.0.1:${nfs_dir}" nfs.version=5 || false
+
+  # Non-empty exports are refused.
+  touch "${nfs_dir}/foo"
+  ! lxc storage create nfs nfs source="127.0.0.1:${nfs_dir}" || false
+  rm "${nfs_dir}/foo"
+
+  lxc storage create nfs nfs source="127.0.0.1:${nfs_dir}"
+  [ "$(lxc storage get nfs nfs.version)" = "4.2" ]
+  grep -qF "127.0.0.1:${nfs_dir} ${LXD_DIR}/storage-pools/nfs nfs4 " /proc/mounts
+
+  # Config keys affecting the mount can't be changed while the pool is in use.
+  ! lxc storage set nfs nfs.version=4.1 || false
+
+  # Custom volumes.
+  lxc storage volume create nfs vol1
+  lxc storage volume rename nfs vol1 vol2
+  lxc storage volume copy nfs/vol2 nfs/vol1
+  lxc storage volume snapshot nfs vol1 snap0
+  lxc storage volume restore nfs vol1 snap0
+  [ -d "${nfs_dir}/custom/default_vol1" ]
+  lxc storage volume delete nfs vol1
+  lxc storage volume delete nfs vol2
+
+  # Instances.
+  ensure_import_testimage
+  lxc init testimage c1 -s nfs
+  lxc start c1
+  lxc exec c1 -- touch /foo
+  [ -e "${nfs_dir}/containers/c1/rootfs/foo" ]
+  lxc snapshot c1
+  lxc copy c1 c2
+  lxc delete -f c1 c2
+
+  lxc storage delete nfs
+  [ -z "$(ls -A "${nfs_dir}")" ]
+
+  exportfs -u "127.0.0.1:${nfs_dir}"
+  rmdir "${nfs_dir}"
+}
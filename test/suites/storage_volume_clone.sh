@@ -0,0 +1,58 @@
+test_storage_volume_clone() {
+  lxd_backend=$(storage_backend "$LXD_DIR")
+  if [ "${lxd_backend}" != "zfs" ] && [ "${lxd_backend}" != "btrfs" ] && [ "${lxd_backend}" != "lvm" ]; then
+    return
+  fi
+
+  pool="lxdtest-$(basename "${LXD_DIR}")-clone"
+  other_pool="lxdtest-$(basename "${LXD_DIR}")-clone-dir"
+  project="clone-project"
+
+  if [ "${lxd_backend}" = "lvm" ]; then
+    lxc storage create "${pool}" lvm volume.size=25MiB lvm.use_thinpool=true
+  else
+    lxc storage create "${pool}" "${lxd_backend}" size=1GiB
+  fi
+
+  lxc storage create "${other_pool}" dir
+  lxc project create "${project}" -c features.storage.volumes=true
+
+  lxc storage volume create "${pool}" vol1 user.foo=bar
+  lxc storage volume snapshot "${pool}" vol1 snap0
+
+  # Clone the volume into another project.
+  lxc storage volume copy "${pool}/vol1" "${pool}/clone1" --clone --target-project "${project}"
+  [ "$(lxc storage volume get "${pool}" clone1 user.foo --project "${project}")" = "bar" ]
+  [ "$(lxc storage volume get "${pool}" clone1 volatile.clone.source --project "${project}")" = "default/vol1" ]
+
+  # Clones don't include the snapshots of their source.
+  [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/clone1/snapshots?project=${project}" | jq length)" = "0" ]
+
+  # The clone source is kept when the clone config is changed.
+  lxc storage volume set "${pool}" clone1 user.foo=baz --project "${project}"
+  lxc storage volume unset "${pool}" clone1 volatile.clone.source --project "${project}"
+  [ "$(lxc storage volume get "${pool}" clone1 volatile.clone.source --project "${project}")" = "default/vol1" ]
+  [ "$(lxc storage volume get "${pool}" vol1 user.foo)" = "bar" ]
+  ! lxc storage volume set "${pool}" clone1 volatile.clone.source=default/vol2 --project "${project}" || false
+  ! lxc storage volume set "${pool}" vol1 volatile.clone.source="${project}/clone1" || false
+
+  # Clones can only be created from volumes in the same pool, and can't be refreshed.
+  ! lxc storage volume copy "${pool}/vol1/snap0" "${pool}/clone2" --clone || false
+  ! lxc storage volume copy "${pool}/vol1" "${other_pool}/clone2" --clone || false
+  ! lxc storage volume copy "${pool}/vol1" "${pool}/clone1" --clone --refresh --target-project "${project}" || false
+  ! lxc storage volume show "${other_pool}" clone2 || false
+
+  # A volume with clones can't be deleted or renamed.
+  ! lxc storage volume delete "${pool}" vol1 || false
+  ! lxc storage volume rename "${pool}" vol1 vol2 || false
+  lxc storage volume show "${pool}" vol1
+
+  # Once its clones are gone, the volume can be renamed and deleted.
+  lxc storage volume delete "${pool}" clone1 --project "${project}"
+  lxc storage volume rename "${pool}" vol1 vol2
+  lxc storage volume delete "${pool}" vol2
+
+  lxc project delete "${project}"
+  lxc storage delete "${other_pool}"
+  lxc storage delete "${pool}"
+}